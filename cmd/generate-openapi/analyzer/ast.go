@@ -0,0 +1,231 @@
+package analyzer
+
+import (
+	"bufio"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// ASTAnalyzer reads handler doc comments from Go source so routes can be
+// documented from the functions that serve them
+type ASTAnalyzer struct {
+	root       string
+	modulePath string
+	fileSet    *token.FileSet
+
+	mu       sync.Mutex
+	packages map[string][]*ast.File // Parsed files keyed by package directory
+}
+
+// NewASTAnalyzer creates an analyzer for the Go module rooted at root
+func NewASTAnalyzer(root string) *ASTAnalyzer {
+	return &ASTAnalyzer{
+		root:       root,
+		modulePath: modulePath(filepath.Join(root, "go.mod")),
+		fileSet:    token.NewFileSet(),
+		packages:   make(map[string][]*ast.File),
+	}
+}
+
+// ExtractDoc returns the doc comment of a function or method split into a summary,
+// its first paragraph, and a description made of the remaining paragraphs.
+//
+// funcName is "Func", "Type.Method" or "(*Type).Method", optionally qualified by
+// the package name as in "users.GetUserHandler". pkgPath is the package's import
+// path or its directory relative to the module root.
+func (a *ASTAnalyzer) ExtractDoc(funcName string, pkgPath string) (summary, description string, err error) {
+	files, err := a.parsePackage(pkgPath)
+	if err != nil {
+		return "", "", err
+	}
+
+	receiver, name := splitFuncName(funcName, files)
+	for _, file := range files {
+		for _, decl := range file.Decls {
+			fn, ok := decl.(*ast.FuncDecl)
+			if !ok || fn.Name.Name != name || receiverName(fn) != receiver {
+				continue
+			}
+			if fn.Doc == nil {
+				return "", "", nil
+			}
+			summary, description, _ = strings.Cut(strings.TrimSpace(fn.Doc.Text()), "\n\n")
+			return strings.Join(strings.Fields(summary), " "), strings.TrimSpace(description), nil
+		}
+	}
+	return "", "", fmt.Errorf("function %s not found in package %s", funcName, pkgPath)
+}
+
+// parsePackage parses and caches the non-test Go files of a package
+func (a *ASTAnalyzer) parsePackage(pkgPath string) ([]*ast.File, error) {
+	dir := pkgPath
+	if a.modulePath != "" && (pkgPath == a.modulePath || strings.HasPrefix(pkgPath, a.modulePath+"/")) {
+		dir = strings.TrimPrefix(strings.TrimPrefix(pkgPath, a.modulePath), "/")
+	}
+	dir = filepath.Join(a.root, filepath.FromSlash(dir))
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if files, ok := a.packages[dir]; ok {
+		return files, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list package %s: %w", pkgPath, err)
+	}
+
+	var files []*ast.File
+	for _, path := range matches {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(a.fileSet, path, nil, parser.ParseComments)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no Go files found for package %s", pkgPath)
+	}
+
+	a.packages[dir] = files
+	return files, nil
+}
+
+// splitFuncName returns the receiver type and function name of funcName, dropping
+// a leading package qualifier that names the parsed package
+func splitFuncName(funcName string, files []*ast.File) (receiver, name string) {
+	parts := strings.Split(funcName, ".")
+	if len(parts) > 1 && parts[0] == files[0].Name.Name {
+		parts = parts[1:]
+	}
+
+	name = parts[len(parts)-1]
+	if len(parts) > 1 {
+		receiver = strings.Trim(strings.Join(parts[:len(parts)-1], "."), "(*)")
+	}
+	return receiver, name
+}
+
+// receiverName returns the receiver type name of a method, or "" for a function
+func receiverName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	// Generic receivers such as Store[T] name their type through an index expression
+	switch typ := expr.(type) {
+	case *ast.IndexExpr:
+		expr = typ.X
+	case *ast.IndexListExpr:
+		expr = typ.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// modulePath returns the module path declared in a go.mod file, or "" when it
+// cannot be read
+func modulePath(goMod string) string {
+	f, err := os.Open(goMod)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if path, ok := strings.CutPrefix(strings.TrimSpace(scanner.Text()), "module "); ok {
+			return strings.Trim(strings.TrimSpace(path), `"`)
+		}
+	}
+	return ""
+}
+
+// FindModuleRoot returns the directory of the go.mod file at or above dir
+func FindModuleRoot(dir string) (string, error) {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return "", err
+	}
+	for {
+		if _, err := os.Stat(filepath.Join(dir, "go.mod")); err == nil {
+			return dir, nil
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return "", fmt.Errorf("no go.mod found at or above the working directory")
+		}
+		dir = parent
+	}
+}
+
+// WarningHandlerDoc reports a HandlerFuncName whose doc comment could not be read
+const WarningHandlerDoc = "handler-doc"
+
+// applyHandlerDoc fills an operation's empty summary and description from the doc
+// comment of the route's HandlerFuncName
+func (g *Generator) applyHandlerDoc(route types.RouteInfo, operation *Operation) {
+	if g.astAnalyzer == nil {
+		root := g.ModuleRoot
+		if root == "" {
+			var err error
+			if root, err = FindModuleRoot("."); err != nil {
+				// Without the source every route would fail the same way, so say it once
+				g.SkipHandlerDocs = true
+				g.warnings = append(g.warnings, Warning{
+					Category: WarningHandlerDoc,
+					Message:  fmt.Sprintf("handler doc comments are not read: %v", err),
+				})
+				return
+			}
+		}
+		g.astAnalyzer = NewASTAnalyzer(root)
+	}
+
+	pkgPath, funcName := handlerPackage(route.HandlerFuncName)
+	summary, description, err := g.astAnalyzer.ExtractDoc(funcName, pkgPath)
+	if err != nil {
+		g.warnings = append(g.warnings, Warning{
+			Category: WarningHandlerDoc,
+			Module:   route.Module,
+			Route:    strings.ToUpper(route.Method) + " " + route.Path,
+			Message:  fmt.Sprintf("cannot read the doc comment of %s: %v", route.HandlerFuncName, err),
+		})
+		return
+	}
+
+	if operation.Summary == "" {
+		operation.Summary = summary
+	}
+	if operation.Description == "" {
+		operation.Description = description
+	}
+}
+
+// handlerPackage splits a HandlerFuncName into the package path and the function
+// name within it. Bare package names refer to packages under internal/.
+func handlerPackage(handlerFuncName string) (pkgPath, funcName string) {
+	slash := strings.LastIndex(handlerFuncName, "/")
+	pkgName, funcName, _ := strings.Cut(handlerFuncName[slash+1:], ".")
+	if slash < 0 {
+		return "internal/" + pkgName, funcName
+	}
+	return handlerFuncName[:slash+1] + pkgName, funcName
+}
@@ -0,0 +1,131 @@
+package analyzer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+const usersSource = `package users
+
+import "net/http"
+
+// UsersHandler serves the users routes
+type UsersHandler struct{}
+
+// GetUserHandler returns a single user by ID
+//
+// Responds with 404 when no user has the ID. Deleted users are
+// reported as not found as well.
+func GetUserHandler(w http.ResponseWriter, r *http.Request) {}
+
+// List returns every user, ordered by
+// creation time
+func (h *UsersHandler) List(w http.ResponseWriter, r *http.Request) {}
+
+func undocumented(w http.ResponseWriter, r *http.Request) {}
+`
+
+// writeUsersModule writes a module containing the synthetic users package and returns its root
+func writeUsersModule(t *testing.T) string {
+	t.Helper()
+	root := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(root, "internal", "users"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/app\n\ngo 1.24\n")
+	writeFile(t, filepath.Join(root, "internal", "users", "handler.go"), usersSource)
+	return root
+}
+
+func TestASTAnalyzer_ExtractDoc(t *testing.T) {
+	analyzer := NewASTAnalyzer(writeUsersModule(t))
+
+	tests := []struct {
+		funcName    string
+		pkgPath     string
+		summary     string
+		description string
+	}{
+		{"users.GetUserHandler", "internal/users", "GetUserHandler returns a single user by ID",
+			"Responds with 404 when no user has the ID. Deleted users are\nreported as not found as well."},
+		{"GetUserHandler", "example.com/app/internal/users", "GetUserHandler returns a single user by ID",
+			"Responds with 404 when no user has the ID. Deleted users are\nreported as not found as well."},
+		{"users.UsersHandler.List", "internal/users", "List returns every user, ordered by creation time", ""},
+		{"(*UsersHandler).List", "internal/users", "List returns every user, ordered by creation time", ""},
+		{"undocumented", "internal/users", "", ""},
+	}
+
+	for _, tt := range tests {
+		summary, description, err := analyzer.ExtractDoc(tt.funcName, tt.pkgPath)
+		if err != nil {
+			t.Errorf("ExtractDoc(%q, %q) error = %v", tt.funcName, tt.pkgPath, err)
+			continue
+		}
+		if summary != tt.summary || description != tt.description {
+			t.Errorf("ExtractDoc(%q, %q) = %q, %q; want %q, %q", tt.funcName, tt.pkgPath, summary, description, tt.summary, tt.description)
+		}
+	}
+}
+
+func TestASTAnalyzer_ExtractDocErrors(t *testing.T) {
+	analyzer := NewASTAnalyzer(writeUsersModule(t))
+
+	if _, _, err := analyzer.ExtractDoc("users.Missing", "internal/users"); err == nil {
+		t.Error("Expected an error for an unknown function")
+	}
+	// List is a method, so the bare name does not match it
+	if _, _, err := analyzer.ExtractDoc("List", "internal/users"); err == nil {
+		t.Error("Expected an error for a method looked up without its receiver")
+	}
+	if _, _, err := analyzer.ExtractDoc("users.GetUserHandler", "internal/missing"); err == nil {
+		t.Error("Expected an error for a missing package")
+	}
+}
+
+func TestBuildOperation_HandlerFuncName(t *testing.T) {
+	gen := NewGenerator()
+	gen.astAnalyzer = NewASTAnalyzer(writeUsersModule(t))
+
+	operation := gen.buildOperation(types.RouteInfo{Method: "GET", Path: "/users/{id}", Module: "users", HandlerFuncName: "users.GetUserHandler"})
+	if operation.Summary != "GetUserHandler returns a single user by ID" || !strings.HasPrefix(operation.Description, "Responds with 404") {
+		t.Errorf("Expected the doc comment as summary and description, got %q, %q", operation.Summary, operation.Description)
+	}
+
+	// An explicit summary wins over the doc comment
+	operation = gen.buildOperation(types.RouteInfo{Method: "GET", Path: "/users", Module: "users", Summary: "List users", HandlerFuncName: "users.UsersHandler.List"})
+	if operation.Summary != "List users" {
+		t.Errorf("Expected the route summary to be kept, got %q", operation.Summary)
+	}
+
+	gen.buildOperation(types.RouteInfo{Method: "GET", Path: "/gone", Module: "users", HandlerFuncName: "users.Gone"})
+	warnings := gen.Warnings()
+	if len(warnings) != 1 || warnings[0].Category != WarningHandlerDoc || warnings[0].Route != "GET /gone" {
+		t.Errorf("Expected a handler-doc warning for the unknown handler, got %+v", warnings)
+	}
+}
+
+func TestBuildOperation_HandlerDocsOptions(t *testing.T) {
+	root := writeUsersModule(t)
+	route := types.RouteInfo{Method: "GET", Path: "/users/{id}", Module: "users", HandlerFuncName: "users.GetUserHandler"}
+
+	// The module root is found from any directory inside it
+	t.Chdir(filepath.Join(root, "internal", "users"))
+	if operation := NewGenerator().buildOperation(route); operation.Summary != "GetUserHandler returns a single user by ID" {
+		t.Errorf("Expected the doc comment from the enclosing module, got %q", operation.Summary)
+	}
+
+	t.Chdir(t.TempDir())
+	gen := NewGenerator(WithHandlerDocs(true, root))
+	if operation := gen.buildOperation(route); operation.Summary != "GetUserHandler returns a single user by ID" {
+		t.Errorf("Expected the doc comment from the configured root, got %q", operation.Summary)
+	}
+
+	gen = NewGenerator(WithHandlerDocs(false, root))
+	if operation := gen.buildOperation(route); operation.Summary != "" || len(gen.Warnings()) != 0 {
+		t.Errorf("Expected no doc comment or warnings with handler docs disabled, got %q, %+v", operation.Summary, gen.Warnings())
+	}
+}
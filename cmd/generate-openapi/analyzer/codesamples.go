@@ -0,0 +1,383 @@
+package analyzer
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// CodeSample is one entry of an operation's x-codeSamples extension, rendered
+// natively by ReDoc
+type CodeSample struct {
+	Lang   string `yaml:"lang"`
+	Label  string `yaml:"label"`
+	Source string `yaml:"source"`
+}
+
+// sampleMaxDepth bounds example synthesis for deeply nested or recursive schemas
+const sampleMaxDepth = 8
+
+// codeSampleRequest is the concrete request every language's sample sends
+type codeSampleRequest struct {
+	Method  string
+	URL     string     // Base URL, path and query string
+	BaseURL string     // Base URL and path, without the query string
+	Query   url.Values // Query parameters, also encoded in URL
+	Headers [][2]string
+	Body    string // Indented JSON body, empty when the request has none
+	rawBody interface{}
+}
+
+// buildCodeSamples returns curl, Go and Python samples for an operation, or nil
+// for multipart routes, which the samples do not cover
+func (g *Generator) buildCodeSamples(route types.RouteInfo, operation *Operation) []CodeSample {
+	if route.RequestContentType == types.ContentTypeMultipartForm {
+		return nil
+	}
+
+	req := g.sampleRequest(route, operation)
+	return []CodeSample{
+		{Lang: "Shell", Label: "curl", Source: curlSample(req)},
+		{Lang: "Go", Label: "Go", Source: goSample(req)},
+		{Lang: "Python", Label: "Python", Source: pythonSample(req)},
+	}
+}
+
+// sampleRequest synthesizes the request the samples send: the first configured
+// server, the route path with example path parameters, query parameters with
+// example values, required headers, credentials for the route's security scheme
+// and an example JSON body built from the request schema
+func (g *Generator) sampleRequest(route types.RouteInfo, operation *Operation) codeSampleRequest {
+	base := "http://localhost:8080"
+	if servers := g.buildServers(); len(servers) > 0 {
+		base = strings.TrimRight(servers[0].URL, "/")
+	}
+
+	path := route.Path
+	for _, param := range operation.Parameters {
+		if param.In == "path" {
+			path = strings.ReplaceAll(path, "{"+param.Name+"}", url.PathEscape(g.samplePathValue(param)))
+		}
+	}
+
+	req := codeSampleRequest{
+		Method:  strings.ToUpper(route.Method),
+		BaseURL: base + path,
+		Query:   url.Values{},
+	}
+	g.sampleCredentials(operation, &req)
+
+	for _, param := range operation.Parameters {
+		switch {
+		case param.In == "query":
+			req.Query[param.Name] = sampleQueryValues(param, g.sampleValue(param.Schema, 0))
+		case param.In == "header" && param.Required:
+			req.Headers = append(req.Headers, [2]string{param.Name, fmt.Sprint(g.sampleValue(param.Schema, 0))})
+		}
+	}
+
+	req.URL = req.BaseURL
+	if len(req.Query) > 0 {
+		req.URL += "?" + req.Query.Encode()
+	}
+
+	if operation.RequestBody != nil {
+		if media, ok := operation.RequestBody.Content["application/json"]; ok {
+			req.rawBody = g.sampleValue(schemaRefMap(media.Schema), 0)
+			req.Body = sampleJSON(req.rawBody)
+			req.Headers = append([][2]string{{"Content-Type", "application/json"}}, req.Headers...)
+		}
+	}
+	return req
+}
+
+// samplePathValue returns the example value substituted for a path parameter.
+// Plain strings get a value naming the parameter rather than the generic "string".
+func (g *Generator) samplePathValue(param Parameter) string {
+	value := g.sampleValue(param.Schema, 0)
+	if value == "string" {
+		return "example-" + param.Name
+	}
+	return fmt.Sprint(value)
+}
+
+// sampleCredentials adds placeholder credentials for the first security scheme an
+// operation accepts, in the header, query parameter or cookie the scheme names
+func (g *Generator) sampleCredentials(operation *Operation, req *codeSampleRequest) {
+	if len(operation.Security) == 0 {
+		return
+	}
+	schemes := g.buildSecuritySchemes()
+	for _, name := range slices.Sorted(maps.Keys(operation.Security[0])) {
+		scheme, ok := schemes[name]
+		if !ok {
+			continue
+		}
+		switch {
+		case scheme.Type == "apiKey" && scheme.In == "query":
+			req.Query.Set(scheme.Name, "<api-key>")
+		case scheme.Type == "apiKey" && scheme.In == "cookie":
+			req.Headers = append(req.Headers, [2]string{"Cookie", scheme.Name + "=<api-key>"})
+		case scheme.Type == "apiKey":
+			req.Headers = append(req.Headers, [2]string{scheme.Name, "<api-key>"})
+		case scheme.Type == "http" && strings.EqualFold(scheme.Scheme, "basic"):
+			req.Headers = append(req.Headers, [2]string{"Authorization", "Basic <credentials>"})
+		default:
+			// Bearer, OAuth2 and OpenID Connect all send an access token
+			req.Headers = append(req.Headers, [2]string{"Authorization", "Bearer <token>"})
+		}
+	}
+}
+
+// sampleQueryValues encodes a query parameter's sample value following its style:
+// exploded arrays repeat the name, others join their items with the style's delimiter
+func sampleQueryValues(param Parameter, value interface{}) []string {
+	items, ok := value.([]interface{})
+	if !ok {
+		return []string{fmt.Sprint(value)}
+	}
+
+	values := make([]string, len(items))
+	for i, item := range items {
+		values[i] = fmt.Sprint(item)
+	}
+	if param.Explode == nil || *param.Explode {
+		return values
+	}
+
+	delimiter := ","
+	switch param.Style {
+	case queryStyleSpaceDelimited:
+		delimiter = " "
+	case queryStylePipeDelimited:
+		delimiter = "|"
+	}
+	return []string{strings.Join(values, delimiter)}
+}
+
+// schemaRefMap returns a SchemaRef as a schema map
+func schemaRefMap(ref SchemaRef) map[string]interface{} {
+	if ref.Ref != "" {
+		return map[string]interface{}{"$ref": ref.Ref}
+	}
+	return ref.Inline
+}
+
+// sampleValue synthesizes an example value for a schema, preferring its example,
+// default and minimum, and resolving component references
+func (g *Generator) sampleValue(schema map[string]interface{}, depth int) interface{} {
+	if schema == nil || depth > sampleMaxDepth {
+		return nil
+	}
+	if ref, ok := schema["$ref"].(string); ok {
+		name, _ := schemaRefName(ref)
+		g.mu.Lock()
+		component, _ := g.typeSchemas[name].(map[string]interface{})
+		g.mu.Unlock()
+		return g.sampleValue(component, depth+1)
+	}
+	if example, ok := schema["example"]; ok {
+		return example
+	}
+	if value, ok := schema["default"]; ok {
+		return value
+	}
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok && len(oneOf) > 0 {
+		variant, _ := oneOf[0].(map[string]interface{})
+		return g.sampleValue(variant, depth+1)
+	}
+
+	switch schema["type"] {
+	case "string":
+		switch schema["format"] {
+		case "date-time":
+			return "2024-01-01T00:00:00Z"
+		case "binary":
+			return ""
+		}
+		return "string"
+	case "integer", "number":
+		if minimum, ok := schema["minimum"]; ok {
+			return minimum
+		}
+		return 0
+	case "boolean":
+		return false
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		if item := g.sampleValue(items, depth+1); item != nil {
+			return []interface{}{item}
+		}
+		return []interface{}{}
+	default:
+		object := make(map[string]interface{})
+		properties, _ := schema["properties"].(map[string]interface{})
+		for name, property := range properties {
+			propertySchema, _ := property.(map[string]interface{})
+			object[name] = g.sampleValue(propertySchema, depth+1)
+		}
+		return object
+	}
+}
+
+// sampleJSON encodes an example body as indented JSON without HTML escaping
+func sampleJSON(value interface{}) string {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return "{}"
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
+
+// shellQuote wraps s in single quotes for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// curlSample renders the request as a curl command
+func curlSample(req codeSampleRequest) string {
+	var b strings.Builder
+	b.WriteString("curl")
+	fmt.Fprintf(&b, " -X %s %s", req.Method, shellQuote(req.URL))
+	for _, header := range req.Headers {
+		fmt.Fprintf(&b, " \\\n  -H %s", shellQuote(header[0]+": "+header[1]))
+	}
+	if req.Body != "" {
+		fmt.Fprintf(&b, " \\\n  -d %s", shellQuote(req.Body))
+	}
+	return b.String()
+}
+
+// goSample renders the request as a Go program using net/http
+func goSample(req codeSampleRequest) string {
+	var b strings.Builder
+	b.WriteString("package main\n\nimport (\n\t\"fmt\"\n\t\"io\"\n\t\"net/http\"\n")
+	if req.Body != "" {
+		b.WriteString("\t\"strings\"\n")
+	}
+	b.WriteString(")\n\nfunc main() {\n")
+
+	body := "nil"
+	if req.Body != "" {
+		literal := "`" + req.Body + "`"
+		if strings.Contains(req.Body, "`") {
+			literal = strconv.Quote(req.Body)
+		}
+		fmt.Fprintf(&b, "\tbody := strings.NewReader(%s)\n", literal)
+		body = "body"
+	}
+	fmt.Fprintf(&b, "\treq, err := http.NewRequest(%s, %s, %s)\n", strconv.Quote(req.Method), strconv.Quote(req.URL), body)
+	b.WriteString("\tif err != nil {\n\t\tpanic(err)\n\t}\n")
+	for _, header := range req.Headers {
+		fmt.Fprintf(&b, "\treq.Header.Set(%s, %s)\n", strconv.Quote(header[0]), strconv.Quote(header[1]))
+	}
+	b.WriteString("\n\tresp, err := http.DefaultClient.Do(req)\n\tif err != nil {\n\t\tpanic(err)\n\t}\n\tdefer resp.Body.Close()\n\n")
+	b.WriteString("\tout, _ := io.ReadAll(resp.Body)\n\tfmt.Println(resp.Status, string(out))\n}")
+	return b.String()
+}
+
+// pythonSample renders the request using the requests library
+func pythonSample(req codeSampleRequest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "import requests\n\nresponse = requests.%s(\n    %s,\n", strings.ToLower(req.Method), pythonLiteral(req.BaseURL, 1))
+
+	if len(req.Query) > 0 {
+		params := make(map[string]interface{}, len(req.Query))
+		for name := range req.Query {
+			params[name] = req.Query.Get(name)
+		}
+		fmt.Fprintf(&b, "    params=%s,\n", pythonLiteral(params, 1))
+	}
+	// requests sets Content-Type itself for json=
+	var headers map[string]interface{}
+	for _, header := range req.Headers {
+		if header[0] == "Content-Type" && req.Body != "" {
+			continue
+		}
+		if headers == nil {
+			headers = make(map[string]interface{})
+		}
+		headers[header[0]] = header[1]
+	}
+	if headers != nil {
+		fmt.Fprintf(&b, "    headers=%s,\n", pythonLiteral(headers, 1))
+	}
+	if req.Body != "" {
+		fmt.Fprintf(&b, "    json=%s,\n", pythonLiteral(req.rawBody, 1))
+	}
+	b.WriteString(")\nprint(response.status_code, response.text)")
+	return b.String()
+}
+
+// pythonLiteral renders a JSON-compatible value as a Python literal indented for
+// the given nesting level
+func pythonLiteral(value interface{}, level int) string {
+	indent := strings.Repeat("    ", level)
+	switch v := value.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	case string:
+		// JSON string escapes are valid Python string escapes
+		return sampleJSON(v)
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return "{}"
+		}
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		var b strings.Builder
+		b.WriteString("{\n")
+		for _, key := range keys {
+			fmt.Fprintf(&b, "%s    %s: %s,\n", indent, pythonLiteral(key, level+1), pythonLiteral(v[key], level+1))
+		}
+		b.WriteString(indent + "}")
+		return b.String()
+	case []interface{}:
+		if len(v) == 0 {
+			return "[]"
+		}
+		var b strings.Builder
+		b.WriteString("[\n")
+		for _, item := range v {
+			fmt.Fprintf(&b, "%s    %s,\n", indent, pythonLiteral(item, level+1))
+		}
+		b.WriteString(indent + "]")
+		return b.String()
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// codeSamplesMarkdown renders samples as fenced code blocks for an operation
+// description, for viewers such as Swagger UI that ignore x-codeSamples
+func codeSamplesMarkdown(samples []CodeSample) string {
+	fences := map[string]string{"Shell": "bash", "Go": "go", "Python": "python"}
+
+	var b strings.Builder
+	for i, sample := range samples {
+		if i > 0 {
+			b.WriteString("\n\n")
+		}
+		fmt.Fprintf(&b, "**%s**\n\n```%s\n%s\n```", sample.Label, fences[sample.Lang], sample.Source)
+	}
+	return b.String()
+}
@@ -0,0 +1,160 @@
+package analyzer
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
+)
+
+var updateGolden = flag.Bool("update", false, "Rewrite golden files under testdata")
+
+// SampleRequest exercises JSON and shell escaping in generated samples
+type SampleRequest struct {
+	Title string   `json:"title" example:"It's <done>"`
+	Count int      `json:"count"`
+	Tags  []string `json:"tags"`
+	Draft bool     `json:"draft"`
+}
+
+func codeSampleRoutes() []types.RouteInfo {
+	return []types.RouteInfo{
+		{Method: "POST", Path: "/v1/notes", RequestType: reflect.TypeOf(SampleRequest{}), ResponseType: reflect.TypeOf(TestResponse{}), Module: "notes", Summary: "Create note"},
+		{Method: "GET", Path: "/v1/users/{id}/notes", ResponseType: reflect.TypeOf([]TestResponse{}), Module: "notes", Summary: "List notes", Paginated: true},
+	}
+}
+
+func loadCodeSampleGenerator(t *testing.T) *Generator {
+	t.Helper()
+	gen := NewGenerator()
+	if err := gen.LoadRoutes(codeSampleRoutes()); err != nil {
+		t.Fatalf("LoadRoutes() error = %v", err)
+	}
+	return gen
+}
+
+// checkGolden compares got with testdata/codesamples/name, rewriting it with -update
+func checkGolden(t *testing.T, name, got string) {
+	t.Helper()
+	path := filepath.Join("testdata", "codesamples", name)
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read golden file: %v (run with -update to create it)", err)
+	}
+	if got+"\n" != string(want) {
+		t.Errorf("%s mismatch\n--- got ---\n%s\n--- want ---\n%s", name, got, want)
+	}
+}
+
+func TestCodeSamples_Golden(t *testing.T) {
+	gen := loadCodeSampleGenerator(t)
+	gen.CodeSamples = true
+
+	cases := map[string]types.RouteInfo{"post_body": codeSampleRoutes()[0], "get_query": codeSampleRoutes()[1]}
+	extensions := map[string]string{"curl": "curl", "Go": "go", "Python": "py"}
+
+	for name, route := range cases {
+		t.Run(name, func(t *testing.T) {
+			samples := gen.buildOperation(route).XCodeSamples
+			if len(samples) != 3 {
+				t.Fatalf("Expected curl, Go and Python samples, got %+v", samples)
+			}
+			for _, sample := range samples {
+				checkGolden(t, name+"."+extensions[sample.Label]+".golden", sample.Source)
+			}
+		})
+	}
+}
+
+func TestCodeSamples_Options(t *testing.T) {
+	gen := loadCodeSampleGenerator(t)
+	route := codeSampleRoutes()[0]
+
+	if operation := gen.buildOperation(route); operation.XCodeSamples != nil || operation.Description != "" {
+		t.Errorf("Code samples should be off by default, got %+v", operation.XCodeSamples)
+	}
+
+	gen.CodeSamplesInDescription = true
+	operation := gen.buildOperation(route)
+	if len(operation.XCodeSamples) != 3 {
+		t.Errorf("CodeSamplesInDescription should imply x-codeSamples, got %+v", operation.XCodeSamples)
+	}
+	for _, fence := range []string{"```bash\ncurl", "```go\npackage main", "```python\nimport requests"} {
+		if !strings.Contains(operation.Description, fence) {
+			t.Errorf("Description should contain %q, got %q", fence, operation.Description)
+		}
+	}
+
+	// Multipart uploads are not covered by the samples
+	upload := types.RouteInfo{Method: "POST", Path: "/v1/files", Module: "files", RequestContentType: types.ContentTypeMultipartForm,
+		FormFields: map[string]string{"file": "binary"}}
+	if samples := gen.buildOperation(upload).XCodeSamples; samples != nil {
+		t.Errorf("Expected no samples for multipart routes, got %+v", samples)
+	}
+}
+
+func TestCodeSamples_UseConfiguredServer(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+	config.SetForTest("openapi.servers", []map[string]interface{}{{"url": "https://api.example.com/", "description": "Production"}})
+
+	gen := loadCodeSampleGenerator(t)
+	gen.CodeSamples = true
+	for _, sample := range gen.buildOperation(codeSampleRoutes()[0]).XCodeSamples {
+		if !strings.Contains(sample.Source, "https://api.example.com/v1/notes") {
+			t.Errorf("%s sample should use the configured server, got\n%s", sample.Label, sample.Source)
+		}
+	}
+}
+
+func TestCodeSamples_Credentials(t *testing.T) {
+	gen := loadCodeSampleGenerator(t)
+	gen.CodeSamples = true
+	gen.AddSecurityScheme("BearerAuth", SecurityScheme{Type: "http", Scheme: "bearer"})
+	gen.AddSecurityScheme("QueryKey", SecurityScheme{Type: "apiKey", In: "query", Name: "api_key"})
+
+	tests := []struct {
+		scheme   string
+		expected map[string]string // Sample label to the credential it must send
+	}{
+		{"BearerAuth", map[string]string{
+			"curl":   `-H 'Authorization: Bearer <token>'`,
+			"Go":     `req.Header.Set("Authorization", "Bearer <token>")`,
+			"Python": `"Authorization": "Bearer <token>"`,
+		}},
+		{"QueryKey", map[string]string{
+			"curl":   `api_key=%3Capi-key%3E`,
+			"Go":     `api_key=%3Capi-key%3E`,
+			"Python": `"api_key": "<api-key>"`,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.scheme, func(t *testing.T) {
+			route := codeSampleRoutes()[1]
+			route.Security = []string{tt.scheme}
+			for _, sample := range gen.buildOperation(route).XCodeSamples {
+				if !strings.Contains(sample.Source, tt.expected[sample.Label]) {
+					t.Errorf("%s sample should contain %s, got\n%s", sample.Label, tt.expected[sample.Label], sample.Source)
+				}
+				if strings.Contains(sample.Source, "{id}") {
+					t.Errorf("%s sample should substitute the {id} path parameter, got\n%s", sample.Label, sample.Source)
+				}
+			}
+		})
+	}
+}
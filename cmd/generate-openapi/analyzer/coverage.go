@@ -0,0 +1,97 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// Coverage warning categories reported when WarnUndocumented is set
+const (
+	WarningUndocumentedRoute = "undocumented-route"
+	WarningPhantomRoute      = "phantom-route"
+)
+
+// MuxRoute is a route actually mounted on the HTTP mux
+type MuxRoute struct {
+	Method string // Empty when the mux serves the path for every method
+	Path   string
+}
+
+// CoverageResult compares the routes documented in the spec with those served by the mux
+type CoverageResult struct {
+	Matched      []types.RouteInfo // Documented routes the mux serves
+	Phantom      []types.RouteInfo // Documented routes the mux does not serve
+	Undocumented []MuxRoute        // Served routes missing from the spec
+}
+
+// CoverageReport matches spec routes against mux routes by method and path
+func CoverageReport(specRoutes []types.RouteInfo, muxRoutes []MuxRoute) *CoverageResult {
+	result := &CoverageResult{}
+
+	for _, route := range specRoutes {
+		served := false
+		for _, mounted := range muxRoutes {
+			if mounted.matches(route.Method, route.Path) {
+				served = true
+				break
+			}
+		}
+		if served {
+			result.Matched = append(result.Matched, route)
+		} else {
+			result.Phantom = append(result.Phantom, route)
+		}
+	}
+
+	for _, mounted := range muxRoutes {
+		documented := false
+		for _, route := range specRoutes {
+			if mounted.matches(route.Method, route.Path) {
+				documented = true
+				break
+			}
+		}
+		if !documented {
+			result.Undocumented = append(result.Undocumented, mounted)
+		}
+	}
+
+	return result
+}
+
+// matches reports whether the mux route serves method and path
+func (m MuxRoute) matches(method, path string) bool {
+	if m.Path != path {
+		return false
+	}
+	return m.Method == "" || strings.EqualFold(m.Method, method)
+}
+
+// coverageWarnings converts a coverage report for routes into lint warnings
+func (g *Generator) coverageWarnings(routes []types.RouteInfo) []Warning {
+	report := CoverageReport(routes, g.MuxRoutes)
+
+	var warnings []Warning
+	for _, mounted := range report.Undocumented {
+		method := strings.ToUpper(mounted.Method)
+		if method == "" {
+			method = "ANY"
+		}
+		warnings = append(warnings, Warning{
+			Category: WarningUndocumentedRoute,
+			Route:    fmt.Sprintf("%s %s", method, mounted.Path),
+			Message:  "route is served by the mux but missing from the spec",
+		})
+	}
+	for _, route := range report.Phantom {
+		warnings = append(warnings, Warning{
+			Category: WarningPhantomRoute,
+			Module:   route.Module,
+			Route:    fmt.Sprintf("%s %s", strings.ToUpper(route.Method), route.Path),
+			Message:  "route is documented in the spec but not served by the mux",
+		})
+	}
+	return warnings
+}
@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+func TestCoverageReport(t *testing.T) {
+	specRoutes := []types.RouteInfo{
+		{Method: "GET", Path: "/health", Module: "health"},
+		{Method: "GET", Path: "/legacy", Module: "legacy"},
+	}
+	muxRoutes := []MuxRoute{
+		{Method: "GET", Path: "/health"},
+		{Method: "GET", Path: "/models"},
+		{Path: "/health"}, // Method-agnostic mounts match any documented method
+	}
+
+	report := CoverageReport(specRoutes, muxRoutes)
+
+	if len(report.Undocumented) != 1 || report.Undocumented[0].Path != "/models" {
+		t.Errorf("Expected one undocumented route GET /models, got %v", report.Undocumented)
+	}
+	if len(report.Phantom) != 1 || report.Phantom[0].Path != "/legacy" {
+		t.Errorf("Expected one phantom route GET /legacy, got %v", report.Phantom)
+	}
+}
+
+func TestCoverageWarnings(t *testing.T) {
+	gen := NewGenerator()
+	gen.MuxRoutes = []MuxRoute{{Method: "GET", Path: "/models"}}
+
+	warnings := gen.coverageWarnings([]types.RouteInfo{{Method: "GET", Path: "/legacy", Module: "legacy"}})
+
+	if len(warnings) != 2 {
+		t.Fatalf("Expected two coverage warnings, got %v", warnings)
+	}
+	if warnings[0].Category != WarningUndocumentedRoute || warnings[0].Route != "GET /models" {
+		t.Errorf("Expected undocumented-route warning for GET /models, got %v", warnings[0])
+	}
+	if warnings[1].Category != WarningPhantomRoute || warnings[1].Module != "legacy" {
+		t.Errorf("Expected phantom-route warning in module legacy, got %v", warnings[1])
+	}
+}
@@ -0,0 +1,122 @@
+package analyzer
+
+import "slices"
+
+// OpenAPI versions written to a spec's openapi field
+const (
+	OpenAPIVersion30 = "3.0.3"
+	OpenAPIVersion31 = "3.1.0"
+)
+
+// jsonSchemaDefsRef prefixes $refs to schemas under a root $defs
+const jsonSchemaDefsRef = "#/$defs/"
+
+// openAPIVersion returns the configured OpenAPI version, defaulting to OpenAPIVersion30
+func (g *Generator) openAPIVersion() string {
+	if g.OpenAPIVersion == "" {
+		return OpenAPIVersion30
+	}
+	return g.OpenAPIVersion
+}
+
+// useJSONSchemaDefs reports whether schemas belong under $defs. OpenAPI 3.0 has no
+// $defs, so 3.0 specs keep components.schemas even with UseJSONSchemaDefs set.
+func (g *Generator) useJSONSchemaDefs() bool {
+	return g.UseJSONSchemaDefs && g.openAPIVersion() == OpenAPIVersion31
+}
+
+// MoveSchemasToDefs moves the component schemas to the root $defs, as JSON Schema
+// 2020-12 and OpenAPI 3.1 allow, and rewrites every reference to #/$defs/Name
+func (s *OpenAPISpec) MoveSchemasToDefs() {
+	rewrite := func(ref string) string {
+		if name, ok := schemaRefName(ref); ok {
+			return jsonSchemaDefsRef + name
+		}
+		return ref
+	}
+
+	defs := make(map[string]interface{}, len(s.Components.Schemas))
+	for name, schema := range s.Components.Schemas {
+		defs[name] = renameRefs(schema, rewrite)
+	}
+	s.Defs = defs
+	s.Components.Schemas = nil
+
+	for path, pathItem := range s.Paths {
+		s.Paths[path] = mapPathItemSchemas(pathItem, rewriteRefs(rewrite))
+	}
+}
+
+// ConvertSchemasTo31 rewrites the OpenAPI 3.0 schema keywords that OpenAPI 3.1
+// replaced with JSON Schema 2020-12 forms: nullable becomes a "null" type and
+// example becomes examples
+func (s *OpenAPISpec) ConvertSchemasTo31() {
+	for name, schema := range s.Components.Schemas {
+		if schema, ok := schema.(map[string]interface{}); ok {
+			s.Components.Schemas[name] = schemaTo31(schema)
+		}
+	}
+	for path, pathItem := range s.Paths {
+		s.Paths[path] = mapPathItemSchemas(pathItem, schemaTo31)
+	}
+}
+
+// Schema keywords whose values are a schema, a list of schemas or a map of schemas
+var (
+	subschemaKeywords     = []string{"items", "additionalProperties", "not", "contains", "if", "then", "else"}
+	subschemaListKeywords = []string{"allOf", "anyOf", "oneOf", "prefixItems"}
+	subschemaMapKeywords  = []string{"properties", "patternProperties", "$defs", "dependentSchemas"}
+)
+
+// schemaTo31 returns a copy of a schema with its nested schemas converted for
+// OpenAPI 3.1. Schemas share nested maps with the schema cache, so they are never
+// edited in place.
+func schemaTo31(schema map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{}, len(schema))
+	for key, value := range schema {
+		switch {
+		case key == "nullable":
+			// Handled with type below
+		case key == "example":
+			converted["examples"] = []interface{}{value}
+		case slices.Contains(subschemaKeywords, key):
+			converted[key] = subschemaTo31(value)
+		case slices.Contains(subschemaListKeywords, key):
+			if list, ok := value.([]interface{}); ok {
+				items := make([]interface{}, len(list))
+				for i, item := range list {
+					items[i] = subschemaTo31(item)
+				}
+				value = items
+			}
+			converted[key] = value
+		case slices.Contains(subschemaMapKeywords, key):
+			if named, ok := value.(map[string]interface{}); ok {
+				schemas := make(map[string]interface{}, len(named))
+				for name, item := range named {
+					schemas[name] = subschemaTo31(item)
+				}
+				value = schemas
+			}
+			converted[key] = value
+		default:
+			converted[key] = value
+		}
+	}
+
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		if typ, ok := schema["type"].(string); ok {
+			converted["type"] = []interface{}{typ, "null"}
+		}
+	}
+	return converted
+}
+
+// subschemaTo31 converts a keyword value that holds a schema; booleans such as
+// additionalProperties: false are kept
+func subschemaTo31(value interface{}) interface{} {
+	if schema, ok := value.(map[string]interface{}); ok {
+		return schemaTo31(schema)
+	}
+	return value
+}
@@ -0,0 +1,165 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"gopkg.in/yaml.v3"
+)
+
+// generateDefsSpec generates a spec whose component and union references cover
+// operations and nested schemas, returning it as a generic document
+func generateDefsSpec(t *testing.T, opts ...GeneratorOption) map[string]interface{} {
+	t.Helper()
+	ClearUnions()
+	t.Cleanup(ClearUnions)
+	RegisterUnion(reflect.TypeOf((*Shape)(nil)).Elem(), reflect.TypeOf(TextShape{}), reflect.TypeOf(ImageShape{}))
+
+	routes := append(hookRoutes(), types.RouteInfo{Method: "POST", Path: "/messages", RequestType: reflect.TypeOf(ChatMessage{}), ResponseType: reflect.TypeOf(ChatMessage{}), Module: "messages", Summary: "Send message"})
+	out, err := NewGenerator(opts...).GenerateSpecForRoutes(routes)
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("Generated spec is not valid YAML: %v", err)
+	}
+	return doc
+}
+
+// collectRefs returns every $ref string in a YAML document
+func collectRefs(value interface{}) []string {
+	var refs []string
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, child := range v {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				refs = append(refs, ref)
+				continue
+			}
+			refs = append(refs, collectRefs(child)...)
+		}
+	case []interface{}:
+		for _, child := range v {
+			refs = append(refs, collectRefs(child)...)
+		}
+	}
+	return refs
+}
+
+// assertRefsResolve checks that every $ref uses prefix and names a schema in schemas
+func assertRefsResolve(t *testing.T, doc map[string]interface{}, prefix string, schemas map[string]interface{}) {
+	t.Helper()
+	refs := collectRefs(doc)
+	if len(refs) == 0 {
+		t.Fatal("Expected the spec to contain references")
+	}
+	var nested bool
+	for _, ref := range refs {
+		if !strings.HasPrefix(ref, prefix) {
+			t.Errorf("Reference %q does not start with %q", ref, prefix)
+			continue
+		}
+		if _, ok := schemas[strings.TrimPrefix(ref, prefix)]; !ok {
+			t.Errorf("Reference %q does not resolve", ref)
+		}
+		nested = nested || strings.HasSuffix(ref, "TextShape")
+	}
+	if !nested {
+		t.Errorf("Expected union variant references inside schemas, got %v", refs)
+	}
+}
+
+func TestGenerateSpec_JSONSchemaDefs31(t *testing.T) {
+	doc := generateDefsSpec(t, WithOpenAPIVersion(OpenAPIVersion31), WithJSONSchemaDefs(true))
+
+	if doc["openapi"] != OpenAPIVersion31 {
+		t.Errorf("openapi = %v, expected %s", doc["openapi"], OpenAPIVersion31)
+	}
+	defs, ok := doc["$defs"].(map[string]interface{})
+	if !ok || defs["User"] == nil || defs["ChatMessage"] == nil {
+		t.Fatalf("Expected schemas under $defs, got %v", doc["$defs"])
+	}
+	if components, _ := doc["components"].(map[string]interface{}); components["schemas"] != nil {
+		t.Errorf("Expected no components.schemas alongside $defs, got %v", components["schemas"])
+	}
+	assertRefsResolve(t, doc, jsonSchemaDefsRef, defs)
+
+	// Discriminator mappings point into $defs too
+	shape := defs["ChatMessage"].(map[string]interface{})["properties"].(map[string]interface{})["shape"].(map[string]interface{})
+	mapping, _ := shape["discriminator"].(map[string]interface{})["mapping"].(map[string]interface{})
+	if len(mapping) == 0 {
+		t.Fatalf("Expected a discriminator mapping, got %v", shape)
+	}
+	for name, ref := range mapping {
+		if ref != jsonSchemaDefsRef+name {
+			t.Errorf("mapping[%s] = %v, expected %s%s", name, ref, jsonSchemaDefsRef, name)
+		}
+	}
+}
+
+// nullableExample has a nullable timestamp and an example value
+type nullableExample struct {
+	Name      string     `json:"name" example:"Ada"`
+	DeletedAt *time.Time `json:"deleted_at"`
+}
+
+func TestGenerateSpec_ConvertsKeywordsFor31(t *testing.T) {
+	routes := []types.RouteInfo{{Method: "GET", Path: "/users", ResponseType: reflect.TypeOf(nullableExample{}), Module: "users", Summary: "Get user"}}
+
+	for _, version := range []string{OpenAPIVersion30, OpenAPIVersion31} {
+		out, err := NewGenerator(WithOpenAPIVersion(version)).GenerateSpecForRoutes(routes)
+		if err != nil {
+			t.Fatalf("GenerateSpecForRoutes(%s) error = %v", version, err)
+		}
+		var doc map[string]interface{}
+		if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+			t.Fatalf("Generated spec is not valid YAML: %v", err)
+		}
+		schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+		properties := schemas["nullableExample"].(map[string]interface{})["properties"].(map[string]interface{})
+		name := properties["name"].(map[string]interface{})
+		deletedAt := properties["deleted_at"].(map[string]interface{})
+
+		if version == OpenAPIVersion30 {
+			if name["example"] != "Ada" || deletedAt["nullable"] != true {
+				t.Errorf("OpenAPI 3.0 should keep example and nullable, got %v and %v", name, deletedAt)
+			}
+			continue
+		}
+		if _, ok := name["example"]; ok || !reflect.DeepEqual(name["examples"], []interface{}{"Ada"}) {
+			t.Errorf("OpenAPI 3.1 should use examples, got %v", name)
+		}
+		if _, ok := deletedAt["nullable"]; ok || !reflect.DeepEqual(deletedAt["type"], []interface{}{"string", "null"}) {
+			t.Errorf("OpenAPI 3.1 should use a null type, got %v", deletedAt)
+		}
+	}
+}
+
+func TestGenerateSpec_JSONSchemaDefsIgnoredFor30(t *testing.T) {
+	doc := generateDefsSpec(t, WithJSONSchemaDefs(true))
+
+	if doc["openapi"] != OpenAPIVersion30 {
+		t.Errorf("openapi = %v, expected %s", doc["openapi"], OpenAPIVersion30)
+	}
+	if _, ok := doc["$defs"]; ok {
+		t.Error("OpenAPI 3.0 has no $defs; expected components.schemas only")
+	}
+	components, _ := doc["components"].(map[string]interface{})
+	schemas, ok := components["schemas"].(map[string]interface{})
+	if !ok || schemas["User"] == nil {
+		t.Fatalf("Expected schemas under components.schemas, got %v", components)
+	}
+	assertRefsResolve(t, doc, componentSchemasRef, schemas)
+}
+
+func TestValidateSpec_JSONSchemaDefs(t *testing.T) {
+	gen := NewGenerator(WithOpenAPIVersion(OpenAPIVersion31), WithJSONSchemaDefs(true), WithValidation(true))
+	if _, err := gen.GenerateSpecForRoutes(hookRoutes()); err != nil {
+		t.Errorf("Expected a $defs spec to pass validation, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package analyzer
+
+// dereferencer inlines component schema references, tracking the components that
+// must stay because inlining them would recurse forever
+type dereferencer struct {
+	schemas map[string]interface{}
+	cyclic  map[string]bool
+}
+
+// Dereference inlines every component schema $ref in the spec's paths for tools
+// that cannot follow references. A $ref back to a schema that is already being
+// inlined is left in place, and only the schemas such cycles reference are kept
+// in components.schemas. Discriminator mappings name the inlined components, so
+// they are dropped; the propertyName is kept.
+func (s *OpenAPISpec) Dereference() {
+	d := &dereferencer{schemas: s.Components.Schemas, cyclic: make(map[string]bool)}
+	for path, pathItem := range s.Paths {
+		s.Paths[path] = mapPathItemSchemas(pathItem, d.inlineSchema)
+	}
+
+	// Kept schemas are dereferenced too, which can reveal further cycles
+	kept := make(map[string]interface{})
+	for len(kept) < len(d.cyclic) {
+		for name := range d.cyclic {
+			if _, done := kept[name]; !done {
+				kept[name] = d.inline(d.schemas[name], map[string]bool{name: true})
+			}
+		}
+	}
+
+	s.Components.Schemas = nil
+	if len(kept) > 0 {
+		s.Components.Schemas = kept
+	}
+}
+
+// inlineSchema returns a copy of schema with its component references inlined
+func (d *dereferencer) inlineSchema(schema map[string]interface{}) map[string]interface{} {
+	inlined, _ := d.inline(schema, make(map[string]bool)).(map[string]interface{})
+	return inlined
+}
+
+// inline returns a copy of a schema value with component references replaced by
+// the schemas they name. visiting holds the components being inlined on the
+// current path; references to them are kept to break the cycle.
+func (d *dereferencer) inline(value interface{}, visiting map[string]bool) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		ref, _ := v["$ref"].(string)
+		if name, ok := schemaRefName(ref); ok {
+			target, exists := d.schemas[name].(map[string]interface{})
+			if !exists {
+				return v
+			}
+			if visiting[name] {
+				d.cyclic[name] = true
+				return v
+			}
+
+			visiting[name] = true
+			resolved, _ := d.inline(target, visiting).(map[string]interface{})
+			delete(visiting, name)
+
+			// Keywords beside the $ref, such as a description, annotate the inlined schema
+			for key, child := range v {
+				if key != "$ref" {
+					resolved[key] = d.inline(child, visiting)
+				}
+			}
+			return resolved
+		}
+
+		inlined := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			if discriminator, ok := child.(map[string]interface{}); ok && key == "discriminator" {
+				inlined[key] = withoutMapping(discriminator)
+				continue
+			}
+			inlined[key] = d.inline(child, visiting)
+		}
+		return inlined
+	case []interface{}:
+		inlined := make([]interface{}, len(v))
+		for i, child := range v {
+			inlined[i] = d.inline(child, visiting)
+		}
+		return inlined
+	default:
+		return value
+	}
+}
+
+// withoutMapping returns a copy of a discriminator without its mapping
+func withoutMapping(discriminator map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(discriminator))
+	for key, value := range discriminator {
+		if key != "mapping" {
+			copied[key] = value
+		}
+	}
+	return copied
+}
@@ -0,0 +1,137 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"gopkg.in/yaml.v3"
+)
+
+// Conversation nests structs and a union whose variants are components
+type Conversation struct {
+	Title    string        `json:"title"`
+	Owner    User          `json:"owner"`
+	Messages []ChatMessage `json:"messages"`
+}
+
+// generateDereferenceDoc generates the conversation routes as a generic document
+func generateDereferenceDoc(t *testing.T, dereference bool) map[string]interface{} {
+	t.Helper()
+	ClearUnions()
+	t.Cleanup(ClearUnions)
+	RegisterUnion(reflect.TypeOf((*Shape)(nil)).Elem(), reflect.TypeOf(TextShape{}), reflect.TypeOf(ImageShape{}))
+
+	routes := []types.RouteInfo{
+		{Method: "POST", Path: "/conversations", RequestType: reflect.TypeOf(Conversation{}), ResponseType: reflect.TypeOf(Conversation{}), Module: "conversations", Summary: "Create conversation"},
+		{Method: "GET", Path: "/conversations/{id}", ResponseType: reflect.TypeOf(Conversation{}), Module: "conversations", Summary: "Get conversation"},
+	}
+	out, err := NewGenerator(WithDereference(dereference)).GenerateSpecForRoutes(routes)
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+	if dereference && strings.Contains(out, "$ref") {
+		t.Errorf("Expected no $ref in an acyclic dereferenced spec, got:\n%s", out)
+	}
+
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+		t.Fatalf("Generated spec is not valid YAML: %v", err)
+	}
+	return doc
+}
+
+// resolveRefs inlines the component references of an acyclic document, dropping
+// the discriminator mappings that name them
+func resolveRefs(value interface{}, schemas map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			return resolveRefs(schemas[strings.TrimPrefix(ref, componentSchemasRef)], schemas)
+		}
+		resolved := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			resolved[key] = resolveRefs(child, schemas)
+		}
+		if discriminator, ok := resolved["discriminator"].(map[string]interface{}); ok {
+			delete(discriminator, "mapping")
+		}
+		return resolved
+	case []interface{}:
+		resolved := make([]interface{}, len(v))
+		for i, child := range v {
+			resolved[i] = resolveRefs(child, schemas)
+		}
+		return resolved
+	default:
+		return value
+	}
+}
+
+func TestGenerateSpec_DereferenceMatchesReferencedShapes(t *testing.T) {
+	referenced := generateDereferenceDoc(t, false)
+	dereferenced := generateDereferenceDoc(t, true)
+
+	components, _ := referenced["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	if schemas["TextShape"] == nil {
+		t.Fatalf("Expected the referenced spec to have union variant components, got %v", components)
+	}
+	if components, _ := dereferenced["components"].(map[string]interface{}); components["schemas"] != nil {
+		t.Errorf("Expected components.schemas to be dropped, got %v", components["schemas"])
+	}
+
+	if !strings.Contains(fmt.Sprint(referenced), "mapping:") {
+		t.Fatal("Expected the referenced spec to map discriminator values to components")
+	}
+	if strings.Contains(fmt.Sprint(dereferenced), "#/components/schemas/") {
+		t.Error("Expected no discriminator mapping to point at dropped components")
+	}
+
+	expected := resolveRefs(referenced["paths"], schemas)
+	if !reflect.DeepEqual(dereferenced["paths"], expected) {
+		t.Errorf("Dereferenced paths differ from the resolved referenced paths\ngot:  %v\nwant: %v", dereferenced["paths"], expected)
+	}
+}
+
+func TestDereference_KeepsCycles(t *testing.T) {
+	nodeRef := map[string]interface{}{"$ref": componentSchemasRef + "Node"}
+	spec := OpenAPISpec{
+		Paths: map[string]PathItem{
+			"/tree": {Get: &Operation{Responses: map[string]Response{
+				"200": {Description: "Success", Content: map[string]MediaTypeObject{
+					"application/json": {Schema: SchemaRef{Ref: componentSchemasRef + "Tree"}},
+				}},
+			}}},
+		},
+		Components: Components{Schemas: map[string]interface{}{
+			"Tree": map[string]interface{}{"type": "object", "properties": map[string]interface{}{"root": nodeRef}},
+			"Node": map[string]interface{}{"type": "object", "properties": map[string]interface{}{
+				"children": map[string]interface{}{"type": "array", "items": nodeRef},
+			}},
+		}},
+	}
+
+	spec.Dereference()
+
+	root := spec.Paths["/tree"].Get.Responses["200"].Content["application/json"].Schema
+	if root.Ref != "" {
+		t.Fatalf("Expected the response schema to be inlined, got %s", root.Ref)
+	}
+	node := root.Inline["properties"].(map[string]interface{})["root"].(map[string]interface{})
+	children := node["properties"].(map[string]interface{})["children"].(map[string]interface{})
+	if !reflect.DeepEqual(children["items"], nodeRef) {
+		t.Errorf("Expected the recursive items to keep their $ref, got %v", children["items"])
+	}
+
+	if _, ok := spec.Components.Schemas["Node"]; !ok || len(spec.Components.Schemas) != 1 {
+		t.Errorf("Expected only the cyclic Node schema to be kept, got %v", spec.Components.Schemas)
+	}
+	kept := spec.Components.Schemas["Node"].(map[string]interface{})
+	keptChildren := kept["properties"].(map[string]interface{})["children"].(map[string]interface{})
+	if !reflect.DeepEqual(keptChildren["items"], nodeRef) {
+		t.Errorf("Expected the kept Node schema to reference itself, got %v", keptChildren["items"])
+	}
+}
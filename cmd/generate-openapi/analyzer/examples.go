@@ -0,0 +1,90 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// attachExamples sets the example of every component schema that has a
+// <name>.json fixture in ExamplesDir. Schemas without a fixture are left alone.
+func (g *Generator) attachExamples() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	for name, schema := range g.typeSchemas {
+		component, ok := schema.(map[string]interface{})
+		if !ok {
+			continue
+		}
+
+		path := filepath.Join(g.ExamplesDir, name+".json")
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		var example interface{}
+		if err := json.Unmarshal(data, &example); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+		component["example"] = example
+	}
+	return nil
+}
+
+// WarningExample reports a route example whose value cannot be encoded as JSON
+const WarningExample = "example"
+
+// Example is a named example of a media type's content
+type Example struct {
+	Summary     string      `yaml:"summary,omitempty"`
+	Description string      `yaml:"description,omitempty"`
+	Value       interface{} `yaml:"value"`
+}
+
+// buildExamples converts a route's named examples for a media type object. Values
+// are round-tripped through encoding/json so structs use their JSON field names.
+func (g *Generator) buildExamples(route types.RouteInfo, examples map[string]types.ExampleValue) map[string]Example {
+	if len(examples) == 0 {
+		return nil
+	}
+
+	built := make(map[string]Example, len(examples))
+	for name, example := range examples {
+		value, err := jsonValue(example.Value)
+		if err != nil {
+			g.warnings = append(g.warnings, Warning{
+				Category: WarningExample,
+				Module:   route.Module,
+				Route:    strings.ToUpper(route.Method) + " " + route.Path,
+				Message:  fmt.Sprintf("example %q cannot be encoded as JSON: %v", name, err),
+			})
+			continue
+		}
+		built[name] = Example{Summary: example.Summary, Description: example.Description, Value: value}
+	}
+	return built
+}
+
+// jsonValue returns v as the maps, slices and scalars encoding/json decodes it to
+func jsonValue(v interface{}) (interface{}, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,120 @@
+package analyzer
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"gopkg.in/yaml.v3"
+)
+
+func TestLoadRoutes_ExamplesDir(t *testing.T) {
+	routes := []types.RouteInfo{
+		{Method: "POST", Path: "/users", RequestType: reflect.TypeOf(TestRequest{}), ResponseType: reflect.TypeOf(User{}), Module: "users"},
+	}
+
+	gen := NewGenerator()
+	gen.ExamplesDir = filepath.Join("testdata", "examples")
+	if err := gen.LoadRoutes(routes); err != nil {
+		t.Fatalf("LoadRoutes() error = %v", err)
+	}
+
+	user, _ := gen.Schema("User")
+	if example, ok := user["example"].(map[string]interface{}); !ok || example["name"] != "Ada Lovelace" {
+		t.Errorf("Expected the User fixture as the schema example, got %v", user["example"])
+	}
+	if request, _ := gen.Schema("TestRequest"); request["example"] != nil {
+		t.Errorf("Schemas without a fixture should have no example, got %v", request["example"])
+	}
+}
+
+func TestLoadRoutes_ExamplesDirInvalidFixture(t *testing.T) {
+	dir := t.TempDir()
+	writeFile(t, filepath.Join(dir, "User.json"), `{"name": `)
+
+	gen := NewGenerator()
+	gen.ExamplesDir = dir
+	err := gen.LoadRoutes([]types.RouteInfo{{Method: "GET", Path: "/users", ResponseType: reflect.TypeOf(User{}), Module: "users"}})
+	if err == nil || !strings.Contains(err.Error(), "User.json") {
+		t.Errorf("Expected an error naming the invalid fixture, got %v", err)
+	}
+}
+
+func TestBuildRequestBody_NamedExamples(t *testing.T) {
+	routes := []types.RouteInfo{
+		{
+			Method:       "POST",
+			Path:         "/users",
+			RequestType:  reflect.TypeOf(TestRequest{}),
+			ResponseType: reflect.TypeOf(User{}),
+			Module:       "users",
+			Examples: map[string]types.ExampleValue{
+				"valid-user":   {Summary: "A typical user", Value: TestRequest{Name: "Ada", Count: 2, Enabled: true}},
+				"minimal-user": {Summary: "Only required fields", Description: "Optional fields are omitted", Value: map[string]interface{}{"name": "Bob"}},
+			},
+			ResponseExamples: map[string]types.ExampleValue{
+				"created": {Value: User{Name: "Ada"}},
+			},
+		},
+	}
+
+	spec, err := NewGenerator().GenerateSpecForRoutes(routes)
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	var parsed struct {
+		Paths map[string]map[string]struct {
+			RequestBody struct {
+				Content map[string]struct {
+					Examples map[string]map[string]interface{} `yaml:"examples"`
+				} `yaml:"content"`
+			} `yaml:"requestBody"`
+			Responses map[string]struct {
+				Content map[string]struct {
+					Examples map[string]map[string]interface{} `yaml:"examples"`
+				} `yaml:"content"`
+			} `yaml:"responses"`
+		} `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal([]byte(spec), &parsed); err != nil {
+		t.Fatalf("Generated spec is not valid YAML: %v", err)
+	}
+
+	op := parsed.Paths["/users"]["post"]
+	examples := op.RequestBody.Content["application/json"].Examples
+	if len(examples) != 2 {
+		t.Fatalf("Expected 2 request body examples, got %v", examples)
+	}
+
+	expectedValid := map[string]interface{}{
+		"summary": "A typical user",
+		"value":   map[string]interface{}{"name": "Ada", "count": 2, "enabled": true},
+	}
+	if !reflect.DeepEqual(examples["valid-user"], expectedValid) {
+		t.Errorf("valid-user = %v, expected %v", examples["valid-user"], expectedValid)
+	}
+	if examples["minimal-user"]["description"] != "Optional fields are omitted" {
+		t.Errorf("minimal-user = %v, expected its description", examples["minimal-user"])
+	}
+
+	created := op.Responses["200"].Content["application/json"].Examples["created"]
+	if !reflect.DeepEqual(created["value"], map[string]interface{}{"name": "Ada"}) {
+		t.Errorf("created response example = %v, expected the User value", created)
+	}
+}
+
+func TestBuildExamples_UnencodableValue(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{Method: "POST", Path: "/users", Module: "users"}
+
+	examples := gen.buildExamples(route, map[string]types.ExampleValue{"broken": {Value: make(chan int)}})
+	if len(examples) != 0 {
+		t.Errorf("Expected the unencodable example to be dropped, got %v", examples)
+	}
+	if len(gen.warnings) != 1 || gen.warnings[0].Category != WarningExample {
+		t.Errorf("Expected one %s warning, got %v", WarningExample, gen.warnings)
+	}
+}
@@ -0,0 +1,40 @@
+package analyzer
+
+import (
+	"path"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// isExcluded reports whether route matches an Exclude entry. Entries starting with
+// "/" are path.Match globs against the route path; any other entry names a module.
+func (g *Generator) isExcluded(route types.RouteInfo) bool {
+	for _, pattern := range g.Exclude {
+		if !strings.HasPrefix(pattern, "/") {
+			if route.Module == pattern {
+				return true
+			}
+			continue
+		}
+		if matched, _ := path.Match(pattern, route.Path); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// filterExcluded returns routes without those matching g.Exclude
+func (g *Generator) filterExcluded(routes []types.RouteInfo) []types.RouteInfo {
+	if len(g.Exclude) == 0 {
+		return routes
+	}
+
+	kept := make([]types.RouteInfo, 0, len(routes))
+	for _, route := range routes {
+		if !g.isExcluded(route) {
+			kept = append(kept, route)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,85 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"gopkg.in/yaml.v3"
+)
+
+type debugDump struct {
+	Goroutines int `json:"goroutines"`
+}
+
+func TestGenerateSpecForRoutes_ExcludeModule(t *testing.T) {
+	routes := []types.RouteInfo{
+		{Method: "POST", Path: "/chat", RequestType: reflect.TypeOf(TestRequest{}), ResponseType: reflect.TypeOf(TestResponse{}), Module: "chat"},
+		{Method: "GET", Path: "/debug/dump", ResponseType: reflect.TypeOf(debugDump{}), Module: "debug"},
+	}
+
+	for _, keepUnused := range []bool{false, true} {
+		gen := NewGenerator()
+		gen.Exclude = []string{"debug"}
+		gen.KeepUnusedSchemas = keepUnused
+
+		spec, err := gen.GenerateSpecForRoutes(routes)
+		if err != nil {
+			t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+		}
+
+		var parsed struct {
+			Tags       []map[string]interface{}          `yaml:"tags"`
+			Paths      map[string]interface{}            `yaml:"paths"`
+			Components map[string]map[string]interface{} `yaml:"components"`
+		}
+		if err := yaml.Unmarshal([]byte(spec), &parsed); err != nil {
+			t.Fatalf("Generated spec is not valid YAML: %v", err)
+		}
+
+		if _, exists := parsed.Paths["/debug/dump"]; exists {
+			t.Error("Excluded module's path should be absent")
+		}
+		if _, exists := parsed.Paths["/chat"]; !exists {
+			t.Error("Other modules' paths should remain")
+		}
+		if _, exists := parsed.Components["schemas"]["debugDump"]; exists {
+			t.Errorf("Schema used only by the excluded module should be absent (keep-unused=%v)", keepUnused)
+		}
+		if strings.Contains(spec, "name: debug") {
+			t.Error("Excluded module should not appear as a tag")
+		}
+	}
+}
+
+func TestIsExcluded(t *testing.T) {
+	gen := NewGenerator()
+	gen.Exclude = []string{"metrics", "/debug/*"}
+
+	tests := []struct {
+		route types.RouteInfo
+		want  bool
+	}{
+		{types.RouteInfo{Path: "/metrics", Module: "metrics"}, true},
+		{types.RouteInfo{Path: "/debug/pprof", Module: "ops"}, true},
+		{types.RouteInfo{Path: "/debug/pprof/heap", Module: "ops"}, false},
+		{types.RouteInfo{Path: "/chat", Module: "chat"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := gen.isExcluded(tt.route); got != tt.want {
+			t.Errorf("isExcluded(%s in %s) = %v, want %v", tt.route.Path, tt.route.Module, got, tt.want)
+		}
+	}
+}
+
+func TestGenerateSpecForRoutes_AllExcluded(t *testing.T) {
+	gen := NewGenerator()
+	gen.Exclude = []string{"chat"}
+
+	_, err := gen.GenerateSpecForRoutes([]types.RouteInfo{{Method: "GET", Path: "/chat", Module: "chat"}})
+	if err == nil {
+		t.Error("GenerateSpecForRoutes() should fail when every route is excluded")
+	}
+}
@@ -1,59 +1,304 @@
 package analyzer
 
 import (
+	"errors"
 	"fmt"
 	"go/parser"
 	"go/token"
+	"maps"
+	"os"
 	"path/filepath"
 	"reflect"
+	"runtime"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/JerkyTreats/llm/internal/api/types"
 )
 
 // Generator handles the generation of OpenAPI specifications from Go code
 type Generator struct {
-	fileSet      *token.FileSet
-	routes       []types.RouteInfo
-	typeSchemas  map[string]interface{}
+	fileSet       *token.FileSet
+	routes        []types.RouteInfo
+	typeSchemas   map[string]interface{}
+	warnings      []Warning
+	prunedSchemas []string
+	// tagDescriptions holds descriptions set with SetTagDescription, keyed by tag name
+	tagDescriptions map[string]string
+	// infoContact and infoLicense are set with SetInfoContact and SetInfoLicense
+	infoContact *Contact
+	infoLicense *License
+	// omitHeader leaves out the leading comment block; set with SetIncludeHeader
+	omitHeader bool
+	// operationIDStyle cases generated operation IDs; set with SetOperationIDStyle
+	operationIDStyle OperationIDStyle
+
+	// mu guards typeSchemas, schemaCache and schemaTypes while route schemas are
+	// generated concurrently
+	mu          sync.Mutex
+	schemaCache map[reflect.Type]map[string]interface{}
+	// schemaTypes maps component schema names to the Go type they were claimed by
+	schemaTypes map[string]reflect.Type
+	// schemaWorkers bounds concurrent route schema generation; zero uses GOMAXPROCS
+	schemaWorkers int
+
+	// astAnalyzer reads HandlerFuncName doc comments, created on first use
+	astAnalyzer *ASTAnalyzer
+
+	// Hooks registered with OnOperation, OnSchema and OnSpec
+	operationHooks []OperationHook
+	schemaHooks    []SchemaHook
+	specHooks      []SpecHook
+	// skippedSchemas are the schema names an OnSchema hook skipped
+	skippedSchemas map[string]bool
+	// variantSchemas hold union variant schemas generated by schema workers until
+	// they are added through the schema hooks
+	variantSchemas map[string]namedSchema
+
+	// securitySchemes are the schemes registered with AddSecurityScheme
+	securitySchemes map[string]SecurityScheme
+
+	// SortPaths builds paths from routes sorted by path then method instead of registration order
+	SortPaths bool
+	// KeepUnusedSchemas disables pruning of component schemas no path references
+	KeepUnusedSchemas bool
+	// WarnUndocumented makes GenerateSpec compare the registry against MuxRoutes
+	WarnUndocumented bool
+	// MuxRoutes lists the routes actually mounted on the HTTP mux
+	MuxRoutes []MuxRoute
+	// DryRun makes GenerateSpec validate without producing a spec
+	DryRun bool
+	// HoistSharedParams moves path parameters shared by every operation on a path
+	// to the path item instead of repeating them per operation
+	HoistSharedParams bool
+	// NamingStrategy names the component schemas of Go types; nil uses BareName
+	NamingStrategy NamingStrategy
+	// Dereference inlines every component schema $ref into the paths and drops
+	// components.schemas, keeping only schemas referenced by cycles
+	Dereference bool
+	// OpenAPIVersion is the version written to openapi: OpenAPIVersion30 (the
+	// default) or OpenAPIVersion31
+	OpenAPIVersion string
+	// UseJSONSchemaDefs moves component schemas to a root $defs referenced as
+	// #/$defs/Name; it only applies to OpenAPIVersion31 specs
+	UseJSONSchemaDefs bool
+	// Version is the API version written to info.version; GenerateSpec detects it
+	// with DetectVersion when empty
+	Version string
+	// Exclude lists modules and path globs (entries starting with "/") to leave out
+	// of the spec; schemas only they use are never generated
+	Exclude []string
+	// NormalizePaths strips trailing slashes from paths other than "/" so /users/
+	// and /users share a path item; the first route registered for a method wins
+	NormalizePaths bool
+	// DefaultResponse adds a default response with the route's error body to
+	// every operation, for clients that prefer it to enumerated error statuses
+	DefaultResponse bool
+	// ExamplesDir holds example fixtures named <ComponentName>.json, such as
+	// testdata/examples; each is attached to its component schema as example
+	ExamplesDir string
+	// Servers replaces the servers configured under openapi.servers when set
+	Servers []Server
+	// PathPrefix is prepended to every route path, for APIs mounted below the root
+	PathPrefix string
+	// Validate makes spec generation fail when the generated spec has lint errors
+	Validate bool
+	// RequireResponseSchema makes spec generation fail for JSON routes without a
+	// ResponseType, other than 204 routes and those listed in EmptyResponseRoutes
+	RequireResponseSchema bool
+	// EmptyResponseRoutes lists routes whose empty response is intentional, as
+	// "METHOD /path" or a bare path for every method
+	EmptyResponseRoutes []string
+	// CodeSamples adds x-codeSamples (curl, Go and Python) to every operation
+	CodeSamples bool
+	// SkipHandlerDocs leaves summaries and descriptions to RouteInfo instead of
+	// reading them from the doc comments of HandlerFuncName in the Go source
+	SkipHandlerDocs bool
+	// ModuleRoot is the Go module directory handler doc comments are read from;
+	// empty uses the module containing the working directory
+	ModuleRoot string
+	// CodeSamplesInDescription also appends the samples to operation descriptions
+	// as fenced code blocks for Swagger UI, which ignores x-codeSamples; it
+	// implies CodeSamples
+	CodeSamplesInDescription bool
 }
 
-// NewGenerator creates a new OpenAPI generator
-func NewGenerator() *Generator {
-	return &Generator{
+// DefaultVersion is the API version used when Generator.Version is not set and no
+// version is configured or stamped into the build
+const DefaultVersion = "1.0.0"
+
+// NewGenerator creates a new OpenAPI generator configured by opts, applied in order
+func NewGenerator(opts ...GeneratorOption) *Generator {
+	g := &Generator{
 		fileSet:     token.NewFileSet(),
 		typeSchemas: make(map[string]interface{}),
+		schemaCache: make(map[reflect.Type]map[string]interface{}),
+	}
+	for _, opt := range opts {
+		opt(g)
 	}
+	return g
 }
 
-// GenerateSpec generates a complete OpenAPI specification
+// GenerateSpec generates a complete OpenAPI specification. In dry-run mode it only
+// validates and returns an empty spec.
 func (g *Generator) GenerateSpec() (string, error) {
+	if g.Version == "" {
+		g.Version = DetectVersion()
+	}
+
+	if g.DryRun {
+		return "", g.ValidateOnly()
+	}
+
+	return g.generateFromRegistry()
+}
+
+// ValidateOnly runs every generation step against the registry and returns the first
+// error, discarding the generated spec. Warnings remain available afterwards.
+func (g *Generator) ValidateOnly() error {
+	_, err := g.generateFromRegistry()
+	return err
+}
+
+// generateFromRegistry discovers registered routes and generates their specification
+func (g *Generator) generateFromRegistry() (string, error) {
+	routes, err := g.registryRoutes()
+	if err != nil {
+		return "", err
+	}
+
+	spec, err := g.GenerateSpecForRoutes(routes)
+	if err != nil {
+		return "", err
+	}
+
+	// Report routes the spec and the mux disagree on
+	if g.WarnUndocumented {
+		g.warnings = append(g.warnings, g.coverageWarnings(routes)...)
+	}
+
+	return spec, nil
+}
+
+// registryRoutes discovers modules and returns the routes their init() functions registered
+func (g *Generator) registryRoutes() ([]types.RouteInfo, error) {
 	// Force import of modules to trigger init() functions
 	if err := g.discoverRoutes(); err != nil {
-		return "", fmt.Errorf("failed to discover routes: %w", err)
+		return nil, fmt.Errorf("failed to discover routes: %w", err)
 	}
 
 	// Get routes from the registry (populated by init() functions)
-	g.routes = types.GetRegisteredRoutes()
-	
-	if len(g.routes) == 0 {
-		return "", fmt.Errorf("no routes discovered in registry")
+	routes := types.GetRegisteredRoutes()
+	if len(routes) == 0 {
+		return nil, fmt.Errorf("no routes discovered in registry")
 	}
+	return routes, nil
+}
 
-	// Generate type schemas
-	if err := g.generateSchemas(); err != nil {
-		return "", fmt.Errorf("failed to generate schemas: %w", err)
+// GenerateSpecForRoutes generates an OpenAPI specification covering only the given routes
+func (g *Generator) GenerateSpecForRoutes(routes []types.RouteInfo) (string, error) {
+	if len(routes) == 0 {
+		return "", fmt.Errorf("no routes provided")
+	}
+
+	routes = g.filterExcluded(routes)
+	if len(routes) == 0 {
+		return "", fmt.Errorf("all routes are excluded")
+	}
+
+	if err := g.checkSecuritySchemes(routes); err != nil {
+		return "", err
 	}
-	
-	// Add standard schemas
-	g.addStandardSchemas()
+
+	if g.RequireResponseSchema {
+		if err := g.checkResponseSchemas(routes); err != nil {
+			return "", err
+		}
+	}
+
+	// Generate type schemas and add standard schemas
+	if err := g.LoadRoutes(routes); err != nil {
+		return "", err
+	}
+
+	// Collect quality warnings for the loaded routes
+	g.warnings = g.lint()
 
 	// Build the OpenAPI spec
 	spec := g.buildOpenAPISpec()
-	
+	if err := g.checkSchemaRefs(spec); err != nil {
+		return "", err
+	}
+
+	if g.Validate {
+		if err := validateSpec(spec); err != nil {
+			return "", err
+		}
+	}
+
 	return spec, nil
 }
 
+// LoadRoutes sets the generator's routes and generates their component schemas
+// without consulting the global registry
+func (g *Generator) LoadRoutes(routes []types.RouteInfo) error {
+	g.routes = routes
+
+	if err := g.generateSchemas(); err != nil {
+		return fmt.Errorf("failed to generate schemas: %w", err)
+	}
+
+	g.addStandardSchemas()
+
+	if g.ExamplesDir != "" {
+		if err := g.attachExamples(); err != nil {
+			return fmt.Errorf("failed to load examples: %w", err)
+		}
+	}
+	return nil
+}
+
+// SetTagDescription sets the description of a top-level tag. Tags no route uses are
+// left out of the spec, so describing a module that has no routes is harmless.
+func (g *Generator) SetTagDescription(name, description string) {
+	if g.tagDescriptions == nil {
+		g.tagDescriptions = make(map[string]string)
+	}
+	g.tagDescriptions[name] = description
+}
+
+// SetInfoContact sets info.contact; empty fields are left out of the spec
+func (g *Generator) SetInfoContact(name, url, email string) {
+	g.infoContact = &Contact{Name: name, URL: url, Email: email}
+}
+
+// SetInfoLicense sets info.license. OpenAPI requires the license name; url is optional.
+func (g *Generator) SetInfoLicense(name, url string) {
+	g.infoLicense = &License{Name: name, URL: url}
+}
+
+// SetIncludeHeader controls whether the generated spec starts with the "DO NOT
+// EDIT" comment block, which is included by default
+func (g *Generator) SetIncludeHeader(include bool) {
+	g.omitHeader = !include
+}
+
+// Schema returns a generated component schema by name
+func (g *Generator) Schema(name string) (map[string]interface{}, bool) {
+	schema, ok := g.typeSchemas[name].(map[string]interface{})
+	return schema, ok
+}
+
+// Responses returns the documented responses for a route keyed by status code
+func (g *Generator) Responses(route types.RouteInfo) map[string]Response {
+	return g.buildResponses(route)
+}
+
 // discoverRoutes scans the codebase for init() functions that register routes
 func (g *Generator) discoverRoutes() error {
 	// Parse Go files to trigger module loading and init() functions
@@ -64,7 +309,7 @@ func (g *Generator) discoverRoutes() error {
 	for _, dir := range packageDirs {
 		if err := g.parsePackageDir(dir); err != nil {
 			// Log warning but continue - some packages might not exist
-			fmt.Printf("Warning: failed to parse package %s: %v\n", dir, err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to parse package %s: %v\n", dir, err)
 		}
 	}
 
@@ -87,43 +332,279 @@ func (g *Generator) parsePackageDir(dir string) error {
 		_, err := parser.ParseFile(g.fileSet, file, nil, parser.ParseComments)
 		if err != nil {
 			// Log warning but continue
-			fmt.Printf("Warning: failed to parse file %s: %v\n", file, err)
+			fmt.Fprintf(os.Stderr, "Warning: failed to parse file %s: %v\n", file, err)
 		}
 	}
 
 	return nil
 }
 
-// generateSchemas generates JSON schemas for request/response types
+// namedSchema is a generated schema paired with its component name and the type
+// it was generated from, nil for synthesized schemas
+type namedSchema struct {
+	name   string
+	schema map[string]interface{}
+	typ    reflect.Type
+}
+
+// routeSchemas holds the schemas generated for one route by a worker
+type routeSchemas struct {
+	schemas []namedSchema
+	err     error
+}
+
+// schemaState tracks the types on the current generation path so circular references
+// are detected per goroutine without leaking into sibling fields
+type schemaState struct {
+	visiting map[reflect.Type]bool
+	cycles   int
+	// refs is set by GenerateJSONSchema; circular references then become $refs to
+	// definitions and the referenced types are recorded here by name
+	refs map[string]reflect.Type
+}
+
+// newSchemaState creates an empty generation path
+func newSchemaState() *schemaState {
+	return &schemaState{visiting: make(map[reflect.Type]bool)}
+}
+
+// generateSchemas generates JSON schemas for request/response types. Routes are
+// processed by a worker pool and merged in registration order so output is deterministic.
 func (g *Generator) generateSchemas() error {
-	for _, route := range g.routes {
-		if route.RequestType != nil {
-			schema, err := g.generateTypeSchema(route.RequestType)
+	if g.schemaCache == nil {
+		g.schemaCache = make(map[reflect.Type]map[string]interface{})
+	}
+
+	results := make([]routeSchemas, len(g.routes))
+	jobs := make(chan int)
+
+	var wg sync.WaitGroup
+	for w := 0; w < g.workerCount(); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i] = g.generateRouteSchemas(g.routes[i])
+			}
+		}()
+	}
+
+	for i := range g.routes {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, result := range results {
+		if result.err != nil {
+			return result.err
+		}
+	}
+
+	// Routes sharing a type produce the same schema; hooks see each name once
+	added := make(map[string]bool)
+	for _, result := range results {
+		for _, named := range result.schemas {
+			if named.typ != nil {
+				if err := g.claimSchemaName(named.name, named.typ); err != nil {
+					return err
+				}
+			}
+			if !added[named.name] {
+				added[named.name] = true
+				g.addSchema(named.typ, named.name, named.schema)
+			}
+		}
+	}
+
+	// Union variants found along the way go through the hooks too, in name order
+	g.mu.Lock()
+	variants := g.variantSchemas
+	g.variantSchemas = nil
+	g.mu.Unlock()
+	for _, name := range slices.Sorted(maps.Keys(variants)) {
+		if !added[name] {
+			added[name] = true
+			g.addSchema(variants[name].typ, name, variants[name].schema)
+		}
+	}
+
+	return nil
+}
+
+// workerCount returns the number of schema workers to run for the loaded routes
+func (g *Generator) workerCount() int {
+	workers := g.schemaWorkers
+	if workers <= 0 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+	if workers > len(g.routes) {
+		workers = len(g.routes)
+	}
+	return workers
+}
+
+// generateRouteSchemas generates the request and response schemas for a single route
+func (g *Generator) generateRouteSchemas(route types.RouteInfo) routeSchemas {
+	var result routeSchemas
+
+	if route.RequestType != nil {
+		schema, err := g.generateTypeSchema(route.RequestType)
+		if err != nil {
+			result.err = fmt.Errorf("route %s %s, %w (request type %v)", strings.ToUpper(route.Method), route.Path, err, route.RequestType)
+			return result
+		}
+		if isComponentType(route.RequestType) {
+			result.schemas = append(result.schemas, namedSchema{g.getTypeName(route.RequestType), schema, route.RequestType})
+		}
+	}
+
+	if _, err := responseContentType(route.ResponseFormat); err != nil {
+		result.err = fmt.Errorf("route %s %s, %w", strings.ToUpper(route.Method), route.Path, err)
+		return result
+	}
+
+	// File downloads are documented as binary content, so ResponseType needs no schema
+	if route.ResponseType != nil && isJSONResponse(route) {
+		schema, err := g.generateTypeSchema(route.ResponseType)
+		if err != nil {
+			result.err = fmt.Errorf("route %s %s, %w (response type %v)", strings.ToUpper(route.Method), route.Path, err, route.ResponseType)
+			return result
+		}
+		if isComponentType(route.ResponseType) {
+			result.schemas = append(result.schemas, namedSchema{g.getTypeName(route.ResponseType), schema, route.ResponseType})
+		}
+	}
+
+	if route.ErrorResponseType != nil {
+		schema, err := g.generateTypeSchema(route.ErrorResponseType)
+		if err != nil {
+			result.err = fmt.Errorf("route %s %s, %w (error response type %v)", strings.ToUpper(route.Method), route.Path, err, route.ErrorResponseType)
+			return result
+		}
+		if isComponentType(route.ErrorResponseType) {
+			result.schemas = append(result.schemas, namedSchema{g.getTypeName(route.ErrorResponseType), schema, route.ErrorResponseType})
+		}
+	}
+
+	for _, name := range slices.Sorted(maps.Keys(route.Callbacks)) {
+		cb := route.Callbacks[name]
+		if err := validateCallback(name, cb); err != nil {
+			result.err = fmt.Errorf("route %s %s, %w", strings.ToUpper(route.Method), route.Path, err)
+			return result
+		}
+		ops := callbackOperations(cb)
+		for _, method := range slices.Sorted(maps.Keys(ops)) {
+			requestType := ops[method].RequestType
+			if requestType == nil {
+				continue
+			}
+			schema, err := g.generateTypeSchema(requestType)
 			if err != nil {
-				return fmt.Errorf("failed to generate schema for request type %v: %w", route.RequestType, err)
+				result.err = fmt.Errorf("route %s %s, %w (callback %s request type %v)", strings.ToUpper(route.Method), route.Path, err, name, requestType)
+				return result
+			}
+			if isComponentType(requestType) {
+				result.schemas = append(result.schemas, namedSchema{g.getTypeName(requestType), schema, requestType})
 			}
-			g.typeSchemas[g.getTypeName(route.RequestType)] = schema
 		}
+	}
 
-		if route.ResponseType != nil {
-			schema, err := g.generateTypeSchema(route.ResponseType)
+	if route.QueryType != nil {
+		if _, err := g.queryParameters(route.QueryType); err != nil {
+			result.err = fmt.Errorf("route %s %s, %w (query type %v)", strings.ToUpper(route.Method), route.Path, err, route.QueryType)
+			return result
+		}
+	}
+
+	if route.Pagination != nil {
+		if _, err := paginationStyleParameters(route.Pagination.Style); err != nil {
+			result.err = fmt.Errorf("route %s %s, %w", strings.ToUpper(route.Method), route.Path, err)
+			return result
+		}
+		if wrapsInPage(route) {
+			schema, err := g.paginatedResponseSchema(route.ResponseType)
 			if err != nil {
-				return fmt.Errorf("failed to generate schema for response type %v: %w", route.ResponseType, err)
+				result.err = fmt.Errorf("route %s %s, %w", strings.ToUpper(route.Method), route.Path, err)
+				return result
 			}
-			g.typeSchemas[g.getTypeName(route.ResponseType)] = schema
+			result.schemas = append(result.schemas, namedSchema{g.paginatedResponseName(route.ResponseType), schema, nil})
 		}
 	}
 
-	return nil
+	return result
 }
 
 // generateTypeSchema generates a JSON schema for a Go type using reflection
 func (g *Generator) generateTypeSchema(t reflect.Type) (map[string]interface{}, error) {
-	return g.generateSchemaForType(t, make(map[reflect.Type]bool))
+	return g.generateSchemaForType(t, newSchemaState())
+}
+
+// cachedSchema returns a copy of the memoized schema for t, if one exists
+func (g *Generator) cachedSchema(t reflect.Type) (map[string]interface{}, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	schema, ok := g.schemaCache[t]
+	if !ok {
+		return nil, false
+	}
+	return copySchema(schema), true
+}
+
+// storeSchema memoizes a completed schema for t
+func (g *Generator) storeSchema(t reflect.Type, schema map[string]interface{}) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.schemaCache == nil {
+		g.schemaCache = make(map[reflect.Type]map[string]interface{})
+	}
+	g.schemaCache[t] = copySchema(schema)
+}
+
+// copySchema returns a deep copy so callers and hooks can edit a schema, including
+// its nested properties, without touching the cache
+func copySchema(schema map[string]interface{}) map[string]interface{} {
+	copied := make(map[string]interface{}, len(schema))
+	for key, value := range schema {
+		copied[key] = copySchemaValue(value)
+	}
+	return copied
+}
+
+// copySchemaValue deep-copies the maps and slices a schema value is built from
+func copySchemaValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return copySchema(v)
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copySchemaValue(item)
+		}
+		return copied
+	case []map[string]interface{}:
+		copied := make([]map[string]interface{}, len(v))
+		for i, item := range v {
+			copied[i] = copySchema(item)
+		}
+		return copied
+	case []string:
+		return append([]string(nil), v...)
+	case map[string]string:
+		copied := make(map[string]string, len(v))
+		for key, item := range v {
+			copied[key] = item
+		}
+		return copied
+	default:
+		return value
+	}
 }
 
 // generateSchemaForType recursively generates schema, handling circular references
-func (g *Generator) generateSchemaForType(t reflect.Type, visited map[reflect.Type]bool) (map[string]interface{}, error) {
+func (g *Generator) generateSchemaForType(t reflect.Type, state *schemaState) (map[string]interface{}, error) {
 	// Dereference pointers first
 	for t.Kind() == reflect.Ptr {
 		t = t.Elem()
@@ -135,25 +616,56 @@ func (g *Generator) generateSchemaForType(t reflect.Type, visited map[reflect.Ty
 		return map[string]interface{}{"type": "string"}, nil
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
 		return map[string]interface{}{"type": "integer"}, nil
+	case reflect.Uint8:
+		return map[string]interface{}{"type": "integer", "format": "int32", "minimum": 0, "maximum": 255}, nil
 	case reflect.Float32, reflect.Float64:
 		return map[string]interface{}{"type": "number"}, nil
 	case reflect.Bool:
 		return map[string]interface{}{"type": "boolean"}, nil
+	case reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		// encoding/json cannot marshal these kinds, so they cannot appear in a JSON body
+		return nil, fmt.Errorf("unsupported type %s", t.String())
 	}
 
 	// Handle circular references for complex types only
-	if visited == nil {
-		visited = make(map[reflect.Type]bool)
+	if state == nil {
+		state = newSchemaState()
 	}
-	
-	if visited[t] {
+
+	if state.visiting[t] {
+		state.cycles++
+		if state.refs != nil {
+			name := g.getTypeName(t)
+			state.refs[name] = t
+			return map[string]interface{}{"$ref": jsonSchemaDefinitionsRef + name}, nil
+		}
 		return map[string]interface{}{
 			"type": "object",
 			"description": fmt.Sprintf("Circular reference to %s", t.String()),
 		}, nil
 	}
-	visited[t] = true
 
+	if schema, ok := g.cachedSchema(t); ok {
+		return schema, nil
+	}
+
+	state.visiting[t] = true
+	cycles := state.cycles
+
+	schema, err := g.generateComplexSchema(t, state)
+
+	delete(state.visiting, t)
+
+	// Schemas containing circular stubs depend on the path they were reached by
+	if err == nil && state.cycles == cycles {
+		g.storeSchema(t, schema)
+	}
+
+	return schema, err
+}
+
+// generateComplexSchema generates the schema for a non-primitive type already on the path
+func (g *Generator) generateComplexSchema(t reflect.Type, state *schemaState) (map[string]interface{}, error) {
 	switch t.Kind() {
 	case reflect.Struct:
 		// Special handling for time.Time
@@ -163,9 +675,13 @@ func (g *Generator) generateSchemaForType(t reflect.Type, visited map[reflect.Ty
 				"format": "date-time",
 			}, nil
 		}
-		return g.generateStructSchema(t, visited)
+		return g.generateStructSchema(t, state)
 	case reflect.Slice, reflect.Array:
-		elemSchema, err := g.generateSchemaForType(t.Elem(), visited)
+		// encoding/json writes []byte as a base64 string; byte arrays stay arrays
+		if t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8 {
+			return map[string]interface{}{"type": "string", "format": "byte"}, nil
+		}
+		elemSchema, err := g.generateSchemaForType(t.Elem(), state)
 		if err != nil {
 			return nil, err
 		}
@@ -179,6 +695,10 @@ func (g *Generator) generateSchemaForType(t reflect.Type, visited map[reflect.Ty
 			"additionalProperties": true,
 		}, nil
 	case reflect.Interface:
+		// Registered unions become oneOf; anything else stays permissive
+		if variants := unionVariants(t); len(variants) > 0 {
+			return g.generateUnionSchema(variants, state)
+		}
 		return map[string]interface{}{
 			"type": "object",
 			"additionalProperties": true,
@@ -192,23 +712,43 @@ func (g *Generator) generateSchemaForType(t reflect.Type, visited map[reflect.Ty
 }
 
 // generateStructSchema generates a schema for a struct type
-func (g *Generator) generateStructSchema(t reflect.Type, visited map[reflect.Type]bool) (map[string]interface{}, error) {
+func (g *Generator) generateStructSchema(t reflect.Type, state *schemaState) (map[string]interface{}, error) {
 	properties := make(map[string]interface{})
 	required := []string{}
 
+	// Promoted fields can repeat a name, so each is listed in required at most once
+	seen := make(map[string]bool)
+	addRequired := func(name string) {
+		if !seen[name] {
+			seen[name] = true
+			required = append(required, name)
+		}
+	}
+
 	for i := 0; i < t.NumField(); i++ {
 		field := t.Field(i)
-		
-		// Skip unexported fields
-		if !field.IsExported() {
-			continue
-		}
 
 		jsonTag := field.Tag.Get("json")
 		if jsonTag == "-" {
 			continue // Skip fields marked with json:"-"
 		}
 
+		// Untagged embedded structs have their fields promoted, as encoding/json does
+		if field.Anonymous && jsonTag == "" && isStructType(field.Type) {
+			if err := g.promoteEmbeddedFields(field.Type, state, properties, addRequired); err != nil {
+				return nil, withFieldPath(field.Name, err)
+			}
+			continue
+		}
+
+		// Skip unexported fields
+		if !field.IsExported() {
+			continue
+		}
+
+		// A *time.Time such as deleted_at is an optional, nullable timestamp
+		nullableTime := isTimePointer(field.Type)
+
 		fieldName := field.Name
 		if jsonTag != "" {
 			// Parse json tag (e.g., "field_name,omitempty")
@@ -217,26 +757,49 @@ func (g *Generator) generateStructSchema(t reflect.Type, visited map[reflect.Typ
 				fieldName = parts[0]
 			}
 			
-			// Check if field is optional (has omitempty)
-			omitempty := false
+			// Check if field is optional (has omitempty or omitzero)
+			omitempty := field.Tag.Get("schema") == "omitzero"
 			for _, part := range parts[1:] {
-				if part == "omitempty" {
+				if part == "omitempty" || part == "omitzero" {
 					omitempty = true
 					break
 				}
 			}
 			
-			if !omitempty {
-				required = append(required, fieldName)
+			if !omitempty && !nullableTime {
+				addRequired(fieldName)
 			}
-		} else {
+		} else if field.Tag.Get("schema") != "omitzero" && !nullableTime {
 			// No json tag, field is required by default
-			required = append(required, fieldName)
+			addRequired(fieldName)
 		}
 
-		fieldSchema, err := g.generateSchemaForType(field.Type, visited)
+		fieldSchema, err := g.generateSchemaForType(field.Type, state)
 		if err != nil {
-			return nil, fmt.Errorf("failed to generate schema for field %s: %w", field.Name, err)
+			return nil, withFieldPath(fieldName, err)
+		}
+
+		// Attach a discriminator to union fields that declare one
+		if discriminator := field.Tag.Get("discriminator"); discriminator != "" {
+			addDiscriminator(fieldSchema, discriminator)
+		}
+
+		if nullableTime {
+			fieldSchema["nullable"] = true
+		}
+
+		// A format tag overrides the string format, e.g. format:"date" for a
+		// time.Time holding a calendar date
+		if format := field.Tag.Get("format"); format != "" && fieldSchema["type"] == "string" {
+			fieldSchema["format"] = format
+		}
+
+		// Document the field from its description and example tags
+		if description := field.Tag.Get("description"); description != "" {
+			fieldSchema["description"] = description
+		}
+		if example := field.Tag.Get("example"); example != "" {
+			fieldSchema["example"] = example
 		}
 
 		properties[fieldName] = fieldSchema
@@ -254,6 +817,67 @@ func (g *Generator) generateStructSchema(t reflect.Type, visited map[reflect.Typ
 	return schema, nil
 }
 
+// promoteEmbeddedFields merges the properties of an embedded struct into properties.
+// Fields already declared take precedence over promoted ones.
+func (g *Generator) promoteEmbeddedFields(t reflect.Type, state *schemaState, properties map[string]interface{}, addRequired func(string)) error {
+	embedded, err := g.generateSchemaForType(t, state)
+	if err != nil {
+		return err
+	}
+
+	embeddedProperties, _ := embedded["properties"].(map[string]interface{})
+	for name, schema := range embeddedProperties {
+		if _, exists := properties[name]; !exists {
+			properties[name] = schema
+		}
+	}
+	if embeddedRequired, ok := embedded["required"].([]string); ok {
+		for _, name := range embeddedRequired {
+			addRequired(name)
+		}
+	}
+	return nil
+}
+
+// isTimePointer reports whether t is a pointer to time.Time
+func isTimePointer(t reflect.Type) bool {
+	return t.Kind() == reflect.Ptr && t.Elem().PkgPath() == "time" && t.Elem().Name() == "Time"
+}
+
+// isStructType reports whether t is a struct or a pointer to one, excluding time.Time
+func isStructType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && !(t.PkgPath() == "time" && t.Name() == "Time")
+}
+
+// schemaError records the JSON field path at which schema generation failed
+type schemaError struct {
+	fieldPath []string
+	err       error
+}
+
+// Error returns the failure prefixed with the dotted field path
+func (e *schemaError) Error() string {
+	return fmt.Sprintf("field %s: %v", strings.Join(e.fieldPath, "."), e.err)
+}
+
+// Unwrap returns the underlying schema generation error
+func (e *schemaError) Unwrap() error {
+	return e.err
+}
+
+// withFieldPath prepends a field name to the path of a schema generation error
+func withFieldPath(fieldName string, err error) error {
+	var se *schemaError
+	if errors.As(err, &se) {
+		se.fieldPath = append([]string{fieldName}, se.fieldPath...)
+		return se
+	}
+	return &schemaError{fieldPath: []string{fieldName}, err: err}
+}
+
 // getTypeName returns a clean name for a type to use as a schema reference
 func (g *Generator) getTypeName(t reflect.Type) string {
 	// Handle array/slice types first
@@ -263,19 +887,137 @@ func (g *Generator) getTypeName(t reflect.Type) string {
 		return elemName + "Array"
 	}
 	
+	// Named types (including generic instantiations) are named by the naming
+	// strategy; aliases are already resolved by reflect
+	if t.Name() != "" {
+		return sanitizeSchemaName(g.namingStrategy()(t))
+	}
+
 	// Remove package path, keep only the type name
 	name := t.String()
 	if lastDot := strings.LastIndex(name, "."); lastDot != -1 {
 		name = name[lastDot+1:]
 	}
 	
+	return sanitizeSchemaName(name)
+}
+
+// sanitizeSchemaName drops characters OpenAPI does not allow in component keys,
+// which must match ^[a-zA-Z0-9.\-_]+$
+func sanitizeSchemaName(name string) string {
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			return r
+		default:
+			return -1
+		}
+	}, name)
+}
+
+// isComponentType reports whether a route type is emitted as a named component schema.
+// Only named structs and slices of named structs are; everything else is inlined.
+func isComponentType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		return isComponentVariant(t.Elem())
+	}
+	return isComponentVariant(t)
+}
+
+// cleanTypeName turns a reflected type name such as "Paginated[example.com/pkg.User]"
+// into a schema-safe name such as "PaginatedUser"
+func cleanTypeName(name string) string {
+	open := strings.Index(name, "[")
+	if open == -1 || !strings.HasSuffix(name, "]") {
+		return stripPackagePath(name)
+	}
+
+	cleaned := stripPackagePath(name[:open])
+	for _, arg := range splitTypeArgs(name[open+1 : len(name)-1]) {
+		cleaned += capitalize(cleanTypeArg(arg))
+	}
+	return cleaned
+}
+
+// cleanTypeArg cleans a single generic type argument, which may be a composite type
+func cleanTypeArg(arg string) string {
+	arg = strings.TrimLeft(strings.TrimSpace(arg), "*")
+
+	switch {
+	case strings.HasPrefix(arg, "[]"):
+		return capitalize(cleanTypeArg(arg[2:])) + "Array"
+	case strings.HasPrefix(arg, "map["):
+		if end := matchingBracket(arg, 3); end != -1 {
+			return "Map" + capitalize(cleanTypeArg(arg[4:end])) + capitalize(cleanTypeArg(arg[end+1:]))
+		}
+	case strings.HasPrefix(arg, "interface {"):
+		return "Any"
+	}
+
+	return cleanTypeName(arg)
+}
+
+// splitTypeArgs splits a type argument list on top-level commas
+func splitTypeArgs(args string) []string {
+	var parts []string
+	depth, start := 0, 0
+	for i, r := range args {
+		switch r {
+		case '[', '{':
+			depth++
+		case ']', '}':
+			depth--
+		case ',':
+			if depth == 0 {
+				parts = append(parts, args[start:i])
+				start = i + 1
+			}
+		}
+	}
+	return append(parts, args[start:])
+}
+
+// matchingBracket returns the index of the bracket closing the one at open, or -1
+func matchingBracket(s string, open int) int {
+	depth := 0
+	for i := open; i < len(s); i++ {
+		switch s[i] {
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth == 0 {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// stripPackagePath removes an import path prefix such as "example.com/pkg." from a type name
+func stripPackagePath(name string) string {
+	if lastDot := strings.LastIndex(name, "."); lastDot != -1 {
+		return name[lastDot+1:]
+	}
 	return name
 }
 
+// capitalize title-cases the first rune of s, leaving the rest unchanged
+func capitalize(s string) string {
+	r, size := utf8.DecodeRuneInString(s)
+	if r == utf8.RuneError {
+		return s
+	}
+	return string(unicode.ToTitle(r)) + s[size:]
+}
+
 // addStandardSchemas adds common schemas used across all APIs
 func (g *Generator) addStandardSchemas() {
 	// Standard error response schema
-	g.typeSchemas["ErrorResponse"] = map[string]interface{}{
+	g.addSchema(nil, "ErrorResponse", map[string]interface{}{
 		"type": "object",
 		"required": []string{"error", "message", "status"},
 		"properties": map[string]interface{}{
@@ -292,10 +1034,52 @@ func (g *Generator) addStandardSchemas() {
 				"description": "HTTP status code",
 			},
 		},
-	}
+	})
 }
 
 // GetDiscoveredRoutes returns the routes discovered by the generator
 func (g *Generator) GetDiscoveredRoutes() []types.RouteInfo {
 	return g.routes
+}
+
+// PrunedSchemas returns the names of schemas dropped from the last generated spec
+func (g *Generator) PrunedSchemas() []string {
+	return g.prunedSchemas
+}
+
+// GetRoutesByModule returns the discovered routes belonging to module
+func (g *Generator) GetRoutesByModule(module string) []types.RouteInfo {
+	var routes []types.RouteInfo
+	for _, route := range g.routes {
+		if route.Module == module {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// GetRoutesByMethod returns the discovered routes using the HTTP method, case-insensitively
+func (g *Generator) GetRoutesByMethod(method string) []types.RouteInfo {
+	var routes []types.RouteInfo
+	for _, route := range g.routes {
+		if strings.EqualFold(route.Method, method) {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
+// SortRoutes returns a copy of routes sorted alphabetically by path, then by method
+func SortRoutes(routes []types.RouteInfo) []types.RouteInfo {
+	sorted := make([]types.RouteInfo, len(routes))
+	copy(sorted, routes)
+
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Path != sorted[j].Path {
+			return sorted[i].Path < sorted[j].Path
+		}
+		return strings.ToUpper(sorted[i].Method) < strings.ToUpper(sorted[j].Method)
+	})
+
+	return sorted
 }
\ No newline at end of file
@@ -1,6 +1,8 @@
 package analyzer
 
 import (
+	"fmt"
+	"os"
 	"reflect"
 	"strings"
 	"testing"
@@ -100,6 +102,82 @@ func TestGenerateTypeSchema_TimeType(t *testing.T) {
 	}
 }
 
+func TestGenerateTypeSchema_DateFormatTag(t *testing.T) {
+	type Event struct {
+		Birthday  time.Time  `json:"birthday" format:"date"`
+		Due       *time.Time `json:"due,omitempty" format:"date"`
+		CreatedAt time.Time  `json:"created_at"`
+	}
+
+	gen := NewGenerator()
+	schema, err := gen.generateTypeSchema(reflect.TypeOf(Event{}))
+	if err != nil {
+		t.Fatalf("generateTypeSchema() error = %v", err)
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	for field, want := range map[string]string{"birthday": "date", "due": "date", "created_at": "date-time"} {
+		property := properties[field].(map[string]interface{})
+		if property["type"] != "string" || property["format"] != want {
+			t.Errorf("%s = %v, want a string with format %s", field, property, want)
+		}
+	}
+
+	// The tag must not leak into the cached time.Time schema
+	plain, _ := gen.generateTypeSchema(reflect.TypeOf(time.Time{}))
+	if plain["format"] != "date-time" {
+		t.Errorf("time.Time should still map to date-time, got %v", plain["format"])
+	}
+}
+
+func TestGenerateTypeSchema_NullableTimePointer(t *testing.T) {
+	type Record struct {
+		CreatedAt time.Time  `json:"created_at"`
+		DeletedAt *time.Time `json:"deleted_at"`
+	}
+
+	gen := NewGenerator()
+	schema, err := gen.generateTypeSchema(reflect.TypeOf(Record{}))
+	if err != nil {
+		t.Fatalf("generateTypeSchema() error = %v", err)
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	deletedAt := properties["deleted_at"].(map[string]interface{})
+	if deletedAt["type"] != "string" || deletedAt["format"] != "date-time" || deletedAt["nullable"] != true {
+		t.Errorf("deleted_at = %v, want a nullable date-time string", deletedAt)
+	}
+	if _, nullable := properties["created_at"].(map[string]interface{})["nullable"]; nullable {
+		t.Error("A time.Time value should not be nullable")
+	}
+	if required := schema["required"].([]string); !reflect.DeepEqual(required, []string{"created_at"}) {
+		t.Errorf("required = %v, want only created_at", required)
+	}
+}
+
+func TestGenerateTypeSchema_Bytes(t *testing.T) {
+	type Packet struct {
+		Level byte   `json:"level"`
+		Data  []byte `json:"data"`
+	}
+
+	gen := NewGenerator()
+	schema, err := gen.generateTypeSchema(reflect.TypeOf(Packet{}))
+	if err != nil {
+		t.Fatalf("generateTypeSchema() error = %v", err)
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	level := properties["level"].(map[string]interface{})
+	if level["type"] != "integer" || level["minimum"] != 0 || level["maximum"] != 255 {
+		t.Errorf("level = %v, want an integer between 0 and 255", level)
+	}
+	data := properties["data"].(map[string]interface{})
+	if data["type"] != "string" || data["format"] != "byte" {
+		t.Errorf("data = %v, want a base64 string with format byte", data)
+	}
+}
+
 func TestGenerateTypeSchema_Struct(t *testing.T) {
 	gen := NewGenerator()
 	
@@ -430,4 +508,488 @@ func TestJSONTagParsing(t *testing.T) {
 	if requiredFields["omit_empty"] {
 		t.Error("Field with omitempty should not be required")
 	}
-}
\ No newline at end of file
+}
+
+func TestGenerateSchemas_ErrorIncludesRouteAndField(t *testing.T) {
+	gen := NewGenerator()
+
+	type Address struct {
+		Street string   `json:"street"`
+		Zip    chan int `json:"zip"`
+	}
+
+	type CreateUserRequest struct {
+		Name    string  `json:"name"`
+		Address Address `json:"address"`
+	}
+
+	gen.routes = []types.RouteInfo{
+		{
+			Method:      "POST",
+			Path:        "/users",
+			RequestType: reflect.TypeOf(CreateUserRequest{}),
+			Module:      "users",
+		},
+	}
+
+	err := gen.generateSchemas()
+	if err == nil {
+		t.Fatal("generateSchemas() should fail for an unsupported field type")
+	}
+
+	if !strings.Contains(err.Error(), "route POST /users") {
+		t.Errorf("Error should mention the route, got: %v", err)
+	}
+
+	if !strings.Contains(err.Error(), "field address.zip") {
+		t.Errorf("Error should mention the field path, got: %v", err)
+	}
+}
+
+func TestJSONTagParsing_OmitZero(t *testing.T) {
+	gen := NewGenerator()
+
+	type Inner struct {
+		Value string `json:"value"`
+	}
+
+	type OmitZeroStruct struct {
+		Name      string `json:"name,omitzero"`
+		Count     int    `json:"count,omitzero"`
+		Inner     Inner  `json:"inner,omitzero"`
+		SchemaTag string `json:"schema_tag" schema:"omitzero"`
+		OmitEmpty string `json:"omit_empty,omitempty"`
+		Required  string `json:"required"`
+	}
+
+	schema, err := gen.generateTypeSchema(reflect.TypeOf(OmitZeroStruct{}))
+	if err != nil {
+		t.Fatalf("generateTypeSchema() error = %v", err)
+	}
+
+	properties, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected properties to be a map")
+	}
+
+	required, ok := schema["required"].([]string)
+	if !ok {
+		t.Fatal("Expected required to be a string slice")
+	}
+
+	requiredFields := make(map[string]bool)
+	for _, field := range required {
+		requiredFields[field] = true
+	}
+
+	// omitzero fields behave like omitempty: present but not required
+	for _, field := range []string{"name", "count", "inner", "schema_tag", "omit_empty"} {
+		if _, exists := properties[field]; !exists {
+			t.Errorf("Field '%s' should still be present", field)
+		}
+		if requiredFields[field] {
+			t.Errorf("Field '%s' should not be required", field)
+		}
+	}
+
+	if !requiredFields["required"] {
+		t.Error("Field without omitempty or omitzero should be required")
+	}
+}
+
+func TestGetRoutesByModule(t *testing.T) {
+	gen := NewGenerator()
+
+	gen.routes = []types.RouteInfo{
+		{Method: "GET", Path: "/users", Module: "users"},
+		{Method: "POST", Path: "/users", Module: "users"},
+		{Method: "GET", Path: "/health", Module: "health"},
+	}
+
+	users := gen.GetRoutesByModule("users")
+	if len(users) != 2 {
+		t.Errorf("Expected 2 routes for module 'users', got %d", len(users))
+	}
+
+	health := gen.GetRoutesByModule("health")
+	if len(health) != 1 || health[0].Path != "/health" {
+		t.Errorf("Expected only '/health' for module 'health', got %v", health)
+	}
+
+	if missing := gen.GetRoutesByModule("missing"); len(missing) != 0 {
+		t.Errorf("Expected no routes for unknown module, got %d", len(missing))
+	}
+}
+
+func TestGetRoutesByMethod(t *testing.T) {
+	gen := NewGenerator()
+
+	gen.routes = []types.RouteInfo{
+		{Method: "GET", Path: "/users", Module: "users"},
+		{Method: "POST", Path: "/users", Module: "users"},
+		{Method: "GET", Path: "/health", Module: "health"},
+	}
+
+	gets := gen.GetRoutesByMethod("get")
+	if len(gets) != 2 {
+		t.Errorf("Expected 2 GET routes across modules, got %d", len(gets))
+	}
+
+	if deletes := gen.GetRoutesByMethod("DELETE"); len(deletes) != 0 {
+		t.Errorf("Expected no DELETE routes, got %d", len(deletes))
+	}
+}
+
+func TestSortRoutes(t *testing.T) {
+	routes := []types.RouteInfo{
+		{Method: "POST", Path: "/users"},
+		{Method: "GET", Path: "/health"},
+		{Method: "GET", Path: "/users"},
+		{Method: "DELETE", Path: "/users"},
+	}
+
+	sorted := SortRoutes(routes)
+
+	expected := []string{"GET /health", "DELETE /users", "GET /users", "POST /users"}
+	for i, route := range sorted {
+		if got := route.Method + " " + route.Path; got != expected[i] {
+			t.Errorf("Position %d: expected %s, got %s", i, expected[i], got)
+		}
+	}
+
+	// The input slice must not be reordered
+	if routes[0].Method != "POST" {
+		t.Error("SortRoutes() should not modify its input")
+	}
+
+	if empty := SortRoutes(nil); len(empty) != 0 {
+		t.Errorf("Expected empty result for nil input, got %d", len(empty))
+	}
+}
+
+type Paginated[T any] struct {
+	Items []T `json:"items"`
+	Total int `json:"total"`
+}
+
+type User struct {
+	Name string `json:"name"`
+}
+
+type Model struct {
+	ID string `json:"id"`
+}
+
+// UserAlias is a type alias and must resolve to User
+type UserAlias = User
+
+func TestGetTypeName_Generics(t *testing.T) {
+	gen := NewGenerator()
+
+	tests := []struct {
+		name     string
+		input    reflect.Type
+		expected string
+	}{
+		{"generic struct", reflect.TypeOf(Paginated[User]{}), "PaginatedUser"},
+		{"other instantiation", reflect.TypeOf(Paginated[Model]{}), "PaginatedModel"},
+		{"basic type argument", reflect.TypeOf(Paginated[string]{}), "PaginatedString"},
+		{"slice type argument", reflect.TypeOf(Paginated[[]User]{}), "PaginatedUserArray"},
+		{"pointer type argument", reflect.TypeOf(Paginated[*User]{}), "PaginatedUser"},
+		{"map type argument", reflect.TypeOf(Paginated[map[string]User]{}), "PaginatedMapStringUser"},
+		{"nested generic", reflect.TypeOf(Paginated[Paginated[User]]{}), "PaginatedPaginatedUser"},
+		{"generic slice", reflect.TypeOf([]Paginated[User]{}), "PaginatedUserArray"},
+		{"type alias", reflect.TypeOf(UserAlias{}), "User"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := gen.getTypeName(tt.input)
+			if result != tt.expected {
+				t.Errorf("getTypeName() = %v, expected %v", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateSchemas_GenericInstantiations(t *testing.T) {
+	gen := NewGenerator()
+
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/users", ResponseType: reflect.TypeOf(Paginated[User]{}), Module: "users", Summary: "List users"},
+		{Method: "GET", Path: "/models", ResponseType: reflect.TypeOf(Paginated[Model]{}), Module: "models", Summary: "List models"},
+	}
+	if err := gen.LoadRoutes(routes); err != nil {
+		t.Fatalf("LoadRoutes() error = %v", err)
+	}
+
+	userSchema, ok := gen.Schema("PaginatedUser")
+	if !ok {
+		t.Fatal("Schema 'PaginatedUser' should have been generated")
+	}
+	if _, ok := gen.Schema("PaginatedModel"); !ok {
+		t.Fatal("Schema 'PaginatedModel' should have been generated")
+	}
+
+	// The instantiated element type must be descended into
+	items := userSchema["properties"].(map[string]interface{})["items"].(map[string]interface{})
+	itemProps := items["items"].(map[string]interface{})["properties"].(map[string]interface{})
+	if _, exists := itemProps["name"]; !exists {
+		t.Error("PaginatedUser items should use the User schema")
+	}
+
+	for _, route := range routes {
+		ref := gen.Responses(route)["200"].Content["application/json"].Schema.Ref
+		expected := "#/components/schemas/" + gen.getTypeName(route.ResponseType)
+		if ref != expected {
+			t.Errorf("Route %s: expected ref %s, got %s", route.Path, expected, ref)
+		}
+	}
+}
+
+type sharedLeaf struct {
+	ID    string    `json:"id"`
+	Tags  []string  `json:"tags"`
+	Stamp time.Time `json:"stamp"`
+}
+
+type sharedBranch struct {
+	Primary   sharedLeaf   `json:"primary"`
+	Secondary sharedLeaf   `json:"secondary"`
+	Leaves    []sharedLeaf `json:"leaves"`
+}
+
+type sharedTree struct {
+	Left  sharedBranch  `json:"left"`
+	Right *sharedBranch `json:"right,omitempty"`
+	Root  sharedLeaf    `json:"root"`
+}
+
+// syntheticRoutes builds count routes with distinct request types that all embed the same nested tree
+func syntheticRoutes(count int) []types.RouteInfo {
+	routes := make([]types.RouteInfo, 0, count)
+	for i := 0; i < count; i++ {
+		requestType := reflect.StructOf([]reflect.StructField{
+			{Name: "Tree", Type: reflect.TypeOf(sharedTree{}), Tag: `json:"tree"`},
+			{Name: fmt.Sprintf("Field%d", i), Type: reflect.TypeOf(""), Tag: reflect.StructTag(fmt.Sprintf(`json:"field_%d"`, i))},
+		})
+		routes = append(routes, types.RouteInfo{
+			Method:       "POST",
+			Path:         fmt.Sprintf("/synthetic/%d", i),
+			RequestType:  requestType,
+			ResponseType: reflect.TypeOf(sharedTree{}),
+			Module:       "synthetic",
+		})
+	}
+	return routes
+}
+
+func TestGenerateSchemas_ConcurrentMatchesSequential(t *testing.T) {
+	routes := syntheticRoutes(50)
+
+	sequential := NewGenerator()
+	sequential.schemaWorkers = 1
+	sequential.routes = routes
+	if err := sequential.generateSchemas(); err != nil {
+		t.Fatalf("sequential generateSchemas() error = %v", err)
+	}
+
+	concurrent := NewGenerator()
+	concurrent.schemaWorkers = 8
+	concurrent.routes = routes
+	if err := concurrent.generateSchemas(); err != nil {
+		t.Fatalf("concurrent generateSchemas() error = %v", err)
+	}
+
+	if !reflect.DeepEqual(sequential.typeSchemas, concurrent.typeSchemas) {
+		t.Error("Concurrent schema generation should produce the same schemas as sequential generation")
+	}
+}
+
+func TestGenerateTypeSchema_SiblingFieldsOfSameType(t *testing.T) {
+	gen := NewGenerator()
+
+	schema, err := gen.generateTypeSchema(reflect.TypeOf(sharedBranch{}))
+	if err != nil {
+		t.Fatalf("generateTypeSchema() error = %v", err)
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	for _, name := range []string{"primary", "secondary"} {
+		field := properties[name].(map[string]interface{})
+		if _, ok := field["properties"]; !ok {
+			t.Errorf("Field '%s' should be fully expanded, got %v", name, field)
+		}
+	}
+}
+
+func TestGenerateTypeSchema_MemoizedCopiesAreIndependent(t *testing.T) {
+	gen := NewGenerator()
+
+	first, err := gen.generateTypeSchema(reflect.TypeOf(sharedLeaf{}))
+	if err != nil {
+		t.Fatalf("generateTypeSchema() error = %v", err)
+	}
+	first["description"] = "annotated"
+	first["properties"].(map[string]interface{})["id"].(map[string]interface{})["format"] = "uuid"
+	first["required"] = append(first["required"].([]string)[:0], "stamp")
+
+	second, err := gen.generateTypeSchema(reflect.TypeOf(sharedLeaf{}))
+	if err != nil {
+		t.Fatalf("generateTypeSchema() error = %v", err)
+	}
+	if _, exists := second["description"]; exists {
+		t.Error("Annotating a returned schema should not leak into the memoized schema")
+	}
+	if _, exists := second["properties"].(map[string]interface{})["id"].(map[string]interface{})["format"]; exists {
+		t.Error("Editing a nested property should not leak into the memoized schema")
+	}
+	if required := second["required"].([]string); required[0] != "id" {
+		t.Errorf("Editing required in place should not leak into the memoized schema, got %v", required)
+	}
+}
+
+func BenchmarkGenerateSchemas(b *testing.B) {
+	routes := syntheticRoutes(200)
+
+	for _, workers := range []int{1, 0} {
+		name := "sequential"
+		if workers == 0 {
+			name = "concurrent"
+		}
+		b.Run(name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				gen := NewGenerator()
+				gen.schemaWorkers = workers
+				gen.routes = routes
+				if err := gen.generateSchemas(); err != nil {
+					b.Fatalf("generateSchemas() error = %v", err)
+				}
+			}
+		})
+	}
+}
+
+// useRegistry replaces the global route registry for the duration of a test
+func useRegistry(t *testing.T, routes []types.RouteInfo) {
+	t.Helper()
+
+	original := types.GetRegisteredRoutes()
+	t.Cleanup(func() { types.UpdateRouteRegistry(original) })
+
+	types.ClearRegistry()
+	for _, route := range routes {
+		types.RegisterRoute(route)
+	}
+}
+
+// assertEmptyDir fails if dir contains any entries
+func assertEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected no files to be written, found %d entries", len(entries))
+	}
+}
+
+func TestValidateOnly_NoRoutes(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	useRegistry(t, nil)
+
+	gen := NewGenerator()
+	err := gen.ValidateOnly()
+	if err == nil || !strings.Contains(err.Error(), "no routes discovered") {
+		t.Errorf("ValidateOnly() should report no routes discovered, got %v", err)
+	}
+
+	assertEmptyDir(t, dir)
+}
+
+func TestValidateOnly_ValidRoutes(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+	useRegistry(t, []types.RouteInfo{
+		{Method: "POST", Path: "/test", RequestType: reflect.TypeOf(TestRequest{}), ResponseType: reflect.TypeOf(TestResponse{}), Module: "test", Summary: "Test"},
+	})
+
+	gen := NewGenerator()
+	if err := gen.ValidateOnly(); err != nil {
+		t.Errorf("ValidateOnly() unexpected error = %v", err)
+	}
+
+	assertEmptyDir(t, dir)
+}
+
+func TestGenerateSpec_DryRun(t *testing.T) {
+	t.Chdir(t.TempDir())
+	useRegistry(t, []types.RouteInfo{
+		{Method: "GET", Path: "/test", ResponseType: reflect.TypeOf(TestResponse{}), Module: "test", Summary: "Test"},
+	})
+
+	gen := NewGenerator()
+	gen.DryRun = true
+
+	spec, err := gen.GenerateSpec()
+	if err != nil {
+		t.Fatalf("GenerateSpec() dry run unexpected error = %v", err)
+	}
+	if spec != "" {
+		t.Error("GenerateSpec() should not return a spec in dry-run mode")
+	}
+}
+
+type AuditTrail struct {
+	CreatedAt time.Time `json:"created_at"`
+	Author    string    `json:"author"`
+}
+
+type Timestamps struct {
+	Created time.Time `json:"created_at"`
+	Updated time.Time `json:"updated_at"`
+}
+
+// auditedRecordType embeds both AuditTrail and Timestamps, whose promoted fields share
+// the created_at name. It is built with reflection because vet rejects the repeated tag.
+func auditedRecordType() reflect.Type {
+	return reflect.StructOf([]reflect.StructField{
+		{Name: "AuditTrail", Type: reflect.TypeOf(AuditTrail{}), Anonymous: true},
+		{Name: "Timestamps", Type: reflect.TypeOf(Timestamps{}), Anonymous: true},
+		{Name: "ID", Type: reflect.TypeOf(""), Tag: `json:"id"`},
+	})
+}
+
+func TestGenerateTypeSchema_PromotedFieldsDeduplicated(t *testing.T) {
+	gen := NewGenerator()
+
+	schema, err := gen.generateTypeSchema(auditedRecordType())
+	if err != nil {
+		t.Fatalf("generateTypeSchema() error = %v", err)
+	}
+
+	required := schema["required"].([]string)
+	count := 0
+	for _, name := range required {
+		if name == "created_at" {
+			count++
+		}
+	}
+	if count != 1 {
+		t.Errorf("Expected created_at in required exactly once, got %v", required)
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	expected := []string{"created_at", "author", "updated_at", "id"}
+	if len(properties) != len(expected) {
+		t.Errorf("Expected %d properties, got %d: %v", len(expected), len(properties), properties)
+	}
+	for _, name := range expected {
+		if _, exists := properties[name]; !exists {
+			t.Errorf("Expected promoted property %s", name)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// reservedHeaders are header parameters OpenAPI ignores: Authorization is described
+// by securitySchemes, Accept and Content-Type by the operation's media types
+var reservedHeaders = []string{"Authorization", "Accept", "Content-Type"}
+
+// isReservedHeader reports whether name is one of reservedHeaders, ignoring case
+func isReservedHeader(name string) bool {
+	for _, reserved := range reservedHeaders {
+		if strings.EqualFold(name, reserved) {
+			return true
+		}
+	}
+	return false
+}
+
+// headerParameters returns the in: header parameters for a route's declared
+// headers, skipping reserved headers; the lint pass warns about those
+func headerParameters(headers []types.HeaderParam) []Parameter {
+	var params []Parameter
+	for _, header := range headers {
+		if isReservedHeader(header.Name) {
+			continue
+		}
+
+		headerType := header.Type
+		if headerType == "" {
+			headerType = "string"
+		}
+		params = append(params, Parameter{
+			Name:        header.Name,
+			In:          "header",
+			Description: header.Description,
+			Required:    header.Required,
+			Schema:      map[string]interface{}{"type": headerType},
+		})
+	}
+	return params
+}
@@ -0,0 +1,336 @@
+package analyzer
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// OperationHook customizes an operation before it is added to the spec. Returning
+// true leaves the operation out.
+type OperationHook func(route types.RouteInfo, op *Operation) (skip bool)
+
+// SchemaHook customizes a component schema before it is stored. t is nil for
+// schemas the generator synthesizes, such as ErrorResponse and pagination
+// envelopes. Returning true leaves the schema out of the components.
+type SchemaHook func(t reflect.Type, name string, schema map[string]interface{}) (skip bool)
+
+// SpecHook customizes the finished spec before it is marshaled
+type SpecHook func(spec *OpenAPISpec)
+
+// OnOperation registers a hook run for every operation, in registration order
+func (g *Generator) OnOperation(hook OperationHook) {
+	g.operationHooks = append(g.operationHooks, hook)
+}
+
+// OnSchema registers a hook run once for every component schema, in registration order
+func (g *Generator) OnSchema(hook SchemaHook) {
+	g.schemaHooks = append(g.schemaHooks, hook)
+}
+
+// OnSpec registers a hook run on the finished spec, in registration order
+func (g *Generator) OnSpec(hook SpecHook) {
+	g.specHooks = append(g.specHooks, hook)
+}
+
+// skipOperation runs the operation hooks, stopping at the first that skips op
+func (g *Generator) skipOperation(route types.RouteInfo, op *Operation) bool {
+	for _, hook := range g.operationHooks {
+		if hook(route, op) {
+			return true
+		}
+	}
+	return false
+}
+
+// addSchema runs the schema hooks on a component schema and stores it unless a
+// hook skips it
+func (g *Generator) addSchema(t reflect.Type, name string, schema map[string]interface{}) {
+	for _, hook := range g.schemaHooks {
+		if hook(t, name, schema) {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			if g.skippedSchemas == nil {
+				g.skippedSchemas = make(map[string]bool)
+			}
+			g.skippedSchemas[name] = true
+			return
+		}
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.typeSchemas[name] = schema
+}
+
+// RenameSchema renames a component schema and rewrites every reference to it
+func (s *OpenAPISpec) RenameSchema(from, to string) {
+	schema, exists := s.Components.Schemas[from]
+	if !exists {
+		return
+	}
+
+	oldRef, newRef := componentSchemasRef+from, componentSchemasRef+to
+	rewrite := func(ref string) string {
+		if ref == oldRef {
+			return newRef
+		}
+		return ref
+	}
+	schemas := make(map[string]interface{}, len(s.Components.Schemas))
+	for name, component := range s.Components.Schemas {
+		if name != from {
+			schemas[name] = renameRefs(component, rewrite)
+		}
+	}
+	schemas[to] = renameRefs(schema, rewrite)
+	s.Components.Schemas = schemas
+
+	for path, pathItem := range s.Paths {
+		s.Paths[path] = mapPathItemSchemas(pathItem, rewriteRefs(rewrite))
+	}
+}
+
+// mapPathItemSchemas replaces every schema in a path item's operations, parameters
+// and callbacks with mapSchema's result
+func mapPathItemSchemas(pathItem PathItem, mapSchema func(map[string]interface{}) map[string]interface{}) PathItem {
+	pathItem.Parameters = mapParameterSchemas(pathItem.Parameters, mapSchema)
+
+	for _, op := range pathItem.Operations() {
+		op.Parameters = mapParameterSchemas(op.Parameters, mapSchema)
+		if op.RequestBody != nil {
+			op.RequestBody.Content = mapContentSchemas(op.RequestBody.Content, mapSchema)
+		}
+		for status, response := range op.Responses {
+			response.Content = mapContentSchemas(response.Content, mapSchema)
+			op.Responses[status] = response
+		}
+		for name, callback := range op.Callbacks {
+			for expression, callbackItem := range callback {
+				callback[expression] = mapPathItemSchemas(callbackItem, mapSchema)
+			}
+			op.Callbacks[name] = callback
+		}
+	}
+	return pathItem
+}
+
+// renameMapping returns a copy of a discriminator with its mapping values replaced
+// by rewrite; entries rewritten to "" are dropped. Generated mappings are
+// map[string]string; parsed ones are generic maps.
+func renameMapping(discriminator map[string]interface{}, rewrite func(ref string) string) map[string]interface{} {
+	renamed := make(map[string]interface{}, len(discriminator))
+	for key, value := range discriminator {
+		renamed[key] = value
+	}
+	switch mapping := discriminator["mapping"].(type) {
+	case map[string]string:
+		rewritten := make(map[string]string, len(mapping))
+		for name, ref := range mapping {
+			if ref = rewrite(ref); ref != "" {
+				rewritten[name] = ref
+			}
+		}
+		renamed["mapping"] = rewritten
+	case map[string]interface{}:
+		rewritten := make(map[string]interface{}, len(mapping))
+		for name, ref := range mapping {
+			if ref, ok := ref.(string); ok {
+				if ref = rewrite(ref); ref != "" {
+					rewritten[name] = ref
+				}
+				continue
+			}
+			rewritten[name] = ref
+		}
+		renamed["mapping"] = rewritten
+	}
+	return renamed
+}
+
+// WarningSkippedSchema reports a reference to a schema an OnSchema hook skipped
+const WarningSkippedSchema = "skipped-schema"
+
+// dropSkippedRefs removes the references to schemas an OnSchema hook skipped, so
+// the spec has no dangling references; each becomes an unconstrained schema and
+// is reported as a WarningSkippedSchema
+func (g *Generator) dropSkippedRefs(spec *OpenAPISpec) {
+	if len(g.skippedSchemas) == 0 {
+		return
+	}
+
+	if len(spec.Components.Schemas) > 0 {
+		schemas := make(map[string]interface{}, len(spec.Components.Schemas))
+		for _, name := range slices.Sorted(maps.Keys(spec.Components.Schemas)) {
+			var dropped []string
+			schemas[name] = dropRefs(spec.Components.Schemas[name], g.skippedSchemas, &dropped)
+			for _, skipped := range compactNames(dropped) {
+				g.warnings = append(g.warnings, Warning{
+					Category: WarningSkippedSchema,
+					Message:  fmt.Sprintf("schema %s references schema %s, which an OnSchema hook skipped", name, skipped),
+				})
+			}
+		}
+		spec.Components.Schemas = schemas
+	}
+
+	for _, path := range slices.Sorted(maps.Keys(spec.Paths)) {
+		pathItem := spec.Paths[path]
+		var shared []string
+		pathItem.Parameters = mapParameterSchemas(pathItem.Parameters, dropSchemaRefs(g.skippedSchemas, &shared))
+		for _, entry := range pathItem.methodOperations() {
+			dropped := slices.Clone(shared)
+			var single PathItem
+			single.setOperation(entry.method, entry.op)
+			mapPathItemSchemas(single, dropSchemaRefs(g.skippedSchemas, &dropped))
+
+			for _, skipped := range compactNames(dropped) {
+				module := ""
+				if len(entry.op.Tags) > 0 {
+					module = entry.op.Tags[0]
+				}
+				g.warnings = append(g.warnings, Warning{
+					Category: WarningSkippedSchema,
+					Module:   module,
+					Route:    entry.method + " " + path,
+					Message:  fmt.Sprintf("references schema %s, which an OnSchema hook skipped", skipped),
+				})
+			}
+		}
+		spec.Paths[path] = pathItem
+	}
+}
+
+// dropSchemaRefs returns a schema mapper removing references to skipped schemas,
+// appending the names it removes to dropped
+func dropSchemaRefs(skipped map[string]bool, dropped *[]string) func(map[string]interface{}) map[string]interface{} {
+	return func(schema map[string]interface{}) map[string]interface{} {
+		kept, _ := dropRefs(schema, skipped, dropped).(map[string]interface{})
+		return kept
+	}
+}
+
+// dropRefs returns a copy of a schema value without $refs or discriminator mapping
+// entries naming a skipped schema, appending the names it removes to dropped
+func dropRefs(value interface{}, skipped map[string]bool, dropped *[]string) interface{} {
+	isSkipped := func(ref string) bool {
+		name, ok := schemaRefName(ref)
+		if ok && skipped[name] {
+			*dropped = append(*dropped, name)
+		}
+		return ok && skipped[name]
+	}
+
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		kept := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				if !isSkipped(ref) {
+					kept[key] = ref
+				}
+				continue
+			}
+			if discriminator, ok := child.(map[string]interface{}); ok && key == "discriminator" {
+				kept[key] = renameMapping(discriminator, func(ref string) string {
+					if isSkipped(ref) {
+						return ""
+					}
+					return ref
+				})
+				continue
+			}
+			kept[key] = dropRefs(child, skipped, dropped)
+		}
+		return kept
+	case []interface{}:
+		kept := make([]interface{}, len(v))
+		for i, child := range v {
+			kept[i] = dropRefs(child, skipped, dropped)
+		}
+		return kept
+	default:
+		return value
+	}
+}
+
+// compactNames returns names sorted without duplicates
+func compactNames(names []string) []string {
+	slices.Sort(names)
+	return slices.Compact(names)
+}
+
+// mapParameterSchemas returns params with their schemas replaced by mapSchema's result
+func mapParameterSchemas(params []Parameter, mapSchema func(map[string]interface{}) map[string]interface{}) []Parameter {
+	for i := range params {
+		if params[i].Schema != nil {
+			params[i].Schema = mapSchema(params[i].Schema)
+		}
+	}
+	return params
+}
+
+// mapContentSchemas returns content with its schemas replaced by mapSchema's result.
+// A result holding only a $ref is stored as a reference.
+func mapContentSchemas(content map[string]MediaTypeObject, mapSchema func(map[string]interface{}) map[string]interface{}) map[string]MediaTypeObject {
+	for mediaType, media := range content {
+		if media.Schema.Ref == "" && media.Schema.Inline == nil {
+			continue
+		}
+		schema := mapSchema(schemaRefMap(media.Schema))
+		if ref, ok := schema["$ref"].(string); ok && len(schema) == 1 {
+			media.Schema = SchemaRef{Ref: ref}
+		} else {
+			media.Schema = SchemaRef{Inline: schema}
+		}
+		content[mediaType] = media
+	}
+	return content
+}
+
+// rewriteRefs returns a schema mapper replacing every $ref with rewrite's result
+func rewriteRefs(rewrite func(ref string) string) func(map[string]interface{}) map[string]interface{} {
+	return func(schema map[string]interface{}) map[string]interface{} {
+		renamed, _ := renameRefs(schema, rewrite).(map[string]interface{})
+		return renamed
+	}
+}
+
+// renameRefs returns a copy of a schema value with every $ref, and every
+// discriminator mapping value, replaced by rewrite. Schemas share nested maps with
+// the schema cache, so they are never edited in place.
+func renameRefs(value interface{}, rewrite func(ref string) string) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if v == nil {
+			return v
+		}
+		renamed := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				renamed[key] = rewrite(ref)
+				continue
+			}
+			if discriminator, ok := child.(map[string]interface{}); ok && key == "discriminator" {
+				renamed[key] = renameMapping(discriminator, rewrite)
+				continue
+			}
+			renamed[key] = renameRefs(child, rewrite)
+		}
+		return renamed
+	case []interface{}:
+		renamed := make([]interface{}, len(v))
+		for i, child := range v {
+			renamed[i] = renameRefs(child, rewrite)
+		}
+		return renamed
+	default:
+		return value
+	}
+}
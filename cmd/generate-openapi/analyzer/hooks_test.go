@@ -0,0 +1,143 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"gopkg.in/yaml.v3"
+)
+
+func hookRoutes() []types.RouteInfo {
+	return []types.RouteInfo{
+		{Method: "POST", Path: "/users", RequestType: reflect.TypeOf(TestRequest{}), ResponseType: reflect.TypeOf(User{}), Module: "users", Summary: "Create user"},
+		{Method: "GET", Path: "/users/{id}", ResponseType: reflect.TypeOf(User{}), Module: "users", Summary: "Get user"},
+		{Method: "GET", Path: "/debug/dump", ResponseType: reflect.TypeOf(debugDump{}), Module: "debug", Summary: "Dump state"},
+	}
+}
+
+func generateWithHooks(t *testing.T, gen *Generator) OpenAPISpec {
+	t.Helper()
+	out, err := gen.GenerateSpecForRoutes(hookRoutes())
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(out), &spec); err != nil {
+		t.Fatalf("Generated spec is not valid YAML: %v", err)
+	}
+	return spec
+}
+
+func TestHooks_OperationAddsHeaderAndSkipsRoute(t *testing.T) {
+	gen := NewGenerator()
+	tenant := Parameter{Name: "X-Tenant-ID", In: "header", Required: true, Schema: map[string]interface{}{"type": "string"}}
+
+	var order []string
+	gen.OnOperation(func(route types.RouteInfo, op *Operation) bool {
+		order = append(order, "tenant")
+		op.Parameters = append(op.Parameters, tenant)
+		return false
+	})
+	gen.OnOperation(func(route types.RouteInfo, op *Operation) bool {
+		order = append(order, "debug")
+		return route.Module == "debug"
+	})
+
+	spec := generateWithHooks(t, gen)
+
+	if _, exists := spec.Paths["/debug/dump"]; exists {
+		t.Error("A skipped operation should be left out of the paths")
+	}
+	if _, exists := spec.Components.Schemas["debugDump"]; exists {
+		t.Error("Schemas only a skipped operation used should be pruned")
+	}
+	for path, pathItem := range spec.Paths {
+		for _, op := range pathItem.Operations() {
+			if !hasParameter(op.Parameters, tenant) {
+				t.Errorf("%s %s is missing the X-Tenant-ID header", path, op.OperationID)
+			}
+		}
+	}
+	if strings.Join(order[:2], ",") != "tenant,debug" {
+		t.Errorf("Hooks should run in registration order, got %v", order)
+	}
+}
+
+func TestHooks_RenameSchema(t *testing.T) {
+	gen := NewGenerator()
+	gen.OnSpec(func(spec *OpenAPISpec) {
+		spec.RenameSchema("User", "Account")
+	})
+
+	spec := generateWithHooks(t, gen)
+
+	if _, exists := spec.Components.Schemas["User"]; exists {
+		t.Error("The old schema name should be gone")
+	}
+	if _, exists := spec.Components.Schemas["Account"]; !exists {
+		t.Fatal("The renamed schema should be present")
+	}
+	for _, op := range []*Operation{spec.Paths["/users"].Post, spec.Paths["/users/{id}"].Get} {
+		if ref := op.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/Account" {
+			t.Errorf("%s response should reference Account, got %q", op.OperationID, ref)
+		}
+	}
+}
+
+func TestHooks_Schema(t *testing.T) {
+	gen := NewGenerator()
+
+	seen := make(map[string]reflect.Type)
+	gen.OnSchema(func(typ reflect.Type, name string, schema map[string]interface{}) bool {
+		seen[name] = typ
+		schema["x-internal"] = name == "debugDump"
+		return name == "TestRequest"
+	})
+
+	spec := generateWithHooks(t, gen)
+
+	if seen["User"] != reflect.TypeOf(User{}) || seen["ErrorResponse"] != nil {
+		t.Errorf("Hooks should receive the schema's type, nil for synthesized schemas, got %v", seen)
+	}
+	if _, exists := spec.Components.Schemas["TestRequest"]; exists {
+		t.Error("A skipped schema should be left out of the components")
+	}
+	if dump := spec.Components.Schemas["debugDump"].(map[string]interface{}); dump["x-internal"] != true {
+		t.Errorf("Expected the hook's x-internal flag, got %v", dump)
+	}
+}
+
+func TestHooks_SchemaSeesUnionVariants(t *testing.T) {
+	ClearUnions()
+	t.Cleanup(ClearUnions)
+	RegisterUnion(reflect.TypeOf((*Shape)(nil)).Elem(), reflect.TypeOf(TextShape{}), reflect.TypeOf(ImageShape{}))
+
+	gen := NewGenerator()
+	seen := make(map[string]int)
+	gen.OnSchema(func(typ reflect.Type, name string, schema map[string]interface{}) bool {
+		seen[name]++
+		return name == "ImageShape"
+	})
+
+	routes := []types.RouteInfo{{Method: "POST", Path: "/messages", RequestType: reflect.TypeOf(ChatMessage{}), ResponseType: reflect.TypeOf(ChatMessage{}), Module: "messages", Summary: "Send message"}}
+	out, err := gen.GenerateSpecForRoutes(routes)
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	if seen["TextShape"] != 1 || seen["ImageShape"] != 1 {
+		t.Errorf("Schema hooks should run once for each union variant, got %v", seen)
+	}
+	if strings.Contains(out, componentSchemasRef+"ImageShape") {
+		t.Errorf("References to a skipped schema should be dropped, got:\n%s", out)
+	}
+	var reported bool
+	for _, warning := range gen.Warnings() {
+		reported = reported || (warning.Category == WarningSkippedSchema && strings.Contains(warning.Message, "ImageShape"))
+	}
+	if !reported {
+		t.Errorf("Expected a %s warning naming ImageShape, got %v", WarningSkippedSchema, gen.Warnings())
+	}
+}
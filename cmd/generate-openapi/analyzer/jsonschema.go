@@ -0,0 +1,142 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+// JSONSchemaDraft07 is the $schema of documents built by GenerateJSONSchema
+const JSONSchemaDraft07 = "http://json-schema.org/draft-07/schema#"
+
+// jsonSchemaDefinitionsRef prefixes $refs to a JSON Schema document's definitions
+const jsonSchemaDefinitionsRef = "#/definitions/"
+
+// GenerateJSONSchema returns a standalone JSON Schema (Draft-07) document for t,
+// suitable for validating payloads at runtime. It uses the same schemas as the
+// OpenAPI spec, except that circular references and named union variants point
+// into the document's definitions, and OpenAPI-only keywords are translated:
+// example becomes examples, nullable becomes a "null" type and discriminator is
+// dropped. definitions is omitted when nothing references it.
+func (g *Generator) GenerateJSONSchema(t reflect.Type) (map[string]interface{}, error) {
+	refs := make(map[string]reflect.Type)
+	state := newSchemaState()
+	state.refs = refs
+
+	root, err := g.generateSchemaForType(t, state)
+	if err != nil {
+		return nil, fmt.Errorf("type %v: %w", t, err)
+	}
+
+	definitions := make(map[string]interface{})
+	document := g.toDraft07(root, definitions)
+
+	// Generating a definition can reach further circular types, so repeat until
+	// every recorded type has one
+	for {
+		pending := make([]string, 0, len(refs))
+		for name := range refs {
+			if _, done := definitions[name]; !done {
+				pending = append(pending, name)
+			}
+		}
+		if len(pending) == 0 {
+			break
+		}
+		sort.Strings(pending)
+
+		for _, name := range pending {
+			state := newSchemaState()
+			state.refs = refs
+			schema, err := g.generateSchemaForType(refs[name], state)
+			if err != nil {
+				return nil, fmt.Errorf("type %v: %w", refs[name], err)
+			}
+			definitions[name] = g.toDraft07(schema, definitions)
+		}
+	}
+
+	document["$schema"] = JSONSchemaDraft07
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Name() != "" {
+		document["title"] = g.getTypeName(t)
+	}
+	if len(definitions) > 0 {
+		document["definitions"] = definitions
+	}
+	return document, nil
+}
+
+// toDraft07 returns a copy of an OpenAPI schema rewritten for JSON Schema
+// Draft-07. Component $refs are pointed at definitions, which are filled in from
+// the generator's component schemas as they are found.
+func (g *Generator) toDraft07(schema map[string]interface{}, definitions map[string]interface{}) map[string]interface{} {
+	converted := make(map[string]interface{}, len(schema))
+	for key, value := range schema {
+		switch key {
+		case "discriminator":
+			// Draft-07 has no discriminator; oneOf alone still validates
+		case "nullable":
+			// Handled with type below
+		case "example":
+			converted["examples"] = []interface{}{value}
+		case "$ref":
+			converted[key] = g.draft07Ref(value, definitions)
+		case "properties":
+			properties := make(map[string]interface{})
+			for name, property := range value.(map[string]interface{}) {
+				properties[name] = g.toDraft07Value(property, definitions)
+			}
+			converted[key] = properties
+		default:
+			converted[key] = g.toDraft07Value(value, definitions)
+		}
+	}
+
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		if typ, ok := schema["type"].(string); ok {
+			converted["type"] = []interface{}{typ, "null"}
+		}
+	}
+	return converted
+}
+
+// toDraft07Value converts the nested schemas within a schema keyword's value
+func (g *Generator) toDraft07Value(value interface{}, definitions map[string]interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		return g.toDraft07(v, definitions)
+	case []interface{}:
+		items := make([]interface{}, len(v))
+		for i, item := range v {
+			items[i] = g.toDraft07Value(item, definitions)
+		}
+		return items
+	default:
+		return value
+	}
+}
+
+// draft07Ref rewrites a component $ref to a definitions $ref, adding the
+// component schema to definitions the first time it is referenced
+func (g *Generator) draft07Ref(value interface{}, definitions map[string]interface{}) interface{} {
+	ref, ok := value.(string)
+	if !ok {
+		return value
+	}
+	name, ok := schemaRefName(ref)
+	if !ok {
+		return ref
+	}
+
+	if _, done := definitions[name]; !done {
+		if component, exists := g.componentSchema(name); exists {
+			// Reserve the name first so self-referencing components terminate
+			definitions[name] = nil
+			definitions[name] = g.toDraft07(component, definitions)
+		}
+	}
+	return jsonSchemaDefinitionsRef + name
+}
@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type jsonSchemaNode struct {
+	Value    string            `json:"value" example:"root"`
+	Children []*jsonSchemaNode `json:"children,omitempty"`
+}
+
+type jsonSchemaText struct {
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
+type jsonSchemaImage struct {
+	Kind string `json:"kind"`
+	URL  string `json:"url"`
+}
+
+type jsonSchemaPart interface{}
+
+type jsonSchemaMessage struct {
+	Part jsonSchemaPart `json:"part" discriminator:"kind"`
+}
+
+// checkDraft07 fails the test if document is not a self-contained Draft-07
+// document: every $ref must resolve and no OpenAPI-only keyword may remain
+func checkDraft07(t *testing.T, document map[string]interface{}) {
+	t.Helper()
+
+	if document["$schema"] != JSONSchemaDraft07 {
+		t.Errorf("$schema = %v, want %s", document["$schema"], JSONSchemaDraft07)
+	}
+	if _, err := json.Marshal(document); err != nil {
+		t.Fatalf("Document is not JSON-encodable: %v", err)
+	}
+
+	definitions, _ := document["definitions"].(map[string]interface{})
+	var walk func(path string, value interface{})
+	walk = func(path string, value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key, child := range v {
+				switch key {
+				case "nullable", "example", "discriminator":
+					t.Errorf("%s: OpenAPI keyword %q is not valid Draft-07", path, key)
+				case "$ref":
+					name := strings.TrimPrefix(child.(string), jsonSchemaDefinitionsRef)
+					if _, ok := definitions[name]; !ok || name == child {
+						t.Errorf("%s: $ref %v does not resolve", path, child)
+					}
+				}
+				walk(path+"."+key, child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(path+"[]", child)
+			}
+		}
+	}
+	walk("$", document)
+}
+
+func TestGenerateJSONSchema(t *testing.T) {
+	gen := NewGenerator()
+
+	document, err := gen.GenerateJSONSchema(reflect.TypeOf(TestRequest{}))
+	if err != nil {
+		t.Fatalf("GenerateJSONSchema() error = %v", err)
+	}
+	checkDraft07(t, document)
+
+	if document["type"] != "object" || document["title"] != "TestRequest" {
+		t.Errorf("Unexpected document %v", document)
+	}
+	if _, ok := document["definitions"]; ok {
+		t.Error("definitions should be omitted when nothing references it")
+	}
+	properties := document["properties"].(map[string]interface{})
+	for _, name := range []string{"name", "count", "enabled", "optional_val"} {
+		if _, ok := properties[name]; !ok {
+			t.Errorf("Expected property %s", name)
+		}
+	}
+	if required := document["required"].([]string); !reflect.DeepEqual(required, []string{"name", "count", "enabled"}) {
+		t.Errorf("required = %v", required)
+	}
+}
+
+func TestGenerateJSONSchema_CircularReference(t *testing.T) {
+	gen := NewGenerator()
+
+	document, err := gen.GenerateJSONSchema(reflect.TypeOf(&jsonSchemaNode{}))
+	if err != nil {
+		t.Fatalf("GenerateJSONSchema() error = %v", err)
+	}
+	checkDraft07(t, document)
+
+	children := document["properties"].(map[string]interface{})["children"].(map[string]interface{})
+	if ref := children["items"].(map[string]interface{})["$ref"]; ref != "#/definitions/jsonSchemaNode" {
+		t.Errorf("Circular items $ref = %v", ref)
+	}
+	value := document["properties"].(map[string]interface{})["value"].(map[string]interface{})
+	if !reflect.DeepEqual(value["examples"], []interface{}{"root"}) {
+		t.Errorf("example should become examples, got %v", value)
+	}
+
+	// The OpenAPI schema for the same type is unchanged
+	schema, _ := gen.generateTypeSchema(reflect.TypeOf(jsonSchemaNode{}))
+	items := schema["properties"].(map[string]interface{})["children"].(map[string]interface{})["items"].(map[string]interface{})
+	if _, ok := items["$ref"]; ok {
+		t.Error("OpenAPI schemas should keep inlining circular references")
+	}
+}
+
+func TestGenerateJSONSchema_UnionVariants(t *testing.T) {
+	ClearUnions()
+	t.Cleanup(ClearUnions)
+	RegisterUnion(reflect.TypeOf((*jsonSchemaPart)(nil)), reflect.TypeOf(jsonSchemaText{}), reflect.TypeOf(jsonSchemaImage{}))
+
+	gen := NewGenerator()
+	document, err := gen.GenerateJSONSchema(reflect.TypeOf(jsonSchemaMessage{}))
+	if err != nil {
+		t.Fatalf("GenerateJSONSchema() error = %v", err)
+	}
+	checkDraft07(t, document)
+
+	definitions := document["definitions"].(map[string]interface{})
+	for _, name := range []string{"jsonSchemaText", "jsonSchemaImage"} {
+		if _, ok := definitions[name]; !ok {
+			t.Errorf("Expected union variant %s in definitions", name)
+		}
+	}
+}
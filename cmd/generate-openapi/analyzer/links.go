@@ -0,0 +1,101 @@
+package analyzer
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// WarningLink reports a response link to an unknown operation or one whose name
+// is already used by a different link
+const WarningLink = "link"
+
+// Link describes an operation reachable with values from a response
+type Link struct {
+	OperationID string            `yaml:"operationId,omitempty"`
+	Parameters  map[string]string `yaml:"parameters,omitempty"`
+	Description string            `yaml:"description,omitempty"`
+}
+
+// LinkRef is a reference to a component link, or an inline link when its name is
+// taken by a different link
+type LinkRef struct {
+	Ref  string `yaml:"$ref,omitempty"`
+	Link `yaml:",inline"`
+}
+
+// buildLinks converts a route's response links to inline spec links
+func buildLinks(links map[string]types.Link) map[string]LinkRef {
+	if len(links) == 0 {
+		return nil
+	}
+
+	built := make(map[string]LinkRef, len(links))
+	for name, link := range links {
+		built[name] = LinkRef{Link: Link{
+			OperationID: link.OperationID,
+			Parameters:  link.Parameters,
+			Description: link.Description,
+		}}
+	}
+	return built
+}
+
+// hoistLinks moves the response links of every operation into components.links,
+// replacing each with a reference, and returns the component links. Links to
+// operations missing from paths are reported; a name reused for a different link
+// is reported and that link stays inline.
+func (g *Generator) hoistLinks(paths map[string]PathItem) map[string]Link {
+	operationIDs := make(map[string]bool)
+	for _, pathItem := range paths {
+		for _, op := range pathItem.Operations() {
+			operationIDs[op.OperationID] = true
+		}
+	}
+
+	components := make(map[string]Link)
+	for _, path := range slices.Sorted(maps.Keys(paths)) {
+		pathItem := paths[path]
+		for _, entry := range pathItem.methodOperations() {
+			route := entry.method + " " + path
+			for _, status := range slices.Sorted(maps.Keys(entry.op.Responses)) {
+				links := entry.op.Responses[status].Links
+				for _, name := range slices.Sorted(maps.Keys(links)) {
+					link := links[name].Link
+					if !operationIDs[link.OperationID] {
+						g.linkWarning(entry.op, route, fmt.Sprintf("link %s targets unknown operation %q", name, link.OperationID))
+					}
+
+					if existing, taken := components[name]; taken && !reflect.DeepEqual(existing, link) {
+						g.linkWarning(entry.op, route, fmt.Sprintf("link name %s is already used by a different link; keeping it inline", name))
+						continue
+					}
+					components[name] = link
+					links[name] = LinkRef{Ref: "#/components/links/" + name}
+				}
+			}
+		}
+	}
+
+	if len(components) == 0 {
+		return nil
+	}
+	return components
+}
+
+// linkWarning records a WarningLink for an operation
+func (g *Generator) linkWarning(op *Operation, route, message string) {
+	module := ""
+	if len(op.Tags) > 0 {
+		module = op.Tags[0]
+	}
+	g.warnings = append(g.warnings, Warning{
+		Category: WarningLink,
+		Module:   module,
+		Route:    route,
+		Message:  message,
+	})
+}
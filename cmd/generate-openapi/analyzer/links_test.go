@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// linkRoutes returns a createUser route linking its response to getUser
+func linkRoutes() []types.RouteInfo {
+	return []types.RouteInfo{
+		{
+			Method: "POST", Path: "/users", RequestType: reflect.TypeOf(TestRequest{}), ResponseType: reflect.TypeOf(User{}), Module: "users",
+			ResponseLinks: map[string]types.Link{
+				"GetUserByID": {
+					OperationID: "getUser",
+					Parameters:  map[string]string{"id": "$response.body#/id"},
+					Description: "The id returned by createUser can be passed to getUser",
+				},
+			},
+		},
+		{Method: "GET", Path: "/users/{id}", ResponseType: reflect.TypeOf(User{}), Module: "users"},
+	}
+}
+
+// namedOperations sets the createUser and getUser operation ids used by linkRoutes
+func namedOperations(route types.RouteInfo, op *Operation) bool {
+	if route.Method == "POST" {
+		op.OperationID = "createUser"
+	} else {
+		op.OperationID = "getUser"
+	}
+	return false
+}
+
+func TestResponseLinks_CreateUserToGetUser(t *testing.T) {
+	gen := NewGenerator()
+	gen.OnOperation(namedOperations)
+
+	out, err := gen.GenerateSpecForRoutes(linkRoutes())
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	expectedResponse := `                "200":
+                    description: Success
+                    content:
+                        application/json:
+                            schema:
+                                $ref: '#/components/schemas/User'
+                    links:
+                        GetUserByID:
+                            $ref: '#/components/links/GetUserByID'
+`
+	if !strings.Contains(out, expectedResponse) {
+		t.Errorf("Expected the createUser 200 response to reference the link, got:\n%s", out)
+	}
+
+	expectedComponent := `    links:
+        GetUserByID:
+            operationId: getUser
+            parameters:
+                id: $response.body#/id
+            description: The id returned by createUser can be passed to getUser
+`
+	if !strings.Contains(out, expectedComponent) {
+		t.Errorf("Expected components.links to define GetUserByID, got:\n%s", out)
+	}
+
+	for _, warning := range gen.Warnings() {
+		if warning.Category == WarningLink {
+			t.Errorf("Unexpected link warning: %+v", warning)
+		}
+	}
+}
+
+func TestResponseLinks_UnknownOperation(t *testing.T) {
+	gen := NewGenerator()
+	if _, err := gen.GenerateSpecForRoutes(linkRoutes()); err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	var warned bool
+	for _, warning := range gen.Warnings() {
+		if warning.Category == WarningLink && warning.Route == "POST /users" && strings.Contains(warning.Message, `unknown operation "getUser"`) {
+			warned = true
+		}
+	}
+	if !warned {
+		t.Errorf("Expected a link warning for the unknown getUser operation, got %+v", gen.Warnings())
+	}
+}
+
+func TestHoistLinks_ConflictingNameStaysInline(t *testing.T) {
+	routes := linkRoutes()
+	routes = append(routes, types.RouteInfo{
+		Method: "PUT", Path: "/users/{id}", RequestType: reflect.TypeOf(TestRequest{}), ResponseType: reflect.TypeOf(User{}), Module: "users",
+		ResponseLinks: map[string]types.Link{
+			"GetUserByID": {OperationID: "getUser", Parameters: map[string]string{"id": "$request.path.id"}},
+		},
+	})
+
+	gen := NewGenerator()
+	gen.OnOperation(namedOperations)
+	gen.OnOperation(func(route types.RouteInfo, op *Operation) bool {
+		if route.Method == "PUT" {
+			op.OperationID = "updateUser"
+		}
+		return false
+	})
+	if err := gen.LoadRoutes(routes); err != nil {
+		t.Fatalf("LoadRoutes() error = %v", err)
+	}
+
+	paths := gen.buildPaths()
+	components := gen.hoistLinks(paths)
+
+	if len(components) != 1 || components["GetUserByID"].Parameters["id"] != "$response.body#/id" {
+		t.Errorf("Expected the first GetUserByID link in components, got %+v", components)
+	}
+	inline := paths["/users/{id}"].Put.Responses["200"].Links["GetUserByID"]
+	if inline.Ref != "" || inline.Parameters["id"] != "$request.path.id" {
+		t.Errorf("Expected the conflicting link to stay inline, got %+v", inline)
+	}
+	if len(gen.warnings) != 1 || gen.warnings[0].Category != WarningLink {
+		t.Errorf("Expected one link warning for the name conflict, got %+v", gen.warnings)
+	}
+}
@@ -0,0 +1,245 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Warning categories reported by the lint pass
+const (
+	WarningMissingSummary    = "missing-summary"
+	WarningUndocumentedField = "undocumented-field"
+	WarningSingleRouteModule = "single-route-module"
+	WarningPropertyNaming    = "property-naming"
+	WarningUntypedResponse   = "untyped-response"
+	WarningSupersededVersion = "superseded-version"
+	WarningInvalidSunset     = "invalid-sunset"
+	WarningReservedHeader    = "reserved-header"
+)
+
+// snakeCasePattern matches the lower snake_case JSON property names used across the API
+var snakeCasePattern = regexp.MustCompile(`^[a-z][a-z0-9]*(_[a-z0-9]+)*$`)
+
+// Warning describes a spec quality issue found during generation
+type Warning struct {
+	Category string // One of the Warning* categories
+	Module   string // Module the offending route belongs to
+	Route    string // Offending route as "METHOD /path", empty for module-level warnings
+	Message  string // Human-readable description of the issue
+}
+
+// String formats the warning for display
+func (w Warning) String() string {
+	if w.Route == "" {
+		return fmt.Sprintf("[%s] %s", w.Category, w.Message)
+	}
+	return fmt.Sprintf("[%s] %s: %s", w.Category, w.Route, w.Message)
+}
+
+// Warnings returns the quality warnings collected by the last generation
+func (g *Generator) Warnings() []Warning {
+	return g.warnings
+}
+
+// WarningsByModule groups warnings by module, returning the sorted module names alongside
+func WarningsByModule(warnings []Warning) ([]string, map[string][]Warning) {
+	grouped := make(map[string][]Warning)
+	for _, w := range warnings {
+		grouped[w.Module] = append(grouped[w.Module], w)
+	}
+
+	modules := make([]string, 0, len(grouped))
+	for module := range grouped {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	return modules, grouped
+}
+
+// lint inspects the loaded routes for common documentation quality issues
+func (g *Generator) lint() []Warning {
+	var warnings []Warning
+	moduleRoutes := make(map[string]int)
+
+	for _, route := range g.routes {
+		moduleRoutes[route.Module]++
+		routeName := fmt.Sprintf("%s %s", strings.ToUpper(route.Method), route.Path)
+
+		if strings.TrimSpace(route.Summary) == "" {
+			warnings = append(warnings, Warning{
+				Category: WarningMissingSummary,
+				Module:   route.Module,
+				Route:    routeName,
+				Message:  "route has no summary",
+			})
+		}
+
+		if route.RequestType != nil {
+			for _, field := range undocumentedFields(route.RequestType) {
+				warnings = append(warnings, Warning{
+					Category: WarningUndocumentedField,
+					Module:   route.Module,
+					Route:    routeName,
+					Message:  fmt.Sprintf("request field %s has no description or example tag", field),
+				})
+			}
+		}
+
+		for _, t := range []reflect.Type{route.RequestType, route.ResponseType} {
+			if t == nil {
+				continue
+			}
+			for _, property := range misnamedProperties(t) {
+				warnings = append(warnings, Warning{
+					Category: WarningPropertyNaming,
+					Module:   route.Module,
+					Route:    routeName,
+					Message:  fmt.Sprintf("property %s in %s is not snake_case", property, g.getTypeName(t)),
+				})
+			}
+		}
+
+		if route.SunsetDate != "" {
+			if _, err := time.Parse(time.DateOnly, route.SunsetDate); err != nil {
+				warnings = append(warnings, Warning{
+					Category: WarningInvalidSunset,
+					Module:   route.Module,
+					Route:    routeName,
+					Message:  fmt.Sprintf("sunset date %q is not an RFC 3339 full-date (YYYY-MM-DD)", route.SunsetDate),
+				})
+			}
+		}
+
+		for _, header := range route.HeaderParams {
+			if isReservedHeader(header.Name) {
+				warnings = append(warnings, Warning{
+					Category: WarningReservedHeader,
+					Module:   route.Module,
+					Route:    routeName,
+					Message:  fmt.Sprintf("header parameter %s is left out of the spec; OpenAPI ignores it as a parameter", header.Name),
+				})
+			}
+		}
+
+		if !route.Deprecated && strings.HasPrefix(route.Path, "/v1/") {
+			successor := "/v2/" + strings.TrimPrefix(route.Path, "/v1/")
+			if g.hasRoute(route.Method, successor) {
+				warnings = append(warnings, Warning{
+					Category: WarningSupersededVersion,
+					Module:   route.Module,
+					Route:    routeName,
+					Message:  fmt.Sprintf("route is superseded by %s but is not marked deprecated", successor),
+				})
+			}
+		}
+
+		if route.ResponseType != nil && isUntypedMap(route.ResponseType) {
+			warnings = append(warnings, Warning{
+				Category: WarningUntypedResponse,
+				Module:   route.Module,
+				Route:    routeName,
+				Message:  "response type is a bare map[string]interface{}; define a struct instead",
+			})
+		}
+	}
+
+	modules := make([]string, 0, len(moduleRoutes))
+	for module := range moduleRoutes {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+
+	for _, module := range modules {
+		if moduleRoutes[module] == 1 {
+			warnings = append(warnings, Warning{
+				Category: WarningSingleRouteModule,
+				Module:   module,
+				Message:  fmt.Sprintf("module %s has only one route; consider merging its tag", module),
+			})
+		}
+	}
+
+	return warnings
+}
+
+// hasRoute reports whether a route with method and path is loaded
+func (g *Generator) hasRoute(method, path string) bool {
+	for _, route := range g.routes {
+		if route.Path == path && strings.EqualFold(route.Method, method) {
+			return true
+		}
+	}
+	return false
+}
+
+// undocumentedFields returns the JSON paths of struct fields without description or example tags
+func undocumentedFields(t reflect.Type) []string {
+	var fields []string
+	walkStructFields(t, "", make(map[reflect.Type]bool), func(path string, field reflect.StructField) {
+		if field.Tag.Get("description") == "" && field.Tag.Get("example") == "" {
+			fields = append(fields, path)
+		}
+	})
+	return fields
+}
+
+// misnamedProperties returns the JSON paths of properties that are not snake_case
+func misnamedProperties(t reflect.Type) []string {
+	var properties []string
+	walkStructFields(t, "", make(map[reflect.Type]bool), func(path string, field reflect.StructField) {
+		name := path[strings.LastIndex(path, ".")+1:]
+		if !snakeCasePattern.MatchString(name) {
+			properties = append(properties, path)
+		}
+	})
+	return properties
+}
+
+// walkStructFields calls fn for every JSON-visible field reachable from t
+func walkStructFields(t reflect.Type, prefix string, visited map[reflect.Type]bool, fn func(path string, field reflect.StructField)) {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || (t.PkgPath() == "time" && t.Name() == "Time") || visited[t] {
+		return
+	}
+	visited[t] = true
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		jsonTag := field.Tag.Get("json")
+		if jsonTag == "-" {
+			continue
+		}
+
+		name := field.Name
+		if tagName := strings.Split(jsonTag, ",")[0]; tagName != "" {
+			name = tagName
+		}
+
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+
+		fn(path, field)
+		walkStructFields(field.Type, path, visited, fn)
+	}
+}
+
+// isUntypedMap reports whether t is a map with interface{} values
+func isUntypedMap(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Map && t.Elem().Kind() == reflect.Interface
+}
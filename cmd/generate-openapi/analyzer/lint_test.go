@@ -0,0 +1,179 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+type lintDocumentedRequest struct {
+	Name string `json:"name" description:"Display name"`
+}
+
+type lintUndocumentedRequest struct {
+	Name string `json:"name"`
+}
+
+type lintCamelCaseResponse struct {
+	UserName string `json:"userName"`
+}
+
+type lintSnakeCaseResponse struct {
+	UserName string `json:"user_name"`
+}
+
+// warningsFor generates a spec for routes and returns the warnings of a single category
+func warningsFor(t *testing.T, routes []types.RouteInfo, category string) []Warning {
+	t.Helper()
+
+	gen := NewGenerator()
+	if _, err := gen.GenerateSpecForRoutes(routes); err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	var matched []Warning
+	for _, w := range gen.Warnings() {
+		if w.Category == category {
+			matched = append(matched, w)
+		}
+	}
+	return matched
+}
+
+func TestLint_MissingSummary(t *testing.T) {
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/a", ResponseType: reflect.TypeOf(lintSnakeCaseResponse{}), Module: "m"},
+		{Method: "GET", Path: "/b", ResponseType: reflect.TypeOf(lintSnakeCaseResponse{}), Module: "m", Summary: "B"},
+	}
+
+	warnings := warningsFor(t, routes, WarningMissingSummary)
+	if len(warnings) != 1 || warnings[0].Route != "GET /a" {
+		t.Errorf("Expected one missing-summary warning for GET /a, got %v", warnings)
+	}
+}
+
+func TestLint_UndocumentedField(t *testing.T) {
+	routes := []types.RouteInfo{
+		{Method: "POST", Path: "/a", RequestType: reflect.TypeOf(lintUndocumentedRequest{}), Module: "m", Summary: "A"},
+		{Method: "POST", Path: "/b", RequestType: reflect.TypeOf(lintDocumentedRequest{}), Module: "m", Summary: "B"},
+	}
+
+	warnings := warningsFor(t, routes, WarningUndocumentedField)
+	if len(warnings) != 1 || warnings[0].Route != "POST /a" {
+		t.Errorf("Expected one undocumented-field warning for POST /a, got %v", warnings)
+	}
+}
+
+func TestLint_SingleRouteModule(t *testing.T) {
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/a", Module: "lonely", Summary: "A"},
+		{Method: "GET", Path: "/b", Module: "busy", Summary: "B"},
+		{Method: "GET", Path: "/c", Module: "busy", Summary: "C"},
+	}
+
+	warnings := warningsFor(t, routes, WarningSingleRouteModule)
+	if len(warnings) != 1 || warnings[0].Module != "lonely" {
+		t.Errorf("Expected one single-route-module warning for 'lonely', got %v", warnings)
+	}
+}
+
+func TestLint_PropertyNaming(t *testing.T) {
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/a", ResponseType: reflect.TypeOf(lintCamelCaseResponse{}), Module: "m", Summary: "A"},
+		{Method: "GET", Path: "/b", ResponseType: reflect.TypeOf(lintSnakeCaseResponse{}), Module: "m", Summary: "B"},
+	}
+
+	warnings := warningsFor(t, routes, WarningPropertyNaming)
+	if len(warnings) != 1 || warnings[0].Route != "GET /a" {
+		t.Errorf("Expected one property-naming warning for GET /a, got %v", warnings)
+	}
+}
+
+func TestLint_UntypedResponse(t *testing.T) {
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/a", ResponseType: reflect.TypeOf(map[string]interface{}{}), Module: "m", Summary: "A"},
+		{Method: "GET", Path: "/b", ResponseType: reflect.TypeOf(lintSnakeCaseResponse{}), Module: "m", Summary: "B"},
+	}
+
+	warnings := warningsFor(t, routes, WarningUntypedResponse)
+	if len(warnings) != 1 || warnings[0].Route != "GET /a" {
+		t.Errorf("Expected one untyped-response warning for GET /a, got %v", warnings)
+	}
+}
+
+func TestWarningsByModule(t *testing.T) {
+	warnings := []Warning{
+		{Category: WarningMissingSummary, Module: "zeta"},
+		{Category: WarningMissingSummary, Module: "alpha"},
+		{Category: WarningUntypedResponse, Module: "zeta"},
+	}
+
+	modules, grouped := WarningsByModule(warnings)
+	if !reflect.DeepEqual(modules, []string{"alpha", "zeta"}) {
+		t.Errorf("Expected sorted modules [alpha zeta], got %v", modules)
+	}
+	if len(grouped["zeta"]) != 2 {
+		t.Errorf("Expected 2 warnings for 'zeta', got %d", len(grouped["zeta"]))
+	}
+}
+
+func TestGenerateTypeSchema_DescriptionAndExampleTags(t *testing.T) {
+	gen := NewGenerator()
+
+	type Tagged struct {
+		Name string `json:"name" description:"Display name" example:"Ada"`
+	}
+
+	schema, err := gen.generateTypeSchema(reflect.TypeOf(Tagged{}))
+	if err != nil {
+		t.Fatalf("generateTypeSchema() error = %v", err)
+	}
+
+	name := schema["properties"].(map[string]interface{})["name"].(map[string]interface{})
+	if name["description"] != "Display name" {
+		t.Errorf("Expected description from tag, got %v", name["description"])
+	}
+	if name["example"] != "Ada" {
+		t.Errorf("Expected example from tag, got %v", name["example"])
+	}
+}
+
+func TestLint_SupersededVersion(t *testing.T) {
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/v1/users", Module: "users", Summary: "Old"},
+		{Method: "GET", Path: "/v2/users", Module: "users", Summary: "New"},
+		{Method: "GET", Path: "/v1/models", Module: "users", Summary: "Old but deprecated", Deprecated: true},
+		{Method: "GET", Path: "/v2/models", Module: "users", Summary: "New"},
+		{Method: "GET", Path: "/v1/health", Module: "users", Summary: "No successor"},
+	}
+
+	warnings := warningsFor(t, routes, WarningSupersededVersion)
+	if len(warnings) != 1 || warnings[0].Route != "GET /v1/users" {
+		t.Errorf("Expected one superseded-version warning for GET /v1/users, got %v", warnings)
+	}
+}
+
+func TestLint_InvalidSunset(t *testing.T) {
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/a", Module: "m", Summary: "A", Deprecated: true, SunsetDate: "31/12/2025"},
+		{Method: "GET", Path: "/b", Module: "m", Summary: "B", Deprecated: true, SunsetDate: "2025-12-31"},
+	}
+
+	warnings := warningsFor(t, routes, WarningInvalidSunset)
+	if len(warnings) != 1 || warnings[0].Route != "GET /a" {
+		t.Errorf("Expected one invalid-sunset warning for GET /a, got %v", warnings)
+	}
+}
+
+func TestLint_ReservedHeader(t *testing.T) {
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/a", Module: "m", Summary: "A", HeaderParams: []types.HeaderParam{{Name: "authorization", Required: true}}},
+		{Method: "GET", Path: "/b", Module: "m", Summary: "B", HeaderParams: []types.HeaderParam{{Name: "X-Org-ID", Required: true}}},
+	}
+
+	warnings := warningsFor(t, routes, WarningReservedHeader)
+	if len(warnings) != 1 || warnings[0].Route != "GET /a" {
+		t.Errorf("Expected one reserved-header warning for GET /a, got %v", warnings)
+	}
+}
@@ -0,0 +1,139 @@
+package analyzer
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// MergeSpecs combines several generated YAML specifications into one. Paths, every
+// components map and $defs are unioned; the same operation defined twice, or two
+// different components sharing a name, is an error. Info and servers come from the
+// first spec.
+func MergeSpecs(specs []string) (string, error) {
+	if len(specs) == 0 {
+		return "", fmt.Errorf("no specs provided")
+	}
+
+	var merged OpenAPISpec
+	for i, content := range specs {
+		var spec OpenAPISpec
+		if err := yaml.Unmarshal([]byte(content), &spec); err != nil {
+			return "", fmt.Errorf("spec %d: failed to parse: %w", i, err)
+		}
+
+		if i == 0 {
+			merged = OpenAPISpec{
+				OpenAPI: spec.OpenAPI,
+				Info:    spec.Info,
+				Servers: spec.Servers,
+				Paths:   make(map[string]PathItem),
+				Components: Components{
+					Schemas:         make(map[string]interface{}),
+					SecuritySchemes: make(map[string]SecurityScheme),
+					Links:           make(map[string]Link),
+				},
+				Defs: make(map[string]interface{}),
+			}
+		}
+
+		for path, item := range spec.Paths {
+			combined, err := mergePathItem(path, merged.Paths[path], item)
+			if err != nil {
+				return "", fmt.Errorf("spec %d: %w", i, err)
+			}
+			merged.Paths[path] = combined
+		}
+
+		if err := mergeComponents("schema", merged.Components.Schemas, spec.Components.Schemas); err != nil {
+			return "", fmt.Errorf("spec %d: %w", i, err)
+		}
+		if err := mergeComponents("security scheme", merged.Components.SecuritySchemes, spec.Components.SecuritySchemes); err != nil {
+			return "", fmt.Errorf("spec %d: %w", i, err)
+		}
+		if err := mergeComponents("link", merged.Components.Links, spec.Components.Links); err != nil {
+			return "", fmt.Errorf("spec %d: %w", i, err)
+		}
+		if err := mergeComponents("$defs schema", merged.Defs, spec.Defs); err != nil {
+			return "", fmt.Errorf("spec %d: %w", i, err)
+		}
+
+		merged.Tags = mergeTags(merged.Tags, spec.Tags)
+	}
+
+	yamlData, err := yaml.Marshal(merged)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal merged spec: %w", err)
+	}
+
+	return specHeader + string(yamlData), nil
+}
+
+// mergePathItem adds the operations of incoming to existing, failing if both define the same method
+func mergePathItem(path string, existing, incoming PathItem) (PathItem, error) {
+	slots := []struct {
+		method   string
+		target   **Operation
+		incoming *Operation
+	}{
+		{"GET", &existing.Get, incoming.Get},
+		{"POST", &existing.Post, incoming.Post},
+		{"PUT", &existing.Put, incoming.Put},
+		{"DELETE", &existing.Delete, incoming.Delete},
+	}
+
+	for _, slot := range slots {
+		if slot.incoming == nil {
+			continue
+		}
+		if *slot.target != nil {
+			return existing, fmt.Errorf("path conflict: %s %s is defined by more than one spec", slot.method, path)
+		}
+		*slot.target = slot.incoming
+	}
+
+	for _, param := range incoming.Parameters {
+		if !hasParameter(existing.Parameters, param) {
+			existing.Parameters = append(existing.Parameters, param)
+		}
+	}
+
+	return existing, nil
+}
+
+// mergeComponents adds incoming to merged. Identical components shared by several
+// specs collapse into one; a different component under a taken name is an error.
+func mergeComponents[T any](kind string, merged, incoming map[string]T) error {
+	for _, name := range slices.Sorted(maps.Keys(incoming)) {
+		component := incoming[name]
+		if existing, exists := merged[name]; exists {
+			if !reflect.DeepEqual(existing, component) {
+				return fmt.Errorf("%s %s conflicts with a different %s of the same name", kind, name, kind)
+			}
+			continue
+		}
+		merged[name] = component
+	}
+	return nil
+}
+
+// mergeTags unions tags by name, keeping the first description seen, sorted by name
+func mergeTags(existing, incoming []Tag) []Tag {
+	seen := make(map[string]bool, len(existing))
+	for _, tag := range existing {
+		seen[tag.Name] = true
+	}
+	for _, tag := range incoming {
+		if !seen[tag.Name] {
+			seen[tag.Name] = true
+			existing = append(existing, tag)
+		}
+	}
+
+	sort.Slice(existing, func(i, j int) bool { return existing[i].Name < existing[j].Name })
+	return existing
+}
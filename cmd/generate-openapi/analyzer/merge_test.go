@@ -0,0 +1,189 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"gopkg.in/yaml.v3"
+)
+
+// generateFor returns the spec generated for routes by a fresh generator
+func generateFor(t *testing.T, routes []types.RouteInfo) string {
+	t.Helper()
+
+	spec, err := NewGenerator().GenerateSpecForRoutes(routes)
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+	return spec
+}
+
+func TestMergeSpecs(t *testing.T) {
+	chat := generateFor(t, []types.RouteInfo{
+		{Method: "POST", Path: "/chat", RequestType: reflect.TypeOf(TestRequest{}), ResponseType: reflect.TypeOf(TestResponse{}), Module: "chat", Summary: "Chat"},
+	})
+	models := generateFor(t, []types.RouteInfo{
+		{Method: "GET", Path: "/models", ResponseType: reflect.TypeOf(NestedStruct{}), Module: "models", Summary: "Models"},
+		{Method: "GET", Path: "/chat", ResponseType: reflect.TypeOf(TestResponse{}), Module: "chat", Summary: "Chat history"},
+	})
+
+	merged, err := MergeSpecs([]string{chat, models})
+	if err != nil {
+		t.Fatalf("MergeSpecs() error = %v", err)
+	}
+
+	if !strings.HasPrefix(merged, "# Auto-generated OpenAPI specification") {
+		t.Error("Merged spec should keep the generation header")
+	}
+
+	paths := specPaths(t, merged)
+	for _, path := range []string{"/chat", "/models"} {
+		if _, exists := paths[path]; !exists {
+			t.Errorf("Merged spec should contain path %s", path)
+		}
+	}
+	chatPath := paths["/chat"].(map[string]interface{})
+	if _, exists := chatPath["get"]; !exists {
+		t.Error("Merged /chat should contain the GET operation from the second spec")
+	}
+	if _, exists := chatPath["post"]; !exists {
+		t.Error("Merged /chat should contain the POST operation from the first spec")
+	}
+
+	schemas := specSchemas(t, merged)
+	for _, name := range []string{"TestRequest", "TestResponse", "NestedStruct", "ErrorResponse"} {
+		if _, exists := schemas[name]; !exists {
+			t.Errorf("Merged spec should contain schema %s", name)
+		}
+	}
+}
+
+func TestMergeSpecs_PathConflict(t *testing.T) {
+	route := types.RouteInfo{Method: "GET", Path: "/models", ResponseType: reflect.TypeOf(TestResponse{}), Module: "models", Summary: "Models"}
+	spec := generateFor(t, []types.RouteInfo{route})
+
+	_, err := MergeSpecs([]string{spec, spec})
+	if err == nil {
+		t.Fatal("MergeSpecs() should fail when two specs define the same operation")
+	}
+	if !strings.Contains(err.Error(), "path conflict: GET /models") {
+		t.Errorf("Error should name the conflicting operation, got: %v", err)
+	}
+}
+
+func TestMergeSpecs_SchemaDeduplication(t *testing.T) {
+	first := `
+openapi: 3.0.3
+info: {title: First, version: "1.0.0"}
+paths: {}
+components:
+  schemas:
+    Shared: {type: object, properties: {id: {type: string}}}
+`
+	same := `
+openapi: 3.0.3
+info: {title: Second, version: "2.0.0"}
+paths: {}
+components:
+  schemas:
+    Shared: {type: object, properties: {id: {type: string}}}
+`
+	different := `
+openapi: 3.0.3
+info: {title: Third, version: "3.0.0"}
+paths: {}
+components:
+  schemas:
+    Shared: {type: object, properties: {id: {type: integer}}}
+`
+
+	merged, err := MergeSpecs([]string{first, same})
+	if err != nil {
+		t.Fatalf("MergeSpecs() should deduplicate identical schemas, error = %v", err)
+	}
+	if len(specSchemas(t, merged)) != 1 {
+		t.Error("Identical schemas should be merged into one")
+	}
+	if !strings.Contains(merged, "title: First") {
+		t.Error("Merged spec should use the first spec's info")
+	}
+
+	_, err = MergeSpecs([]string{first, different})
+	if err == nil || !strings.Contains(err.Error(), "schema Shared conflicts") {
+		t.Errorf("MergeSpecs() should reject differing schemas with the same name, got %v", err)
+	}
+}
+
+func TestMergeSpecs_Components(t *testing.T) {
+	first := `
+openapi: 3.1.0
+info: {title: First, version: "1.0.0"}
+paths: {}
+components:
+  securitySchemes:
+    oauth2: {type: oauth2, flows: {authorizationCode: {authorizationUrl: /authorize, tokenUrl: /token, scopes: {}}}}
+  links:
+    GetUser: {operationId: getUser}
+$defs:
+  User: {type: object}
+`
+	second := `
+openapi: 3.1.0
+info: {title: Second, version: "2.0.0"}
+paths: {}
+components:
+  securitySchemes:
+    apiKey: {type: apiKey, name: X-API-Key, in: header}
+  links:
+    GetUser: {operationId: getUser}
+$defs:
+  Model: {type: object}
+`
+
+	merged, err := MergeSpecs([]string{first, second})
+	if err != nil {
+		t.Fatalf("MergeSpecs() error = %v", err)
+	}
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(merged), &spec); err != nil {
+		t.Fatalf("Merged spec is not valid YAML: %v", err)
+	}
+	if len(spec.Components.SecuritySchemes) != 2 || len(spec.Components.Links) != 1 || len(spec.Defs) != 2 {
+		t.Errorf("Merged spec should union security schemes, links and $defs, got %+v and $defs %v", spec.Components, spec.Defs)
+	}
+
+	conflicts := map[string]string{
+		"securitySchemes": "security scheme oauth2 conflicts",
+		"links":           "link GetUser conflicts",
+		"$defs":           "$defs schema User conflicts",
+	}
+	for section, expected := range conflicts {
+		var conflicting string
+		switch section {
+		case "securitySchemes":
+			conflicting = strings.Replace(first, "tokenUrl: /token", "tokenUrl: /other", 1)
+		case "links":
+			conflicting = strings.Replace(first, "operationId: getUser", "operationId: listUsers", 1)
+		case "$defs":
+			conflicting = strings.Replace(first, "User: {type: object}", "User: {type: string}", 1)
+		}
+		if _, err := MergeSpecs([]string{first, conflicting}); err == nil || !strings.Contains(err.Error(), expected) {
+			t.Errorf("MergeSpecs() with a conflicting %s = %v, want %q", section, err, expected)
+		}
+	}
+}
+
+// specPaths decodes the paths section of a YAML spec
+func specPaths(t *testing.T, spec string) map[string]interface{} {
+	t.Helper()
+
+	var decoded struct {
+		Paths map[string]interface{} `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal([]byte(spec), &decoded); err != nil {
+		t.Fatalf("Spec is not valid YAML: %v", err)
+	}
+	return decoded.Paths
+}
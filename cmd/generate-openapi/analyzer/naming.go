@@ -0,0 +1,130 @@
+package analyzer
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// NamingStrategy names the component schema of a named Go type. The result is
+// stripped of characters OpenAPI does not allow in component keys, and slices
+// are named after their element with an Array suffix.
+type NamingStrategy func(t reflect.Type) string
+
+// Built-in naming strategies
+var (
+	// BareName names schemas after the type alone: provider.ChatRequest is ChatRequest
+	BareName NamingStrategy = bareTypeName
+	// PackageQualified prefixes the package name: provider.ChatRequest is ProviderChatRequest
+	PackageQualified NamingStrategy = packageQualifiedTypeName
+)
+
+// bareTypeName returns the type name with package paths stripped from it and its
+// type arguments
+func bareTypeName(t reflect.Type) string {
+	return cleanTypeName(t.Name())
+}
+
+// packageQualifiedTypeName returns the bare type name prefixed with its package name
+func packageQualifiedTypeName(t reflect.Type) string {
+	if t.PkgPath() == "" {
+		return bareTypeName(t)
+	}
+	return capitalize(path.Base(t.PkgPath())) + bareTypeName(t)
+}
+
+// namingStrategy returns the configured NamingStrategy, defaulting to BareName
+func (g *Generator) namingStrategy() NamingStrategy {
+	if g.NamingStrategy == nil {
+		return BareName
+	}
+	return g.NamingStrategy
+}
+
+// claimSchemaName records that name is the component schema of t, failing when
+// the naming strategy already gave that name to a different type
+func (g *Generator) claimSchemaName(name string, t reflect.Type) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.schemaTypes == nil {
+		g.schemaTypes = make(map[string]reflect.Type)
+	}
+	if existing, ok := g.schemaTypes[name]; ok && existing != t {
+		return fmt.Errorf("schema name %s is used by both %v and %v; use a NamingStrategy that tells them apart", name, existing, t)
+	}
+	g.schemaTypes[name] = t
+	return nil
+}
+
+// checkSchemaRefs verifies that every schema $ref and discriminator mapping in a
+// generated spec names a schema the spec defines, so a naming strategy can never leave a stale name
+// behind.
+func (g *Generator) checkSchemaRefs(spec string) error {
+	var doc map[string]interface{}
+	if err := yaml.Unmarshal([]byte(spec), &doc); err != nil {
+		return fmt.Errorf("failed to parse generated spec: %w", err)
+	}
+	components, _ := doc["components"].(map[string]interface{})
+	schemas, _ := components["schemas"].(map[string]interface{})
+	defs, _ := doc["$defs"].(map[string]interface{})
+
+	var dangling []string
+	seen := make(map[string]bool)
+	check := func(ref string) {
+		var defined bool
+		switch {
+		case strings.HasPrefix(ref, componentSchemasRef):
+			name := strings.TrimPrefix(ref, componentSchemasRef)
+			_, defined = schemas[name]
+		case strings.HasPrefix(ref, jsonSchemaDefsRef):
+			name := strings.TrimPrefix(ref, jsonSchemaDefsRef)
+			_, defined = defs[name]
+		default:
+			defined = true
+		}
+		if !defined && !seen[ref] {
+			seen[ref] = true
+			dangling = append(dangling, ref)
+		}
+	}
+
+	var walk func(value interface{})
+	walk = func(value interface{}) {
+		switch v := value.(type) {
+		case map[string]interface{}:
+			for key, child := range v {
+				if ref, ok := child.(string); ok && key == "$ref" {
+					check(ref)
+					continue
+				}
+				// Discriminator mappings hold schema references too
+				if discriminator, ok := child.(map[string]interface{}); ok && key == "discriminator" {
+					mapping, _ := discriminator["mapping"].(map[string]interface{})
+					for _, ref := range mapping {
+						if ref, ok := ref.(string); ok {
+							check(ref)
+						}
+					}
+					continue
+				}
+				walk(child)
+			}
+		case []interface{}:
+			for _, child := range v {
+				walk(child)
+			}
+		}
+	}
+	walk(doc)
+
+	if len(dangling) > 0 {
+		sort.Strings(dangling)
+		return fmt.Errorf("generated spec references undefined schemas: %s", strings.Join(dangling, ", "))
+	}
+	return nil
+}
@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/version"
+	"gopkg.in/yaml.v3"
+)
+
+// namingRoutes covers request, response, slice, paginated, union variant and
+// cross-package component names
+func namingRoutes() []types.RouteInfo {
+	return []types.RouteInfo{
+		{Method: "POST", Path: "/messages", RequestType: reflect.TypeOf(ChatMessage{}), ResponseType: reflect.TypeOf(TestResponse{}), Module: "messages", Summary: "Send message"},
+		{Method: "GET", Path: "/users", ResponseType: reflect.TypeOf([]User{}), Module: "users", Summary: "List users"},
+		{Method: "GET", Path: "/models", ResponseType: reflect.TypeOf(Model{}), Module: "models", Summary: "List models", Pagination: &types.PaginationInfo{Style: types.PaginationCursor}},
+		{Method: "GET", Path: "/version", ResponseType: reflect.TypeOf(version.BuildInfo{}), Module: "health", Summary: "Get version"},
+	}
+}
+
+func TestNamingStrategy_RefsMatchComponents(t *testing.T) {
+	tests := []struct {
+		name     string
+		strategy NamingStrategy
+		expected []string
+	}{
+		{"bare", BareName, []string{
+			"BuildInfo", "ChatMessage", "ErrorResponse", "ImageShape", "Model", "PaginatedResponseModel", "TestResponse", "TextShape", "UserArray",
+		}},
+		{"package qualified", PackageQualified, []string{
+			"AnalyzerChatMessage", "AnalyzerImageShape", "AnalyzerModel", "AnalyzerTestResponse", "AnalyzerTextShape", "AnalyzerUserArray", "ErrorResponse", "PaginatedResponseAnalyzerModel", "VersionBuildInfo",
+		}},
+		{"custom", func(t reflect.Type) string { return "Api_" + t.Name() }, []string{
+			"Api_BuildInfo", "Api_ChatMessage", "Api_ImageShape", "Api_Model", "Api_TestResponse", "Api_TextShape", "Api_UserArray", "ErrorResponse", "PaginatedResponseApi_Model",
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ClearUnions()
+			t.Cleanup(ClearUnions)
+			RegisterUnion(reflect.TypeOf((*Shape)(nil)).Elem(), reflect.TypeOf(TextShape{}), reflect.TypeOf(ImageShape{}))
+
+			out, err := NewGenerator(WithNamingStrategy(tt.strategy)).GenerateSpecForRoutes(namingRoutes())
+			if err != nil {
+				t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+			}
+			var doc map[string]interface{}
+			if err := yaml.Unmarshal([]byte(out), &doc); err != nil {
+				t.Fatalf("Generated spec is not valid YAML: %v", err)
+			}
+
+			schemas := doc["components"].(map[string]interface{})["schemas"].(map[string]interface{})
+			var names []string
+			for name := range schemas {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("Component names = %v, expected %v", names, tt.expected)
+			}
+
+			refs := collectRefs(doc)
+			if len(refs) == 0 {
+				t.Fatal("Expected the spec to contain references")
+			}
+			for _, ref := range refs {
+				if _, ok := schemas[strings.TrimPrefix(ref, componentSchemasRef)]; !ok {
+					t.Errorf("Reference %q does not match a component", ref)
+				}
+			}
+		})
+	}
+}
+
+func TestNamingStrategy_Collision(t *testing.T) {
+	// A second type named User in the same package collides under either built-in
+	packageUser := reflect.TypeOf(User{})
+	type User struct {
+		Email string `json:"email"`
+	}
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/users/{id}", ResponseType: packageUser, Module: "users"},
+		{Method: "GET", Path: "/accounts/{id}", ResponseType: reflect.TypeOf(User{}), Module: "accounts"},
+	}
+
+	for _, strategy := range []NamingStrategy{BareName, PackageQualified} {
+		_, err := NewGenerator(WithNamingStrategy(strategy)).GenerateSpecForRoutes(routes)
+		if err == nil || !strings.Contains(err.Error(), "is used by both") {
+			t.Errorf("Expected a schema name collision, got %v", err)
+		}
+	}
+
+	// A strategy that tells the types apart resolves it
+	byScope := func(t reflect.Type) string {
+		if t == packageUser {
+			return "User"
+		}
+		return "AccountUser"
+	}
+	if _, err := NewGenerator(WithNamingStrategy(byScope)).GenerateSpecForRoutes(routes); err != nil {
+		t.Errorf("Expected distinct names to resolve the collision, got %v", err)
+	}
+}
+
+func TestCheckSchemaRefs_Stale(t *testing.T) {
+	spec := `paths:
+  /users:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                $ref: '#/components/schemas/OldUser'
+components:
+  schemas:
+    User:
+      type: object
+`
+	err := NewGenerator().checkSchemaRefs(spec)
+	if err == nil || !strings.Contains(err.Error(), "#/components/schemas/OldUser") {
+		t.Errorf("Expected the stale reference to be reported, got %v", err)
+	}
+}
@@ -0,0 +1,94 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// OperationIDStyle controls how generateOperationID joins the method verb and path
+// segments of an operation ID
+type OperationIDStyle string
+
+// Operation ID styles accepted by SetOperationIDStyle
+const (
+	OperationIDCamel OperationIDStyle = "camel" // getv1ModelsAPIURL (default)
+	OperationIDSnake OperationIDStyle = "snake" // get_v1_models_api_url
+	OperationIDKebab OperationIDStyle = "kebab" // get-v1-models-api-url
+)
+
+// ParseOperationIDStyle returns the operation ID style named s, camel, snake or kebab
+func ParseOperationIDStyle(s string) (OperationIDStyle, error) {
+	switch style := OperationIDStyle(s); style {
+	case OperationIDCamel, OperationIDSnake, OperationIDKebab:
+		return style, nil
+	default:
+		return "", fmt.Errorf("unknown operation ID style %q, expected camel, snake or kebab", s)
+	}
+}
+
+// WithOperationIDStyle sets how generated operation IDs are cased; see SetOperationIDStyle
+func WithOperationIDStyle(style OperationIDStyle) GeneratorOption {
+	return func(g *Generator) {
+		g.SetOperationIDStyle(style)
+	}
+}
+
+// SetOperationIDStyle sets how generated operation IDs are cased, for client
+// generators that derive method names from them. Unknown styles, which
+// ParseOperationIDStyle rejects, fall back to the default OperationIDCamel.
+func (g *Generator) SetOperationIDStyle(style OperationIDStyle) {
+	g.operationIDStyle = style
+}
+
+// joinOperationID joins lower-case operation ID words in the configured style.
+// Path parameter braces are dropped from every word.
+func (g *Generator) joinOperationID(words []string) string {
+	var separator string
+	switch g.operationIDStyle {
+	case OperationIDSnake:
+		separator = "_"
+	case OperationIDKebab:
+		separator = "-"
+	default:
+		// The first path segment keeps its casing; later ones are title-cased
+		camel := words[0]
+		for i, word := range words[1:] {
+			if i == 0 {
+				camel += word
+			} else {
+				camel += titleSegment(word)
+			}
+		}
+		return camel
+	}
+
+	parts := make([]string, 0, len(words))
+	for _, word := range words {
+		for _, part := range splitCamelCase(strings.Trim(word, "{}")) {
+			parts = append(parts, strings.ToLower(part))
+		}
+	}
+	return strings.Join(parts, separator)
+}
+
+// splitCamelCase splits a word on its case boundaries, so "userId" gives "user"
+// and "Id", and "APIKeys" gives "API" and "Keys". Digits stay with the letters
+// before them.
+func splitCamelCase(word string) []string {
+	runes := []rune(word)
+	var parts []string
+	start := 0
+	for i := 1; i < len(runes); i++ {
+		if !unicode.IsUpper(runes[i]) {
+			continue
+		}
+		// A lower-case letter or digit ends a word, and so does the last capital
+		// of an acronym followed by a capitalized word
+		if !unicode.IsUpper(runes[i-1]) || (i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+			parts = append(parts, string(runes[start:i]))
+			start = i
+		}
+	}
+	return append(parts, string(runes[start:]))
+}
@@ -0,0 +1,219 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// GeneratorOption configures a Generator created with NewGenerator
+type GeneratorOption func(*Generator)
+
+// WithVersion sets the API version written to info.version. An empty version is
+// detected with DetectVersion when the spec is generated.
+func WithVersion(v string) GeneratorOption {
+	return func(g *Generator) {
+		g.Version = v
+	}
+}
+
+// WithServers sets the servers listed in the spec, replacing openapi.servers from config
+func WithServers(servers ...Server) GeneratorOption {
+	return func(g *Generator) {
+		g.Servers = servers
+	}
+}
+
+// WithTagDescriptions sets the descriptions of top-level tags, keyed by tag name
+func WithTagDescriptions(m map[string]string) GeneratorOption {
+	return func(g *Generator) {
+		for name, description := range m {
+			g.SetTagDescription(name, description)
+		}
+	}
+}
+
+// WithInfoContact sets info.contact; see SetInfoContact
+func WithInfoContact(name, url, email string) GeneratorOption {
+	return func(g *Generator) {
+		g.SetInfoContact(name, url, email)
+	}
+}
+
+// WithInfoLicense sets info.license; see SetInfoLicense
+func WithInfoLicense(name, url string) GeneratorOption {
+	return func(g *Generator) {
+		g.SetInfoLicense(name, url)
+	}
+}
+
+// WithIncludeHeader controls whether the generated spec starts with the "DO NOT
+// EDIT" comment block. It is included by default; leave it out when piping the
+// spec into tools that reject YAML comments.
+func WithIncludeHeader(include bool) GeneratorOption {
+	return func(g *Generator) {
+		g.SetIncludeHeader(include)
+	}
+}
+
+// WithSortPaths builds paths sorted by path then method instead of registration order
+func WithSortPaths(b bool) GeneratorOption {
+	return func(g *Generator) {
+		g.SortPaths = b
+	}
+}
+
+// WithKeepUnusedSchemas keeps component schemas that no path references
+func WithKeepUnusedSchemas(b bool) GeneratorOption {
+	return func(g *Generator) {
+		g.KeepUnusedSchemas = b
+	}
+}
+
+// WithHoistSharedParams declares path parameters shared by every operation on a
+// path once on the path item
+func WithHoistSharedParams(b bool) GeneratorOption {
+	return func(g *Generator) {
+		g.HoistSharedParams = b
+	}
+}
+
+// WithNormalizePaths strips trailing slashes so /users/ and /users share a path item
+func WithNormalizePaths(b bool) GeneratorOption {
+	return func(g *Generator) {
+		g.NormalizePaths = b
+	}
+}
+
+// WithDefaultResponse adds a default response with the route's error body to
+// every operation
+func WithDefaultResponse(b bool) GeneratorOption {
+	return func(g *Generator) {
+		g.DefaultResponse = b
+	}
+}
+
+// WithExamplesDir attaches the <ComponentName>.json fixtures in dir to their
+// component schemas as examples
+func WithExamplesDir(dir string) GeneratorOption {
+	return func(g *Generator) {
+		g.ExamplesDir = dir
+	}
+}
+
+// WithCodeSamples adds x-codeSamples to every operation; inDescription also
+// appends them to operation descriptions for Swagger UI
+func WithCodeSamples(enabled, inDescription bool) GeneratorOption {
+	return func(g *Generator) {
+		g.CodeSamples = enabled
+		g.CodeSamplesInDescription = inDescription
+	}
+}
+
+// WithExclude leaves modules and path globs (entries starting with "/") out of the spec
+func WithExclude(patterns ...string) GeneratorOption {
+	return func(g *Generator) {
+		g.Exclude = append(g.Exclude, patterns...)
+	}
+}
+
+// WithValidation makes spec generation fail when the generated spec has lint errors
+func WithValidation(b bool) GeneratorOption {
+	return func(g *Generator) {
+		g.Validate = b
+	}
+}
+
+// WithRequireResponseSchema makes spec generation fail for JSON routes without a
+// ResponseType. allowEmpty lists routes whose empty response is intentional, as
+// "METHOD /path" or a bare path for every method.
+func WithRequireResponseSchema(b bool, allowEmpty ...string) GeneratorOption {
+	return func(g *Generator) {
+		g.RequireResponseSchema = b
+		g.EmptyResponseRoutes = append(g.EmptyResponseRoutes, allowEmpty...)
+	}
+}
+
+// WithOpenAPIVersion sets the OpenAPI version written to openapi, OpenAPIVersion30
+// or OpenAPIVersion31
+func WithOpenAPIVersion(v string) GeneratorOption {
+	return func(g *Generator) {
+		g.OpenAPIVersion = v
+	}
+}
+
+// WithJSONSchemaDefs emits schemas under a root $defs referenced as #/$defs/Name
+// when the spec is OpenAPIVersion31
+func WithJSONSchemaDefs(b bool) GeneratorOption {
+	return func(g *Generator) {
+		g.UseJSONSchemaDefs = b
+	}
+}
+
+// WithNamingStrategy names component schemas with strategy, such as
+// PackageQualified or a custom function
+func WithNamingStrategy(strategy NamingStrategy) GeneratorOption {
+	return func(g *Generator) {
+		g.NamingStrategy = strategy
+	}
+}
+
+// WithDereference inlines every component schema $ref into the paths, for tools
+// that cannot follow references
+func WithDereference(b bool) GeneratorOption {
+	return func(g *Generator) {
+		g.Dereference = b
+	}
+}
+
+// WithPathPrefix mounts every route under prefix, such as "/api"
+func WithPathPrefix(prefix string) GeneratorOption {
+	return func(g *Generator) {
+		g.PathPrefix = prefix
+	}
+}
+
+// WithHandlerDocs reads operation summaries and descriptions from handler doc
+// comments in the Go source under root, or from the module containing the
+// working directory when root is empty. Disabled, routes are documented only by
+// their RouteInfo.
+func WithHandlerDocs(enabled bool, root string) GeneratorOption {
+	return func(g *Generator) {
+		g.SkipHandlerDocs = !enabled
+		g.ModuleRoot = root
+	}
+}
+
+// prefixPath joins a route path onto the configured PathPrefix
+func (g *Generator) prefixPath(path string) string {
+	prefix := strings.TrimRight(g.PathPrefix, "/")
+	if prefix == "" {
+		return path
+	}
+	if !strings.HasPrefix(prefix, "/") {
+		prefix = "/" + prefix
+	}
+	if path == "/" || path == "" {
+		return prefix
+	}
+	return prefix + path
+}
+
+// validateSpec lints a generated spec and returns an error listing every
+// error-severity issue
+func validateSpec(spec string) error {
+	linter, err := NewLinter([]byte(spec))
+	if err != nil {
+		return err
+	}
+
+	var errs []string
+	for _, issue := range linter.Lint() {
+		if issue.Severity == SeverityError {
+			errs = append(errs, issue.String())
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("generated spec failed validation:\n%s", strings.Join(errs, "\n"))
+	}
+	return nil
+}
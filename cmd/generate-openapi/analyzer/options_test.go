@@ -0,0 +1,129 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+func TestNewGenerator_Options(t *testing.T) {
+	gen := NewGenerator(
+		WithVersion("2.1.0"),
+		WithServers(Server{URL: "https://api.example.com", Description: "Production"}),
+		WithTagDescriptions(map[string]string{"users": "User management"}),
+		WithSortPaths(true),
+		WithValidation(true),
+		WithPathPrefix("/api"),
+	)
+
+	if gen.Version != "2.1.0" {
+		t.Errorf("Version = %q, expected 2.1.0", gen.Version)
+	}
+	if expected := []Server{{URL: "https://api.example.com", Description: "Production"}}; !reflect.DeepEqual(gen.Servers, expected) {
+		t.Errorf("Servers = %v, expected %v", gen.Servers, expected)
+	}
+	if gen.tagDescriptions["users"] != "User management" {
+		t.Errorf("tagDescriptions = %v, expected a users description", gen.tagDescriptions)
+	}
+	if !gen.SortPaths {
+		t.Error("SortPaths should be set")
+	}
+	if !gen.Validate {
+		t.Error("Validate should be set")
+	}
+	if gen.PathPrefix != "/api" {
+		t.Errorf("PathPrefix = %q, expected /api", gen.PathPrefix)
+	}
+}
+
+func TestNewGenerator_OptionsApplyInOrder(t *testing.T) {
+	gen := NewGenerator(WithVersion("1.2.0"), WithVersion(""))
+	if gen.Version != "" {
+		t.Errorf("Version = %q, expected the last option to win", gen.Version)
+	}
+}
+
+func TestWithServers_OverridesConfig(t *testing.T) {
+	servers := []Server{{URL: "https://staging.example.com", Description: "Staging"}}
+	spec := generateWithHooks(t, NewGenerator(WithServers(servers...)))
+
+	if !reflect.DeepEqual(spec.Servers, servers) {
+		t.Errorf("Servers = %v, expected %v", spec.Servers, servers)
+	}
+}
+
+func TestWithTagDescriptions(t *testing.T) {
+	spec := generateWithHooks(t, NewGenerator(WithTagDescriptions(map[string]string{"users": "User management"})))
+
+	for _, tag := range spec.Tags {
+		if tag.Name == "users" && tag.Description != "User management" {
+			t.Errorf("users tag description = %q, expected User management", tag.Description)
+		}
+	}
+}
+
+func TestWithSortPaths(t *testing.T) {
+	if !NewGenerator(WithSortPaths(true)).SortPaths {
+		t.Error("WithSortPaths(true) should enable sorted paths")
+	}
+	if NewGenerator(WithSortPaths(false)).SortPaths {
+		t.Error("WithSortPaths(false) should leave paths in registration order")
+	}
+}
+
+func TestWithPathPrefix(t *testing.T) {
+	tests := []struct {
+		prefix   string
+		expected []string
+	}{
+		{"/api", []string{"/api/users", "/api/users/{id}", "/api/debug/dump"}},
+		{"api/", []string{"/api/users", "/api/users/{id}", "/api/debug/dump"}},
+		{"", []string{"/users", "/users/{id}", "/debug/dump"}},
+	}
+
+	for _, tt := range tests {
+		spec := generateWithHooks(t, NewGenerator(WithPathPrefix(tt.prefix)))
+		if len(spec.Paths) != len(tt.expected) {
+			t.Errorf("prefix %q: got %d paths, expected %d", tt.prefix, len(spec.Paths), len(tt.expected))
+		}
+		for _, path := range tt.expected {
+			if _, exists := spec.Paths[path]; !exists {
+				t.Errorf("prefix %q: expected path %s", tt.prefix, path)
+			}
+		}
+	}
+}
+
+func TestPrefixPath_Root(t *testing.T) {
+	gen := NewGenerator(WithPathPrefix("/api"))
+	if path := gen.prefixPath("/"); path != "/api" {
+		t.Errorf("prefixPath(/) = %q, expected /api", path)
+	}
+}
+
+func TestWithValidation(t *testing.T) {
+	// Give every operation the same operationId, which the linter rejects
+	duplicateIDs := func(route types.RouteInfo, op *Operation) bool {
+		op.OperationID = "duplicate"
+		return false
+	}
+
+	gen := NewGenerator(WithValidation(false))
+	gen.OnOperation(duplicateIDs)
+	if _, err := gen.GenerateSpecForRoutes(hookRoutes()); err != nil {
+		t.Fatalf("GenerateSpecForRoutes() without validation error = %v", err)
+	}
+
+	gen = NewGenerator(WithValidation(true))
+	gen.OnOperation(duplicateIDs)
+	_, err := gen.GenerateSpecForRoutes(hookRoutes())
+	if err == nil || !strings.Contains(err.Error(), `duplicate operationId "duplicate"`) {
+		t.Errorf("GenerateSpecForRoutes() error = %v, expected a duplicate operationId validation error", err)
+	}
+
+	if _, err := NewGenerator(WithValidation(true)).GenerateSpecForRoutes(hookRoutes()); err != nil {
+		t.Errorf("GenerateSpecForRoutes() of a valid spec error = %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// maxPageSize bounds the page size parameters of the Pagination styles
+const maxPageSize = 1000
+
+// pageType is the wrapper of Pagination style routes; its instantiation over any
+// supplies the fields of every generated page schema
+var pageType = reflect.TypeOf(types.PaginatedResponse[any]{})
+
+// paginationParameters returns the limit and offset query parameters read by
+// handler.ParsePagination
+func paginationParameters() []Parameter {
+	return []Parameter{
+		{
+			Name:        "limit",
+			In:          "query",
+			Description: "Maximum number of items to return",
+			Schema:      map[string]interface{}{"type": "integer", "minimum": 1},
+		},
+		{
+			Name:        "offset",
+			In:          "query",
+			Description: "Number of items to skip",
+			Schema:      map[string]interface{}{"type": "integer", "minimum": 0, "default": 0},
+		},
+	}
+}
+
+// pageSizeParameter returns a page size query parameter bounded by maxPageSize
+func pageSizeParameter(name string) Parameter {
+	return Parameter{
+		Name:        name,
+		In:          "query",
+		Description: "Maximum number of items to return",
+		Schema:      map[string]interface{}{"type": "integer", "minimum": 1, "maximum": maxPageSize},
+	}
+}
+
+// paginationStyleParameters returns the query parameters for a pagination style
+func paginationStyleParameters(style string) ([]Parameter, error) {
+	cursor := func(name, description string) Parameter {
+		return Parameter{
+			Name:        name,
+			In:          "query",
+			Description: description,
+			Schema:      map[string]interface{}{"type": "string"},
+		}
+	}
+
+	switch style {
+	case types.PaginationOffset:
+		return []Parameter{
+			{
+				Name:        "page",
+				In:          "query",
+				Description: "Page number, starting at 1",
+				Schema:      map[string]interface{}{"type": "integer", "minimum": 1, "maximum": maxPageSize, "default": 1},
+			},
+			pageSizeParameter("pageSize"),
+		}, nil
+	case types.PaginationCursor:
+		return []Parameter{
+			cursor("cursor", "Opaque cursor returned as nextCursor by the previous page"),
+			pageSizeParameter("limit"),
+		}, nil
+	case types.PaginationKeyset:
+		return []Parameter{
+			cursor("after", "Return items after this key"),
+			cursor("before", "Return items before this key"),
+			pageSizeParameter("limit"),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported pagination style %q", style)
+	}
+}
+
+// routePaginationParameters returns the query parameters of a paginated route.
+// Paginated documents limit and offset and a Pagination style adds its own; a
+// parameter both declare is documented once.
+func routePaginationParameters(route types.RouteInfo) []Parameter {
+	var params []Parameter
+	if route.Paginated {
+		params = paginationParameters()
+	}
+	if route.Pagination != nil {
+		// The style was validated when schemas were generated
+		styleParams, _ := paginationStyleParameters(route.Pagination.Style)
+		for _, param := range styleParams {
+			if !slices.ContainsFunc(params, func(p Parameter) bool { return p.Name == param.Name }) {
+				params = append(params, param)
+			}
+		}
+	}
+	return params
+}
+
+// wrapsInPage reports whether a route's ResponseType items are wrapped in a
+// generated PaginatedResponse schema. Routes already returning a page are not.
+func wrapsInPage(route types.RouteInfo) bool {
+	return route.Pagination != nil && route.ResponseType != nil && !isPageType(route.ResponseType)
+}
+
+// isPageType reports whether t is an instantiation of types.PaginatedResponse or
+// types.Paginated
+func isPageType(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.PkgPath() == pageType.PkgPath() &&
+		(strings.HasPrefix(t.Name(), "PaginatedResponse[") || strings.HasPrefix(t.Name(), "Paginated["))
+}
+
+// paginatedResponseName returns the component name of the page of itemType, the
+// name types.PaginatedResponse instantiated with itemType is given
+func (g *Generator) paginatedResponseName(itemType reflect.Type) string {
+	return "PaginatedResponse" + g.getTypeName(itemType)
+}
+
+// paginatedResponseSchema builds the types.PaginatedResponse schema whose data
+// holds itemType
+func (g *Generator) paginatedResponseSchema(itemType reflect.Type) (map[string]interface{}, error) {
+	page, err := g.generateTypeSchema(pageType)
+	if err != nil {
+		return nil, err
+	}
+
+	item := g.schemaRef(itemType)
+	items := item.Inline
+	if item.Ref != "" {
+		items = map[string]interface{}{"$ref": item.Ref}
+	}
+
+	// Generated schemas share nested maps with the schema cache, so replace rather than edit
+	properties := make(map[string]interface{})
+	for name, property := range page["properties"].(map[string]interface{}) {
+		properties[name] = property
+	}
+	properties["data"] = map[string]interface{}{"type": "array", "items": items}
+
+	schema := make(map[string]interface{}, len(page))
+	for key, value := range page {
+		schema[key] = value
+	}
+	schema["properties"] = properties
+	return schema, nil
+}
@@ -0,0 +1,176 @@
+package analyzer
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+func TestBuildOperation_PaginationStyles(t *testing.T) {
+	tests := []struct {
+		style    string
+		expected []string
+	}{
+		{types.PaginationOffset, []string{"page", "pageSize"}},
+		{types.PaginationCursor, []string{"cursor", "limit"}},
+		{types.PaginationKeyset, []string{"after", "before", "limit"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style, func(t *testing.T) {
+			gen := NewGenerator()
+			route := types.RouteInfo{
+				Method:       "GET",
+				Path:         "/models",
+				ResponseType: reflect.TypeOf(Model{}),
+				Module:       "models",
+				Pagination:   &types.PaginationInfo{Style: tt.style},
+			}
+			if err := gen.LoadRoutes([]types.RouteInfo{route}); err != nil {
+				t.Fatalf("LoadRoutes() error = %v", err)
+			}
+
+			operation := gen.buildOperation(route)
+
+			var names []string
+			for _, param := range operation.Parameters {
+				if param.In != "query" {
+					t.Errorf("Parameter %s should be a query parameter", param.Name)
+				}
+				names = append(names, param.Name)
+			}
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("Expected parameters %v, got %v", tt.expected, names)
+			}
+		})
+	}
+}
+
+func TestBuildOperation_PaginatedAndPaginationShareParameters(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{
+		Method:       "GET",
+		Path:         "/models",
+		ResponseType: reflect.TypeOf(Model{}),
+		Module:       "models",
+		Paginated:    true,
+		Pagination:   &types.PaginationInfo{Style: types.PaginationCursor},
+	}
+	if err := gen.LoadRoutes([]types.RouteInfo{route}); err != nil {
+		t.Fatalf("LoadRoutes() error = %v", err)
+	}
+
+	var names []string
+	for _, param := range gen.buildOperation(route).Parameters {
+		names = append(names, param.Name)
+	}
+	if expected := []string{"limit", "offset", "cursor"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("Expected parameters %v with limit once, got %v", expected, names)
+	}
+}
+
+func TestPaginationStyleParameters_UnknownStyle(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{
+		Method:       "GET",
+		Path:         "/models",
+		ResponseType: reflect.TypeOf(Model{}),
+		Module:       "models",
+		Pagination:   &types.PaginationInfo{Style: "pages"},
+	}
+
+	err := gen.LoadRoutes([]types.RouteInfo{route})
+	if err == nil || !strings.Contains(err.Error(), `unsupported pagination style "pages"`) {
+		t.Errorf("LoadRoutes() should reject unknown pagination styles, got %v", err)
+	}
+}
+
+func TestBuildResponses_PaginatedResponseWrapper(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{
+		Method:       "GET",
+		Path:         "/models",
+		ResponseType: reflect.TypeOf(Model{}),
+		Module:       "models",
+		Summary:      "List models",
+		Pagination:   &types.PaginationInfo{Style: types.PaginationCursor},
+	}
+
+	spec, err := gen.GenerateSpecForRoutes([]types.RouteInfo{route})
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	if ref := gen.Responses(route)["200"].Content["application/json"].Schema.Ref; ref != componentSchemasRef+"PaginatedResponseModel" {
+		t.Errorf("Expected 200 to reference PaginatedResponseModel, got %s", ref)
+	}
+
+	wrapper, ok := specSchemas(t, spec)["PaginatedResponseModel"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Spec should contain the PaginatedResponseModel schema")
+	}
+	properties := wrapper["properties"].(map[string]interface{})
+	for _, name := range []string{"data", "total", "nextCursor"} {
+		if _, exists := properties[name]; !exists {
+			t.Errorf("Wrapper should have the types.PaginatedResponse property %s", name)
+		}
+	}
+	items := properties["data"].(map[string]interface{})["items"].(map[string]interface{})
+	if items["$ref"] != componentSchemasRef+"Model" {
+		t.Errorf("Wrapper data should reference Model, got %v", items)
+	}
+	if _, exists := specSchemas(t, spec)["Model"]; !exists {
+		t.Error("Item schema referenced by the wrapper should not be pruned")
+	}
+}
+
+func TestBuildResponses_PaginatedResponseTypeIsNotWrapped(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{
+		Method:       "GET",
+		Path:         "/models",
+		ResponseType: reflect.TypeOf(types.PaginatedResponse[Model]{}),
+		Module:       "models",
+		Summary:      "List models",
+		Pagination:   &types.PaginationInfo{Style: types.PaginationOffset},
+	}
+
+	if _, err := gen.GenerateSpecForRoutes([]types.RouteInfo{route}); err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+	if ref := gen.Responses(route)["200"].Content["application/json"].Schema.Ref; ref != componentSchemasRef+"PaginatedResponseModel" {
+		t.Errorf("Expected 200 to reference the declared PaginatedResponseModel, got %s", ref)
+	}
+}
+
+func TestPaginationStyleParameters_PageSizeBounds(t *testing.T) {
+	tests := []struct {
+		style string
+		name  string
+	}{
+		{types.PaginationOffset, "page"},
+		{types.PaginationOffset, "pageSize"},
+		{types.PaginationCursor, "limit"},
+		{types.PaginationKeyset, "limit"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.style+" "+tt.name, func(t *testing.T) {
+			params, err := paginationStyleParameters(tt.style)
+			if err != nil {
+				t.Fatalf("paginationStyleParameters() error = %v", err)
+			}
+			idx := slices.IndexFunc(params, func(p Parameter) bool { return p.Name == tt.name })
+			if idx < 0 {
+				t.Fatalf("Style %s should document %s", tt.style, tt.name)
+			}
+			schema := params[idx].Schema
+			if schema["type"] != "integer" || schema["minimum"] != 1 || schema["maximum"] != maxPageSize {
+				t.Errorf("%s should be an integer between 1 and %d, got %v", tt.name, maxPageSize, schema)
+			}
+		})
+	}
+}
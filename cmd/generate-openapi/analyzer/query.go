@@ -0,0 +1,121 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// Serialization styles accepted for array query parameters
+const (
+	queryStyleForm           = "form"           // tags=a&tags=b, or tags=a,b without explode
+	queryStyleSpaceDelimited = "spaceDelimited" // tags=a%20b
+	queryStylePipeDelimited  = "pipeDelimited"  // tags=a|b
+)
+
+// queryField is a parsed query struct tag, e.g. query:"tags,style=form,explode=false"
+type queryField struct {
+	name     string
+	required bool
+	style    string
+	explode  *bool
+}
+
+// parseQueryTag parses a query tag's name followed by required, style=<style> and
+// explode=<bool> options
+func parseQueryTag(tag string) (queryField, error) {
+	parts := strings.Split(tag, ",")
+	field := queryField{name: parts[0]}
+
+	for _, option := range parts[1:] {
+		key, value, _ := strings.Cut(option, "=")
+		switch key {
+		case "required":
+			field.required = true
+		case "style":
+			switch value {
+			case queryStyleForm, queryStyleSpaceDelimited, queryStylePipeDelimited:
+				field.style = value
+			default:
+				return field, fmt.Errorf("query parameter %s has unsupported style %q", field.name, value)
+			}
+		case "explode":
+			explode, err := strconv.ParseBool(value)
+			if err != nil {
+				return field, fmt.Errorf("query parameter %s has invalid explode %q", field.name, value)
+			}
+			field.explode = &explode
+		default:
+			return field, fmt.Errorf("query parameter %s has unknown option %q", field.name, option)
+		}
+	}
+	return field, nil
+}
+
+// queryParameters returns the query parameters documented by the query-tagged fields
+// of queryType. Array parameters state their style and explode, defaulting to form
+// with explode so tags=a&tags=b repeats the name for each value.
+func (g *Generator) queryParameters(queryType reflect.Type) ([]Parameter, error) {
+	for queryType.Kind() == reflect.Ptr {
+		queryType = queryType.Elem()
+	}
+	if queryType.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("query type %v is not a struct", queryType)
+	}
+
+	var params []Parameter
+	for i := 0; i < queryType.NumField(); i++ {
+		structField := queryType.Field(i)
+		tag, ok := structField.Tag.Lookup("query")
+		if !ok || tag == "-" || !structField.IsExported() {
+			continue
+		}
+
+		field, err := parseQueryTag(tag)
+		if err != nil {
+			return nil, err
+		}
+		if field.name == "" {
+			field.name = structField.Name
+		}
+
+		fieldType := structField.Type
+		for fieldType.Kind() == reflect.Ptr {
+			fieldType = fieldType.Elem()
+		}
+		isArray := fieldType.Kind() == reflect.Slice || fieldType.Kind() == reflect.Array
+		if !isArray && (field.style != "" || field.explode != nil) {
+			return nil, fmt.Errorf("query parameter %s sets style or explode but is not an array", field.name)
+		}
+
+		schema, err := g.generateTypeSchema(fieldType)
+		if err != nil {
+			return nil, withFieldPath(structField.Name, err)
+		}
+		if example := structField.Tag.Get("example"); example != "" && !isArray {
+			schema["example"] = example
+		}
+
+		param := Parameter{
+			Name:        field.name,
+			In:          "query",
+			Description: structField.Tag.Get("description"),
+			Required:    field.required,
+			Schema:      schema,
+		}
+		if isArray {
+			param.Style = queryStyleForm
+			if field.style != "" {
+				param.Style = field.style
+			}
+			explode := true
+			if field.explode != nil {
+				explode = *field.explode
+			}
+			param.Explode = &explode
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
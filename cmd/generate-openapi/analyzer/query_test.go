@@ -0,0 +1,140 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"gopkg.in/yaml.v3"
+)
+
+// ListNotesQuery exercises the query tag options
+type ListNotesQuery struct {
+	Tags   []string `query:"tags,style=form,explode=false" description:"Tags the notes must carry"`
+	IDs    []int    `query:"ids"`
+	Owners []string `query:"owner,style=pipeDelimited"`
+	Search string   `query:"q,required" example:"groceries"`
+	Limit  *int     `query:"limit"`
+	Cursor string
+}
+
+func queryRoute(queryType reflect.Type) types.RouteInfo {
+	return types.RouteInfo{Method: "GET", Path: "/v1/notes", QueryType: queryType, ResponseType: reflect.TypeOf(TestResponse{}), Module: "notes"}
+}
+
+func TestQueryParameters_Styles(t *testing.T) {
+	gen := NewGenerator()
+	params, err := gen.queryParameters(reflect.TypeOf(ListNotesQuery{}))
+	if err != nil {
+		t.Fatalf("queryParameters() error = %v", err)
+	}
+
+	byName := make(map[string]Parameter)
+	var names []string
+	for _, param := range params {
+		if param.In != "query" {
+			t.Errorf("Parameter %s should be a query parameter", param.Name)
+		}
+		byName[param.Name] = param
+		names = append(names, param.Name)
+	}
+	if expected := []string{"tags", "ids", "owner", "q", "limit"}; !reflect.DeepEqual(names, expected) {
+		t.Fatalf("Parameters = %v, expected %v", names, expected)
+	}
+
+	tests := []struct {
+		name    string
+		style   string
+		explode *bool
+	}{
+		{"tags", "form", boolPtr(false)},
+		{"ids", "form", boolPtr(true)},
+		{"owner", "pipeDelimited", boolPtr(true)},
+		{"q", "", nil},
+		{"limit", "", nil},
+	}
+	for _, tt := range tests {
+		param := byName[tt.name]
+		if param.Style != tt.style || !reflect.DeepEqual(param.Explode, tt.explode) {
+			t.Errorf("%s: style %q explode %v, expected %q %v", tt.name, param.Style, param.Explode, tt.style, tt.explode)
+		}
+	}
+
+	if tags := byName["tags"]; tags.Schema["type"] != "array" || tags.Description != "Tags the notes must carry" || tags.Required {
+		t.Errorf("Unexpected tags parameter %+v", tags)
+	}
+	if q := byName["q"]; !q.Required || q.Schema["example"] != "groceries" {
+		t.Errorf("Expected q to be required with its example, got %+v", q)
+	}
+}
+
+func TestGenerateSpec_CSVQueryParameter(t *testing.T) {
+	gen := NewGenerator()
+	out, err := gen.GenerateSpecForRoutes([]types.RouteInfo{queryRoute(reflect.TypeOf(ListNotesQuery{}))})
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+	if !strings.Contains(out, "style: form\n") || !strings.Contains(out, "explode: false\n") {
+		t.Errorf("Expected style and explode in the YAML, got:\n%s", out)
+	}
+
+	var spec OpenAPISpec
+	if err := yaml.Unmarshal([]byte(out), &spec); err != nil {
+		t.Fatalf("Generated spec is not valid YAML: %v", err)
+	}
+	tags := spec.Paths["/v1/notes"].Get.Parameters[0]
+	if tags.Name != "tags" || tags.Style != "form" || tags.Explode == nil || *tags.Explode {
+		t.Errorf("Expected a comma-separated tags parameter, got %+v", tags)
+	}
+}
+
+func TestSampleRequest_CSVQueryParameter(t *testing.T) {
+	type csvQuery struct {
+		Tags []string `query:"tags,explode=false"`
+		IDs  []string `query:"id"`
+	}
+	gen := NewGenerator()
+	route := queryRoute(reflect.TypeOf(csvQuery{}))
+	if err := gen.LoadRoutes([]types.RouteInfo{route}); err != nil {
+		t.Fatalf("LoadRoutes() error = %v", err)
+	}
+
+	req := gen.sampleRequest(route, gen.buildOperation(route))
+	if expected := "http://localhost:8080/v1/notes?id=string&tags=string"; req.URL != expected {
+		t.Errorf("URL = %q, expected %q", req.URL, expected)
+	}
+}
+
+func TestQueryParameters_Invalid(t *testing.T) {
+	tests := []struct {
+		name      string
+		queryType interface{}
+		expected  string
+	}{
+		{"unknown style", struct {
+			Tags []string `query:"tags,style=deepObject"`
+		}{}, `unsupported style "deepObject"`},
+		{"invalid explode", struct {
+			Tags []string `query:"tags,explode=maybe"`
+		}{}, `invalid explode "maybe"`},
+		{"style on scalar", struct {
+			Name string `query:"name,style=form"`
+		}{}, "not an array"},
+		{"not a struct", "", "is not a struct"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gen := NewGenerator()
+			_, err := gen.GenerateSpecForRoutes([]types.RouteInfo{queryRoute(reflect.TypeOf(tt.queryType))})
+			if err == nil || !strings.Contains(err.Error(), tt.expected) {
+				t.Errorf("Expected an error containing %q, got %v", tt.expected, err)
+			}
+		})
+	}
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,45 @@
+package analyzer
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// checkResponseSchemas returns an error listing JSON routes without a ResponseType,
+// which would be documented as a bare success response. 204 No Content routes,
+// documentation routes, non-JSON formats and routes in EmptyResponseRoutes are allowed.
+func (g *Generator) checkResponseSchemas(routes []types.RouteInfo) error {
+	var missing []string
+	for _, route := range routes {
+		if route.ResponseType != nil || route.SuccessStatus == http.StatusNoContent ||
+			route.DocumentationRoute || !isJSONResponse(route) || g.allowsEmptyResponse(route) {
+			continue
+		}
+		missing = append(missing, fmt.Sprintf("%s %s (%s module)", strings.ToUpper(route.Method), route.Path, route.Module))
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("routes have no response schema; set ResponseType, SuccessStatus 204 or allow the empty response:\n  %s", strings.Join(missing, "\n  "))
+	}
+	return nil
+}
+
+// allowsEmptyResponse reports whether route matches an EmptyResponseRoutes entry,
+// either "METHOD /path" or a bare path for every method
+func (g *Generator) allowsEmptyResponse(route types.RouteInfo) bool {
+	for _, entry := range g.EmptyResponseRoutes {
+		method, path, hasMethod := strings.Cut(strings.TrimSpace(entry), " ")
+		if !hasMethod {
+			if method == route.Path {
+				return true
+			}
+			continue
+		}
+		if strings.EqualFold(method, route.Method) && strings.TrimSpace(path) == route.Path {
+			return true
+		}
+	}
+	return false
+}
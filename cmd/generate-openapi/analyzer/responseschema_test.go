@@ -0,0 +1,53 @@
+package analyzer
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// emptyResponseRoutes returns a typed route, a 204 route and an unannotated route
+// without a response type
+func emptyResponseRoutes() []types.RouteInfo {
+	return []types.RouteInfo{
+		{Method: "GET", Path: "/users/{id}", ResponseType: reflect.TypeOf(User{}), Module: "users"},
+		{Method: "DELETE", Path: "/users/{id}", SuccessStatus: http.StatusNoContent, Module: "users"},
+		{Method: "POST", Path: "/users/{id}/ping", Module: "users"},
+	}
+}
+
+func TestRequireResponseSchema_FailsOnNilResponseType(t *testing.T) {
+	_, err := NewGenerator(WithRequireResponseSchema(true)).GenerateSpecForRoutes(emptyResponseRoutes())
+	if err == nil {
+		t.Fatal("GenerateSpecForRoutes() should fail for a route without a response type")
+	}
+	if !strings.Contains(err.Error(), "POST /users/{id}/ping (users module)") {
+		t.Errorf("Expected the error to name the route, got %v", err)
+	}
+	if strings.Contains(err.Error(), "DELETE") {
+		t.Errorf("204 routes should not need a response type, got %v", err)
+	}
+}
+
+func TestRequireResponseSchema_AllowList(t *testing.T) {
+	for _, entry := range []string{"POST /users/{id}/ping", "post /users/{id}/ping", "/users/{id}/ping"} {
+		gen := NewGenerator(WithRequireResponseSchema(true, entry))
+		if _, err := gen.GenerateSpecForRoutes(emptyResponseRoutes()); err != nil {
+			t.Errorf("allow-list entry %q: GenerateSpecForRoutes() error = %v", entry, err)
+		}
+	}
+
+	gen := NewGenerator(WithRequireResponseSchema(true, "GET /users/{id}/ping"))
+	if _, err := gen.GenerateSpecForRoutes(emptyResponseRoutes()); err == nil {
+		t.Error("An entry for another method should not allow the POST route")
+	}
+}
+
+func TestRequireResponseSchema_DisabledByDefault(t *testing.T) {
+	if _, err := NewGenerator().GenerateSpecForRoutes(emptyResponseRoutes()); err != nil {
+		t.Errorf("GenerateSpecForRoutes() error = %v, expected nil response types to be allowed by default", err)
+	}
+}
@@ -0,0 +1,161 @@
+package analyzer
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/openapi/oauth"
+)
+
+// OAuth2SchemeName is the security scheme name the OAuth2 config is emitted under
+const OAuth2SchemeName = "OAuth2"
+
+// SecurityScheme defines a security scheme operations can require
+type SecurityScheme struct {
+	Type         string      `yaml:"type"`
+	Description  string      `yaml:"description,omitempty"`
+	Name         string      `yaml:"name,omitempty"`
+	In           string      `yaml:"in,omitempty"`
+	Scheme       string      `yaml:"scheme,omitempty"`
+	BearerFormat string      `yaml:"bearerFormat,omitempty"`
+	Flows        *OAuthFlows `yaml:"flows,omitempty"`
+}
+
+// OAuthFlows lists the OAuth2 flows a scheme supports
+type OAuthFlows struct {
+	AuthorizationCode *OAuthFlow `yaml:"authorizationCode,omitempty"`
+}
+
+// OAuthFlow describes a single OAuth2 flow
+type OAuthFlow struct {
+	AuthorizationURL string            `yaml:"authorizationUrl,omitempty"`
+	TokenURL         string            `yaml:"tokenUrl,omitempty"`
+	RefreshURL       string            `yaml:"refreshUrl,omitempty"`
+	Scopes           map[string]string `yaml:"scopes"`
+}
+
+// SecurityRequirement names the security schemes an operation requires, mapped to
+// the OAuth2 scopes needed (empty for other scheme types)
+type SecurityRequirement map[string][]string
+
+// ValidationError reports routes that reference security schemes the generator
+// has not registered, which would leave dangling references in the spec
+type ValidationError struct {
+	// MissingSchemes maps each unregistered scheme to the routes referencing it,
+	// as "METHOD /path"
+	MissingSchemes map[string][]string
+}
+
+// Error lists each missing scheme with the routes that reference it
+func (e *ValidationError) Error() string {
+	names := make([]string, 0, len(e.MissingSchemes))
+	for name := range e.MissingSchemes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	lines := make([]string, 0, len(names))
+	for _, name := range names {
+		lines = append(lines, fmt.Sprintf("%s (referenced by %s)", name, strings.Join(e.MissingSchemes[name], ", ")))
+	}
+	return fmt.Sprintf("routes reference unregistered security schemes; register them with AddSecurityScheme:\n  %s", strings.Join(lines, "\n  "))
+}
+
+// checkSecuritySchemes returns a ValidationError when a route's Security names a
+// scheme that is neither registered with AddSecurityScheme nor configured by docs.oauth2
+func (g *Generator) checkSecuritySchemes(routes []types.RouteInfo) error {
+	var registered map[string]SecurityScheme
+	missing := make(map[string][]string)
+	for _, route := range routes {
+		if len(route.Security) == 0 {
+			continue
+		}
+		if registered == nil {
+			registered = g.buildSecuritySchemes()
+		}
+		for _, name := range route.Security {
+			if _, ok := registered[name]; !ok {
+				missing[name] = append(missing[name], fmt.Sprintf("%s %s", strings.ToUpper(route.Method), route.Path))
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return &ValidationError{MissingSchemes: missing}
+	}
+	return nil
+}
+
+// securityRequirements returns the requirements for a route: one per scheme in its
+// Security, any one of which authorizes it, plus the OAuth2 scheme when docs.oauth2
+// protects the route's module. OAuth2 requirements request every configured scope.
+func securityRequirements(route types.RouteInfo) []SecurityRequirement {
+	oauth2, _ := oauth.Load() // Errors are reported with the security schemes
+	names := route.Security
+	if oauth2.Enabled() && slices.Contains(oauth2.Modules, route.Module) && !slices.Contains(names, OAuth2SchemeName) {
+		names = append(slices.Clip(names), OAuth2SchemeName)
+	}
+
+	var requirements []SecurityRequirement
+	for _, name := range names {
+		scopes := []string{}
+		if name == OAuth2SchemeName {
+			scopes = append(scopes, slices.Sorted(maps.Keys(oauth2.Scopes))...)
+		}
+		requirements = append(requirements, SecurityRequirement{name: scopes})
+	}
+	return requirements
+}
+
+// AddSecurityScheme registers a security scheme emitted under components.securitySchemes
+func (g *Generator) AddSecurityScheme(name string, scheme SecurityScheme) {
+	if g.securitySchemes == nil {
+		g.securitySchemes = make(map[string]SecurityScheme)
+	}
+	g.securitySchemes[name] = scheme
+}
+
+// buildSecuritySchemes returns the registered security schemes plus the OAuth2
+// scheme when docs.oauth2 configures one, or nil when there are none
+func (g *Generator) buildSecuritySchemes() map[string]SecurityScheme {
+	oauth2, err := oauth.Load()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	}
+	if len(g.securitySchemes) == 0 && !oauth2.Enabled() {
+		return nil
+	}
+
+	schemes := make(map[string]SecurityScheme, len(g.securitySchemes)+1)
+	for name, scheme := range g.securitySchemes {
+		schemes[name] = scheme
+	}
+	if oauth2.Enabled() {
+		schemes[OAuth2SchemeName] = oauth2Scheme(oauth2)
+	}
+	return schemes
+}
+
+// oauth2Scheme returns the authorization code security scheme for an OAuth2 config
+func oauth2Scheme(oauth2 oauth.Config) SecurityScheme {
+	scopes := oauth2.Scopes
+	if scopes == nil {
+		scopes = map[string]string{} // The scopes map is required even when empty
+	}
+	return SecurityScheme{
+		Type: "oauth2",
+		Flows: &OAuthFlows{
+			AuthorizationCode: &OAuthFlow{
+				AuthorizationURL: oauth2.AuthorizationURL,
+				TokenURL:         oauth2.TokenURL,
+				RefreshURL:       oauth2.RefreshURL,
+				Scopes:           scopes,
+			},
+		},
+	}
+}
@@ -0,0 +1,177 @@
+package analyzer
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
+	"github.com/JerkyTreats/llm/internal/openapi/oauth"
+	"gopkg.in/yaml.v3"
+)
+
+func TestBuildSecuritySchemes_NoneConfigured(t *testing.T) {
+	config.ResetForTest()
+	config.SetConfigPath("/nonexistent/path/config.json")
+	t.Cleanup(config.ResetForTest)
+
+	gen := NewGenerator()
+	if schemes := gen.buildSecuritySchemes(); schemes != nil {
+		t.Errorf("buildSecuritySchemes() = %v, expected nil", schemes)
+	}
+}
+
+func TestBuildSecuritySchemes_OAuth2FromConfig(t *testing.T) {
+	config.ResetForTest()
+	config.SetConfigPath("/nonexistent/path/config.json")
+	t.Cleanup(config.ResetForTest)
+
+	config.SetForTest(oauth.ConfigKey, map[string]interface{}{
+		"authorization_url": "https://idp.example.com/authorize",
+		"token_url":         "https://idp.example.com/token",
+		"client_id":         "docs-ui",
+		"use_pkce":          true,
+		"scopes":            map[string]interface{}{"chat": "Create chat completions"},
+	})
+
+	gen := NewGenerator()
+	gen.AddSecurityScheme("ApiKeyAuth", SecurityScheme{Type: "apiKey", Name: "X-API-Key", In: "header"})
+	schemes := gen.buildSecuritySchemes()
+
+	expected := map[string]SecurityScheme{
+		"ApiKeyAuth": {Type: "apiKey", Name: "X-API-Key", In: "header"},
+		OAuth2SchemeName: {
+			Type: "oauth2",
+			Flows: &OAuthFlows{
+				AuthorizationCode: &OAuthFlow{
+					AuthorizationURL: "https://idp.example.com/authorize",
+					TokenURL:         "https://idp.example.com/token",
+					Scopes:           map[string]string{"chat": "Create chat completions"},
+				},
+			},
+		},
+	}
+	if !reflect.DeepEqual(schemes, expected) {
+		t.Errorf("buildSecuritySchemes() = %+v, expected %+v", schemes, expected)
+	}
+}
+
+func TestBuildOpenAPISpec_OAuth2SecurityScheme(t *testing.T) {
+	config.ResetForTest()
+	config.SetConfigPath("/nonexistent/path/config.json")
+	t.Cleanup(config.ResetForTest)
+
+	config.SetForTest(oauth.ConfigKey, map[string]interface{}{
+		"authorization_url": "https://idp.example.com/authorize",
+		"token_url":         "https://idp.example.com/token",
+	})
+
+	spec := NewGenerator().buildOpenAPISpec()
+
+	expected := `    securitySchemes:
+        OAuth2:
+            type: oauth2
+            flows:
+                authorizationCode:
+                    authorizationUrl: https://idp.example.com/authorize
+                    tokenUrl: https://idp.example.com/token
+                    scopes: {}
+`
+	if !strings.Contains(spec, expected) {
+		t.Errorf("Expected OAuth2 security scheme in spec:\n%s", spec)
+	}
+}
+
+func TestGenerateSpecForRoutes_MissingSecurityScheme(t *testing.T) {
+	config.ResetForTest()
+	config.SetConfigPath("/nonexistent/path/config.json")
+	t.Cleanup(config.ResetForTest)
+
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/v1/models", ResponseType: reflect.TypeOf(TestResponse{}), Module: "models", Security: []string{"ApiKeyAuth"}},
+		{Method: "POST", Path: "/v1/chat", RequestType: reflect.TypeOf(TestRequest{}), ResponseType: reflect.TypeOf(TestResponse{}), Module: "chat", Security: []string{"BearerAuth", "ApiKeyAuth"}},
+	}
+
+	gen := NewGenerator()
+	gen.AddSecurityScheme("ApiKeyAuth", SecurityScheme{Type: "apiKey", Name: "X-API-Key", In: "header"})
+	_, err := gen.GenerateSpecForRoutes(routes)
+
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("GenerateSpecForRoutes() error = %v, expected a ValidationError", err)
+	}
+	expected := map[string][]string{"BearerAuth": {"POST /v1/chat"}}
+	if !reflect.DeepEqual(validationErr.MissingSchemes, expected) {
+		t.Errorf("MissingSchemes = %v, expected %v", validationErr.MissingSchemes, expected)
+	}
+	if message := err.Error(); !strings.Contains(message, "BearerAuth") || !strings.Contains(message, "POST /v1/chat") {
+		t.Errorf("Error should name the scheme and the route, got %q", message)
+	}
+}
+
+func TestGenerateSpecForRoutes_RouteSecurity(t *testing.T) {
+	config.ResetForTest()
+	config.SetConfigPath("/nonexistent/path/config.json")
+	t.Cleanup(config.ResetForTest)
+
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/v1/models", ResponseType: reflect.TypeOf(TestResponse{}), Module: "models", Security: []string{"ApiKeyAuth"}},
+	}
+
+	gen := NewGenerator()
+	gen.AddSecurityScheme("ApiKeyAuth", SecurityScheme{Type: "apiKey", Name: "X-API-Key", In: "header"})
+	spec, err := gen.GenerateSpecForRoutes(routes)
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	var parsed OpenAPISpec
+	if err := yaml.Unmarshal([]byte(spec), &parsed); err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	security := parsed.Paths["/v1/models"].Get.Security
+	expected := []SecurityRequirement{{"ApiKeyAuth": {}}}
+	if !reflect.DeepEqual(security, expected) {
+		t.Errorf("Operation security = %v, expected %v", security, expected)
+	}
+}
+
+func TestGenerateSpecForRoutes_OAuth2ProtectsModules(t *testing.T) {
+	config.ResetForTest()
+	config.SetConfigPath("/nonexistent/path/config.json")
+	t.Cleanup(config.ResetForTest)
+
+	config.SetForTest(oauth.ConfigKey, map[string]interface{}{
+		"authorization_url": "https://idp.example.com/authorize",
+		"token_url":         "https://idp.example.com/token",
+		"scopes":            map[string]interface{}{"chat": "Create chat completions", "admin": "Manage the API"},
+		"modules":           []interface{}{"sessions"},
+	})
+
+	routes := []types.RouteInfo{
+		{Method: "POST", Path: "/v1/sessions", ResponseType: reflect.TypeOf(TestResponse{}), Module: "sessions"},
+		{Method: "GET", Path: "/v1/models", ResponseType: reflect.TypeOf(TestResponse{}), Module: "models", Security: []string{OAuth2SchemeName}},
+		{Method: "GET", Path: "/health", ResponseType: reflect.TypeOf(TestResponse{}), Module: "health"},
+	}
+	spec, err := NewGenerator().GenerateSpecForRoutes(routes)
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	var parsed OpenAPISpec
+	if err := yaml.Unmarshal([]byte(spec), &parsed); err != nil {
+		t.Fatalf("Failed to parse spec: %v", err)
+	}
+	expected := []SecurityRequirement{{OAuth2SchemeName: {"admin", "chat"}}}
+	if security := parsed.Paths["/v1/sessions"].Post.Security; !reflect.DeepEqual(security, expected) {
+		t.Errorf("Protected module security = %v, expected %v", security, expected)
+	}
+	if security := parsed.Paths["/v1/models"].Get.Security; !reflect.DeepEqual(security, expected) {
+		t.Errorf("Route security = %v, expected %v", security, expected)
+	}
+	if security := parsed.Paths["/health"].Get.Security; security != nil {
+		t.Errorf("Unprotected routes should have no security, got %v", security)
+	}
+}
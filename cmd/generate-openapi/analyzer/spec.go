@@ -2,26 +2,61 @@ package analyzer
 
 import (
 	"fmt"
+	"maps"
+	"net/http"
+	"os"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
 	"gopkg.in/yaml.v3"
 )
 
+// specHeader is prepended to every generated specification
+const specHeader = "# Auto-generated OpenAPI specification\n# DO NOT EDIT MANUALLY - Changes will be overwritten\n\n"
+
 // OpenAPISpec represents the complete OpenAPI 3.0 specification structure
 type OpenAPISpec struct {
 	OpenAPI    string                 `yaml:"openapi"`
 	Info       Info                   `yaml:"info"`
 	Servers    []Server               `yaml:"servers"`
+	Tags       []Tag                  `yaml:"tags,omitempty"`
 	Paths      map[string]PathItem    `yaml:"paths"`
 	Components Components             `yaml:"components"`
+	// Defs holds the component schemas in OpenAPI 3.1 specs generated with UseJSONSchemaDefs
+	Defs       map[string]interface{} `yaml:"$defs,omitempty"`
 }
 
 // Info contains API metadata
 type Info struct {
-	Title       string `yaml:"title"`
-	Description string `yaml:"description"`
-	Version     string `yaml:"version"`
+	Title       string   `yaml:"title"`
+	Description string   `yaml:"description"`
+	Version     string   `yaml:"version"`
+	Contact     *Contact `yaml:"contact,omitempty"`
+	License     *License `yaml:"license,omitempty"`
+}
+
+// Contact is the contact information for the exposed API
+type Contact struct {
+	Name  string `yaml:"name,omitempty"`
+	URL   string `yaml:"url,omitempty"`
+	Email string `yaml:"email,omitempty"`
+}
+
+// License is the license the exposed API is offered under
+type License struct {
+	Name string `yaml:"name"`
+	URL  string `yaml:"url,omitempty"`
+}
+
+// Tag describes a tag used to group operations
+type Tag struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description,omitempty"`
 }
 
 // Server represents an API server
@@ -36,16 +71,87 @@ type PathItem struct {
 	Post   *Operation `yaml:"post,omitempty"`
 	Put    *Operation `yaml:"put,omitempty"`
 	Delete *Operation `yaml:"delete,omitempty"`
+
+	// Parameters apply to every operation on the path
+	Parameters []Parameter `yaml:"parameters,omitempty"`
+}
+
+// Operations returns the non-nil operations defined on the path
+func (p PathItem) Operations() []*Operation {
+	var operations []*Operation
+	for _, entry := range p.methodOperations() {
+		operations = append(operations, entry.op)
+	}
+	return operations
+}
+
+// methodOperation is an operation with the HTTP method it is defined under
+type methodOperation struct {
+	method string
+	op     *Operation
+}
+
+// methodOperations returns the non-nil operations defined on the path with their methods
+func (p PathItem) methodOperations() []methodOperation {
+	var operations []methodOperation
+	for _, entry := range []methodOperation{{"GET", p.Get}, {"POST", p.Post}, {"PUT", p.Put}, {"DELETE", p.Delete}} {
+		if entry.op != nil {
+			operations = append(operations, entry)
+		}
+	}
+	return operations
+}
+
+// setOperation stores op under method; unsupported methods are ignored
+func (p *PathItem) setOperation(method string, op *Operation) {
+	switch strings.ToUpper(method) {
+	case "GET":
+		p.Get = op
+	case "POST":
+		p.Post = op
+	case "PUT":
+		p.Put = op
+	case "DELETE":
+		p.Delete = op
+	}
 }
 
 // Operation describes a single API operation
 type Operation struct {
-	Tags        []string            `yaml:"tags,omitempty"`
-	Summary     string              `yaml:"summary,omitempty"`
-	Description string              `yaml:"description,omitempty"`
-	OperationID string              `yaml:"operationId,omitempty"`
-	RequestBody *RequestBody        `yaml:"requestBody,omitempty"`
-	Responses   map[string]Response `yaml:"responses"`
+	Tags         []string              `yaml:"tags,omitempty"`
+	Summary      string                `yaml:"summary,omitempty"`
+	Description  string                `yaml:"description,omitempty"`
+	OperationID  string                `yaml:"operationId,omitempty"`
+	Parameters   []Parameter           `yaml:"parameters,omitempty"`
+	RequestBody  *RequestBody          `yaml:"requestBody,omitempty"`
+	Responses    map[string]Response   `yaml:"responses"`
+	Callbacks    map[string]Callback   `yaml:"callbacks,omitempty"`
+	Security     []SecurityRequirement `yaml:"security,omitempty"`
+	Deprecated   bool                  `yaml:"deprecated,omitempty"`
+	XSunset      string                `yaml:"x-sunset,omitempty"`
+	XRateLimit   *RateLimit            `yaml:"x-rate-limit,omitempty"`
+	XCodeSamples []CodeSample          `yaml:"x-codeSamples,omitempty"`
+}
+
+// Callback maps runtime URL expressions to the requests the API sends there
+type Callback map[string]PathItem
+
+// RateLimit documents a route's rate-limiting policy as the x-rate-limit extension
+type RateLimit struct {
+	Requests int    `yaml:"requests"`
+	Per      string `yaml:"per"`
+	Burst    int    `yaml:"burst,omitempty"`
+}
+
+// Parameter describes a single operation parameter
+type Parameter struct {
+	Name        string                 `yaml:"name"`
+	In          string                 `yaml:"in"`
+	Description string                 `yaml:"description,omitempty"`
+	Required    bool                   `yaml:"required,omitempty"`
+	Style       string                 `yaml:"style,omitempty"`
+	Explode     *bool                  `yaml:"explode,omitempty"`
+	Schema      map[string]interface{} `yaml:"schema"`
 }
 
 // RequestBody describes the request body
@@ -57,42 +163,86 @@ type RequestBody struct {
 
 // MediaTypeObject provides schema and examples for media type
 type MediaTypeObject struct {
-	Schema SchemaRef `yaml:"schema"`
+	Schema   SchemaRef          `yaml:"schema"`
+	Examples map[string]Example `yaml:"examples,omitempty"`
 }
 
 // Response describes a single response
 type Response struct {
 	Description string                     `yaml:"description"`
+	Headers     map[string]Header          `yaml:"headers,omitempty"`
 	Content     map[string]MediaTypeObject `yaml:"content,omitempty"`
+	Links       map[string]LinkRef         `yaml:"links,omitempty"`
 }
 
-// SchemaRef is a reference to a schema
+// Header describes a response header
+type Header struct {
+	Description string                 `yaml:"description,omitempty"`
+	Schema      map[string]interface{} `yaml:"schema"`
+}
+
+// SchemaRef is a reference to a component schema, or an inline schema for types
+// that do not warrant a component
 type SchemaRef struct {
-	Ref string `yaml:"$ref,omitempty"`
+	Ref    string                 `yaml:"$ref,omitempty"`
+	Inline map[string]interface{} `yaml:",inline"`
 }
 
 // Components holds reusable objects for different aspects of the OAS
 type Components struct {
-	Schemas map[string]interface{} `yaml:"schemas"`
+	Schemas         map[string]interface{}    `yaml:"schemas,omitempty"`
+	SecuritySchemes map[string]SecurityScheme `yaml:"securitySchemes,omitempty"`
+	Links           map[string]Link           `yaml:"links,omitempty"`
 }
 
 // buildOpenAPISpec builds the complete OpenAPI specification
 func (g *Generator) buildOpenAPISpec() string {
+	paths := g.buildPaths()
+
 	spec := OpenAPISpec{
-		OpenAPI: "3.0.3",
+		OpenAPI: g.openAPIVersion(),
 		Info: Info{
 			Title:       "LLM API",
 			Description: "Auto-generated API documentation for LLM service with zero-maintenance updates",
-			Version:     "1.0.0",
+			Version:     g.infoVersion(),
+			Contact:     g.infoContact,
+			License:     g.infoLicense,
 		},
-		Servers: []Server{
-			{
-				URL:         "http://localhost:8080",
-				Description: "Development server",
-			},
+		Servers:    g.buildServers(),
+		Tags:       g.buildTags(),
+		Paths:      paths,
+		Components: Components{
+			Schemas:         g.typeSchemas,
+			SecuritySchemes: g.buildSecuritySchemes(),
 		},
-		Paths:      g.buildPaths(),
-		Components: Components{Schemas: g.typeSchemas},
+	}
+
+	// Only include component schemas that the paths actually reference
+	g.prunedSchemas = nil
+	if !g.KeepUnusedSchemas {
+		spec.Components.Schemas = g.pruneSchemas(paths)
+	}
+
+	// Share response links through components so a link is defined once
+	spec.Components.Links = g.hoistLinks(paths)
+
+	// Schemas a hook skipped are gone, so references to them must go too
+	g.dropSkippedRefs(&spec)
+
+	for _, hook := range g.specHooks {
+		hook(&spec)
+	}
+
+	if g.Dereference {
+		spec.Dereference()
+	}
+
+	if g.openAPIVersion() == OpenAPIVersion31 {
+		spec.ConvertSchemasTo31()
+	}
+
+	if g.useJSONSchemaDefs() {
+		spec.MoveSchemasToDefs()
 	}
 
 	// Convert to YAML
@@ -102,60 +252,368 @@ func (g *Generator) buildOpenAPISpec() string {
 	}
 
 	// Add header comment
-	header := "# Auto-generated OpenAPI specification\n# DO NOT EDIT MANUALLY - Changes will be overwritten\n\n"
-	
-	return header + string(yamlData)
+	if g.omitHeader {
+		return string(yamlData)
+	}
+	return specHeader + string(yamlData)
 }
 
+// serversConfigKey is the config key listing the servers shown in the spec
+const serversConfigKey = "openapi.servers"
+
+func init() {
+	config.RegisterKey(serversConfigKey, []interface{}{})
+	config.RegisterKey(VersionConfigKey, "")
+}
+
+// buildServers returns Generator.Servers or the servers configured under
+// openapi.servers, defaulting to the local dev server
+func (g *Generator) buildServers() []Server {
+	if len(g.Servers) > 0 {
+		return g.Servers
+	}
+
+	var servers []Server
+	if err := config.UnmarshalKey(serversConfigKey, &servers); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to read %s from config: %v\n", serversConfigKey, err)
+		servers = nil
+	}
+
+	if len(servers) == 0 {
+		return []Server{
+			{
+				URL:         "http://localhost:8080",
+				Description: "Development server",
+			},
+		}
+	}
+
+	return servers
+}
+
+// WarningDuplicatePath reports a route dropped because NormalizePaths made it
+// collide with an earlier route for the same method
+const WarningDuplicatePath = "duplicate-path"
+
 // buildPaths builds the paths section of the OpenAPI spec
 func (g *Generator) buildPaths() map[string]PathItem {
 	paths := make(map[string]PathItem)
 
+	// De-duplicate in registration order, so sorting cannot change which route wins
+	routes := make([]types.RouteInfo, 0, len(g.routes))
+	built := make(map[string]string) // "METHOD /path" to the route kept for it
 	for _, route := range g.routes {
-		pathItem, exists := paths[route.Path]
-		if !exists {
-			pathItem = PathItem{}
+		route.Path = g.prefixPath(route.Path)
+
+		if g.NormalizePaths {
+			original := route.Path
+			route.Path = normalizePath(route.Path)
+
+			key := strings.ToUpper(route.Method) + " " + route.Path
+			if first, duplicate := built[key]; duplicate {
+				g.warnings = append(g.warnings, Warning{
+					Category: WarningDuplicatePath,
+					Module:   route.Module,
+					Route:    strings.ToUpper(route.Method) + " " + original,
+					Message:  fmt.Sprintf("duplicates %s after path normalization; keeping the first registered route", first),
+				})
+				continue
+			}
+			built[key] = strings.ToUpper(route.Method) + " " + original
 		}
+		routes = append(routes, route)
+	}
 
+	if g.SortPaths {
+		routes = SortRoutes(routes)
+	}
+
+	for _, route := range routes {
 		operation := g.buildOperation(route)
-		
-		switch strings.ToUpper(route.Method) {
-		case "GET":
-			pathItem.Get = operation
-		case "POST":
-			pathItem.Post = operation
-		case "PUT":
-			pathItem.Put = operation
-		case "DELETE":
-			pathItem.Delete = operation
+		if g.skipOperation(route, operation) {
+			continue
+		}
+
+		pathItem, exists := paths[route.Path]
+		if !exists {
+			pathItem = PathItem{}
 		}
+		pathItem.setOperation(route.Method, operation)
 
 		paths[route.Path] = pathItem
 	}
 
+	if g.HoistSharedParams {
+		for path, pathItem := range paths {
+			paths[path] = hoistPathParameters(pathItem)
+		}
+	}
+
 	return paths
 }
 
+// normalizePath strips trailing slashes from a path, keeping the root path "/"
+func normalizePath(path string) string {
+	if trimmed := strings.TrimRight(path, "/"); trimmed != "" {
+		return trimmed
+	}
+	return "/"
+}
+
+// hoistPathParameters moves path parameters declared identically by every operation
+// on a path to the path item, leaving method-specific parameters on the operations
+func hoistPathParameters(pathItem PathItem) PathItem {
+	operations := pathItem.Operations()
+	if len(operations) == 0 {
+		return pathItem
+	}
+
+	var shared []Parameter
+	for _, param := range operations[0].Parameters {
+		if param.In != "path" {
+			continue
+		}
+		inAll := true
+		for _, op := range operations[1:] {
+			if !hasParameter(op.Parameters, param) {
+				inAll = false
+				break
+			}
+		}
+		if inAll {
+			shared = append(shared, param)
+		}
+	}
+
+	if len(shared) == 0 {
+		return pathItem
+	}
+
+	for _, op := range operations {
+		var remaining []Parameter
+		for _, param := range op.Parameters {
+			if !hasParameter(shared, param) {
+				remaining = append(remaining, param)
+			}
+		}
+		op.Parameters = remaining
+	}
+	pathItem.Parameters = shared
+
+	return pathItem
+}
+
+// hasParameter reports whether params contains a parameter identical to param
+func hasParameter(params []Parameter, param Parameter) bool {
+	for _, candidate := range params {
+		if reflect.DeepEqual(candidate, param) {
+			return true
+		}
+	}
+	return false
+}
+
+// pathParameters returns a required string parameter for each {name} template in path
+func pathParameters(path string) []Parameter {
+	var params []Parameter
+	for _, segment := range strings.Split(path, "/") {
+		if strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}") {
+			params = append(params, Parameter{
+				Name:     strings.Trim(segment, "{}"),
+				In:       "path",
+				Required: true,
+				Schema:   map[string]interface{}{"type": "string"},
+			})
+		}
+	}
+	return params
+}
+
 // buildOperation builds an Operation from a RouteInfo
 func (g *Generator) buildOperation(route types.RouteInfo) *Operation {
 	operation := &Operation{
-		Tags:        []string{route.Module},
+		Tags:        routeTags(route),
 		Summary:     route.Summary,
 		OperationID: g.generateOperationID(route),
 		Responses:   g.buildResponses(route),
+		Deprecated:  route.Deprecated,
+		XSunset:     route.SunsetDate,
+	}
+
+	if route.HandlerFuncName != "" && !g.SkipHandlerDocs {
+		g.applyHandlerDoc(route, operation)
+	}
+
+	if route.RateLimit != nil {
+		operation.XRateLimit = &RateLimit{
+			Requests: route.RateLimit.Requests,
+			Per:      route.RateLimit.Per,
+			Burst:    route.RateLimit.BurstSize,
+		}
+	}
+
+	// Path templates such as /users/{id} declare their parameters
+	operation.Parameters = append(operation.Parameters, pathParameters(route.Path)...)
+
+	// Document the query parameters of list endpoints
+	operation.Parameters = append(operation.Parameters, routePaginationParameters(route)...)
+
+	// Query parameters declared by the route's query struct; validated with the schemas
+	if route.QueryType != nil {
+		params, _ := g.queryParameters(route.QueryType)
+		operation.Parameters = append(operation.Parameters, params...)
+	}
+
+	// Request headers the route reads; reserved headers are left to securitySchemes and content negotiation
+	operation.Parameters = append(operation.Parameters, headerParameters(route.HeaderParams)...)
+
+	// Any one of the route's schemes authorizes it; validated against the registry
+	operation.Security = securityRequirements(route)
+
+	if len(route.Callbacks) > 0 {
+		operation.Callbacks = g.buildCallbacks(route.Callbacks)
+	}
+
+	// Idempotent routes accept a client-chosen key for safe retries
+	if route.Idempotent {
+		operation.Parameters = append(operation.Parameters, Parameter{
+			Name:        "Idempotency-Key",
+			In:          "header",
+			Description: "Unique key that makes retries of this request replay the first response",
+			Schema:      map[string]interface{}{"type": "string"},
+		})
 	}
 
 	// Add request body for non-GET methods
-	if route.RequestType != nil && strings.ToUpper(route.Method) != "GET" {
+	hasBody := route.RequestType != nil || route.RequestContentType == types.ContentTypeMultipartForm
+	if hasBody && strings.ToUpper(route.Method) != "GET" {
 		operation.RequestBody = g.buildRequestBody(route)
 	}
 
+	if g.CodeSamples || g.CodeSamplesInDescription {
+		operation.XCodeSamples = g.buildCodeSamples(route, operation)
+		if g.CodeSamplesInDescription && len(operation.XCodeSamples) > 0 {
+			operation.Description = strings.TrimSpace(operation.Description + "\n\n" + codeSamplesMarkdown(operation.XCodeSamples))
+		}
+	}
+
 	return operation
 }
 
+// buildCallbacks documents the requests a route sends to client-supplied URLs. The
+// callbacks were validated when schemas were generated.
+func (g *Generator) buildCallbacks(callbacks map[string]types.CallbackInfo) map[string]Callback {
+	result := make(map[string]Callback, len(callbacks))
+	for name, cb := range callbacks {
+		var pathItem PathItem
+		for method, op := range callbackOperations(cb) {
+			pathItem.setOperation(method, g.buildCallbackOperation(op))
+		}
+		result[name] = Callback{cb.Expression: pathItem}
+	}
+	return result
+}
+
+// buildCallbackOperation documents one request sent to a callback URL
+func (g *Generator) buildCallbackOperation(op *types.CallbackOperation) *Operation {
+	operation := &Operation{
+		Summary:    op.Summary,
+		Parameters: headerParameters(op.HeaderParams),
+		Responses:  make(map[string]Response),
+	}
+	if op.RequestType != nil {
+		operation.RequestBody = &RequestBody{
+			Required: true,
+			Content: map[string]MediaTypeObject{
+				"application/json": {Schema: g.schemaRef(op.RequestType)},
+			},
+		}
+	}
+
+	for status, description := range op.Responses {
+		if description == "" {
+			description = http.StatusText(status)
+		}
+		operation.Responses[strconv.Itoa(status)] = Response{Description: description}
+	}
+	if len(operation.Responses) == 0 {
+		operation.Responses["2XX"] = Response{Description: "Callback received"}
+	}
+	return operation
+}
+
+// callbackOperations returns a callback's requests keyed by HTTP method
+func callbackOperations(cb types.CallbackInfo) map[string]*types.CallbackOperation {
+	ops := make(map[string]*types.CallbackOperation)
+	for method, op := range map[string]*types.CallbackOperation{
+		"GET":    cb.PathItem.Get,
+		"POST":   cb.PathItem.Post,
+		"PUT":    cb.PathItem.Put,
+		"DELETE": cb.PathItem.Delete,
+	} {
+		if op != nil {
+			ops[method] = op
+		}
+	}
+	return ops
+}
+
+// validateCallback reports callbacks the spec cannot represent
+func validateCallback(name string, cb types.CallbackInfo) error {
+	if name == "" {
+		return fmt.Errorf("callback with expression %q has no name", cb.Expression)
+	}
+	if cb.Expression == "" {
+		return fmt.Errorf("callback %s has no URL expression", name)
+	}
+	ops := callbackOperations(cb)
+	if len(ops) == 0 {
+		return fmt.Errorf("callback %s has no operations", name)
+	}
+	for _, method := range slices.Sorted(maps.Keys(ops)) {
+		for status := range ops[method].Responses {
+			if status < 100 || status > 599 {
+				return fmt.Errorf("callback %s %s documents invalid status %d", name, method, status)
+			}
+		}
+	}
+	return nil
+}
+
+// routeTags returns the tags for a route, falling back to its module name
+func routeTags(route types.RouteInfo) []string {
+	if len(route.Tags) > 0 {
+		return route.Tags
+	}
+	return []string{route.Module}
+}
+
+// buildTags returns the unique tags used across all routes, sorted by name. Tags
+// with a description but no routes are omitted so they do not show as empty
+// sections in Swagger UI.
+func (g *Generator) buildTags() []Tag {
+	seen := make(map[string]bool)
+	var names []string
+	for _, route := range g.routes {
+		for _, name := range routeTags(route) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+
+	tags := make([]Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, Tag{Name: name, Description: g.tagDescriptions[name]})
+	}
+	return tags
+}
+
 // generateOperationID generates a unique operation ID
 func (g *Generator) generateOperationID(route types.RouteInfo) string {
-	// Convert path to camelCase operation name
+	// Convert path to an operation name in the configured style
 	pathParts := strings.Split(strings.Trim(route.Path, "/"), "/")
 	var allParts []string
 	
@@ -195,32 +653,55 @@ func (g *Generator) generateOperationID(route types.RouteInfo) string {
 	}
 
 	// Add path parts
-	for i, part := range allParts {
-		if part == "" {
-			continue
-		}
-		if i == 0 {
+	for _, part := range allParts {
+		if part != "" {
 			operationParts = append(operationParts, part)
-		} else {
-			operationParts = append(operationParts, strings.Title(part))
 		}
 	}
 
-	return strings.Join(operationParts, "")
+	return g.joinOperationID(operationParts)
+}
+
+// operationIDAcronyms are path segments written in upper case within operation IDs
+var operationIDAcronyms = map[string]bool{
+	"api":  true,
+	"http": true,
+	"id":   true,
+	"url":  true,
+	"uuid": true,
+}
+
+// titleSegment capitalizes a path segment for an operation ID. Path parameter braces
+// are dropped and known acronyms are upper-cased regardless of their input casing.
+func titleSegment(segment string) string {
+	segment = strings.Trim(segment, "{}")
+	if operationIDAcronyms[strings.ToLower(segment)] {
+		return strings.ToUpper(segment)
+	}
+	return capitalize(segment)
 }
 
 // buildRequestBody builds the request body specification
 func (g *Generator) buildRequestBody(route types.RouteInfo) *RequestBody {
-	typeName := g.getTypeName(route.RequestType)
-	
+	if route.RequestContentType == types.ContentTypeMultipartForm {
+		return &RequestBody{
+			Description: fmt.Sprintf("Request body for %s", route.Summary),
+			Required:    true,
+			Content: map[string]MediaTypeObject{
+				types.ContentTypeMultipartForm: {
+					Schema: SchemaRef{Inline: formSchema(route.FormFields)},
+				},
+			},
+		}
+	}
+
 	return &RequestBody{
 		Description: fmt.Sprintf("Request body for %s", route.Summary),
 		Required:    true,
 		Content: map[string]MediaTypeObject{
 			"application/json": {
-				Schema: SchemaRef{
-					Ref: fmt.Sprintf("#/components/schemas/%s", typeName),
-				},
+				Schema:   g.schemaRef(route.RequestType),
+				Examples: g.buildExamples(route, route.Examples),
 			},
 		},
 	}
@@ -228,35 +709,65 @@ func (g *Generator) buildRequestBody(route types.RouteInfo) *RequestBody {
 
 // buildResponses builds the responses specification
 func (g *Generator) buildResponses(route types.RouteInfo) map[string]Response {
+	// Documentation routes answer errors in plain text, so no error body is claimed
+	if route.DocumentationRoute {
+		return documentationResponses(route)
+	}
+
 	responses := make(map[string]Response)
+	success := successStatus(route)
+	errorSchema := g.errorSchemaRef(route)
 
 	// Success response
-	if route.ResponseType != nil {
-		typeName := g.getTypeName(route.ResponseType)
-		responses["200"] = Response{
+	if !isJSONResponse(route) {
+		// Text documents are plain strings; downloads such as images are binary
+		contentType := successContentType(route)
+		schema := map[string]interface{}{"type": "string", "format": "binary"}
+		if isTextMediaType(contentType) {
+			schema = map[string]interface{}{"type": "string"}
+		}
+		responses[success] = Response{
 			Description: "Success",
 			Content: map[string]MediaTypeObject{
-				"application/json": {
-					Schema: SchemaRef{
-						Ref: fmt.Sprintf("#/components/schemas/%s", typeName),
-					},
+				contentType: {
+					Schema: SchemaRef{Inline: schema},
+				},
+			},
+		}
+	} else if route.ResponseType != nil {
+		schema := g.schemaRef(route.ResponseType)
+		if wrapsInPage(route) {
+			// Paginated routes return pages of ResponseType items
+			schema = SchemaRef{Ref: componentSchemasRef + g.paginatedResponseName(route.ResponseType)}
+		}
+		responses[success] = Response{
+			Description: "Success",
+			Content: map[string]MediaTypeObject{
+				successContentType(route): {
+					Schema:   schema,
+					Examples: g.buildExamples(route, route.ResponseExamples),
 				},
 			},
 		}
 	} else {
-		responses["200"] = Response{
+		responses[success] = Response{
 			Description: "Success",
 		}
 	}
 
+	// Links name the operations the success response's values can be passed to
+	if links := buildLinks(route.ResponseLinks); links != nil {
+		linked := responses[success]
+		linked.Links = links
+		responses[success] = linked
+	}
+
 	// Standard error responses
 	responses["400"] = Response{
 		Description: "Bad Request",
 		Content: map[string]MediaTypeObject{
 			"application/json": {
-				Schema: SchemaRef{
-					Ref: "#/components/schemas/ErrorResponse",
-				},
+				Schema: errorSchema,
 			},
 		},
 	}
@@ -265,26 +776,343 @@ func (g *Generator) buildResponses(route types.RouteInfo) map[string]Response {
 		Description: "Internal Server Error",
 		Content: map[string]MediaTypeObject{
 			"application/json": {
-				Schema: SchemaRef{
-					Ref: "#/components/schemas/ErrorResponse",
-				},
+				Schema: errorSchema,
 			},
 		},
 	}
 
-	// Add method-specific responses
-	if strings.ToUpper(route.Method) != "GET" {
+	// Cacheable routes are revalidated with ETag and If-None-Match
+	if route.Cacheable && !route.Streaming {
+		cached := responses[success]
+		cached.Headers = map[string]Header{
+			"ETag": {
+				Description: "Strong validator for conditional requests with If-None-Match",
+				Schema:      map[string]interface{}{"type": "string"},
+			},
+			"Cache-Control": {
+				Description: "Caching policy for the response",
+				Schema:      map[string]interface{}{"type": "string"},
+			},
+		}
+		responses[success] = cached
+		responses["304"] = Response{
+			Description: "Not Modified",
+		}
+	}
+
+	// Rate-limited routes can reject requests and tell clients when to retry
+	if route.RateLimit != nil {
+		responses["429"] = Response{
+			Description: "Too Many Requests",
+			Headers: map[string]Header{
+				"Retry-After": {
+					Description: "Seconds to wait before retrying",
+					Schema:      map[string]interface{}{"type": "integer"},
+				},
+			},
+			Content: map[string]MediaTypeObject{
+				"application/json": {
+					Schema: errorSchema,
+				},
+			},
+		}
+	}
+
+	// Idempotent routes replay stored responses and reject concurrent duplicates
+	if route.Idempotent {
+		if replayed, ok := responses[success]; ok {
+			if replayed.Headers == nil {
+				replayed.Headers = make(map[string]Header)
+			}
+			replayed.Headers["Idempotency-Replayed"] = Header{
+				Description: "Present with value true when the response was replayed for a duplicate Idempotency-Key",
+				Schema:      map[string]interface{}{"type": "boolean"},
+			}
+			responses[success] = replayed
+		}
+		responses["409"] = Response{
+			Description: "Conflict: a request with the same Idempotency-Key is in progress",
+		}
+	}
+
+	// Add method-specific responses; file downloads without a request body have nothing to reject
+	validatesBody := isJSONResponse(route) || route.RequestType != nil
+	if strings.ToUpper(route.Method) != "GET" && validatesBody {
 		responses["422"] = Response{
 			Description: "Unprocessable Entity",
 			Content: map[string]MediaTypeObject{
 				"application/json": {
-					Schema: SchemaRef{
-						Ref: "#/components/schemas/ErrorResponse",
-					},
+					Schema: errorSchema,
 				},
 			},
 		}
 	}
 
+	// Route-specific error statuses share the route's error body
+	for _, code := range route.ErrorStatuses {
+		key := strconv.Itoa(code)
+		if _, exists := responses[key]; exists {
+			continue
+		}
+		responses[key] = Response{
+			Description: http.StatusText(code),
+			Content: map[string]MediaTypeObject{
+				"application/json": {
+					Schema: errorSchema,
+				},
+			},
+		}
+	}
+
+	// Catch-all for status codes the route does not enumerate
+	if g.DefaultResponse {
+		responses["default"] = Response{
+			Description: "Unexpected error",
+			Content: map[string]MediaTypeObject{
+				"application/json": {
+					Schema: errorSchema,
+				},
+			},
+		}
+	}
+
+	return responses
+}
+
+// errorSchemaRef returns the schema of a route's error bodies: its ErrorResponseType
+// when set, otherwise the shared ErrorResponse
+func (g *Generator) errorSchemaRef(route types.RouteInfo) SchemaRef {
+	if route.ErrorResponseType != nil {
+		return g.schemaRef(route.ErrorResponseType)
+	}
+	return SchemaRef{Ref: "#/components/schemas/ErrorResponse"}
+}
+
+// successStatus returns the documented success status code of a route
+func successStatus(route types.RouteInfo) string {
+	if route.SuccessStatus == 0 {
+		return "200"
+	}
+	return strconv.Itoa(route.SuccessStatus)
+}
+
+// documentationResponses describes the success response of a documentation route
+// in each of its content types: JSON documents as objects, anything else as text.
+// Its ErrorStatuses are answered in plain text.
+func documentationResponses(route types.RouteInfo) map[string]Response {
+	contentTypes := route.ResponseContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = []string{"text/html"}
+	}
+
+	content := make(map[string]MediaTypeObject, len(contentTypes))
+	for _, contentType := range contentTypes {
+		schema := map[string]interface{}{"type": "string"}
+		if isJSONMediaType(contentType) {
+			schema = map[string]interface{}{"type": "object"}
+		}
+		content[contentType] = MediaTypeObject{Schema: SchemaRef{Inline: schema}}
+	}
+
+	responses := map[string]Response{
+		successStatus(route): {Description: "Success", Content: content},
+	}
+	for _, code := range route.ErrorStatuses {
+		responses[strconv.Itoa(code)] = Response{
+			Description: http.StatusText(code),
+			Content: map[string]MediaTypeObject{
+				"text/plain": {Schema: SchemaRef{Inline: map[string]interface{}{"type": "string"}}},
+			},
+		}
+	}
 	return responses
-}
\ No newline at end of file
+}
+
+// isJSONResponse reports whether a route's success response is JSON
+func isJSONResponse(route types.RouteInfo) bool {
+	return isJSONMediaType(successContentType(route))
+}
+
+// successContentType returns the media type of a route's success response: its
+// ResponseContentType, or the media type of its ResponseFormat. JSON media types
+// such as application/vnd.api+json are documented with ResponseType; text, XML and
+// YAML types as strings; anything else as binary.
+func successContentType(route types.RouteInfo) string {
+	if route.ResponseContentType != "" {
+		return route.ResponseContentType
+	}
+	// The format was validated when schemas were generated
+	contentType, _ := responseContentType(route.ResponseFormat)
+	return contentType
+}
+
+// isJSONMediaType reports whether a media type carries JSON, such as
+// application/json or application/problem+json
+func isJSONMediaType(contentType string) bool {
+	return contentType == "application/json" || strings.HasSuffix(contentType, "+json")
+}
+
+// isTextMediaType reports whether a media type is text a client reads as a string,
+// such as text/csv, application/xml or application/x-yaml
+func isTextMediaType(contentType string) bool {
+	switch {
+	case strings.HasPrefix(contentType, "text/"), strings.HasSuffix(contentType, "+xml"), strings.HasSuffix(contentType, "+yaml"):
+		return true
+	}
+	switch contentType {
+	case "application/xml", "application/yaml", "application/x-yaml":
+		return true
+	}
+	return false
+}
+
+// responseContentType maps a RouteInfo.ResponseFormat to its success media type
+func responseContentType(format string) (string, error) {
+	switch format {
+	case "", types.ResponseFormatJSON:
+		return "application/json", nil
+	case types.ResponseFormatFile:
+		return "application/octet-stream", nil
+	case types.ResponseFormatCSV:
+		return "text/csv", nil
+	case types.ResponseFormatImage:
+		return "image/*", nil
+	default:
+		return "", fmt.Errorf("unknown response format %q", format)
+	}
+}
+
+// formSchema builds the object schema for multipart form fields; file fields are
+// binary strings
+func formSchema(fields map[string]string) map[string]interface{} {
+	properties := make(map[string]interface{}, len(fields))
+	for name, fieldType := range fields {
+		if fieldType == types.FormFieldBinary {
+			properties[name] = map[string]interface{}{"type": "string", "format": "binary"}
+			continue
+		}
+		properties[name] = map[string]interface{}{"type": fieldType}
+	}
+
+	return map[string]interface{}{
+		"type":       "object",
+		"properties": properties,
+	}
+}
+
+// schemaRef references the component schema for t, or inlines the schema when t is
+// a primitive, map or other type that is not registered as a component
+func (g *Generator) schemaRef(t reflect.Type) SchemaRef {
+	if isComponentType(t) {
+		return SchemaRef{Ref: fmt.Sprintf("#/components/schemas/%s", g.getTypeName(t))}
+	}
+
+	// Generation already succeeded for every route type, so this cannot fail here
+	schema, err := g.generateTypeSchema(t)
+	if err != nil {
+		return SchemaRef{Inline: map[string]interface{}{"type": "object"}}
+	}
+	return SchemaRef{Inline: schema}
+}
+
+// pruneSchemas returns the component schemas reachable from paths, following
+// schema-to-schema references transitively, and records the names it dropped
+func (g *Generator) pruneSchemas(paths map[string]PathItem) map[string]interface{} {
+	// ErrorResponse is part of the API's public error contract and is always kept
+	pending := []string{"ErrorResponse"}
+	for _, pathItem := range paths {
+		for _, op := range pathItem.Operations() {
+			pending = append(pending, operationRefs(op)...)
+		}
+	}
+
+	schemas := make(map[string]interface{})
+	for len(pending) > 0 {
+		name := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+
+		if _, seen := schemas[name]; seen {
+			continue
+		}
+		schema, exists := g.typeSchemas[name]
+		if !exists {
+			continue
+		}
+		schemas[name] = schema
+		pending = append(pending, nestedRefs(schema)...)
+	}
+
+	for name := range g.typeSchemas {
+		if _, kept := schemas[name]; !kept {
+			g.prunedSchemas = append(g.prunedSchemas, name)
+		}
+	}
+	sort.Strings(g.prunedSchemas)
+
+	return schemas
+}
+
+// operationRefs returns the component names an operation and its callbacks reference
+func operationRefs(op *Operation) []string {
+	var names []string
+	if op.RequestBody != nil {
+		names = append(names, contentRefs(op.RequestBody.Content)...)
+	}
+	for _, response := range op.Responses {
+		names = append(names, contentRefs(response.Content)...)
+	}
+	for _, callback := range op.Callbacks {
+		for _, pathItem := range callback {
+			for _, callbackOp := range pathItem.Operations() {
+				names = append(names, operationRefs(callbackOp)...)
+			}
+		}
+	}
+	return names
+}
+
+// contentRefs returns the component schema names referenced by media type content
+func contentRefs(content map[string]MediaTypeObject) []string {
+	var names []string
+	for _, media := range content {
+		if name, ok := schemaRefName(media.Schema.Ref); ok {
+			names = append(names, name)
+		}
+		// Inline schemas can still reference components, e.g. union variants
+		names = append(names, nestedRefs(media.Schema.Inline)...)
+	}
+	return names
+}
+
+// nestedRefs returns the component schema names referenced anywhere within a schema
+func nestedRefs(schema interface{}) []string {
+	var names []string
+	switch value := schema.(type) {
+	case map[string]interface{}:
+		for key, child := range value {
+			if ref, ok := child.(string); ok && key == "$ref" {
+				if name, ok := schemaRefName(ref); ok {
+					names = append(names, name)
+				}
+				continue
+			}
+			names = append(names, nestedRefs(child)...)
+		}
+	case []interface{}:
+		for _, child := range value {
+			names = append(names, nestedRefs(child)...)
+		}
+	}
+	return names
+}
+
+// componentSchemasRef prefixes $refs to component schemas
+const componentSchemasRef = "#/components/schemas/"
+
+// schemaRefName extracts the schema name from a "#/components/schemas/..." reference
+func schemaRefName(ref string) (string, bool) {
+	if !strings.HasPrefix(ref, componentSchemasRef) {
+		return "", false
+	}
+	return strings.TrimPrefix(ref, componentSchemasRef), true
+}
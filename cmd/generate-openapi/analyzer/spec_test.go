@@ -1,11 +1,14 @@
 package analyzer
 
 import (
+	"net/http"
 	"reflect"
+	"regexp"
 	"strings"
 	"testing"
 
 	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
 	"gopkg.in/yaml.v3"
 )
 
@@ -439,11 +442,6 @@ func TestSpecGenerationHeader(t *testing.T) {
 		},
 	}
 	
-	// Generate minimal schemas
-	gen.typeSchemas["map[string]interface {}"] = map[string]interface{}{
-		"type": "object",
-		"additionalProperties": true,
-	}
 	gen.addStandardSchemas()
 	
 	spec := gen.buildOpenAPISpec()
@@ -460,4 +458,1265 @@ func TestSpecGenerationHeader(t *testing.T) {
 	if !strings.Contains(spec, "# Auto-generated OpenAPI specification") {
 		t.Error("Spec should contain auto-generated header")
 	}
-}
\ No newline at end of file
+}
+
+func TestBuildOpenAPISpec_InfoContactAndLicense(t *testing.T) {
+	gen := NewGenerator()
+
+	spec := gen.buildOpenAPISpec()
+	if strings.Contains(spec, "contact:") || strings.Contains(spec, "license:") {
+		t.Error("Info should omit contact and license when they are not set")
+	}
+
+	gen = NewGenerator(
+		WithInfoContact("API Support", "https://example.com/support", "support@example.com"),
+		WithInfoLicense("Apache 2.0", "https://www.apache.org/licenses/LICENSE-2.0.html"),
+	)
+	spec = gen.buildOpenAPISpec()
+
+	expected := `    contact:
+        name: API Support
+        url: https://example.com/support
+        email: support@example.com
+    license:
+        name: Apache 2.0
+        url: https://www.apache.org/licenses/LICENSE-2.0.html
+`
+	if !strings.Contains(spec, expected) {
+		t.Errorf("Expected contact and license in info, got:\n%s", spec)
+	}
+
+	gen = NewGenerator()
+	gen.SetInfoContact("API Support", "https://example.com/support", "support@example.com")
+	gen.SetInfoLicense("Apache 2.0", "https://www.apache.org/licenses/LICENSE-2.0.html")
+	if spec := gen.buildOpenAPISpec(); !strings.Contains(spec, expected) {
+		t.Errorf("Expected the setters to add contact and license to info, got:\n%s", spec)
+	}
+}
+
+func TestBuildOpenAPISpec_IncludeHeader(t *testing.T) {
+	gen := NewGenerator()
+
+	if spec := gen.buildOpenAPISpec(); !strings.HasPrefix(spec, specHeader) {
+		t.Errorf("Expected the header by default, got:\n%s", spec)
+	}
+
+	spec := NewGenerator(WithIncludeHeader(false)).buildOpenAPISpec()
+	if strings.Contains(spec, "DO NOT EDIT") || strings.HasPrefix(spec, "#") {
+		t.Errorf("Expected no header comment when disabled, got:\n%s", spec)
+	}
+	if !strings.HasPrefix(spec, "openapi: ") {
+		t.Errorf("Expected the spec to start with the openapi field, got:\n%s", spec)
+	}
+
+	gen.SetIncludeHeader(false)
+	if spec := gen.buildOpenAPISpec(); strings.Contains(spec, "DO NOT EDIT") {
+		t.Errorf("Expected SetIncludeHeader(false) to drop the header, got:\n%s", spec)
+	}
+	gen.SetIncludeHeader(true)
+	if spec := gen.buildOpenAPISpec(); !strings.HasPrefix(spec, specHeader) {
+		t.Errorf("Expected SetIncludeHeader(true) to restore the header, got:\n%s", spec)
+	}
+}
+
+func TestBuildResponses_DocumentationRoute(t *testing.T) {
+	gen := NewGenerator()
+	gen.DefaultResponse = true
+
+	tests := []struct {
+		name     string
+		route    types.RouteInfo
+		expected map[string]MediaTypeObject
+	}{
+		{
+			name:  "defaults to HTML",
+			route: types.RouteInfo{Method: "GET", Path: "/swagger", Module: "docs", DocumentationRoute: true},
+			expected: map[string]MediaTypeObject{
+				"text/html": {Schema: SchemaRef{Inline: map[string]interface{}{"type": "string"}}},
+			},
+		},
+		{
+			name: "negotiated spec",
+			route: types.RouteInfo{Method: "GET", Path: "/docs/openapi", Module: "docs", DocumentationRoute: true,
+				ResponseContentTypes: []string{"application/x-yaml", "application/json"}},
+			expected: map[string]MediaTypeObject{
+				"application/x-yaml": {Schema: SchemaRef{Inline: map[string]interface{}{"type": "string"}}},
+				"application/json":   {Schema: SchemaRef{Inline: map[string]interface{}{"type": "object"}}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			responses := gen.buildResponses(tt.route)
+			if len(responses) != 1 {
+				t.Fatalf("Expected only the success response, got %v", responses)
+			}
+			if !reflect.DeepEqual(responses["200"].Content, tt.expected) {
+				t.Errorf("200 content = %v, expected %v", responses["200"].Content, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildResponses_DocumentationRouteErrorStatuses(t *testing.T) {
+	route := types.RouteInfo{Method: "GET", Path: "/docs/metrics", Module: "docs", DocumentationRoute: true,
+		ResponseContentTypes: []string{"text/plain"}, ErrorStatuses: []int{404}}
+
+	responses := NewGenerator().buildResponses(route)
+	notFound, ok := responses["404"]
+	if !ok {
+		t.Fatalf("Expected a 404 response, got %v", responses)
+	}
+	expected := map[string]MediaTypeObject{
+		"text/plain": {Schema: SchemaRef{Inline: map[string]interface{}{"type": "string"}}},
+	}
+	if notFound.Description != "Not Found" || !reflect.DeepEqual(notFound.Content, expected) {
+		t.Errorf("404 response = %+v, expected a plain text Not Found", notFound)
+	}
+}
+
+func TestBuildOpenAPISpec_PrunesUnreferencedSchemas(t *testing.T) {
+	gen := NewGenerator()
+
+	gen.routes = []types.RouteInfo{
+		{
+			Method:       "GET",
+			Path:         "/nested",
+			ResponseType: reflect.TypeOf(NestedStruct{}),
+			Module:       "test",
+			Summary:      "Nested",
+		},
+	}
+
+	if err := gen.generateSchemas(); err != nil {
+		t.Fatalf("generateSchemas() error = %v", err)
+	}
+	gen.addStandardSchemas()
+
+	// Orphan schema no route references
+	gen.typeSchemas["OrphanSchema"] = map[string]interface{}{"type": "object"}
+
+	// Schema only reachable through another schema's $ref
+	gen.typeSchemas["LinkedSchema"] = map[string]interface{}{"type": "string"}
+	gen.typeSchemas["NestedStruct"].(map[string]interface{})["properties"].(map[string]interface{})["linked"] = map[string]interface{}{
+		"$ref": "#/components/schemas/LinkedSchema",
+	}
+
+	spec := gen.buildOpenAPISpec()
+
+	var parsed struct {
+		Components struct {
+			Schemas map[string]interface{} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal([]byte(spec), &parsed); err != nil {
+		t.Fatalf("Generated spec is not valid YAML: %v", err)
+	}
+
+	schemas := parsed.Components.Schemas
+	if _, exists := schemas["OrphanSchema"]; exists {
+		t.Error("Unreferenced schema 'OrphanSchema' should be pruned")
+	}
+
+	for _, name := range []string{"NestedStruct", "LinkedSchema", "ErrorResponse"} {
+		if _, exists := schemas[name]; !exists {
+			t.Errorf("Referenced schema '%s' should survive pruning", name)
+		}
+	}
+}
+
+// specSchemas parses a generated spec and returns its component schemas
+func specSchemas(t *testing.T, spec string) map[string]interface{} {
+	t.Helper()
+
+	var parsed struct {
+		Components struct {
+			Schemas map[string]interface{} `yaml:"schemas"`
+		} `yaml:"components"`
+	}
+	if err := yaml.Unmarshal([]byte(spec), &parsed); err != nil {
+		t.Fatalf("Generated spec is not valid YAML: %v", err)
+	}
+	return parsed.Components.Schemas
+}
+
+func TestBuildOpenAPISpec_PrunesSchemaOfRemovedRoute(t *testing.T) {
+	gen := NewGenerator()
+
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/nested", ResponseType: reflect.TypeOf(NestedStruct{}), Module: "test", Summary: "Nested"},
+		{Method: "POST", Path: "/test", RequestType: reflect.TypeOf(TestRequest{}), Module: "test", Summary: "Test"},
+	}
+	if err := gen.LoadRoutes(routes); err != nil {
+		t.Fatalf("LoadRoutes() error = %v", err)
+	}
+
+	// Make InnerStruct a component only reachable through NestedStruct
+	gen.typeSchemas["InnerStruct"] = map[string]interface{}{"type": "object"}
+	gen.typeSchemas["NestedStruct"].(map[string]interface{})["properties"].(map[string]interface{})["inner"] = map[string]interface{}{
+		"$ref": "#/components/schemas/InnerStruct",
+	}
+
+	// Remove the route that referenced TestRequest and regenerate
+	gen.routes = routes[:1]
+	schemas := specSchemas(t, gen.buildOpenAPISpec())
+
+	if _, exists := schemas["TestRequest"]; exists {
+		t.Error("Schema of a removed route should be pruned")
+	}
+	for _, name := range []string{"NestedStruct", "InnerStruct", "ErrorResponse"} {
+		if _, exists := schemas[name]; !exists {
+			t.Errorf("Schema '%s' should survive pruning", name)
+		}
+	}
+
+	pruned := gen.PrunedSchemas()
+	if len(pruned) != 1 || pruned[0] != "TestRequest" {
+		t.Errorf("Expected PrunedSchemas() to report [TestRequest], got %v", pruned)
+	}
+}
+
+func TestBuildOpenAPISpec_KeepUnusedSchemas(t *testing.T) {
+	gen := NewGenerator()
+	gen.KeepUnusedSchemas = true
+
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/nested", ResponseType: reflect.TypeOf(NestedStruct{}), Module: "test", Summary: "Nested"},
+		{Method: "POST", Path: "/test", RequestType: reflect.TypeOf(TestRequest{}), Module: "test", Summary: "Test"},
+	}
+	if err := gen.LoadRoutes(routes); err != nil {
+		t.Fatalf("LoadRoutes() error = %v", err)
+	}
+
+	gen.routes = routes[:1]
+	schemas := specSchemas(t, gen.buildOpenAPISpec())
+
+	if _, exists := schemas["TestRequest"]; !exists {
+		t.Error("Unused schema should be kept when KeepUnusedSchemas is set")
+	}
+	if len(gen.PrunedSchemas()) != 0 {
+		t.Errorf("Expected no pruned schemas, got %v", gen.PrunedSchemas())
+	}
+}
+
+func TestBuildServers_FromConfig(t *testing.T) {
+	config.ResetForTest()
+	config.SetConfigPath("/nonexistent/path/config.json")
+	t.Cleanup(config.ResetForTest)
+
+	config.SetForTest("openapi.servers", []map[string]interface{}{
+		{"url": "http://localhost:8080", "description": "Development server"},
+		{"url": "https://staging.example.com", "description": "Staging server"},
+		{"url": "https://api.example.com", "description": "Production server"},
+	})
+
+	gen := NewGenerator()
+	servers := gen.buildServers()
+
+	expected := []Server{
+		{URL: "http://localhost:8080", Description: "Development server"},
+		{URL: "https://staging.example.com", Description: "Staging server"},
+		{URL: "https://api.example.com", Description: "Production server"},
+	}
+	if !reflect.DeepEqual(servers, expected) {
+		t.Errorf("buildServers() = %v, expected %v", servers, expected)
+	}
+}
+
+func TestBuildServers_Default(t *testing.T) {
+	config.ResetForTest()
+	config.SetConfigPath("/nonexistent/path/config.json")
+	t.Cleanup(config.ResetForTest)
+
+	gen := NewGenerator()
+	servers := gen.buildServers()
+
+	if len(servers) != 1 || servers[0].URL != "http://localhost:8080" {
+		t.Errorf("Expected default development server, got %v", servers)
+	}
+}
+
+func TestBuildOperation_MultipleTags(t *testing.T) {
+	gen := NewGenerator()
+
+	gen.routes = []types.RouteInfo{
+		{Method: "POST", Path: "/users", Module: "users", Summary: "Create user", Tags: []string{"users", "admin"}},
+		{Method: "GET", Path: "/health", Module: "health", Summary: "Health"},
+		{Method: "GET", Path: "/audit", Module: "audit", Summary: "Audit", Tags: []string{"admin"}},
+	}
+	gen.addStandardSchemas()
+
+	var parsed struct {
+		Tags []struct {
+			Name string `yaml:"name"`
+		} `yaml:"tags"`
+		Paths map[string]map[string]struct {
+			Tags []string `yaml:"tags"`
+		} `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal([]byte(gen.buildOpenAPISpec()), &parsed); err != nil {
+		t.Fatalf("Generated spec is not valid YAML: %v", err)
+	}
+
+	usersTags := parsed.Paths["/users"]["post"].Tags
+	if !reflect.DeepEqual(usersTags, []string{"users", "admin"}) {
+		t.Errorf("Expected operation tags [users admin], got %v", usersTags)
+	}
+
+	healthTags := parsed.Paths["/health"]["get"].Tags
+	if !reflect.DeepEqual(healthTags, []string{"health"}) {
+		t.Errorf("Expected operation tags to fall back to module [health], got %v", healthTags)
+	}
+
+	var globalTags []string
+	for _, tag := range parsed.Tags {
+		globalTags = append(globalTags, tag.Name)
+	}
+	if !reflect.DeepEqual(globalTags, []string{"admin", "health", "users"}) {
+		t.Errorf("Expected unique global tags [admin health users], got %v", globalTags)
+	}
+}
+
+func TestBuildTags_OmitsTagsWithoutRoutes(t *testing.T) {
+	gen := NewGenerator()
+	gen.SetTagDescription("users", "User management")
+	gen.SetTagDescription("billing", "Billing has no routes yet")
+
+	gen.routes = []types.RouteInfo{
+		{Method: "GET", Path: "/users", Module: "users", Summary: "List users"},
+		{Method: "GET", Path: "/health", Module: "health", Summary: "Health"},
+	}
+
+	expected := []Tag{
+		{Name: "health"},
+		{Name: "users", Description: "User management"},
+	}
+	if tags := gen.buildTags(); !reflect.DeepEqual(tags, expected) {
+		t.Errorf("Expected tags %v, got %v", expected, tags)
+	}
+}
+
+func TestBuildOperation_DeprecatedAndSunset(t *testing.T) {
+	gen := NewGenerator()
+
+	gen.routes = []types.RouteInfo{
+		{Method: "GET", Path: "/v1/users", Module: "users", Summary: "Old list", Deprecated: true, SunsetDate: "2025-12-31"},
+		{Method: "GET", Path: "/v2/users", Module: "users", Summary: "New list"},
+	}
+	gen.addStandardSchemas()
+
+	var parsed struct {
+		Paths map[string]map[string]map[string]interface{} `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal([]byte(gen.buildOpenAPISpec()), &parsed); err != nil {
+		t.Fatalf("Generated spec is not valid YAML: %v", err)
+	}
+
+	oldOp := parsed.Paths["/v1/users"]["get"]
+	if oldOp["deprecated"] != true {
+		t.Errorf("Expected deprecated: true, got %v", oldOp["deprecated"])
+	}
+	if oldOp["x-sunset"] != "2025-12-31" {
+		t.Errorf("Expected x-sunset: 2025-12-31, got %v", oldOp["x-sunset"])
+	}
+
+	newOp := parsed.Paths["/v2/users"]["get"]
+	if _, exists := newOp["deprecated"]; exists {
+		t.Error("Non-deprecated operation should omit the deprecated field")
+	}
+	if _, exists := newOp["x-sunset"]; exists {
+		t.Error("Operation without a sunset date should omit x-sunset")
+	}
+}
+
+func TestBuildResponses_InlinesPrimitiveAndMapTypes(t *testing.T) {
+	gen := NewGenerator()
+
+	tests := []struct {
+		name         string
+		responseType reflect.Type
+		expectedType string
+	}{
+		{"string", reflect.TypeOf(""), "string"},
+		{"bool", reflect.TypeOf(true), "boolean"},
+		{"map", reflect.TypeOf(map[string]interface{}{}), "object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := types.RouteInfo{Method: "GET", Path: "/value", ResponseType: tt.responseType, Module: "test"}
+			if err := gen.LoadRoutes([]types.RouteInfo{route}); err != nil {
+				t.Fatalf("LoadRoutes() error = %v", err)
+			}
+
+			schema := gen.buildResponses(route)["200"].Content["application/json"].Schema
+			if schema.Ref != "" {
+				t.Errorf("Expected inline schema, got $ref %s", schema.Ref)
+			}
+			if schema.Inline["type"] != tt.expectedType {
+				t.Errorf("Expected inline type %s, got %v", tt.expectedType, schema.Inline["type"])
+			}
+			if _, exists := gen.typeSchemas[gen.getTypeName(tt.responseType)]; exists {
+				t.Errorf("%s should not be registered as a component schema", tt.name)
+			}
+		})
+	}
+}
+
+func TestBuildOpenAPISpec_ComponentKeysAreValid(t *testing.T) {
+	gen := NewGenerator()
+
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/map", ResponseType: reflect.TypeOf(map[string]interface{}{}), Module: "test", Summary: "Map"},
+		{Method: "GET", Path: "/list", ResponseType: reflect.TypeOf([]TestResponse{}), Module: "test", Summary: "List"},
+		{Method: "GET", Path: "/page", ResponseType: reflect.TypeOf(Paginated[User]{}), Module: "test", Summary: "Page"},
+		{Method: "POST", Path: "/anonymous", RequestType: reflect.TypeOf(struct{ Name string }{}), ResponseType: reflect.TypeOf(""), Module: "test", Summary: "Anonymous"},
+	}
+
+	spec, err := gen.GenerateSpecForRoutes(routes)
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	validKey := regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	for name := range specSchemas(t, spec) {
+		if !validKey.MatchString(name) {
+			t.Errorf("Component key %q is not a valid OpenAPI component name", name)
+		}
+	}
+}
+
+func TestSanitizeSchemaName(t *testing.T) {
+	if got := sanitizeSchemaName("map[string]interface {}"); got != "mapstringinterface" {
+		t.Errorf("sanitizeSchemaName() = %q, want %q", got, "mapstringinterface")
+	}
+	if got := sanitizeSchemaName("v1.User_Name-2"); got != "v1.User_Name-2" {
+		t.Errorf("sanitizeSchemaName() should keep valid characters, got %q", got)
+	}
+}
+
+func TestBuildRequestBody_Multipart(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{
+		Method:             "POST",
+		Path:               "/files",
+		Module:             "files",
+		Summary:            "Upload file",
+		RequestContentType: types.ContentTypeMultipartForm,
+		FormFields:         map[string]string{"file": types.FormFieldBinary, "purpose": "string"},
+	}
+
+	spec, err := gen.GenerateSpecForRoutes([]types.RouteInfo{route})
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	var parsed struct {
+		Paths map[string]map[string]struct {
+			RequestBody struct {
+				Content map[string]struct {
+					Schema map[string]interface{} `yaml:"schema"`
+				} `yaml:"content"`
+			} `yaml:"requestBody"`
+		} `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal([]byte(spec), &parsed); err != nil {
+		t.Fatalf("Generated spec is not valid YAML: %v", err)
+	}
+
+	media, ok := parsed.Paths["/files"]["post"].RequestBody.Content["multipart/form-data"]
+	if !ok {
+		t.Fatal("Request body should have a multipart/form-data content block")
+	}
+	if media.Schema["type"] != "object" {
+		t.Errorf("Multipart schema should be an object, got %v", media.Schema["type"])
+	}
+
+	properties := media.Schema["properties"].(map[string]interface{})
+	file := properties["file"].(map[string]interface{})
+	if file["type"] != "string" || file["format"] != "binary" {
+		t.Errorf("File field should be a binary string, got %v", file)
+	}
+	purpose := properties["purpose"].(map[string]interface{})
+	if purpose["type"] != "string" || purpose["format"] != nil {
+		t.Errorf("Purpose field should be a plain string, got %v", purpose)
+	}
+}
+
+func TestBuildOperation_Paginated(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{
+		Method:       "GET",
+		Path:         "/models",
+		ResponseType: reflect.TypeOf(types.Paginated[Model]{}),
+		Module:       "models",
+		Summary:      "List models",
+		Paginated:    true,
+	}
+
+	spec, err := gen.GenerateSpecForRoutes([]types.RouteInfo{route})
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	operation := gen.buildOperation(route)
+	if len(operation.Parameters) != 2 || operation.Parameters[0].Name != "limit" || operation.Parameters[1].Name != "offset" {
+		t.Fatalf("Expected limit and offset parameters, got %+v", operation.Parameters)
+	}
+	for _, param := range operation.Parameters {
+		if param.In != "query" || param.Schema["type"] != "integer" {
+			t.Errorf("Parameter %s should be an integer query parameter, got %+v", param.Name, param)
+		}
+	}
+
+	if ref := operation.Responses["200"].Content["application/json"].Schema.Ref; ref != "#/components/schemas/PaginatedModel" {
+		t.Errorf("Expected response to reference PaginatedModel, got %s", ref)
+	}
+
+	wrapper, ok := specSchemas(t, spec)["PaginatedModel"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Spec should contain the PaginatedModel wrapper schema")
+	}
+	properties := wrapper["properties"].(map[string]interface{})
+	for _, name := range []string{"items", "total", "limit", "offset", "next_offset"} {
+		if _, exists := properties[name]; !exists {
+			t.Errorf("PaginatedModel should have property %s", name)
+		}
+	}
+	items := properties["items"].(map[string]interface{})["items"].(map[string]interface{})
+	if _, exists := items["properties"].(map[string]interface{})["id"]; !exists {
+		t.Error("PaginatedModel items should use the Model schema")
+	}
+}
+
+func TestBuildOperation_NotPaginated(t *testing.T) {
+	gen := NewGenerator()
+	operation := gen.buildOperation(types.RouteInfo{Method: "GET", Path: "/models", Module: "models"})
+
+	if len(operation.Parameters) != 0 {
+		t.Errorf("Routes not flagged Paginated should have no parameters, got %+v", operation.Parameters)
+	}
+}
+
+func TestBuildOperation_RateLimit(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{
+		Method:       "POST",
+		Path:         "/chat",
+		RequestType:  reflect.TypeOf(TestRequest{}),
+		ResponseType: reflect.TypeOf(TestResponse{}),
+		Module:       "chat",
+		Summary:      "Chat",
+		RateLimit:    &types.RateLimitInfo{Requests: 100, Per: "minute", BurstSize: 10},
+	}
+
+	spec, err := gen.GenerateSpecForRoutes([]types.RouteInfo{route})
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	var parsed struct {
+		Paths map[string]map[string]map[string]interface{} `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal([]byte(spec), &parsed); err != nil {
+		t.Fatalf("Generated spec is not valid YAML: %v", err)
+	}
+	operation := parsed.Paths["/chat"]["post"]
+
+	rateLimit, ok := operation["x-rate-limit"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Operation should have an x-rate-limit extension")
+	}
+	if rateLimit["requests"] != 100 || rateLimit["per"] != "minute" || rateLimit["burst"] != 10 {
+		t.Errorf("Unexpected x-rate-limit %v", rateLimit)
+	}
+
+	tooMany, ok := operation["responses"].(map[string]interface{})["429"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Operation should document a 429 response")
+	}
+	if _, ok := tooMany["headers"].(map[string]interface{})["Retry-After"]; !ok {
+		t.Error("429 response should document the Retry-After header")
+	}
+	if !strings.Contains(spec, "$ref: '#/components/schemas/ErrorResponse'") {
+		t.Error("429 response should reference ErrorResponse")
+	}
+}
+
+func TestBuildOperation_NoRateLimit(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{Method: "GET", Path: "/models", Module: "models"}
+
+	operation := gen.buildOperation(route)
+	if operation.XRateLimit != nil {
+		t.Error("Routes without RateLimit should not have x-rate-limit")
+	}
+	if _, exists := operation.Responses["429"]; exists {
+		t.Error("Routes without RateLimit should not document a 429 response")
+	}
+}
+
+func TestBuildResponses_Cacheable(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{Method: "GET", Path: "/v1/models", ResponseType: reflect.TypeOf(TestResponse{}), Module: "models", Cacheable: true}
+
+	responses := gen.buildResponses(route)
+
+	if _, exists := responses["200"].Headers["ETag"]; !exists {
+		t.Error("Cacheable route should document the ETag header on 200")
+	}
+	if _, exists := responses["304"]; !exists {
+		t.Error("Cacheable route should document a 304 response")
+	}
+
+	route.Streaming = true
+	if _, exists := gen.buildResponses(route)["304"]; exists {
+		t.Error("Streaming routes should not document conditional GET")
+	}
+}
+
+func TestBuildOperation_Idempotent(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{
+		Method:       "POST",
+		Path:         "/v1/completions",
+		RequestType:  reflect.TypeOf(TestRequest{}),
+		ResponseType: reflect.TypeOf(TestResponse{}),
+		Module:       "chat",
+		Idempotent:   true,
+	}
+
+	operation := gen.buildOperation(route)
+	if len(operation.Parameters) != 1 || operation.Parameters[0].Name != "Idempotency-Key" || operation.Parameters[0].In != "header" {
+		t.Errorf("Idempotent route should document the Idempotency-Key header, got %+v", operation.Parameters)
+	}
+	if _, exists := operation.Responses["200"].Headers["Idempotency-Replayed"]; !exists {
+		t.Error("Idempotent route should document the Idempotency-Replayed header on 200")
+	}
+	if _, exists := operation.Responses["409"]; !exists {
+		t.Error("Idempotent route should document a 409 response")
+	}
+
+	route.Idempotent = false
+	operation = gen.buildOperation(route)
+	if len(operation.Parameters) != 0 {
+		t.Errorf("Routes without Idempotent should have no parameters, got %+v", operation.Parameters)
+	}
+	if _, exists := operation.Responses["409"]; exists {
+		t.Error("Routes without Idempotent should not document a 409 response")
+	}
+}
+
+func TestBuildOperation_HeaderParams(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{
+		Method:       "GET",
+		Path:         "/v1/models",
+		ResponseType: reflect.TypeOf(TestResponse{}),
+		Module:       "models",
+		HeaderParams: []types.HeaderParam{
+			{Name: "X-Org-ID", Required: true, Description: "Organization the request acts for"},
+			{Name: "Accept-Language", Description: "Preferred language of model descriptions"},
+			{Name: "Authorization", Required: true},
+		},
+	}
+
+	operation := gen.buildOperation(route)
+	expected := []Parameter{
+		{Name: "X-Org-ID", In: "header", Required: true, Description: "Organization the request acts for", Schema: map[string]interface{}{"type": "string"}},
+		{Name: "Accept-Language", In: "header", Description: "Preferred language of model descriptions", Schema: map[string]interface{}{"type": "string"}},
+	}
+	if !reflect.DeepEqual(operation.Parameters, expected) {
+		t.Errorf("Header parameters = %+v, expected %+v", operation.Parameters, expected)
+	}
+}
+
+func TestBuildResponses_ResponseFormat(t *testing.T) {
+	gen := NewGenerator()
+
+	tests := []struct {
+		format      string
+		contentType string
+		schema      map[string]interface{}
+	}{
+		{types.ResponseFormatFile, "application/octet-stream", map[string]interface{}{"type": "string", "format": "binary"}},
+		{types.ResponseFormatCSV, "text/csv", map[string]interface{}{"type": "string"}},
+		{types.ResponseFormatImage, "image/*", map[string]interface{}{"type": "string", "format": "binary"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			route := types.RouteInfo{Method: "POST", Path: "/export", Module: "export", ResponseFormat: tt.format}
+
+			responses := gen.buildResponses(route)
+			media, ok := responses["200"].Content[tt.contentType]
+			if !ok {
+				t.Fatalf("200 response should use %s, got %v", tt.contentType, responses["200"].Content)
+			}
+			if media.Schema.Ref != "" {
+				t.Errorf("File response should not reference a component, got %s", media.Schema.Ref)
+			}
+			if !reflect.DeepEqual(media.Schema.Inline, tt.schema) {
+				t.Errorf("%s response should have schema %v, got %v", tt.contentType, tt.schema, media.Schema.Inline)
+			}
+			if _, exists := responses["422"]; exists {
+				t.Error("File download routes without a request body should not document a 422 response")
+			}
+		})
+	}
+
+	t.Run("json", func(t *testing.T) {
+		route := types.RouteInfo{Method: "POST", Path: "/chat", ResponseType: reflect.TypeOf(TestResponse{}), Module: "chat", ResponseFormat: types.ResponseFormatJSON}
+
+		responses := gen.buildResponses(route)
+		if responses["200"].Content["application/json"].Schema.Ref != "#/components/schemas/TestResponse" {
+			t.Errorf("JSON response should reference its component, got %v", responses["200"].Content)
+		}
+		if _, exists := responses["422"]; !exists {
+			t.Error("JSON routes should keep the 422 response")
+		}
+	})
+}
+
+func TestBuildResponses_ResponseContentType(t *testing.T) {
+	gen := NewGenerator()
+
+	tests := []struct {
+		name        string
+		route       types.RouteInfo
+		contentType string
+		schema      map[string]interface{}
+	}{
+		{"csv", types.RouteInfo{ResponseContentType: "text/csv"}, "text/csv", map[string]interface{}{"type": "string"}},
+		{"xml", types.RouteInfo{ResponseContentType: "application/xml"}, "application/xml", map[string]interface{}{"type": "string"}},
+		{"yaml", types.RouteInfo{ResponseContentType: "application/x-yaml"}, "application/x-yaml", map[string]interface{}{"type": "string"}},
+		{"binary", types.RouteInfo{ResponseContentType: "application/zip"}, "application/zip", map[string]interface{}{"type": "string", "format": "binary"}},
+		{"overrides format", types.RouteInfo{ResponseFormat: types.ResponseFormatFile, ResponseContentType: "text/plain"}, "text/plain", map[string]interface{}{"type": "string"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			route := tt.route
+			route.Method, route.Path, route.Module = "GET", "/v1/usage/export", "usage"
+
+			content := gen.buildResponses(route)["200"].Content
+			media, ok := content[tt.contentType]
+			if !ok || len(content) != 1 {
+				t.Fatalf("200 response should only use %s, got %v", tt.contentType, content)
+			}
+			if !reflect.DeepEqual(media.Schema.Inline, tt.schema) {
+				t.Errorf("%s response should have schema %v, got %v", tt.contentType, tt.schema, media.Schema.Inline)
+			}
+		})
+	}
+
+	t.Run("json media type", func(t *testing.T) {
+		route := types.RouteInfo{Method: "GET", Path: "/v1/items", ResponseType: reflect.TypeOf(TestResponse{}), Module: "items", ResponseContentType: "application/vnd.api+json"}
+
+		content := gen.buildResponses(route)["200"].Content
+		if len(content) != 1 || content["application/vnd.api+json"].Schema.Ref != "#/components/schemas/TestResponse" {
+			t.Errorf("JSON media types should reference the response component, got %+v", content)
+		}
+	})
+
+	t.Run("default", func(t *testing.T) {
+		route := types.RouteInfo{Method: "GET", Path: "/v1/items", ResponseType: reflect.TypeOf(TestResponse{}), Module: "items"}
+
+		if _, ok := gen.buildResponses(route)["200"].Content["application/json"]; !ok {
+			t.Error("Routes without a ResponseContentType should default to application/json")
+		}
+	})
+}
+
+func TestBuildResponses_SuccessAndErrorStatuses(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{
+		Method:        "DELETE",
+		Path:          "/jobs/{id}",
+		ResponseType:  reflect.TypeOf(TestResponse{}),
+		Module:        "jobs",
+		SuccessStatus: 202,
+		ErrorStatuses: []int{404, 409},
+	}
+
+	responses := gen.buildResponses(route)
+	if _, exists := responses["200"]; exists {
+		t.Error("Routes with a SuccessStatus should not document a 200 response")
+	}
+	if responses["202"].Content["application/json"].Schema.Ref != "#/components/schemas/TestResponse" {
+		t.Errorf("202 response should reference the response type, got %v", responses["202"].Content)
+	}
+
+	for code, description := range map[string]string{"404": "Not Found", "409": "Conflict"} {
+		response, exists := responses[code]
+		if !exists {
+			t.Errorf("Expected a %s response", code)
+			continue
+		}
+		if response.Description != description {
+			t.Errorf("%s description = %q, want %q", code, response.Description, description)
+		}
+		if response.Content["application/json"].Schema.Ref != "#/components/schemas/ErrorResponse" {
+			t.Errorf("%s response should use ErrorResponse, got %v", code, response.Content)
+		}
+	}
+}
+
+type validationErrorResponse struct {
+	Message string            `json:"message"`
+	Fields  map[string]string `json:"fields"`
+}
+
+func TestBuildResponses_ErrorResponseType(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{
+		Method:            "POST",
+		Path:              "/users",
+		RequestType:       reflect.TypeOf(TestRequest{}),
+		ResponseType:      reflect.TypeOf(TestResponse{}),
+		Module:            "users",
+		ErrorStatuses:     []int{404},
+		ErrorResponseType: reflect.TypeOf(validationErrorResponse{}),
+	}
+
+	spec, err := gen.GenerateSpecForRoutes([]types.RouteInfo{route})
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	for _, code := range []string{"400", "404", "422", "500"} {
+		ref := gen.buildResponses(route)[code].Content["application/json"].Schema.Ref
+		if ref != "#/components/schemas/validationErrorResponse" {
+			t.Errorf("%s response should reference the route's error type, got %q", code, ref)
+		}
+	}
+
+	if _, ok := gen.Schema("validationErrorResponse"); !ok {
+		t.Error("Error response type should be registered as a component schema")
+	}
+	if !strings.Contains(spec, "validationErrorResponse:") {
+		t.Error("Error response type schema should be kept in the spec")
+	}
+}
+
+func TestBuildResponses_DefaultResponse(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{Method: "GET", Path: "/users", ResponseType: reflect.TypeOf(TestResponse{}), Module: "users"}
+
+	if _, exists := gen.buildResponses(route)["default"]; exists {
+		t.Error("The default response should be off by default")
+	}
+
+	gen.DefaultResponse = true
+	response, exists := gen.buildResponses(route)["default"]
+	if !exists {
+		t.Fatal("Expected a default response when DefaultResponse is set")
+	}
+	if ref := response.Content["application/json"].Schema.Ref; ref != "#/components/schemas/ErrorResponse" {
+		t.Errorf("The default response should reference ErrorResponse, got %q", ref)
+	}
+}
+
+func TestGenerateSpecForRoutes_UnknownResponseFormat(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{Method: "GET", Path: "/export", Module: "export", ResponseFormat: "pdf"}
+
+	_, err := gen.GenerateSpecForRoutes([]types.RouteInfo{route})
+	if err == nil || !strings.Contains(err.Error(), `unknown response format "pdf"`) {
+		t.Errorf("Expected an unknown response format error, got %v", err)
+	}
+}
+
+type jobCompletedEvent struct {
+	JobID  string `json:"job_id"`
+	Status string `json:"status"`
+}
+
+func TestBuildOperation_Callbacks(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{
+		Method:       "POST",
+		Path:         "/v1/batches",
+		RequestType:  reflect.TypeOf(TestRequest{}),
+		ResponseType: reflect.TypeOf(TestResponse{}),
+		Module:       "batches",
+		Callbacks: map[string]types.CallbackInfo{
+			"jobCompleted": {
+				Expression: "{$request.body#/callback_url}",
+				PathItem: types.PathItem{
+					Post: &types.CallbackOperation{
+						Summary:      "Sent when the batch finishes",
+						HeaderParams: []types.HeaderParam{{Name: "X-Signature", Required: true}},
+						RequestType:  reflect.TypeOf(jobCompletedEvent{}),
+					},
+				},
+			},
+		},
+	}
+
+	spec, err := gen.GenerateSpecForRoutes([]types.RouteInfo{route})
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	var parsed struct {
+		Paths      map[string]map[string]map[string]interface{} `yaml:"paths"`
+		Components map[string]map[string]interface{}            `yaml:"components"`
+	}
+	if err := yaml.Unmarshal([]byte(spec), &parsed); err != nil {
+		t.Fatalf("Generated spec is not valid YAML: %v", err)
+	}
+
+	callbacks, ok := parsed.Paths["/v1/batches"]["post"]["callbacks"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Operation should have callbacks, got:\n%s", spec)
+	}
+	expression, ok := callbacks["jobCompleted"].(map[string]interface{})["{$request.body#/callback_url}"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("jobCompleted should be keyed by its URL expression, got %v", callbacks["jobCompleted"])
+	}
+	post, ok := expression["post"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("Callback should document its POST operation, got %v", expression)
+	}
+	if post["summary"] != "Sent when the batch finishes" {
+		t.Errorf("Unexpected callback summary %v", post["summary"])
+	}
+
+	schema := post["requestBody"].(map[string]interface{})["content"].(map[string]interface{})["application/json"].(map[string]interface{})["schema"].(map[string]interface{})
+	if schema["$ref"] != "#/components/schemas/jobCompletedEvent" {
+		t.Errorf("Callback payload should reference its schema, got %v", schema)
+	}
+	if _, exists := post["responses"].(map[string]interface{})["2XX"]; !exists {
+		t.Error("Callback without responses should document the 2XX acknowledgement")
+	}
+	params, _ := post["parameters"].([]interface{})
+	if len(params) != 1 || params[0].(map[string]interface{})["name"] != "X-Signature" || params[0].(map[string]interface{})["in"] != "header" {
+		t.Errorf("Callback should document its header parameters, got %v", post["parameters"])
+	}
+
+	// Payload schemas used only by callbacks survive pruning
+	if _, exists := parsed.Components["schemas"]["jobCompletedEvent"]; !exists {
+		t.Error("Callback payload schema should be generated")
+	}
+}
+
+func TestBuildOperation_SubscriptionCallbackYAML(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{
+		Method:      "POST",
+		Path:        "/subscriptions",
+		RequestType: reflect.TypeOf(TestRequest{}),
+		Module:      "subscriptions",
+		Callbacks: map[string]types.CallbackInfo{
+			"onEvent": {
+				Expression: "{$request.body#/callback_url}",
+				PathItem: types.PathItem{
+					Post: &types.CallbackOperation{
+						Summary:     "Sent to the subscriber when an event occurs",
+						RequestType: reflect.TypeOf(jobCompletedEvent{}),
+						Responses: map[int]string{
+							http.StatusNoContent: "Event received",
+							http.StatusGone:      "",
+						},
+					},
+				},
+			},
+		},
+	}
+
+	spec, err := gen.GenerateSpecForRoutes([]types.RouteInfo{route})
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	expected := `            callbacks:
+                onEvent:
+                    '{$request.body#/callback_url}':
+                        post:
+                            summary: Sent to the subscriber when an event occurs
+                            requestBody:
+                                required: true
+                                content:
+                                    application/json:
+                                        schema:
+                                            $ref: '#/components/schemas/jobCompletedEvent'
+                            responses:
+                                "204":
+                                    description: Event received
+                                "410":
+                                    description: Gone
+`
+	if !strings.Contains(spec, expected) {
+		t.Errorf("Expected the subscription callbacks block, got:\n%s", spec)
+	}
+}
+
+func TestGenerateSpecForRoutes_InvalidCallback(t *testing.T) {
+	gen := NewGenerator()
+	route := types.RouteInfo{
+		Method:    "POST",
+		Path:      "/v1/batches",
+		Module:    "batches",
+		Callbacks: map[string]types.CallbackInfo{"jobCompleted": {}},
+	}
+
+	_, err := gen.GenerateSpecForRoutes([]types.RouteInfo{route})
+	if err == nil || !strings.Contains(err.Error(), "callback jobCompleted has no URL expression") {
+		t.Errorf("Expected a missing expression error, got %v", err)
+	}
+
+	route.Callbacks = map[string]types.CallbackInfo{"jobCompleted": {Expression: "{$request.body#/callback_url}"}}
+	_, err = gen.GenerateSpecForRoutes([]types.RouteInfo{route})
+	if err == nil || !strings.Contains(err.Error(), "callback jobCompleted has no operations") {
+		t.Errorf("Expected a missing operations error, got %v", err)
+	}
+}
+
+func TestBuildPaths_HoistsSharedPathParameters(t *testing.T) {
+	gen := NewGenerator()
+	gen.HoistSharedParams = true
+	gen.routes = []types.RouteInfo{
+		{Method: "GET", Path: "/users/{id}", ResponseType: reflect.TypeOf(User{}), Module: "users", Paginated: true},
+		{Method: "PUT", Path: "/users/{id}", RequestType: reflect.TypeOf(User{}), Module: "users"},
+		{Method: "DELETE", Path: "/users/{id}", Module: "users"},
+	}
+
+	pathItem := gen.buildPaths()["/users/{id}"]
+
+	if len(pathItem.Parameters) != 1 || pathItem.Parameters[0].Name != "id" || pathItem.Parameters[0].In != "path" {
+		t.Fatalf("Expected the id path parameter once at the path level, got %+v", pathItem.Parameters)
+	}
+	if !pathItem.Parameters[0].Required {
+		t.Error("Path parameters must be required")
+	}
+
+	for _, op := range []*Operation{pathItem.Get, pathItem.Put, pathItem.Delete} {
+		for _, param := range op.Parameters {
+			if param.In == "path" {
+				t.Errorf("Operation %s should not repeat the hoisted path parameter", op.OperationID)
+			}
+		}
+	}
+
+	// Method-specific query parameters stay on the operation
+	if len(pathItem.Get.Parameters) != 2 {
+		t.Errorf("GET should keep its limit and offset query parameters, got %+v", pathItem.Get.Parameters)
+	}
+}
+
+func TestBuildPaths_HoistSharedParamsOption(t *testing.T) {
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/users/{id}", ResponseType: reflect.TypeOf(User{}), Module: "users"},
+		{Method: "DELETE", Path: "/users/{id}", Module: "users"},
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		gen := NewGenerator()
+		gen.HoistSharedParams = true
+		spec, err := gen.GenerateSpecForRoutes(routes)
+		if err != nil {
+			t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+		}
+
+		var parsed struct {
+			Paths map[string]map[string]interface{} `yaml:"paths"`
+		}
+		if err := yaml.Unmarshal([]byte(spec), &parsed); err != nil {
+			t.Fatalf("Generated spec is not valid YAML: %v", err)
+		}
+		pathItem := parsed.Paths["/users/{id}"]
+
+		params, ok := pathItem["parameters"].([]interface{})
+		if !ok || len(params) != 1 || params[0].(map[string]interface{})["name"] != "id" {
+			t.Fatalf("Expected id under the path item, got %v", pathItem["parameters"])
+		}
+		for _, method := range []string{"get", "delete"} {
+			if _, exists := pathItem[method].(map[string]interface{})["parameters"]; exists {
+				t.Errorf("%s should not declare the hoisted id parameter", method)
+			}
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		gen := NewGenerator()
+		gen.routes = routes
+		pathItem := gen.buildPaths()["/users/{id}"]
+
+		if len(pathItem.Parameters) != 0 {
+			t.Errorf("Path item should have no parameters without HoistSharedParams, got %+v", pathItem.Parameters)
+		}
+		for _, op := range []*Operation{pathItem.Get, pathItem.Delete} {
+			if len(op.Parameters) != 1 || op.Parameters[0].Name != "id" {
+				t.Errorf("Operation %s should declare id itself, got %+v", op.OperationID, op.Parameters)
+			}
+		}
+	})
+}
+
+func TestTitleSegment(t *testing.T) {
+	tests := []struct {
+		segment  string
+		expected string
+	}{
+		{"users", "Users"},
+		{"überblick", "Überblick"},
+		{"ǆungla", "ǅungla"},
+		{"été", "Été"},
+		{"id", "ID"},
+		{"iD", "ID"},
+		{"Url", "URL"},
+		{"api", "API"},
+		{"{id}", "ID"},
+		{"{name}", "Name"},
+		{"v1", "V1"},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.segment, func(t *testing.T) {
+			if got := titleSegment(tt.segment); got != tt.expected {
+				t.Errorf("titleSegment(%q) = %q, want %q", tt.segment, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateOperationID_Acronyms(t *testing.T) {
+	gen := NewGenerator()
+
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"/users/{id}", "getusersID"},
+		{"/users/iD", "getusersID"},
+		{"/v1/models/api-url", "getv1ModelsAPIURL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			if got := gen.generateOperationID(types.RouteInfo{Method: "GET", Path: tt.path}); got != tt.expected {
+				t.Errorf("generateOperationID() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateOperationID_Styles(t *testing.T) {
+	route := types.RouteInfo{Method: "POST", Path: "/v1/models/{id}/api-keys"}
+
+	tests := []struct {
+		style    OperationIDStyle
+		expected string
+	}{
+		{OperationIDCamel, "postv1ModelsIDAPIKeys"},
+		{OperationIDSnake, "post_v1_models_id_api_keys"},
+		{OperationIDKebab, "post-v1-models-id-api-keys"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			gen := NewGenerator(WithOperationIDStyle(tt.style))
+			if got := gen.generateOperationID(route); got != tt.expected {
+				t.Errorf("generateOperationID() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+
+	if got := NewGenerator().generateOperationID(route); got != "postv1ModelsIDAPIKeys" {
+		t.Errorf("Default style should be camelCase, got %q", got)
+	}
+
+	gen := NewGenerator()
+	gen.SetOperationIDStyle(OperationIDSnake)
+	if got := gen.generateOperationID(route); got != "post_v1_models_id_api_keys" {
+		t.Errorf("SetOperationIDStyle() should change the style, got %q", got)
+	}
+	if _, err := ParseOperationIDStyle("pascal"); err == nil {
+		t.Error("ParseOperationIDStyle() should reject unknown styles")
+	}
+}
+
+func TestGenerateOperationID_SplitsCamelCaseSegments(t *testing.T) {
+	route := types.RouteInfo{Method: "GET", Path: "/v1/userGroups/{userId}/APIKeys"}
+
+	tests := []struct {
+		style    OperationIDStyle
+		expected string
+	}{
+		{OperationIDSnake, "get_v1_user_groups_user_id_api_keys"},
+		{OperationIDKebab, "get-v1-user-groups-user-id-api-keys"},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.style), func(t *testing.T) {
+			if got := NewGenerator(WithOperationIDStyle(tt.style)).generateOperationID(route); got != tt.expected {
+				t.Errorf("generateOperationID() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBuildPaths_NormalizePaths(t *testing.T) {
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/users/", ResponseType: reflect.TypeOf([]User{}), Module: "users", Summary: "List users"},
+		{Method: "POST", Path: "/users", RequestType: reflect.TypeOf(TestRequest{}), ResponseType: reflect.TypeOf(User{}), Module: "users", Summary: "Create user"},
+		{Method: "GET", Path: "/users", ResponseType: reflect.TypeOf(TestResponse{}), Module: "users", Summary: "List users again"},
+		{Method: "GET", Path: "/", ResponseType: reflect.TypeOf(TestResponse{}), Module: "root", Summary: "Root"},
+	}
+
+	t.Run("enabled", func(t *testing.T) {
+		gen := NewGenerator()
+		gen.NormalizePaths = true
+		gen.routes = routes
+		paths := gen.buildPaths()
+
+		if _, exists := paths["/users/"]; exists {
+			t.Error("/users/ should be normalized to /users")
+		}
+		users, exists := paths["/users"]
+		if !exists || users.Get == nil || users.Post == nil {
+			t.Fatalf("Expected GET and POST under /users, got %+v", users)
+		}
+		if users.Get.Summary != "List users" {
+			t.Errorf("The first registered GET /users should win, got %q", users.Get.Summary)
+		}
+		if _, exists := paths["/"]; !exists {
+			t.Error("The root path / should be preserved")
+		}
+
+		warnings := gen.Warnings()
+		if len(warnings) != 1 || warnings[0].Category != WarningDuplicatePath || warnings[0].Route != "GET /users" ||
+			!strings.Contains(warnings[0].Message, "GET /users/") {
+			t.Errorf("Expected one duplicate-path warning for GET /users, got %v", warnings)
+		}
+	})
+
+	t.Run("sorted", func(t *testing.T) {
+		gen := NewGenerator()
+		gen.NormalizePaths = true
+		gen.SortPaths = true
+		gen.routes = routes
+		paths := gen.buildPaths()
+
+		// Sorting puts /users before /users/, but registration order still decides
+		if summary := paths["/users"].Get.Summary; summary != "List users" {
+			t.Errorf("The first registered GET /users should win with SortPaths, got %q", summary)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		gen := NewGenerator()
+		gen.routes = routes
+		paths := gen.buildPaths()
+
+		if _, exists := paths["/users/"]; !exists {
+			t.Error("Paths should be kept as registered without NormalizePaths")
+		}
+		if len(gen.Warnings()) != 0 {
+			t.Errorf("Expected no warnings, got %v", gen.Warnings())
+		}
+	})
+}
@@ -0,0 +1,200 @@
+package analyzer
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Lint issue severities reported by Linter
+const (
+	SeverityWarn  = "WARN"
+	SeverityError = "ERROR"
+)
+
+// httpMethods are the operation keys a path item may define
+var httpMethods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// LintIssue is a quality problem found in a specification file
+type LintIssue struct {
+	Severity string // SeverityWarn or SeverityError
+	Location string // Dotted location in the spec, e.g. "paths./health.get"
+	Message  string
+}
+
+// String formats the issue as "[SEVERITY] location: message"
+func (i LintIssue) String() string {
+	return fmt.Sprintf("[%s] %s: %s", i.Severity, i.Location, i.Message)
+}
+
+// Linter checks a parsed OpenAPI document for common quality issues
+type Linter struct {
+	paths   map[string]interface{}
+	schemas map[string]interface{}
+}
+
+// NewLinter parses a YAML or JSON OpenAPI document for linting
+func NewLinter(content []byte) (*Linter, error) {
+	var spec struct {
+		Paths      map[string]interface{} `yaml:"paths"`
+		Components struct {
+			Schemas map[string]interface{} `yaml:"schemas"`
+		} `yaml:"components"`
+		Defs map[string]interface{} `yaml:"$defs"`
+	}
+	if err := yaml.Unmarshal(content, &spec); err != nil {
+		return nil, fmt.Errorf("failed to parse spec: %w", err)
+	}
+
+	// OpenAPI 3.1 specs may keep their schemas under $defs instead
+	schemas := spec.Components.Schemas
+	if len(schemas) == 0 {
+		schemas = spec.Defs
+	}
+	return &Linter{paths: spec.Paths, schemas: schemas}, nil
+}
+
+// Lint runs every rule and returns the issues found
+func (l *Linter) Lint() []LintIssue {
+	var issues []LintIssue
+	issues = append(issues, l.CheckEmptyPaths()...)
+	issues = append(issues, l.CheckSummaries()...)
+	issues = append(issues, l.CheckOperationIDs()...)
+	issues = append(issues, l.CheckResponseCodes()...)
+	issues = append(issues, l.CheckSchemaProperties()...)
+	return issues
+}
+
+// HasErrors reports whether any issue is an error
+func HasErrors(issues []LintIssue) bool {
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckEmptyPaths reports paths that define no operations
+func (l *Linter) CheckEmptyPaths() []LintIssue {
+	var issues []LintIssue
+	for _, path := range sortedKeys(l.paths) {
+		if len(l.operations(path)) == 0 {
+			issues = append(issues, LintIssue{SeverityWarn, "paths." + path, "path has no operations"})
+		}
+	}
+	return issues
+}
+
+// CheckSummaries reports operations without a summary
+func (l *Linter) CheckSummaries() []LintIssue {
+	var issues []LintIssue
+	l.eachOperation(func(location string, op map[string]interface{}) {
+		if summary, _ := op["summary"].(string); strings.TrimSpace(summary) == "" {
+			issues = append(issues, LintIssue{SeverityWarn, location, "operation has no summary"})
+		}
+	})
+	return issues
+}
+
+// CheckOperationIDs reports operationIds used by more than one operation
+func (l *Linter) CheckOperationIDs() []LintIssue {
+	var issues []LintIssue
+	firstUse := make(map[string]string)
+	l.eachOperation(func(location string, op map[string]interface{}) {
+		id, _ := op["operationId"].(string)
+		if id == "" {
+			return
+		}
+		if first, exists := firstUse[id]; exists {
+			issues = append(issues, LintIssue{SeverityError, location, fmt.Sprintf("duplicate operationId %q (first used at %s)", id, first)})
+			return
+		}
+		firstUse[id] = location
+	})
+	return issues
+}
+
+// CheckResponseCodes reports invalid status codes as errors and valid codes outside
+// the 2xx, 4xx and 5xx ranges as warnings
+func (l *Linter) CheckResponseCodes() []LintIssue {
+	var issues []LintIssue
+	l.eachOperation(func(location string, op map[string]interface{}) {
+		responses, _ := op["responses"].(map[string]interface{})
+		for _, code := range sortedKeys(responses) {
+			responseLocation := location + ".responses." + code
+			if code == "default" {
+				continue
+			}
+			status, err := strconv.Atoi(code)
+			switch {
+			case err != nil || status < 100 || status > 599:
+				issues = append(issues, LintIssue{SeverityError, responseLocation, fmt.Sprintf("invalid response code %q", code)})
+			case status < 200 || (status >= 300 && status < 400):
+				issues = append(issues, LintIssue{SeverityWarn, responseLocation, fmt.Sprintf("response code %s is outside the 2xx, 4xx and 5xx ranges", code)})
+			}
+		}
+	})
+	return issues
+}
+
+// CheckSchemaProperties reports object schemas that describe no properties
+func (l *Linter) CheckSchemaProperties() []LintIssue {
+	var issues []LintIssue
+	for _, name := range sortedKeys(l.schemas) {
+		schema, ok := l.schemas[name].(map[string]interface{})
+		if !ok || !isEmptyObjectSchema(schema) {
+			continue
+		}
+		issues = append(issues, LintIssue{SeverityWarn, "components.schemas." + name, "object schema has no properties"})
+	}
+	return issues
+}
+
+// isEmptyObjectSchema reports whether schema is a non-primitive with no way to describe its content
+func isEmptyObjectSchema(schema map[string]interface{}) bool {
+	for _, key := range []string{"properties", "additionalProperties", "items", "$ref", "oneOf", "anyOf", "allOf", "enum"} {
+		if _, exists := schema[key]; exists {
+			return false
+		}
+	}
+	schemaType, _ := schema["type"].(string)
+	return schemaType == "" || schemaType == "object"
+}
+
+// eachOperation calls fn for every operation in path then method order
+func (l *Linter) eachOperation(fn func(location string, op map[string]interface{})) {
+	for _, path := range sortedKeys(l.paths) {
+		operations := l.operations(path)
+		for _, method := range httpMethods {
+			if op, ok := operations[method]; ok {
+				fn("paths."+path+"."+method, op)
+			}
+		}
+	}
+}
+
+// operations returns the operations defined on path keyed by method
+func (l *Linter) operations(path string) map[string]map[string]interface{} {
+	pathItem, _ := l.paths[path].(map[string]interface{})
+	operations := make(map[string]map[string]interface{})
+	for _, method := range httpMethods {
+		if op, ok := pathItem[method].(map[string]interface{}); ok {
+			operations[method] = op
+		}
+	}
+	return operations
+}
+
+// sortedKeys returns the keys of m in sorted order
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
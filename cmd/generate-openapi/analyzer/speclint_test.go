@@ -0,0 +1,124 @@
+package analyzer
+
+import (
+	"testing"
+)
+
+// newTestLinter parses spec and fails the test on error
+func newTestLinter(t *testing.T, spec string) *Linter {
+	t.Helper()
+
+	linter, err := NewLinter([]byte(spec))
+	if err != nil {
+		t.Fatalf("NewLinter() error = %v", err)
+	}
+	return linter
+}
+
+func TestLinter_CheckSummaries(t *testing.T) {
+	linter := newTestLinter(t, `
+paths:
+  /a:
+    get: {summary: A, responses: {"200": {description: ok}}}
+  /b:
+    post: {responses: {"200": {description: ok}}}
+`)
+
+	issues := linter.CheckSummaries()
+	if len(issues) != 1 || issues[0].Location != "paths./b.post" || issues[0].Severity != SeverityWarn {
+		t.Errorf("Expected one warning for paths./b.post, got %v", issues)
+	}
+}
+
+func TestLinter_CheckOperationIDs(t *testing.T) {
+	linter := newTestLinter(t, `
+paths:
+  /a:
+    get: {operationId: getThing}
+  /b:
+    get: {operationId: getThing}
+  /c:
+    get: {operationId: getOther}
+`)
+
+	issues := linter.CheckOperationIDs()
+	if len(issues) != 1 || issues[0].Location != "paths./b.get" || issues[0].Severity != SeverityError {
+		t.Errorf("Expected one error for the duplicate at paths./b.get, got %v", issues)
+	}
+}
+
+func TestLinter_CheckEmptyPaths(t *testing.T) {
+	linter := newTestLinter(t, `
+paths:
+  /empty: {}
+  /full:
+    get: {summary: Full}
+`)
+
+	issues := linter.CheckEmptyPaths()
+	if len(issues) != 1 || issues[0].Location != "paths./empty" {
+		t.Errorf("Expected one issue for paths./empty, got %v", issues)
+	}
+}
+
+func TestLinter_CheckSchemaProperties(t *testing.T) {
+	linter := newTestLinter(t, `
+paths: {}
+components:
+  schemas:
+    Empty: {type: object}
+    Untyped: {description: nothing here}
+    Name: {type: string}
+    Map: {type: object, additionalProperties: true}
+    User: {type: object, properties: {name: {type: string}}}
+`)
+
+	issues := linter.CheckSchemaProperties()
+	if len(issues) != 2 || issues[0].Location != "components.schemas.Empty" || issues[1].Location != "components.schemas.Untyped" {
+		t.Errorf("Expected issues for Empty and Untyped, got %v", issues)
+	}
+}
+
+func TestLinter_CheckResponseCodes(t *testing.T) {
+	linter := newTestLinter(t, `
+paths:
+  /a:
+    get:
+      responses:
+        "200": {description: ok}
+        "304": {description: not modified}
+        "404": {description: missing}
+        "999": {description: bogus}
+        default: {description: error}
+`)
+
+	issues := linter.CheckResponseCodes()
+	if len(issues) != 2 {
+		t.Fatalf("Expected two issues, got %v", issues)
+	}
+	if issues[0].Severity != SeverityWarn || issues[0].Location != "paths./a.get.responses.304" {
+		t.Errorf("Expected a warning for 304, got %v", issues[0])
+	}
+	if issues[1].Severity != SeverityError || issues[1].Location != "paths./a.get.responses.999" {
+		t.Errorf("Expected an error for 999, got %v", issues[1])
+	}
+}
+
+func TestLinter_Lint(t *testing.T) {
+	linter := newTestLinter(t, `
+paths:
+  /a:
+    get: {operationId: same, responses: {"200": {description: ok}}}
+    post: {operationId: same, summary: Post, responses: {"200": {description: ok}}}
+`)
+
+	issues := linter.Lint()
+	if !HasErrors(issues) {
+		t.Errorf("Expected an error for the duplicate operationId, got %v", issues)
+	}
+
+	expected := "[WARN] paths./a.get: operation has no summary"
+	if len(issues) == 0 || issues[0].String() != expected {
+		t.Errorf("Expected first issue %q, got %v", expected, issues)
+	}
+}
@@ -0,0 +1,237 @@
+package analyzer
+
+import (
+	"encoding/json"
+	"maps"
+	"slices"
+	"strings"
+)
+
+// typeScriptHeader starts every generated TypeScript definitions file
+const typeScriptHeader = "// Code generated by generate-openapi. DO NOT EDIT.\n"
+
+// TypeScriptDefinitions returns a .d.ts file declaring the component schemas as
+// TypeScript types, in name order. Call it after generating the spec: schema hook
+// edits are kept, and schemas a hook skipped or the spec pruned are left out.
+// Object schemas become interfaces whose optional properties are marked with ?;
+// other schemas become type aliases. Component references use the referenced
+// type's name, and nested objects without a component are written inline.
+func (g *Generator) TypeScriptDefinitions() string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	var b strings.Builder
+	b.WriteString(typeScriptHeader)
+	for _, name := range slices.Sorted(maps.Keys(g.typeSchemas)) {
+		if slices.Contains(g.prunedSchemas, name) {
+			continue
+		}
+		schema, _ := g.typeSchemas[name].(map[string]interface{})
+		b.WriteString("\n")
+		writeTypeScriptComment(&b, schema, "")
+		if _, isObject := schema["properties"].(map[string]interface{}); isObject && schema["allOf"] == nil {
+			b.WriteString("export interface " + name + " " + typeScriptObject(schema, "") + "\n")
+		} else {
+			b.WriteString("export type " + name + " = " + typeScriptType(schema, "") + ";\n")
+		}
+	}
+	return b.String()
+}
+
+// typeScriptType returns the TypeScript type for a schema; indent is the
+// indentation of the line the type is written on
+func typeScriptType(schema map[string]interface{}, indent string) string {
+	if schema == nil {
+		return "unknown"
+	}
+
+	var tsType string
+	switch {
+	case schema["$ref"] != nil:
+		ref, _ := schema["$ref"].(string)
+		tsType = ref[strings.LastIndex(ref, "/")+1:]
+	case schema["enum"] != nil:
+		tsType = typeScriptEnum(schema["enum"])
+	case schema["oneOf"] != nil:
+		tsType = typeScriptUnion(schema["oneOf"], indent)
+	case schema["allOf"] != nil:
+		tsType = typeScriptIntersection(schema, indent)
+	default:
+		tsType = typeScriptPrimitive(schema, indent)
+	}
+
+	if nullable, _ := schema["nullable"].(bool); nullable {
+		tsType += " | null"
+	}
+	return tsType
+}
+
+// typeScriptPrimitive returns the TypeScript type for a schema's type keyword. A
+// 3.1 type array such as [string, "null"] becomes a union of its types.
+func typeScriptPrimitive(schema map[string]interface{}, indent string) string {
+	if typeNames, ok := schema["type"].([]interface{}); ok {
+		single := make(map[string]interface{}, len(schema))
+		maps.Copy(single, schema)
+		types := make([]string, 0, len(typeNames))
+		for _, typeName := range typeNames {
+			if typeName == "null" {
+				types = append(types, "null")
+				continue
+			}
+			single["type"] = typeName
+			types = append(types, typeScriptPrimitive(single, indent))
+		}
+		return strings.Join(types, " | ")
+	}
+
+	switch schema["type"] {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "array":
+		items, _ := schema["items"].(map[string]interface{})
+		itemType := typeScriptType(items, indent)
+		if strings.Contains(itemType, " | ") {
+			return "(" + itemType + ")[]"
+		}
+		return itemType + "[]"
+	case "object":
+		if _, ok := schema["properties"].(map[string]interface{}); ok {
+			return typeScriptObject(schema, indent)
+		}
+		if values, ok := schema["additionalProperties"].(map[string]interface{}); ok {
+			return "Record<string, " + typeScriptType(values, indent) + ">"
+		}
+		return "Record<string, unknown>"
+	}
+	return "unknown"
+}
+
+// typeScriptObject returns an object type literal for a schema's properties,
+// marking properties missing from required as optional
+func typeScriptObject(schema map[string]interface{}, indent string) string {
+	properties, _ := schema["properties"].(map[string]interface{})
+	if len(properties) == 0 {
+		return "{}"
+	}
+
+	required := make(map[string]bool)
+	switch names := schema["required"].(type) {
+	case []string:
+		for _, name := range names {
+			required[name] = true
+		}
+	case []interface{}:
+		for _, name := range names {
+			if s, ok := name.(string); ok {
+				required[s] = true
+			}
+		}
+	}
+
+	fieldIndent := indent + "  "
+	var b strings.Builder
+	b.WriteString("{\n")
+	for _, name := range slices.Sorted(maps.Keys(properties)) {
+		property, _ := properties[name].(map[string]interface{})
+		writeTypeScriptComment(&b, property, fieldIndent)
+
+		optional := "?"
+		if required[name] {
+			optional = ""
+		}
+		b.WriteString(fieldIndent + typeScriptPropertyName(name) + optional + ": " + typeScriptType(property, fieldIndent) + ";\n")
+	}
+	b.WriteString(indent + "}")
+	return b.String()
+}
+
+// typeScriptUnion returns a union of oneOf variants
+func typeScriptUnion(oneOf interface{}, indent string) string {
+	variants, _ := oneOf.([]interface{})
+	types := make([]string, 0, len(variants))
+	for _, variant := range variants {
+		schema, _ := variant.(map[string]interface{})
+		types = append(types, typeScriptType(schema, indent))
+	}
+	if len(types) == 0 {
+		return "unknown"
+	}
+	return strings.Join(types, " | ")
+}
+
+// typeScriptIntersection returns an intersection of allOf members, plus the
+// schema's own properties when it declares any alongside allOf
+func typeScriptIntersection(schema map[string]interface{}, indent string) string {
+	members, _ := schema["allOf"].([]interface{})
+	types := make([]string, 0, len(members)+1)
+	for _, member := range members {
+		memberSchema, _ := member.(map[string]interface{})
+		memberType := typeScriptType(memberSchema, indent)
+		if strings.Contains(memberType, " | ") {
+			memberType = "(" + memberType + ")"
+		}
+		types = append(types, memberType)
+	}
+	if _, ok := schema["properties"].(map[string]interface{}); ok {
+		types = append(types, typeScriptObject(schema, indent))
+	}
+	if len(types) == 0 {
+		return "unknown"
+	}
+	return strings.Join(types, " & ")
+}
+
+// typeScriptEnum returns a union of an enum's values as literal types
+func typeScriptEnum(enum interface{}) string {
+	var values []interface{}
+	switch v := enum.(type) {
+	case []interface{}:
+		values = v
+	case []string:
+		for _, s := range v {
+			values = append(values, s)
+		}
+	}
+
+	literals := make([]string, 0, len(values))
+	for _, value := range values {
+		literal, err := json.Marshal(value)
+		if err != nil {
+			continue
+		}
+		literals = append(literals, string(literal))
+	}
+	if len(literals) == 0 {
+		return "unknown"
+	}
+	return strings.Join(literals, " | ")
+}
+
+// typeScriptPropertyName quotes property names that are not valid identifiers
+func typeScriptPropertyName(name string) string {
+	for i, r := range name {
+		identifier := r == '_' || r == '$' || (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (i > 0 && r >= '0' && r <= '9')
+		if !identifier {
+			quoted, _ := json.Marshal(name)
+			return string(quoted)
+		}
+	}
+	if name == "" {
+		return `""`
+	}
+	return name
+}
+
+// writeTypeScriptComment writes a schema's description as a doc comment
+func writeTypeScriptComment(b *strings.Builder, schema map[string]interface{}, indent string) {
+	description, _ := schema["description"].(string)
+	if description == "" {
+		return
+	}
+	description = strings.ReplaceAll(description, "*/", "*\\/")
+	b.WriteString(indent + "/** " + strings.ReplaceAll(description, "\n", " ") + " */\n")
+}
@@ -0,0 +1,113 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+type tsOrder struct {
+	ID       int      `json:"id"`
+	Tags     []string `json:"tags,omitempty"`
+	Note     *string  `json:"note,omitempty"`
+	Customer User     `json:"customer"`
+}
+
+func TestTypeScriptDefinitions_FinalSpecSchemas(t *testing.T) {
+	gen := NewGenerator()
+	gen.OnSchema(func(t reflect.Type, name string, schema map[string]interface{}) bool {
+		if name == "User" {
+			schema["description"] = "Customer placing the order"
+		}
+		return false
+	})
+	gen.OnSchema(func(t reflect.Type, name string, schema map[string]interface{}) bool {
+		return name == "Model"
+	})
+	// The skipped operation's response schema is pruned from the spec
+	gen.OnOperation(func(route types.RouteInfo, op *Operation) bool {
+		return route.Path == "/unused"
+	})
+	_, err := gen.GenerateSpecForRoutes([]types.RouteInfo{
+		{Method: "GET", Path: "/orders/{id}", ResponseType: reflect.TypeOf(tsOrder{}), Module: "orders"},
+		{Method: "POST", Path: "/users", RequestType: reflect.TypeOf(User{}), Module: "users"},
+		{Method: "GET", Path: "/models", ResponseType: reflect.TypeOf(Model{}), Module: "models"},
+		{Method: "GET", Path: "/unused", ResponseType: reflect.TypeOf(TestRequest{}), Module: "unused"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	out := gen.TypeScriptDefinitions()
+
+	expectedOrder := `export interface tsOrder {
+  customer: {
+    name: string;
+  };
+  id: number;
+  note?: string;
+  tags?: string[];
+}
+`
+	if !strings.Contains(out, expectedOrder) {
+		t.Errorf("Expected the tsOrder interface with optional markers and an inline customer, got:\n%s", out)
+	}
+	if !strings.Contains(out, "/** Customer placing the order */\nexport interface User {\n  name: string;\n}\n") {
+		t.Errorf("Expected the User interface as the schema hook left it, got:\n%s", out)
+	}
+	if strings.Index(out, "export interface User") > strings.Index(out, "export interface tsOrder") {
+		t.Error("Expected interfaces in name order")
+	}
+	if strings.Contains(out, "TestRequest") || strings.Contains(out, "Model") {
+		t.Errorf("Expected only the schemas of the final spec, got:\n%s", out)
+	}
+}
+
+func TestTypeScriptDefinitions_JSONSchemaDefs(t *testing.T) {
+	gen := NewGenerator(WithOpenAPIVersion(OpenAPIVersion31), WithJSONSchemaDefs(true))
+	_, err := gen.GenerateSpecForRoutes([]types.RouteInfo{
+		{Method: "GET", Path: "/orders/{id}", ResponseType: reflect.TypeOf(tsOrder{}), Module: "orders"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	if out := gen.TypeScriptDefinitions(); !strings.Contains(out, "export interface tsOrder {") {
+		t.Errorf("Expected the tsOrder interface when schemas are emitted under $defs, got:\n%s", out)
+	}
+}
+
+func TestTypeScriptType(t *testing.T) {
+	tests := []struct {
+		name     string
+		schema   map[string]interface{}
+		expected string
+	}{
+		{"nullable timestamp", map[string]interface{}{"type": "string", "format": "date-time", "nullable": true}, "string | null"},
+		{"map", map[string]interface{}{"type": "object", "additionalProperties": map[string]interface{}{"type": "integer"}}, "Record<string, number>"},
+		{"free-form object", map[string]interface{}{"type": "object", "additionalProperties": true}, "Record<string, unknown>"},
+		{"enum", map[string]interface{}{"type": "string", "enum": []interface{}{"queued", "done"}}, `"queued" | "done"`},
+		{"union array", map[string]interface{}{"type": "array", "items": map[string]interface{}{"oneOf": []interface{}{
+			map[string]interface{}{"$ref": "#/components/schemas/Cat"},
+			map[string]interface{}{"$ref": "#/components/schemas/Dog"},
+		}}}, "(Cat | Dog)[]"},
+		{"type array", map[string]interface{}{"type": []interface{}{"integer", "null"}}, "number | null"},
+		{"allOf", map[string]interface{}{"allOf": []interface{}{
+			map[string]interface{}{"$ref": "#/components/schemas/Base"},
+			map[string]interface{}{"oneOf": []interface{}{
+				map[string]interface{}{"$ref": "#/components/schemas/Cat"},
+				map[string]interface{}{"$ref": "#/components/schemas/Dog"},
+			}},
+		}}, "Base & (Cat | Dog)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := typeScriptType(tt.schema, ""); got != tt.expected {
+				t.Errorf("typeScriptType() = %q, expected %q", got, tt.expected)
+			}
+		})
+	}
+}
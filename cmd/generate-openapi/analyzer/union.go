@@ -0,0 +1,152 @@
+package analyzer
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	// unionRegistry maps interface types to the concrete variants they may hold
+	unionRegistry = make(map[reflect.Type][]reflect.Type)
+	// unionMutex protects concurrent access to unionRegistry
+	unionMutex sync.RWMutex
+)
+
+// RegisterUnion declares the concrete variants an interface-typed field may hold so its
+// schema is generated as a oneOf. The interface may be given either directly or as a
+// pointer, e.g. reflect.TypeOf((*MessageContent)(nil)).
+func RegisterUnion(iface reflect.Type, variants ...reflect.Type) {
+	if iface.Kind() == reflect.Ptr && iface.Elem().Kind() == reflect.Interface {
+		iface = iface.Elem()
+	}
+
+	unionMutex.Lock()
+	defer unionMutex.Unlock()
+
+	unionRegistry[iface] = append([]reflect.Type(nil), variants...)
+}
+
+// ClearUnions removes all registered union variants (used for testing)
+func ClearUnions() {
+	unionMutex.Lock()
+	defer unionMutex.Unlock()
+
+	unionRegistry = make(map[reflect.Type][]reflect.Type)
+}
+
+// unionVariants returns the registered variants for an interface type
+func unionVariants(iface reflect.Type) []reflect.Type {
+	unionMutex.RLock()
+	defer unionMutex.RUnlock()
+
+	return unionRegistry[iface]
+}
+
+// generateUnionSchema builds a oneOf schema from registered variants. Named struct
+// variants become component schemas referenced by $ref so discriminators can map to them.
+func (g *Generator) generateUnionSchema(variants []reflect.Type, state *schemaState) (map[string]interface{}, error) {
+	oneOf := make([]interface{}, 0, len(variants))
+
+	for _, variant := range variants {
+		if !isComponentVariant(variant) {
+			schema, err := g.generateSchemaForType(variant, state)
+			if err != nil {
+				return nil, fmt.Errorf("union variant %v: %w", variant, err)
+			}
+			oneOf = append(oneOf, schema)
+			continue
+		}
+
+		name := g.getTypeName(variant)
+		if err := g.claimSchemaName(name, variant); err != nil {
+			return nil, fmt.Errorf("union variant %v: %w", variant, err)
+		}
+		if !g.hasComponentSchema(name) {
+			schema, err := g.generateSchemaForType(variant, state)
+			if err != nil {
+				return nil, fmt.Errorf("union variant %v: %w", variant, err)
+			}
+			g.addVariantSchema(variant, name, schema)
+		}
+		oneOf = append(oneOf, map[string]interface{}{"$ref": componentSchemasRef + name})
+	}
+
+	return map[string]interface{}{"oneOf": oneOf}, nil
+}
+
+// hasComponentSchema reports whether a component schema has been generated under
+// name, including variants waiting to be added and schemas a hook skipped
+func (g *Generator) hasComponentSchema(name string) bool {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	_, exists := g.typeSchemas[name]
+	_, pending := g.variantSchemas[name]
+	return exists || pending || g.skippedSchemas[name]
+}
+
+// addVariantSchema records a union variant schema, unless another worker already
+// did, for generateSchemas to add through the schema hooks
+func (g *Generator) addVariantSchema(t reflect.Type, name string, schema map[string]interface{}) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.variantSchemas == nil {
+		g.variantSchemas = make(map[string]namedSchema)
+	}
+	if _, exists := g.variantSchemas[name]; !exists {
+		g.variantSchemas[name] = namedSchema{name: name, schema: schema, typ: t}
+	}
+}
+
+// componentSchema returns the component schema named name, including a union
+// variant not yet added by generateSchemas
+func (g *Generator) componentSchema(name string) (map[string]interface{}, bool) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if schema, ok := g.typeSchemas[name].(map[string]interface{}); ok {
+		return schema, true
+	}
+	variant, ok := g.variantSchemas[name]
+	return variant.schema, ok
+}
+
+// addDiscriminator attaches a discriminator to a oneOf schema, mapping each referenced
+// variant's schema name to its $ref
+func addDiscriminator(schema map[string]interface{}, propertyName string) {
+	oneOf, ok := schema["oneOf"].([]interface{})
+	if !ok {
+		return
+	}
+
+	mapping := make(map[string]string)
+	for _, variant := range oneOf {
+		variantMap, ok := variant.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		ref, ok := variantMap["$ref"].(string)
+		if !ok {
+			continue
+		}
+		if name, ok := schemaRefName(ref); ok {
+			mapping[name] = ref
+		}
+	}
+
+	discriminator := map[string]interface{}{"propertyName": propertyName}
+	if len(mapping) > 0 {
+		discriminator["mapping"] = mapping
+	}
+	schema["discriminator"] = discriminator
+}
+
+// isComponentVariant reports whether a union variant should be emitted as a component schema
+func isComponentVariant(t reflect.Type) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t.Kind() == reflect.Struct && t.Name() != "" && !(t.PkgPath() == "time" && t.Name() == "Time")
+}
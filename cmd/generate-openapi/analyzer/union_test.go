@@ -0,0 +1,122 @@
+package analyzer
+
+import (
+	"reflect"
+	"testing"
+)
+
+// MessageContent is either a plain string or a list of content parts
+type MessageContent interface{}
+
+type ContentPart struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// Shape is a discriminated union of TextShape and ImageShape
+type Shape interface {
+	isShape()
+}
+
+type TextShape struct {
+	Kind string `json:"kind"`
+	Text string `json:"text"`
+}
+
+func (TextShape) isShape() {}
+
+type ImageShape struct {
+	Kind string `json:"kind"`
+	URL  string `json:"url"`
+}
+
+func (ImageShape) isShape() {}
+
+type ChatMessage struct {
+	Role    string         `json:"role"`
+	Content MessageContent `json:"content"`
+	Shape   Shape          `json:"shape" discriminator:"kind"`
+	Extra   interface{}    `json:"extra,omitempty"`
+}
+
+func TestRegisterUnion_OneOf(t *testing.T) {
+	ClearUnions()
+	t.Cleanup(ClearUnions)
+
+	RegisterUnion(reflect.TypeOf((*MessageContent)(nil)), reflect.TypeOf(""), reflect.TypeOf([]ContentPart{}))
+
+	gen := NewGenerator()
+	schema, err := gen.generateTypeSchema(reflect.TypeOf(ChatMessage{}))
+	if err != nil {
+		t.Fatalf("generateTypeSchema() error = %v", err)
+	}
+
+	properties := schema["properties"].(map[string]interface{})
+	content := properties["content"].(map[string]interface{})
+
+	oneOf, ok := content["oneOf"].([]interface{})
+	if !ok || len(oneOf) != 2 {
+		t.Fatalf("Expected content to have a oneOf with 2 variants, got %v", content)
+	}
+
+	if oneOf[0].(map[string]interface{})["type"] != "string" {
+		t.Errorf("Expected first variant to be string, got %v", oneOf[0])
+	}
+
+	arrayVariant := oneOf[1].(map[string]interface{})
+	if arrayVariant["type"] != "array" {
+		t.Errorf("Expected second variant to be array, got %v", arrayVariant)
+	}
+	items := arrayVariant["items"].(map[string]interface{})
+	if _, exists := items["properties"].(map[string]interface{})["text"]; !exists {
+		t.Errorf("Expected array items to use the ContentPart schema, got %v", items)
+	}
+
+	// Unregistered interface fields stay permissive
+	extra := properties["extra"].(map[string]interface{})
+	if extra["type"] != "object" || extra["additionalProperties"] != true {
+		t.Errorf("Unregistered interface field should stay permissive, got %v", extra)
+	}
+}
+
+func TestRegisterUnion_Discriminator(t *testing.T) {
+	ClearUnions()
+	t.Cleanup(ClearUnions)
+
+	RegisterUnion(reflect.TypeOf((*Shape)(nil)).Elem(), reflect.TypeOf(TextShape{}), reflect.TypeOf(ImageShape{}))
+
+	gen := NewGenerator()
+	schema, err := gen.generateTypeSchema(reflect.TypeOf(ChatMessage{}))
+	if err != nil {
+		t.Fatalf("generateTypeSchema() error = %v", err)
+	}
+
+	shape := schema["properties"].(map[string]interface{})["shape"].(map[string]interface{})
+
+	oneOf, ok := shape["oneOf"].([]interface{})
+	if !ok || len(oneOf) != 2 {
+		t.Fatalf("Expected shape to have a oneOf with 2 variants, got %v", shape)
+	}
+
+	discriminator, ok := shape["discriminator"].(map[string]interface{})
+	if !ok {
+		t.Fatal("Expected shape to have a discriminator")
+	}
+	if discriminator["propertyName"] != "kind" {
+		t.Errorf("Expected discriminator propertyName 'kind', got %v", discriminator["propertyName"])
+	}
+
+	expected := map[string]string{
+		"TextShape":  "#/components/schemas/TextShape",
+		"ImageShape": "#/components/schemas/ImageShape",
+	}
+	if !reflect.DeepEqual(discriminator["mapping"], expected) {
+		t.Errorf("Expected discriminator mapping %v, got %v", expected, discriminator["mapping"])
+	}
+
+	for name := range expected {
+		if _, exists := gen.componentSchema(name); !exists {
+			t.Errorf("Struct variant %s should be recorded as a component schema", name)
+		}
+	}
+}
@@ -0,0 +1,104 @@
+package analyzer
+
+import (
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/config"
+	buildversion "github.com/JerkyTreats/llm/internal/version"
+)
+
+// VersionConfigKey sets the API version written to info.version, overriding the
+// build and project versions
+const VersionConfigKey = "openapi.version"
+
+// execCommand creates the git command run by DetectVersion; tests replace it
+var execCommand = exec.Command
+
+// majorVersionSuffix matches the /vN suffix of a module path for major versions 2 and up
+var majorVersionSuffix = regexp.MustCompile(`^v([2-9]|[1-9][0-9]+)$`)
+
+// DetectVersion returns the API version: openapi.version from config, the release
+// version stamped into the binary, or else the version of the project in the working
+// directory, read from a VERSION file, the major version suffix of go.mod's module
+// path, or the latest git tag, in that order. It returns DefaultVersion when none is
+// found.
+func DetectVersion() string {
+	if version := configuredVersion(); version != "" {
+		return version
+	}
+	return detectVersion(".")
+}
+
+// configuredVersion returns openapi.version from config, or the build version of a
+// binary stamped with -ldflags, or "" when neither is set
+func configuredVersion() string {
+	if version := strings.TrimSpace(config.GetString(VersionConfigKey)); version != "" {
+		return version
+	}
+	if buildversion.Stamped() {
+		return buildversion.Version
+	}
+	return ""
+}
+
+// infoVersion returns the version written to info.version. GenerateSpec has
+// already detected Version; specs built for a subset of routes, such as the docs
+// module specs, skip the project lookup and use config or DefaultVersion.
+func (g *Generator) infoVersion() string {
+	if g.Version != "" {
+		return g.Version
+	}
+	if version := configuredVersion(); version != "" {
+		return version
+	}
+	return DefaultVersion
+}
+
+// detectVersion detects the version of the project rooted at root
+func detectVersion(root string) string {
+	if version := versionFromFile(filepath.Join(root, "VERSION")); version != "" {
+		return version
+	}
+	if version := versionFromGoMod(filepath.Join(root, "go.mod")); version != "" {
+		return version
+	}
+	if version := versionFromGitTag(root); version != "" {
+		return version
+	}
+	return DefaultVersion
+}
+
+// versionFromFile returns the trimmed contents of a VERSION file
+func versionFromFile(file string) string {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// versionFromGoMod returns "N.0.0" for a module path ending in /vN
+func versionFromGoMod(file string) string {
+	match := majorVersionSuffix.FindStringSubmatch(path.Base(modulePath(file)))
+	if match == nil {
+		return ""
+	}
+	return match[1] + ".0.0"
+}
+
+// versionFromGitTag returns the most recent tag reachable from HEAD, without its
+// conventional "v" prefix
+func versionFromGitTag(root string) string {
+	cmd := execCommand("git", "describe", "--tags", "--abbrev=0")
+	cmd.Dir = root
+	out, err := cmd.Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.TrimSpace(string(out)), "v")
+}
@@ -0,0 +1,133 @@
+package analyzer
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/config"
+	buildversion "github.com/JerkyTreats/llm/internal/version"
+)
+
+// mockGit makes execCommand run TestHelperProcess, which prints tag or fails when
+// tag is empty
+func mockGit(t *testing.T, tag string) {
+	t.Helper()
+	execCommand = func(name string, args ...string) *exec.Cmd {
+		cmd := exec.Command(os.Args[0], "-test.run=TestHelperProcess", "--", name)
+		cmd.Env = append(os.Environ(), "GO_WANT_HELPER_PROCESS=1", "HELPER_GIT_TAG="+tag)
+		return cmd
+	}
+	t.Cleanup(func() { execCommand = exec.Command })
+}
+
+// TestHelperProcess stands in for git when run by mockGit
+func TestHelperProcess(t *testing.T) {
+	if os.Getenv("GO_WANT_HELPER_PROCESS") != "1" {
+		return
+	}
+	tag := os.Getenv("HELPER_GIT_TAG")
+	if tag == "" {
+		fmt.Fprintln(os.Stderr, "fatal: No names found, cannot describe anything.")
+		os.Exit(128)
+	}
+	fmt.Println(tag)
+	os.Exit(0)
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestDetectVersion_VersionFile(t *testing.T) {
+	mockGit(t, "v9.9.9")
+	root := t.TempDir()
+	writeFile(t, filepath.Join(root, "VERSION"), "2.3.1\n")
+	writeFile(t, filepath.Join(root, "go.mod"), "module example.com/api/v4\n")
+
+	if got := detectVersion(root); got != "2.3.1" {
+		t.Errorf("detectVersion() = %q, want the VERSION file's 2.3.1", got)
+	}
+}
+
+func TestDetectVersion_GoModMajorVersion(t *testing.T) {
+	mockGit(t, "v9.9.9")
+
+	tests := []struct {
+		module string
+		want   string
+	}{
+		{"module example.com/api/v2\n\ngo 1.24\n", "2.0.0"},
+		{"// API module\nmodule \"example.com/api/v12\"\n", "12.0.0"},
+		{"module example.com/api\n", "9.9.9"},    // No suffix falls through to git
+		{"module example.com/api/v1\n", "9.9.9"}, // v1 is not a valid major suffix
+	}
+
+	for _, tt := range tests {
+		root := t.TempDir()
+		writeFile(t, filepath.Join(root, "go.mod"), tt.module)
+		if got := detectVersion(root); got != tt.want {
+			t.Errorf("detectVersion() with go.mod %q = %q, want %q", tt.module, got, tt.want)
+		}
+	}
+}
+
+func TestDetectVersion_GitTag(t *testing.T) {
+	root := t.TempDir()
+
+	mockGit(t, "v1.4.0")
+	if got := detectVersion(root); got != "1.4.0" {
+		t.Errorf("detectVersion() = %q, want the git tag 1.4.0", got)
+	}
+
+	mockGit(t, "")
+	if got := detectVersion(root); got != DefaultVersion {
+		t.Errorf("detectVersion() without a tag = %q, want %s", got, DefaultVersion)
+	}
+}
+
+// stubBuildVersion stamps the build version for the duration of a test
+func stubBuildVersion(t *testing.T, v string) {
+	t.Helper()
+	previous := buildversion.Version
+	buildversion.Version = v
+	t.Cleanup(func() { buildversion.Version = previous })
+}
+
+func TestBuildOpenAPISpec_InfoVersionDefaultsToBuildVersion(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	tests := []struct {
+		name         string
+		buildVersion string
+		configured   string
+		expected     string
+	}{
+		{"unstamped build", buildversion.DevVersion, "", DefaultVersion},
+		{"stamped build", "2.7.1", "", "2.7.1"},
+		{"config overrides build", "2.7.1", "3.0.0-beta", "3.0.0-beta"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stubBuildVersion(t, tt.buildVersion)
+			config.SetForTest(VersionConfigKey, tt.configured)
+
+			spec := generateWithHooks(t, NewGenerator())
+			if spec.Info.Version != tt.expected {
+				t.Errorf("info.version = %q, expected %q", spec.Info.Version, tt.expected)
+			}
+			if tt.configured == "" && tt.buildVersion != buildversion.DevVersion {
+				if got := DetectVersion(); got != tt.buildVersion {
+					t.Errorf("DetectVersion() = %q, expected the build version %q", got, tt.buildVersion)
+				}
+			}
+		})
+	}
+}
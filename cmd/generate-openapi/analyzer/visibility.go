@@ -0,0 +1,50 @@
+package analyzer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// GenerateSpecFiltered generates a spec from the registry for an audience. The
+// public spec omits routes marked VisibilityInternal; the internal spec is the
+// complete reference including every route.
+func (g *Generator) GenerateSpecFiltered(visibility string) (string, error) {
+	routes, err := g.registryRoutes()
+	if err != nil {
+		return "", err
+	}
+
+	filtered, err := filterVisibility(routes, visibility)
+	if err != nil {
+		return "", err
+	}
+	if len(filtered) == 0 {
+		return "", fmt.Errorf("no %s routes in registry", visibility)
+	}
+
+	return g.GenerateSpecForRoutes(filtered)
+}
+
+// filterVisibility returns the routes visible to the given audience
+func filterVisibility(routes []types.RouteInfo, visibility string) ([]types.RouteInfo, error) {
+	if visibility != types.VisibilityPublic && visibility != types.VisibilityInternal {
+		return nil, fmt.Errorf("unknown visibility %q", visibility)
+	}
+
+	var visible []types.RouteInfo
+	for _, route := range routes {
+		switch route.Visibility {
+		case "", types.VisibilityPublic:
+			visible = append(visible, route)
+		case types.VisibilityInternal:
+			if visibility == types.VisibilityInternal {
+				visible = append(visible, route)
+			}
+		default:
+			return nil, fmt.Errorf("route %s %s has unknown visibility %q", strings.ToUpper(route.Method), route.Path, route.Visibility)
+		}
+	}
+	return visible, nil
+}
@@ -0,0 +1,64 @@
+package analyzer
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+type reindexStatus struct {
+	Indexed int `json:"indexed"`
+}
+
+func TestGenerateSpecFiltered(t *testing.T) {
+	t.Chdir(t.TempDir())
+	useRegistry(t, []types.RouteInfo{
+		{Method: "GET", Path: "/models", ResponseType: reflect.TypeOf(TestResponse{}), Module: "models"},
+		{Method: "POST", Path: "/chat", RequestType: reflect.TypeOf(TestRequest{}), Module: "chat", Visibility: types.VisibilityPublic},
+		{Method: "POST", Path: "/admin/reindex", ResponseType: reflect.TypeOf(reindexStatus{}), Module: "admin", Visibility: types.VisibilityInternal},
+	})
+
+	public, err := NewGenerator().GenerateSpecFiltered(types.VisibilityPublic)
+	if err != nil {
+		t.Fatalf("GenerateSpecFiltered(public) error = %v", err)
+	}
+	paths := specPaths(t, public)
+	for _, path := range []string{"/models", "/chat"} {
+		if _, exists := paths[path]; !exists {
+			t.Errorf("Public spec should include %s", path)
+		}
+	}
+	if _, exists := paths["/admin/reindex"]; exists {
+		t.Error("Public spec should omit internal routes")
+	}
+	if strings.Contains(public, "reindexStatus") {
+		t.Error("Public spec should omit schemas only internal routes use")
+	}
+
+	internal, err := NewGenerator().GenerateSpecFiltered(types.VisibilityInternal)
+	if err != nil {
+		t.Fatalf("GenerateSpecFiltered(internal) error = %v", err)
+	}
+	paths = specPaths(t, internal)
+	for _, path := range []string{"/models", "/chat", "/admin/reindex"} {
+		if _, exists := paths[path]; !exists {
+			t.Errorf("Internal spec should include %s", path)
+		}
+	}
+}
+
+func TestGenerateSpecFiltered_UnknownVisibility(t *testing.T) {
+	t.Chdir(t.TempDir())
+	useRegistry(t, []types.RouteInfo{
+		{Method: "GET", Path: "/models", Module: "models", Visibility: "partner"},
+	})
+
+	if _, err := NewGenerator().GenerateSpecFiltered(types.VisibilityPublic); err == nil || !strings.Contains(err.Error(), `unknown visibility "partner"`) {
+		t.Errorf("Expected an unknown route visibility error, got %v", err)
+	}
+	if _, err := NewGenerator().GenerateSpecFiltered("everyone"); err == nil {
+		t.Error("GenerateSpecFiltered() should reject an unknown audience")
+	}
+}
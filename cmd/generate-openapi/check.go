@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// checkSpec compares a freshly generated spec with the file at path and returns a
+// line diff when they differ. An empty diff means the committed spec is up to date.
+func checkSpec(spec, path string) (string, error) {
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if string(existing) == spec {
+		return "", nil
+	}
+
+	return diffLines(path, string(existing), spec), nil
+}
+
+// Limits that keep -check fast and its output readable on large diffs
+const (
+	diffContext   = 3       // Unchanged lines shown around each change
+	maxDiffCells  = 4 << 20 // Largest changed region, in old × new lines, diffed line by line
+	maxDiffOutput = 400     // Diff lines printed before the rest is cut off
+)
+
+// diffOp is one line of a diff: ' ' unchanged, '-' removed or '+' added
+type diffOp struct {
+	kind byte
+	line string
+}
+
+// diffLines renders a unified diff from old to new, with diffContext lines of
+// context around each hunk. Lines shared at the start and end are skipped before
+// diffing; a changed region too large to diff is shown removed then added.
+func diffLines(path, oldText, newText string) string {
+	a := strings.Split(strings.TrimSuffix(oldText, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(newText, "\n"), "\n")
+
+	prefix := 0
+	for prefix < len(a) && prefix < len(b) && a[prefix] == b[prefix] {
+		prefix++
+	}
+	suffix := 0
+	for suffix < len(a)-prefix && suffix < len(b)-prefix && a[len(a)-1-suffix] == b[len(b)-1-suffix] {
+		suffix++
+	}
+
+	ops := make([]diffOp, 0, len(a)+len(b))
+	for _, line := range a[:prefix] {
+		ops = append(ops, diffOp{' ', line})
+	}
+	ops = append(ops, diffMiddle(a[prefix:len(a)-suffix], b[prefix:len(b)-suffix])...)
+	for _, line := range a[len(a)-suffix:] {
+		ops = append(ops, diffOp{' ', line})
+	}
+
+	var out strings.Builder
+	fmt.Fprintf(&out, "--- %s (committed)\n+++ %s (generated)\n", path, path)
+	if !writeHunks(&out, ops) {
+		out.WriteString("Only the trailing newline differs\n")
+	}
+	return out.String()
+}
+
+// diffMiddle diffs the changed region of two texts with a longest common
+// subsequence, or lists it removed then added when it exceeds maxDiffCells
+func diffMiddle(a, b []string) []diffOp {
+	ops := make([]diffOp, 0, len(a)+len(b))
+	if len(a)*len(b) > maxDiffCells {
+		for _, line := range a {
+			ops = append(ops, diffOp{'-', line})
+		}
+		for _, line := range b {
+			ops = append(ops, diffOp{'+', line})
+		}
+		return ops
+	}
+
+	// lcs[i][j] holds the length of the longest common subsequence of a[i:] and b[j:]
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			ops = append(ops, diffOp{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			ops = append(ops, diffOp{'-', a[i]})
+			i++
+		default:
+			ops = append(ops, diffOp{'+', b[j]})
+			j++
+		}
+	}
+	return ops
+}
+
+// writeHunks writes the changed ops as unified diff hunks, stopping once
+// maxDiffOutput lines are written. It reports whether any line changed.
+func writeHunks(out *strings.Builder, ops []diffOp) bool {
+	// oldLine[k] and newLine[k] count the old and new lines before ops[k]
+	oldLine := make([]int, len(ops)+1)
+	newLine := make([]int, len(ops)+1)
+	for k, op := range ops {
+		oldLine[k+1], newLine[k+1] = oldLine[k], newLine[k]
+		if op.kind != '+' {
+			oldLine[k+1]++
+		}
+		if op.kind != '-' {
+			newLine[k+1]++
+		}
+	}
+
+	written := 0
+	changed := false
+	for k := 0; k < len(ops); {
+		if ops[k].kind == ' ' {
+			k++
+			continue
+		}
+		changed = true
+
+		// Extend the hunk while the next change is within two contexts
+		start := max(k-diffContext, 0)
+		end := k
+		for end < len(ops) {
+			if ops[end].kind != ' ' {
+				end++
+				continue
+			}
+			next := end
+			for next < len(ops) && ops[next].kind == ' ' {
+				next++
+			}
+			if next == len(ops) || next-end > 2*diffContext {
+				end = min(end+diffContext, len(ops))
+				break
+			}
+			end = next
+		}
+
+		fmt.Fprintf(out, "@@ -%s +%s @@\n",
+			hunkRange(oldLine[start], oldLine[end]-oldLine[start]),
+			hunkRange(newLine[start], newLine[end]-newLine[start]))
+		for _, op := range ops[start:end] {
+			if written == maxDiffOutput {
+				fmt.Fprintf(out, "... diff cut off after %d lines\n", maxDiffOutput)
+				return true
+			}
+			out.WriteByte(op.kind)
+			out.WriteString(op.line + "\n")
+			written++
+		}
+		k = end
+	}
+	return changed
+}
+
+// hunkRange formats a unified diff line range starting after line before
+func hunkRange(before, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", before)
+	}
+	return fmt.Sprintf("%d,%d", before+1, count)
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckSpec_Match(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	spec := "openapi: 3.0.3\npaths: {}\n"
+	if err := os.WriteFile(path, []byte(spec), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := checkSpec(spec, path)
+	if err != nil {
+		t.Fatalf("checkSpec() error = %v", err)
+	}
+	if diff != "" {
+		t.Errorf("checkSpec() should report no diff for an identical spec, got:\n%s", diff)
+	}
+}
+
+func TestCheckSpec_Mismatch(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+	committed := "openapi: 3.0.3\ninfo:\n    version: 1.0.0\npaths: {}\n"
+	generated := "openapi: 3.0.3\ninfo:\n    version: 1.1.0\npaths: {}\n"
+	if err := os.WriteFile(path, []byte(committed), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	diff, err := checkSpec(generated, path)
+	if err != nil {
+		t.Fatalf("checkSpec() error = %v", err)
+	}
+	if diff == "" {
+		t.Fatal("checkSpec() should report a diff for a stale spec")
+	}
+
+	for _, want := range []string{"-    version: 1.0.0\n", "+    version: 1.1.0\n", " openapi: 3.0.3\n"} {
+		if !strings.Contains(diff, want) {
+			t.Errorf("Diff should contain %q, got:\n%s", want, diff)
+		}
+	}
+}
+
+func TestDiffLines_Hunks(t *testing.T) {
+	var committed, generated []string
+	for i := 1; i <= 40; i++ {
+		line := fmt.Sprintf("line %d", i)
+		committed = append(committed, line)
+		switch i {
+		case 5:
+			generated = append(generated, "line five")
+		case 30:
+			// Removed
+		default:
+			generated = append(generated, line)
+		}
+	}
+
+	diff := diffLines("openapi.yaml", strings.Join(committed, "\n")+"\n", strings.Join(generated, "\n")+"\n")
+
+	expected := `--- openapi.yaml (committed)
++++ openapi.yaml (generated)
+@@ -2,7 +2,7 @@
+ line 2
+ line 3
+ line 4
+-line 5
++line five
+ line 6
+ line 7
+ line 8
+@@ -27,7 +27,6 @@
+ line 27
+ line 28
+ line 29
+-line 30
+ line 31
+ line 32
+ line 33
+`
+	if diff != expected {
+		t.Errorf("diffLines() =\n%s\nexpected:\n%s", diff, expected)
+	}
+}
+
+func TestDiffLines_LargeDiffIsCut(t *testing.T) {
+	var committed, generated strings.Builder
+	for i := 0; i < 5000; i++ {
+		fmt.Fprintf(&committed, "old %d\n", i)
+		fmt.Fprintf(&generated, "new %d\n", i)
+	}
+
+	diff := diffLines("openapi.yaml", committed.String(), generated.String())
+
+	if !strings.Contains(diff, "@@ -1,5000 +1,5000 @@\n-old 0\n") {
+		t.Errorf("Expected one hunk replacing every line, got:\n%.200s", diff)
+	}
+	if !strings.HasSuffix(diff, fmt.Sprintf("... diff cut off after %d lines\n", maxDiffOutput)) {
+		t.Errorf("Expected the diff to be cut off, got:\n%s", diff[len(diff)-200:])
+	}
+}
+
+func TestCheckSpec_MissingFile(t *testing.T) {
+	_, err := checkSpec("openapi: 3.0.3\n", filepath.Join(t.TempDir(), "missing.yaml"))
+	if err == nil {
+		t.Error("checkSpec() should fail when the committed spec does not exist")
+	}
+}
@@ -1,22 +1,68 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
 
 	"github.com/JerkyTreats/llm/cmd/generate-openapi/analyzer"
+	"github.com/JerkyTreats/llm/cmd/generate-openapi/watch"
+	"github.com/JerkyTreats/llm/internal/api/types"
 	
 	// Import packages to trigger init() functions that register routes
-	_ "github.com/JerkyTreats/llm/internal/api/handler"
+	"github.com/JerkyTreats/llm/internal/api/handler"
 	_ "github.com/JerkyTreats/llm/internal/docs"
+	"github.com/JerkyTreats/llm/internal/jobs"
 )
 
+// stdoutOutput is the -output value that writes the spec to stdout
+const stdoutOutput = "-"
+
 func main() {
+	// "lint" checks an existing spec file instead of generating one
+	if len(os.Args) > 1 && os.Args[1] == "lint" {
+		os.Exit(runLint(os.Args[2:]))
+	}
+
 	var (
-		outputFile = flag.String("output", "docs/api/openapi.yaml", "Output file for OpenAPI specification")
-		verbose    = flag.Bool("verbose", false, "Enable verbose logging")
+		outputFile       = flag.String("output", "docs/api/openapi.yaml", "Output file for OpenAPI specification (- writes to stdout)")
+		verbose          = flag.Bool("verbose", false, "Enable verbose logging")
+		strict           = flag.Bool("strict", false, "Exit with a non-zero status when lint warnings are found")
+		keepUnused       = flag.Bool("keep-unused", false, "Keep component schemas that no path references")
+		watchMode        = flag.Bool("watch", false, "Watch Go sources and regenerate the spec on change")
+		execCmd          = flag.String("exec", "", "Rebuild command to run in watch mode (default: go run ./cmd/generate-openapi -output <output> -version <version>)")
+		reloadURL        = flag.String("reload-url", "", "URL to POST to after each successful regeneration in watch mode")
+		debounce         = flag.Duration("debounce", 500*time.Millisecond, "Delay after the last change before regenerating in watch mode")
+		dryRun           = flag.Bool("dry-run", false, "Validate generation without writing the output file")
+		warnUndocumented = flag.Bool("warn-undocumented", false, "Warn about routes served by the mux but missing from the spec, and vice versa")
+		hoistParams      = flag.Bool("hoist-params", false, "Declare path parameters shared by every operation once on the path item")
+		normalizePaths   = flag.Bool("normalize-paths", false, "Strip trailing slashes so /users/ and /users share a path item")
+		defaultResponse  = flag.Bool("default-response", false, "Add a default response with the error body to every operation")
+		examplesDir      = flag.String("examples-dir", "", "Directory of <SchemaName>.json fixtures attached as component schema examples, e.g. testdata/examples")
+		codeSamples      = flag.Bool("code-samples", false, "Add x-codeSamples (curl, Go, Python) to every operation")
+		samplesInDesc    = flag.Bool("code-samples-in-description", false, "Also append code samples to operation descriptions for Swagger UI")
+		exclude          = flag.String("exclude", "", "Comma-separated modules and path globs (starting with /) to leave out of the spec")
+		publicOutput     = flag.String("public-output", "", "Also write a public spec without internal routes to this file")
+		check            = flag.Bool("check", false, "Compare the generated spec with the output file and exit non-zero if they differ")
+		version          = flag.String("version", "", "API version written to info.version (default: openapi.version from config, the build version, or detected from VERSION, go.mod or the latest git tag)")
+		versionedOutput  = flag.Bool("versioned-output", false, "Name the output file openapi-v{major}.yaml after -version, in the -output directory")
+		requireSchema    = flag.Bool("require-response-schema", false, "Fail when a JSON route other than a 204 has no response type")
+		allowEmpty       = flag.String("allow-empty-response", "", "Comma-separated routes (\"METHOD /path\" or /path) whose empty response is intentional under -require-response-schema")
+		schemaNaming     = flag.String("schema-naming", "bare", "Component schema naming: bare (ChatRequest) or package (ProviderChatRequest)")
+		noHeader         = flag.Bool("no-header", false, "Leave out the DO NOT EDIT comment block at the top of the spec")
+		operationIDStyle = flag.String("operation-id-style", "camel", "Operation ID casing: camel (getv1Models), snake (get_v1_models) or kebab (get-v1-models)")
+		dereference      = flag.Bool("dereference", false, "Inline every schema $ref and drop components.schemas, keeping only schemas referenced by cycles")
+		tsOutput         = flag.String("typescript-output", "", "Also write TypeScript definitions of the component schemas to this file, e.g. web/src/api.d.ts")
 	)
 	flag.Parse()
 
@@ -24,11 +70,79 @@ func main() {
 		log.SetFlags(log.LstdFlags | log.Lshortfile)
 	}
 
+	if *version == "" {
+		*version = analyzer.DetectVersion()
+	}
+
+	if *versionedOutput {
+		if *outputFile == stdoutOutput {
+			log.Fatalf("-output %s cannot be combined with -versioned-output", stdoutOutput)
+		}
+		path, err := versionedOutputPath(*outputFile, *version)
+		if err != nil {
+			log.Fatalf("Invalid -version: %v", err)
+		}
+		*outputFile = path
+	}
+
+	// With -output - stdout carries only the spec; everything else goes to stderr
+	report := io.Writer(os.Stdout)
+	if *outputFile == stdoutOutput {
+		report = os.Stderr
+		if *watchMode || *check {
+			log.Fatalf("-output %s cannot be combined with -watch or -check", stdoutOutput)
+		}
+	}
+
+	if *watchMode {
+		runWatch(*outputFile, *version, *execCmd, *reloadURL, *debounce)
+		return
+	}
+
 	log.Printf("Starting OpenAPI specification generation...")
 	log.Printf("Output file: %s", *outputFile)
 
+	registerJobResults()
+
+	idStyle, err := analyzer.ParseOperationIDStyle(*operationIDStyle)
+	if err != nil {
+		log.Fatalf("Invalid -operation-id-style: %v", err)
+	}
+
+	var namingStrategy analyzer.NamingStrategy
+	switch *schemaNaming {
+	case "bare":
+		namingStrategy = analyzer.BareName
+	case "package":
+		namingStrategy = analyzer.PackageQualified
+	default:
+		log.Fatalf("Unknown -schema-naming %q, expected bare or package", *schemaNaming)
+	}
+
+	// The full and public specs are built from the same options
+	opts := []analyzer.GeneratorOption{
+		analyzer.WithVersion(*version),
+		analyzer.WithOperationIDStyle(idStyle),
+		analyzer.WithIncludeHeader(!*noHeader),
+		analyzer.WithKeepUnusedSchemas(*keepUnused),
+		analyzer.WithHoistSharedParams(*hoistParams),
+		analyzer.WithNormalizePaths(*normalizePaths),
+		analyzer.WithDefaultResponse(*defaultResponse),
+		analyzer.WithExamplesDir(*examplesDir),
+		analyzer.WithCodeSamples(*codeSamples, *samplesInDesc),
+		analyzer.WithDereference(*dereference),
+		analyzer.WithNamingStrategy(namingStrategy),
+		analyzer.WithExclude(splitList(*exclude)...),
+		analyzer.WithRequireResponseSchema(*requireSchema, splitList(*allowEmpty)...),
+	}
+
 	// Create analyzer
-	gen := analyzer.NewGenerator()
+	gen := analyzer.NewGenerator(opts...)
+	gen.DryRun = *dryRun
+	if *warnUndocumented {
+		gen.WarnUndocumented = true
+		gen.MuxRoutes = mountedRoutes()
+	}
 
 	// Generate the OpenAPI specification
 	spec, err := gen.GenerateSpec()
@@ -36,11 +150,180 @@ func main() {
 		log.Fatalf("Failed to generate OpenAPI spec: %v", err)
 	}
 
-	// Write to output file
-	if err := os.WriteFile(*outputFile, []byte(spec), 0644); err != nil {
-		log.Fatalf("Failed to write spec to file: %v", err)
+	for _, name := range gen.PrunedSchemas() {
+		log.Printf("Pruned unused schema: %s", name)
+	}
+
+	// Report lint warnings grouped by module. Strict mode fails before anything is
+	// written, leaving the committed spec untouched.
+	warnings := gen.Warnings()
+	printWarnings(report, warnings)
+	if *strict && len(warnings) > 0 {
+		log.Fatalf("Strict mode: %d lint warnings found", len(warnings))
+	}
+
+	if *check {
+		diff, err := checkSpec(spec, *outputFile)
+		if err != nil {
+			log.Fatalf("Failed to check spec: %v", err)
+		}
+		if diff != "" {
+			fmt.Fprint(report, diff)
+			log.Fatalf("%s is out of date; run go run ./cmd/generate-openapi to regenerate it", *outputFile)
+		}
+		log.Printf("%s is up to date", *outputFile)
+		return
+	}
+
+	if *dryRun {
+		log.Printf("Dry run: specification is valid, %s was not written", *outputFile)
+	} else {
+		if err := writeSpec(spec, *outputFile, os.Stdout); err != nil {
+			log.Fatalf("Failed to write spec: %v", err)
+		}
+
+		log.Printf("OpenAPI specification generated successfully at %s", *outputFile)
+
+		if *publicOutput != "" {
+			public := analyzer.NewGenerator(opts...)
+			publicSpec, err := public.GenerateSpecFiltered(types.VisibilityPublic)
+			if err != nil {
+				log.Fatalf("Failed to generate public OpenAPI spec: %v", err)
+			}
+			if err := writeSpec(publicSpec, *publicOutput, os.Stdout); err != nil {
+				log.Fatalf("Failed to write public spec: %v", err)
+			}
+			log.Printf("Public OpenAPI specification generated successfully at %s", *publicOutput)
+		}
+
+		if *tsOutput != "" {
+			if err := os.WriteFile(*tsOutput, []byte(gen.TypeScriptDefinitions()), 0644); err != nil {
+				log.Fatalf("Failed to write TypeScript definitions: %v", err)
+			}
+			log.Printf("TypeScript definitions generated successfully at %s", *tsOutput)
+		}
+	}
+	fmt.Fprintf(report, "Generated OpenAPI spec with %d routes\n", len(gen.GetDiscoveredRoutes()))
+}
+
+// splitList returns the non-empty entries of a comma-separated flag value
+func splitList(value string) []string {
+	var entries []string
+	for _, entry := range strings.Split(value, ",") {
+		if entry = strings.TrimSpace(entry); entry != "" {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// writeSpec writes spec to output, or to stdout when output is "-"
+func writeSpec(spec, output string, stdout io.Writer) error {
+	if output == stdoutOutput {
+		_, err := io.WriteString(stdout, spec)
+		return err
+	}
+	return os.WriteFile(output, []byte(spec), 0644)
+}
+
+// versionedOutputPath returns openapi-v{major}.yaml in output's directory, where
+// major is the major component of a semantic version such as "2.1.0" or "v2"
+func versionedOutputPath(output, version string) (string, error) {
+	major, _, _ := strings.Cut(strings.TrimPrefix(version, "v"), ".")
+	if _, err := strconv.ParseUint(major, 10, 64); err != nil {
+		return "", fmt.Errorf("%q does not start with a numeric major version", version)
+	}
+	return filepath.Join(filepath.Dir(output), "openapi-v"+major+".yaml"), nil
+}
+
+// registerJobResults documents JobResult.Result as a union of the registered job result types
+func registerJobResults() {
+	if results := jobs.ResultTypes(); len(results) > 0 {
+		analyzer.RegisterUnion(reflect.TypeOf((*jobs.Output)(nil)), results...)
+	}
+}
+
+// mountedRoutes builds the application mux and returns the routes it serves
+func mountedRoutes() []analyzer.MuxRoute {
+	registry, err := handler.NewHandlerRegistry()
+	if err != nil {
+		log.Fatalf("Failed to build handler registry: %v", err)
+	}
+
+	var routes []analyzer.MuxRoute
+	for _, route := range registry.GetMountedRoutes() {
+		routes = append(routes, analyzer.MuxRoute{Method: route.Method, Path: route.Path})
+	}
+	return routes
+}
+
+// runLint lints a spec file, printing each issue, and returns the process exit code
+func runLint(args []string) int {
+	flags := flag.NewFlagSet("lint", flag.ExitOnError)
+	specFile := flags.String("spec", "docs/api/openapi.yaml", "Spec file to lint")
+	flags.Parse(args)
+
+	content, err := os.ReadFile(*specFile)
+	if err != nil {
+		log.Printf("Failed to read spec: %v", err)
+		return 1
+	}
+
+	linter, err := analyzer.NewLinter(content)
+	if err != nil {
+		log.Printf("Failed to lint %s: %v", *specFile, err)
+		return 1
+	}
+
+	issues := linter.Lint()
+	for _, issue := range issues {
+		fmt.Println(issue)
+	}
+	fmt.Printf("%d issues found in %s\n", len(issues), *specFile)
+
+	if analyzer.HasErrors(issues) {
+		return 1
+	}
+	return 0
+}
+
+// runWatch regenerates the spec in a subprocess whenever Go sources change
+func runWatch(outputFile, version, execCmd, reloadURL string, debounce time.Duration) {
+	command := []string{"go", "run", "./cmd/generate-openapi", "-output", outputFile, "-version", version}
+	if execCmd != "" {
+		command = strings.Fields(execCmd)
+	}
+
+	watcher, err := watch.NewWatcher(".", command, debounce, reloadURL)
+	if err != nil {
+		log.Fatalf("Failed to create watcher: %v", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := watcher.Run(ctx); err != nil {
+		log.Fatalf("Watch mode failed: %v", err)
+	}
+}
+
+// printWarnings prints lint warnings grouped by module
+func printWarnings(w io.Writer, warnings []analyzer.Warning) {
+	if len(warnings) == 0 {
+		return
 	}
 
-	log.Printf("OpenAPI specification generated successfully at %s", *outputFile)
-	fmt.Printf("Generated OpenAPI spec with %d routes\n", len(gen.GetDiscoveredRoutes()))
-}
\ No newline at end of file
+	fmt.Fprintf(w, "\n%d lint warnings:\n", len(warnings))
+	modules, grouped := analyzer.WarningsByModule(warnings)
+	for _, module := range modules {
+		name := module
+		if name == "" {
+			// Routes served by the mux but absent from the registry have no module
+			name = "(no module)"
+		}
+		fmt.Fprintf(w, "  %s:\n", name)
+		for _, warning := range grouped[module] {
+			fmt.Fprintf(w, "    %s\n", warning)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/JerkyTreats/llm/cmd/generate-openapi/analyzer"
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"gopkg.in/yaml.v3"
+)
+
+type pingResponse struct {
+	Status string `json:"status"`
+}
+
+func TestWriteSpec_Stdout(t *testing.T) {
+	gen := analyzer.NewGenerator()
+	spec, err := gen.GenerateSpecForRoutes([]types.RouteInfo{
+		{Method: "GET", Path: "/ping", ResponseType: reflect.TypeOf(pingResponse{}), Module: "ping"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	t.Chdir(t.TempDir())
+
+	var stdout bytes.Buffer
+	if err := writeSpec(spec, stdoutOutput, &stdout); err != nil {
+		t.Fatalf("writeSpec() error = %v", err)
+	}
+
+	if stdout.String() != spec {
+		t.Errorf("Stdout should contain exactly the spec, got:\n%s", stdout.String())
+	}
+	if _, err := os.Stat(stdoutOutput); !os.IsNotExist(err) {
+		t.Error("Writing to stdout should not create a file named -")
+	}
+}
+
+func TestWriteSpec_File(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "openapi.yaml")
+
+	var stdout bytes.Buffer
+	if err := writeSpec("openapi: 3.0.3\n", path, &stdout); err != nil {
+		t.Fatalf("writeSpec() error = %v", err)
+	}
+
+	written, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Spec file was not written: %v", err)
+	}
+	if string(written) != "openapi: 3.0.3\n" {
+		t.Errorf("Unexpected file contents %q", written)
+	}
+	if stdout.Len() != 0 {
+		t.Errorf("Writing to a file should not print the spec, got %q", stdout.String())
+	}
+}
+
+func TestVersionedOutputPath(t *testing.T) {
+	tests := []struct {
+		output   string
+		version  string
+		expected string
+	}{
+		{"docs/api/openapi.yaml", "2.1.0", filepath.Join("docs", "api", "openapi-v2.yaml")},
+		{"docs/api/openapi.yaml", "1.0.0", filepath.Join("docs", "api", "openapi-v1.yaml")},
+		{"openapi.yaml", "v3", "openapi-v3.yaml"},
+	}
+
+	for _, tt := range tests {
+		got, err := versionedOutputPath(tt.output, tt.version)
+		if err != nil {
+			t.Errorf("versionedOutputPath(%q, %q) error = %v", tt.output, tt.version, err)
+			continue
+		}
+		if got != tt.expected {
+			t.Errorf("versionedOutputPath(%q, %q) = %q, want %q", tt.output, tt.version, got, tt.expected)
+		}
+	}
+
+	if _, err := versionedOutputPath("openapi.yaml", "beta"); err == nil {
+		t.Error("Expected an error for a version without a numeric major component")
+	}
+}
+
+func TestSplitList(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected []string
+	}{
+		{"", nil},
+		{"admin", []string{"admin"}},
+		{" admin, /internal/* ,,", []string{"admin", "/internal/*"}},
+	}
+
+	for _, tt := range tests {
+		if got := splitList(tt.value); !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("splitList(%q) = %q, want %q", tt.value, got, tt.expected)
+		}
+	}
+}
+
+func TestVersionedOutput_WritesMajorVersionFile(t *testing.T) {
+	dir := t.TempDir()
+
+	gen := analyzer.NewGenerator()
+	gen.Version = "2.1.0"
+	spec, err := gen.GenerateSpecForRoutes([]types.RouteInfo{
+		{Method: "GET", Path: "/ping", ResponseType: reflect.TypeOf(pingResponse{}), Module: "ping"},
+	})
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	output, err := versionedOutputPath(filepath.Join(dir, "openapi.yaml"), gen.Version)
+	if err != nil {
+		t.Fatalf("versionedOutputPath() error = %v", err)
+	}
+	if err := writeSpec(spec, output, io.Discard); err != nil {
+		t.Fatalf("writeSpec() error = %v", err)
+	}
+
+	written, err := os.ReadFile(filepath.Join(dir, "openapi-v2.yaml"))
+	if err != nil {
+		t.Fatalf("openapi-v2.yaml was not written: %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "openapi.yaml")); !os.IsNotExist(err) {
+		t.Error("Versioned output should not write openapi.yaml")
+	}
+
+	var parsed struct {
+		Info struct {
+			Version string `yaml:"version"`
+		} `yaml:"info"`
+	}
+	if err := yaml.Unmarshal(written, &parsed); err != nil {
+		t.Fatalf("Written spec is not valid YAML: %v", err)
+	}
+	if parsed.Info.Version != "2.1.0" {
+		t.Errorf("info.version = %q, want 2.1.0", parsed.Info.Version)
+	}
+}
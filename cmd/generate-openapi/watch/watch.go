@@ -0,0 +1,203 @@
+// Package watch re-runs OpenAPI generation whenever Go sources change during development.
+package watch
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// Watcher re-runs a rebuild command whenever Go source files under a root directory change
+type Watcher struct {
+	root      string
+	command   []string
+	debounce  time.Duration
+	reloadURL string
+	run       func(ctx context.Context, command []string) error
+	client    *http.Client
+}
+
+// NewWatcher creates a watcher for root that runs command after changes settle for debounce.
+// When reloadURL is set, a POST is sent to it after each successful rebuild.
+func NewWatcher(root string, command []string, debounce time.Duration, reloadURL string) (*Watcher, error) {
+	if len(command) == 0 {
+		return nil, fmt.Errorf("rebuild command must not be empty")
+	}
+
+	return &Watcher{
+		root:      root,
+		command:   command,
+		debounce:  debounce,
+		reloadURL: reloadURL,
+		run:       runCommand,
+		client:    &http.Client{Timeout: 5 * time.Second},
+	}, nil
+}
+
+// Run watches the source tree until ctx is cancelled
+func (w *Watcher) Run(ctx context.Context) error {
+	fsWatcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer fsWatcher.Close()
+
+	if err := w.addTree(fsWatcher, w.root); err != nil {
+		return err
+	}
+
+	changes := make(chan string)
+	go func() {
+		defer close(changes)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-fsWatcher.Events:
+				if !ok {
+					return
+				}
+				w.handleEvent(ctx, fsWatcher, event, changes)
+			case err, ok := <-fsWatcher.Errors:
+				if !ok {
+					return
+				}
+				log.Printf("Watch error: %v", err)
+			}
+		}
+	}()
+
+	log.Printf("Watching %s for Go source changes (command: %s)", w.root, strings.Join(w.command, " "))
+	w.loop(ctx, changes)
+	return nil
+}
+
+// handleEvent forwards Go source changes and starts watching newly created
+// directories. The change is dropped once ctx is cancelled and loop stops reading.
+func (w *Watcher) handleEvent(ctx context.Context, fsWatcher *fsnotify.Watcher, event fsnotify.Event, changes chan<- string) {
+	if event.Has(fsnotify.Create) {
+		if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+			if err := w.addTree(fsWatcher, event.Name); err != nil {
+				log.Printf("Warning: %v", err)
+			}
+			return
+		}
+	}
+
+	// Ignore non-Go files and pure attribute changes
+	if !isGoSource(event.Name) || event.Op == fsnotify.Chmod {
+		return
+	}
+
+	select {
+	case changes <- event.Name:
+	case <-ctx.Done():
+	}
+}
+
+// loop debounces change notifications and rebuilds once changes settle
+func (w *Watcher) loop(ctx context.Context, changes <-chan string) {
+	timer := time.NewTimer(w.debounce)
+	timer.Stop()
+	defer timer.Stop()
+
+	pending := false
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case name, ok := <-changes:
+			if !ok {
+				return
+			}
+			log.Printf("Detected change in %s", name)
+			pending = true
+			timer.Reset(w.debounce)
+		case <-timer.C:
+			if pending {
+				pending = false
+				w.rebuild(ctx)
+			}
+		}
+	}
+}
+
+// rebuild runs the rebuild command, reports its outcome and timing, and pings the reload URL
+func (w *Watcher) rebuild(ctx context.Context) error {
+	start := time.Now()
+
+	if err := w.run(ctx, w.command); err != nil {
+		log.Printf("Regeneration failed after %s: %v", time.Since(start).Round(time.Millisecond), err)
+		return err
+	}
+
+	log.Printf("Regeneration succeeded in %s", time.Since(start).Round(time.Millisecond))
+
+	if w.reloadURL != "" {
+		if err := w.pingReload(ctx); err != nil {
+			log.Printf("Warning: failed to send reload ping: %v", err)
+		}
+	}
+
+	return nil
+}
+
+// pingReload POSTs to the reload URL so an open Swagger UI can refresh
+func (w *Watcher) pingReload(ctx context.Context) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.reloadURL, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("reload endpoint returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// addTree adds dir and all of its non-hidden subdirectories to the watcher
+func (w *Watcher) addTree(fsWatcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d os.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		name := d.Name()
+		if path != dir && (strings.HasPrefix(name, ".") || name == "vendor") {
+			return filepath.SkipDir
+		}
+		if err := fsWatcher.Add(path); err != nil {
+			return fmt.Errorf("failed to watch %s: %w", path, err)
+		}
+		return nil
+	})
+}
+
+// isGoSource reports whether name is a Go source file
+func isGoSource(name string) bool {
+	return strings.HasSuffix(name, ".go")
+}
+
+// runCommand runs command as a subprocess, streaming its output
+func runCommand(ctx context.Context, command []string) error {
+	cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
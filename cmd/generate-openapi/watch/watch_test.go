@@ -0,0 +1,155 @@
+package watch
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// stubRunner records rebuild invocations instead of spawning a subprocess
+type stubRunner struct {
+	mu    sync.Mutex
+	calls int
+	err   error
+}
+
+func (s *stubRunner) run(ctx context.Context, command []string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.calls++
+	return s.err
+}
+
+func (s *stubRunner) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls
+}
+
+func newTestWatcher(t *testing.T, debounce time.Duration, reloadURL string) (*Watcher, *stubRunner) {
+	t.Helper()
+
+	w, err := NewWatcher(t.TempDir(), []string{"stub"}, debounce, reloadURL)
+	if err != nil {
+		t.Fatalf("NewWatcher() error = %v", err)
+	}
+	stub := &stubRunner{}
+	w.run = stub.run
+	return w, stub
+}
+
+func TestNewWatcher_EmptyCommand(t *testing.T) {
+	if _, err := NewWatcher(".", nil, time.Second, ""); err == nil {
+		t.Error("NewWatcher() should reject an empty command")
+	}
+}
+
+func TestLoop_DebouncesBurstOfChanges(t *testing.T) {
+	w, stub := newTestWatcher(t, 50*time.Millisecond, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	changes := make(chan string)
+	done := make(chan struct{})
+	go func() {
+		w.loop(ctx, changes)
+		close(done)
+	}()
+
+	for i := 0; i < 5; i++ {
+		changes <- "handler.go"
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	if calls := stub.count(); calls != 1 {
+		t.Errorf("Expected 1 rebuild for a burst of changes, got %d", calls)
+	}
+
+	changes <- "handler.go"
+	time.Sleep(200 * time.Millisecond)
+	if calls := stub.count(); calls != 2 {
+		t.Errorf("Expected 2 rebuilds after a second change, got %d", calls)
+	}
+
+	close(changes)
+	<-done
+}
+
+func TestHandleEvent_ReturnsAfterCancel(t *testing.T) {
+	w, _ := newTestWatcher(t, time.Second, "")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// Nothing reads changes, as when loop has already stopped
+	done := make(chan struct{})
+	go func() {
+		w.handleEvent(ctx, nil, fsnotify.Event{Name: "handler.go", Op: fsnotify.Write}, make(chan string))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("handleEvent() blocked sending a change after cancellation")
+	}
+}
+
+func TestRebuild_PingsReloadOnSuccess(t *testing.T) {
+	var pings int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			pings++
+		}
+	}))
+	defer server.Close()
+
+	w, stub := newTestWatcher(t, time.Millisecond, server.URL)
+
+	if err := w.rebuild(context.Background()); err != nil {
+		t.Fatalf("rebuild() error = %v", err)
+	}
+	if pings != 1 {
+		t.Errorf("Expected 1 reload ping after success, got %d", pings)
+	}
+
+	stub.err = context.DeadlineExceeded
+	if err := w.rebuild(context.Background()); err == nil {
+		t.Error("rebuild() should return the command error")
+	}
+	if pings != 1 {
+		t.Errorf("Expected no reload ping after failure, got %d total", pings)
+	}
+}
+
+func TestRunCommand(t *testing.T) {
+	if err := runCommand(context.Background(), []string{"sh", "-c", "exit 0"}); err != nil {
+		t.Errorf("runCommand() should succeed for a zero exit status, got %v", err)
+	}
+
+	if err := runCommand(context.Background(), []string{"sh", "-c", "exit 3"}); err == nil {
+		t.Error("runCommand() should fail for a non-zero exit status")
+	}
+}
+
+func TestIsGoSource(t *testing.T) {
+	tests := map[string]bool{
+		"handler.go":       true,
+		"handler_test.go":  true,
+		"openapi.yaml":     false,
+		"go.mod":           false,
+		"internal/api/x.g": false,
+	}
+
+	for name, expected := range tests {
+		if got := isGoSource(name); got != expected {
+			t.Errorf("isGoSource(%q) = %v, expected %v", name, got, expected)
+		}
+	}
+}
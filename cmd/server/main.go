@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"net/http"
 	"os"
@@ -12,11 +13,48 @@ import (
 	"github.com/JerkyTreats/llm/internal/api/handler"
 	"github.com/JerkyTreats/llm/internal/config"
 	"github.com/JerkyTreats/llm/internal/logging"
+	"github.com/JerkyTreats/llm/internal/tracing"
+	"github.com/JerkyTreats/llm/internal/version"
 )
 
 func main() {
+	configFile := flag.String("config", "", "Config file to load instead of $HOME/.llm/config.{json,yaml,toml}")
+	configFormat := flag.String("config-format", "", "Config file format: json, yaml or toml (default: from the file extension)")
+	flag.Parse()
+
 	logging.Info("Starting LLM API server")
 
+	// Load the config file the flags name and reject it before anything reads it
+	if *configFile != "" {
+		config.SetConfigPath(*configFile)
+	}
+	if err := config.SetConfigFormat(*configFormat); err != nil {
+		logging.Error("Invalid -config-format: %v", err)
+		os.Exit(1)
+	}
+	if *configFile != "" || *configFormat != "" {
+		if err := config.Reload(); err != nil {
+			logging.Error("Failed to load config: %v", err)
+			os.Exit(1)
+		}
+	}
+	config.RegisterDefault("server_port", "8080")
+	warnings, err := config.Validate()
+	for _, warning := range warnings {
+		logging.Warn("%s", warning)
+	}
+	if err != nil {
+		logging.Error("%v", err)
+		os.Exit(1)
+	}
+
+	// Tracing must be configured before handlers are wrapped
+	shutdownTracing, err := tracing.Init(context.Background())
+	if err != nil {
+		logging.Error("Failed to initialize tracing: %v", err)
+		os.Exit(1)
+	}
+
 	// Initialize handler registry
 	handlerRegistry, err := handler.NewHandlerRegistry()
 	if err != nil {
@@ -39,9 +77,19 @@ func main() {
 		IdleTimeout:  120 * time.Second,
 	}
 
+	// Log which build is starting with what configuration
+	build := version.Get()
+	logging.WithFields(
+		"version", build.Version,
+		"commit", build.Commit,
+		"build_date", build.BuildDate,
+		"config_file", config.ConfigFileUsed(),
+		"listen_addr", server.Addr,
+		"routes", len(handlerRegistry.GetMountedRoutes()),
+	).Info("LLM API server starting")
+
 	// Start server in a goroutine
 	go func() {
-		logging.Info("LLM API server starting on port %s", port)
 		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			logging.Error("Server failed to start: %v", err)
 			os.Exit(1)
@@ -65,5 +113,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Stop background work such as the docs rate limiter once requests have drained
+	handlerRegistry.Close()
+
+	if err := shutdownTracing(ctx); err != nil {
+		logging.Warn("Failed to flush traces: %v", err)
+	}
+
 	logging.Info("LLM API server stopped")
 }
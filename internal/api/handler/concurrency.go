@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
+	"github.com/JerkyTreats/llm/internal/logging"
+)
+
+// defaultQueueTimeout bounds the wait for a slot when limits.<group>.queue_timeout is unset
+const defaultQueueTimeout = 30 * time.Second
+
+// Errors returned by ConcurrencyLimiter.Acquire when a request cannot get a slot
+var (
+	ErrQueueFull    = errors.New("concurrency limit reached and queue is full")
+	ErrQueueTimeout = errors.New("timed out waiting for a concurrency slot")
+)
+
+// ConcurrencyStats reports a limiter's current load
+type ConcurrencyStats struct {
+	InFlight      int `json:"in_flight" description:"Requests currently being served"`
+	Queued        int `json:"queued" description:"Requests waiting for a slot"`
+	MaxConcurrent int `json:"max_concurrent" description:"Requests served at once before queueing"`
+	QueueSize     int `json:"queue_size" description:"Requests allowed to wait before rejecting with 503"`
+	Rejected      int `json:"rejected" description:"Requests rejected with 503 since the server started"`
+}
+
+// ConcurrencyLimiter caps the requests a route group serves at once. Requests over
+// the cap wait in a bounded queue for up to the queue timeout.
+type ConcurrencyLimiter struct {
+	slots        chan struct{}
+	queue        chan struct{}
+	queueTimeout time.Duration
+
+	inFlight atomic.Int64
+	queued   atomic.Int64
+	rejected atomic.Int64
+}
+
+func init() {
+	prefix := "limits." + types.ConcurrencyGroupCompletions + "."
+	config.RegisterKey(prefix+"max_concurrent", 0)
+	config.RegisterKey(prefix+"queue_size", 0)
+	config.RegisterKey(prefix+"queue_timeout", "")
+}
+
+// NewConcurrencyLimiter creates a limiter serving maxConcurrent requests at once with
+// up to queueSize more waiting at most queueTimeout for a slot
+func NewConcurrencyLimiter(maxConcurrent, queueSize int, queueTimeout time.Duration) *ConcurrencyLimiter {
+	return &ConcurrencyLimiter{
+		slots:        make(chan struct{}, maxConcurrent),
+		queue:        make(chan struct{}, max(queueSize, 0)),
+		queueTimeout: queueTimeout,
+	}
+}
+
+// newConcurrencyLimiterFromConfig reads limits.<group>.max_concurrent, queue_size and
+// queue_timeout, returning nil when the group has no max_concurrent
+func newConcurrencyLimiterFromConfig(group string) *ConcurrencyLimiter {
+	prefix := "limits." + group + "."
+	maxConcurrent := config.GetInt(prefix + "max_concurrent")
+	if maxConcurrent <= 0 {
+		return nil
+	}
+
+	queueTimeout := defaultQueueTimeout
+	if raw := config.GetString(prefix + "queue_timeout"); raw != "" {
+		timeout, err := time.ParseDuration(raw)
+		if err != nil || timeout <= 0 {
+			logging.Warn("Invalid %squeue_timeout %q, using %s", prefix, raw, defaultQueueTimeout)
+		} else {
+			queueTimeout = timeout
+		}
+	}
+
+	return NewConcurrencyLimiter(maxConcurrent, config.GetInt(prefix+"queue_size"), queueTimeout)
+}
+
+// Acquire takes a slot, queueing when none is free. It returns ErrQueueFull when the
+// queue is full, ErrQueueTimeout when the wait exceeds the queue timeout, and the
+// context's error when the client goes away while queued. The caller must call
+// release exactly once when the request finishes.
+func (l *ConcurrencyLimiter) Acquire(ctx context.Context) (release func(), err error) {
+	select {
+	case l.slots <- struct{}{}:
+		return l.acquired(), nil
+	default:
+	}
+
+	select {
+	case l.queue <- struct{}{}:
+	default:
+		l.rejected.Add(1)
+		return nil, ErrQueueFull
+	}
+	l.queued.Add(1)
+	defer func() {
+		l.queued.Add(-1)
+		<-l.queue
+	}()
+
+	timer := time.NewTimer(l.queueTimeout)
+	defer timer.Stop()
+
+	select {
+	case l.slots <- struct{}{}:
+		return l.acquired(), nil
+	case <-timer.C:
+		l.rejected.Add(1)
+		return nil, ErrQueueTimeout
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// acquired counts a taken slot and returns the function releasing it
+func (l *ConcurrencyLimiter) acquired() func() {
+	l.inFlight.Add(1)
+	var released atomic.Bool
+	return func() {
+		if released.CompareAndSwap(false, true) {
+			l.inFlight.Add(-1)
+			<-l.slots
+		}
+	}
+}
+
+// Stats returns the limiter's current in-flight requests, queue depth and rejections
+func (l *ConcurrencyLimiter) Stats() ConcurrencyStats {
+	return ConcurrencyStats{
+		InFlight:      int(l.inFlight.Load()),
+		Queued:        int(l.queued.Load()),
+		MaxConcurrent: cap(l.slots),
+		QueueSize:     cap(l.queue),
+		Rejected:      int(l.rejected.Load()),
+	}
+}
+
+// retryAfter returns the Retry-After seconds suggested to rejected requests
+func (l *ConcurrencyLimiter) retryAfter() string {
+	return strconv.Itoa(int(math.Max(1, math.Ceil(l.queueTimeout.Seconds()))))
+}
+
+// WithConcurrencyLimit wraps a route's handler so it runs only while holding a slot
+// of limiter. Requests rejected by a full queue or a queue timeout receive 503 with
+// Retry-After; requests whose client disconnects while queued are dropped. The slot
+// is released when the handler returns, which handlers serving r.Context() do as
+// soon as the client disconnects. The handler is unchanged when limiter is nil.
+func WithConcurrencyLimit(route types.RouteInfo, limiter *ConcurrencyLimiter, next http.HandlerFunc) http.HandlerFunc {
+	if limiter == nil {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		release, err := limiter.Acquire(r.Context())
+		if err != nil {
+			if r.Context().Err() != nil {
+				// The client is gone; nobody reads a response
+				return
+			}
+			logging.Warn("Rejecting %s %s from %s group: %v", route.Method, route.Path, route.ConcurrencyGroup, err)
+			w.Header().Set("Retry-After", limiter.retryAfter())
+			types.WriteError(w, http.StatusServiceUnavailable, "server is at capacity, retry later")
+			return
+		}
+		defer release()
+		next(w, r)
+	}
+}
+
+// newConcurrencyLimiters creates the configured limiter of each route group
+func newConcurrencyLimiters(routes []types.RouteInfo) map[string]*ConcurrencyLimiter {
+	limiters := make(map[string]*ConcurrencyLimiter)
+	seen := make(map[string]bool)
+	for _, route := range routes {
+		group := route.ConcurrencyGroup
+		if group == "" || seen[group] {
+			continue
+		}
+		seen[group] = true
+		if limiter := newConcurrencyLimiterFromConfig(group); limiter != nil {
+			limiters[group] = limiter
+		}
+	}
+	return limiters
+}
+
+// concurrencyStats returns the stats of each limiter by group, or nil when none is configured
+func concurrencyStats(limiters map[string]*ConcurrencyLimiter) map[string]ConcurrencyStats {
+	if len(limiters) == 0 {
+		return nil
+	}
+	stats := make(map[string]ConcurrencyStats, len(limiters))
+	for group, limiter := range limiters {
+		stats[group] = limiter.Stats()
+	}
+	return stats
+}
@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
+	"github.com/JerkyTreats/llm/internal/docs"
+	"github.com/JerkyTreats/llm/internal/moderation"
+	"github.com/JerkyTreats/llm/internal/provider"
+)
+
+var completionsRoute = types.RouteInfo{Method: "POST", Path: "/v1/sessions/{id}/messages", ConcurrencyGroup: types.ConcurrencyGroupCompletions}
+
+// blockingHandler serves requests until release is closed or the client goes away
+func blockingHandler(release <-chan struct{}) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+			w.WriteHeader(http.StatusOK)
+		case <-r.Context().Done():
+		}
+	}
+}
+
+// startRequest serves a request in the background, returning its recorder and a
+// channel closed once the handler returns
+func startRequest(ctx context.Context, handler http.HandlerFunc) (*httptest.ResponseRecorder, <-chan struct{}) {
+	w := httptest.NewRecorder()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		handler(w, httptest.NewRequest(http.MethodPost, "/v1/sessions/s/messages", nil).WithContext(ctx))
+	}()
+	return w, done
+}
+
+// waitForStats waits until the limiter reports the given in-flight and queued counts
+func waitForStats(t *testing.T, limiter *ConcurrencyLimiter, inFlight, queued int) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for time.Now().Before(deadline) {
+		stats := limiter.Stats()
+		if stats.InFlight == inFlight && stats.Queued == queued {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("Stats = %+v, expected %d in flight and %d queued", limiter.Stats(), inFlight, queued)
+}
+
+// assertUnavailable checks a 503 ErrorResponse with Retry-After
+func assertUnavailable(t *testing.T, w *httptest.ResponseRecorder, retryAfter string) {
+	t.Helper()
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status 503, got %d", w.Code)
+	}
+	if got := w.Header().Get("Retry-After"); got != retryAfter {
+		t.Errorf("Retry-After = %q, expected %q", got, retryAfter)
+	}
+	var body types.ErrorResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || !body.Error || body.Status != http.StatusServiceUnavailable {
+		t.Errorf("Expected a 503 ErrorResponse body, got %q", w.Body.String())
+	}
+}
+
+func TestWithConcurrencyLimit_QueuesThenRejects(t *testing.T) {
+	limiter := NewConcurrencyLimiter(1, 1, 5*time.Second)
+	release := make(chan struct{})
+	handler := WithConcurrencyLimit(completionsRoute, limiter, blockingHandler(release))
+
+	first, firstDone := startRequest(context.Background(), handler)
+	waitForStats(t, limiter, 1, 0)
+	queued, queuedDone := startRequest(context.Background(), handler)
+	waitForStats(t, limiter, 1, 1)
+
+	// The slot and the queue are both taken
+	rejected := httptest.NewRecorder()
+	handler(rejected, httptest.NewRequest(http.MethodPost, "/v1/sessions/s/messages", nil))
+	assertUnavailable(t, rejected, "5")
+
+	close(release)
+	<-firstDone
+	<-queuedDone
+	if first.Code != http.StatusOK || queued.Code != http.StatusOK {
+		t.Errorf("Expected the running and queued requests to succeed, got %d and %d", first.Code, queued.Code)
+	}
+	waitForStats(t, limiter, 0, 0)
+}
+
+func TestWithConcurrencyLimit_QueueTimeout(t *testing.T) {
+	limiter := NewConcurrencyLimiter(1, 4, 50*time.Millisecond)
+	release := make(chan struct{})
+	defer close(release)
+	handler := WithConcurrencyLimit(completionsRoute, limiter, blockingHandler(release))
+
+	startRequest(context.Background(), handler)
+	waitForStats(t, limiter, 1, 0)
+
+	start := time.Now()
+	w := httptest.NewRecorder()
+	handler(w, httptest.NewRequest(http.MethodPost, "/v1/sessions/s/messages", nil))
+
+	assertUnavailable(t, w, "1")
+	if waited := time.Since(start); waited < 50*time.Millisecond {
+		t.Errorf("Expected the request to wait out the 50ms queue timeout, waited %v", waited)
+	}
+	if stats := limiter.Stats(); stats.Queued != 0 {
+		t.Errorf("Expected the timed-out request to leave the queue, got %+v", stats)
+	}
+}
+
+func TestWithConcurrencyLimit_ReleasesOnCancel(t *testing.T) {
+	limiter := NewConcurrencyLimiter(1, 2, 5*time.Second)
+	release := make(chan struct{})
+	defer close(release)
+	handler := WithConcurrencyLimit(completionsRoute, limiter, blockingHandler(release))
+
+	runningCtx, cancelRunning := context.WithCancel(context.Background())
+	_, runningDone := startRequest(runningCtx, handler)
+	waitForStats(t, limiter, 1, 0)
+
+	queuedCtx, cancelQueued := context.WithCancel(context.Background())
+	abandoned, abandonedDone := startRequest(queuedCtx, handler)
+	waitForStats(t, limiter, 1, 1)
+	_, waitingDone := startRequest(context.Background(), handler)
+	waitForStats(t, limiter, 1, 2)
+
+	// A client leaving the queue gives up its place without a response
+	cancelQueued()
+	<-abandonedDone
+	if abandoned.Code != http.StatusOK || abandoned.Body.Len() != 0 {
+		t.Errorf("Expected no response for a request cancelled while queued, got %d %q", abandoned.Code, abandoned.Body.String())
+	}
+	waitForStats(t, limiter, 1, 1)
+
+	// A client disconnecting mid-request frees its slot for the next queued request
+	cancelRunning()
+	<-runningDone
+	waitForStats(t, limiter, 1, 0)
+
+	select {
+	case <-waitingDone:
+		t.Fatal("The queued request should now be running, not finished")
+	default:
+	}
+}
+
+func TestNewConcurrencyLimiters_FromConfig(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+	config.SetForTest("limits.completions.max_concurrent", 4)
+	config.SetForTest("limits.completions.queue_size", 8)
+	config.SetForTest("limits.completions.queue_timeout", "2s")
+
+	limiters := newConcurrencyLimiters([]types.RouteInfo{
+		completionsRoute,
+		{Method: "POST", Path: "/v1/templates/{name}/complete", ConcurrencyGroup: types.ConcurrencyGroupCompletions},
+		{Method: "GET", Path: "/v1/jobs/{id}"},
+		{Method: "POST", Path: "/v1/embeddings", ConcurrencyGroup: "embeddings"},
+	})
+
+	if len(limiters) != 1 {
+		t.Fatalf("Expected only the configured completions group, got %v", limiters)
+	}
+	limiter := limiters[types.ConcurrencyGroupCompletions]
+	expected := ConcurrencyStats{MaxConcurrent: 4, QueueSize: 8}
+	if stats := limiter.Stats(); stats != expected {
+		t.Errorf("Stats = %+v, expected %+v", stats, expected)
+	}
+	if limiter.queueTimeout != 2*time.Second {
+		t.Errorf("queueTimeout = %v, expected 2s", limiter.queueTimeout)
+	}
+}
+
+func TestHealthHandler_ReportsLimits(t *testing.T) {
+	limiter := NewConcurrencyLimiter(2, 0, time.Second)
+	releaseSlot, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer releaseSlot()
+
+	h := &HealthHandler{limiters: map[string]*ConcurrencyLimiter{types.ConcurrencyGroupCompletions: limiter}}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+
+	var body HealthResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid health response %q: %v", w.Body.String(), err)
+	}
+	expected := ConcurrencyStats{InFlight: 1, MaxConcurrent: 2}
+	if got := body.Limits[types.ConcurrencyGroupCompletions]; got != expected {
+		t.Errorf("completions limits = %+v, expected %+v", got, expected)
+	}
+}
+
+func TestHealthHandler_ServeMetrics(t *testing.T) {
+	limiter := NewConcurrencyLimiter(1, 0, time.Second)
+	releaseSlot, err := limiter.Acquire(context.Background())
+	if err != nil {
+		t.Fatalf("Acquire() error = %v", err)
+	}
+	defer releaseSlot()
+	if _, err := limiter.Acquire(context.Background()); !errors.Is(err, ErrQueueFull) {
+		t.Fatalf("Acquire() error = %v, expected ErrQueueFull", err)
+	}
+
+	moderation.Record(moderation.ActionFlag, "prompt", []string{"violence"})
+
+	router, err := provider.NewRouter([]provider.Provider{provider.NewMockProvider("mock")}, []provider.Route{{Model: "*", Providers: []string{"mock"}}})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(`{"model":"m"}`)))
+
+	h := &HealthHandler{limiters: map[string]*ConcurrencyLimiter{types.ConcurrencyGroupCompletions: limiter}, router: router}
+	w := httptest.NewRecorder()
+	h.ServeMetrics(w, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	if contentType := w.Header().Get("Content-Type"); contentType != docs.PrometheusContentType {
+		t.Errorf("Content-Type = %q, expected %q", contentType, docs.PrometheusContentType)
+	}
+	for _, expected := range []string{
+		"# TYPE llm_concurrency_in_flight gauge\n",
+		`llm_concurrency_in_flight{group="completions"} 1` + "\n",
+		`llm_concurrency_queued{group="completions"} 0` + "\n",
+		`llm_concurrency_max_concurrent{group="completions"} 1` + "\n",
+		`llm_concurrency_queue_size{group="completions"} 0` + "\n",
+		"# TYPE llm_concurrency_rejected_total counter\n",
+		`llm_concurrency_rejected_total{group="completions"} 1` + "\n",
+		"# TYPE llm_provider_requests_total counter\n",
+		`llm_provider_requests_total{provider="mock",outcome="served"} 1` + "\n",
+		`llm_provider_requests_total{provider="mock",outcome="failed"} 0` + "\n",
+		"# TYPE llm_moderation_decisions_total counter\n",
+		`llm_moderation_decisions_total{action="flag",source="prompt",category="violence"} 1` + "\n",
+	} {
+		if !strings.Contains(w.Body.String(), expected) {
+			t.Errorf("Metrics should contain %q, got:\n%s", expected, w.Body.String())
+		}
+	}
+}
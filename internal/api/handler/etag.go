@@ -0,0 +1,104 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// bufferedResponse captures a handler's response so it can be hashed before sending
+type bufferedResponse struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func (b *bufferedResponse) Header() http.Header { return b.header }
+
+func (b *bufferedResponse) Write(p []byte) (int, error) {
+	if b.status == 0 {
+		b.status = http.StatusOK
+	}
+	return b.body.Write(p)
+}
+
+func (b *bufferedResponse) WriteHeader(status int) {
+	if b.status == 0 {
+		b.status = status
+	}
+}
+
+// WithETag wraps a cacheable route's handler with conditional GET support. The
+// response is buffered, hashed into a strong ETag and answered with 304 when it
+// matches If-None-Match. Any compression must wrap this handler so the ETag is
+// computed on the uncompressed body. Streaming routes are returned unchanged.
+func WithETag(route types.RouteInfo, next http.HandlerFunc) http.HandlerFunc {
+	if !route.Cacheable || route.Streaming {
+		return next
+	}
+
+	cacheControl := "no-cache"
+	if route.CacheMaxAge > 0 {
+		cacheControl = fmt.Sprintf("max-age=%d", int(route.CacheMaxAge.Seconds()))
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			next(w, r)
+			return
+		}
+
+		buffered := &bufferedResponse{header: make(http.Header)}
+		next(buffered, r)
+
+		for key, values := range buffered.header {
+			w.Header()[key] = values
+		}
+		if buffered.status == 0 {
+			buffered.status = http.StatusOK
+		}
+
+		// Only successful responses are worth revalidating
+		if buffered.status != http.StatusOK {
+			w.WriteHeader(buffered.status)
+			w.Write(buffered.body.Bytes())
+			return
+		}
+
+		sum := sha256.Sum256(buffered.body.Bytes())
+		etag := `"` + hex.EncodeToString(sum[:]) + `"`
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Cache-Control", cacheControl)
+
+		if etagMatches(r.Header.Get("If-None-Match"), etag) {
+			// A 304 carries no body or body-specific headers
+			w.Header().Del("Content-Type")
+			w.Header().Del("Content-Length")
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		w.Write(buffered.body.Bytes())
+	}
+}
+
+// etagMatches reports whether an If-None-Match header matches etag using the weak
+// comparison RFC 9110 requires for If-None-Match
+func etagMatches(ifNoneMatch, etag string) bool {
+	if ifNoneMatch == "" {
+		return false
+	}
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+func TestWithETag(t *testing.T) {
+	models := []string{"alpha", "beta"}
+	route := types.RouteInfo{Method: "GET", Path: "/v1/models", Cacheable: true, CacheMaxAge: time.Minute}
+
+	handler := WithETag(route, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(models)
+	})
+
+	get := func(ifNoneMatch string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/v1/models", nil)
+		if ifNoneMatch != "" {
+			req.Header.Set("If-None-Match", ifNoneMatch)
+		}
+		rec := httptest.NewRecorder()
+		handler(rec, req)
+		return rec
+	}
+
+	first := get("")
+	etag := first.Header().Get("ETag")
+	if first.Code != http.StatusOK || etag == "" {
+		t.Fatalf("First request should return 200 with an ETag, got %d and %q", first.Code, etag)
+	}
+	if got := first.Header().Get("Cache-Control"); got != "max-age=60" {
+		t.Errorf("Expected Cache-Control max-age=60, got %q", got)
+	}
+
+	second := get(etag)
+	if second.Code != http.StatusNotModified {
+		t.Errorf("Matching If-None-Match should return 304, got %d", second.Code)
+	}
+	if second.Body.Len() != 0 {
+		t.Errorf("304 response should have an empty body, got %q", second.Body.String())
+	}
+
+	models = append(models, "gamma")
+	third := get(etag)
+	if third.Code != http.StatusOK {
+		t.Errorf("Modified data should return 200, got %d", third.Code)
+	}
+	if newETag := third.Header().Get("ETag"); newETag == "" || newETag == etag {
+		t.Errorf("Modified data should produce a new ETag, got %q", newETag)
+	}
+}
+
+func TestWithETag_SkipsStreamingAndUncacheable(t *testing.T) {
+	next := func(w http.ResponseWriter, r *http.Request) { w.Write([]byte("chunk")) }
+
+	for _, route := range []types.RouteInfo{
+		{Method: "GET", Path: "/stream", Cacheable: true, Streaming: true},
+		{Method: "GET", Path: "/plain"},
+	} {
+		rec := httptest.NewRecorder()
+		WithETag(route, next)(rec, httptest.NewRequest(http.MethodGet, route.Path, nil))
+
+		if rec.Header().Get("ETag") != "" {
+			t.Errorf("Route %s should not get an ETag", route.Path)
+		}
+	}
+}
@@ -5,14 +5,27 @@ import (
 
 	"github.com/JerkyTreats/llm/internal/api/types"
 	"github.com/JerkyTreats/llm/internal/docs"
+	"github.com/JerkyTreats/llm/internal/jobs"
 	"github.com/JerkyTreats/llm/internal/logging"
+	"github.com/JerkyTreats/llm/internal/provider"
+	"github.com/JerkyTreats/llm/internal/sessions"
+	"github.com/JerkyTreats/llm/internal/templates"
+	"github.com/JerkyTreats/llm/internal/tracing"
 )
 
 // HandlerRegistry manages all HTTP handlers for the application
 type HandlerRegistry struct {
-	healthHandler *HealthHandler
-	docsHandler   *docs.DocsHandler
-	mux           *http.ServeMux
+	healthHandler    *HealthHandler
+	versionHandler   *VersionHandler
+	docsHandler      *docs.DocsHandler
+	jobsHandler      *jobs.JobsHandler
+	templatesHandler *templates.TemplatesHandler
+	sessionsHandler  *sessions.SessionsHandler
+	router           *provider.Router
+	mux              *http.ServeMux
+	mounted          []types.RouteInfo
+	idempotency      IdempotencyStore
+	limiters         map[string]*ConcurrencyLimiter
 }
 
 // NewHandlerRegistry creates a new handler registry with all handlers initialized
@@ -25,16 +38,62 @@ func NewHandlerRegistry() (*HandlerRegistry, error) {
 		return nil, err
 	}
 
+	// Initialize version handler
+	versionHandler, err := NewVersionHandler()
+	if err != nil {
+		return nil, err
+	}
+
 	// Initialize docs handler
-	docsHandler, err := docs.NewDocsHandler()
+	docsHandler, err := docs.NewDocsHandler(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize jobs handler
+	jobsHandler, err := jobs.NewJobsHandler()
+	if err != nil {
+		return nil, err
+	}
+
+	// Initialize templates handler
+	templatesHandler, err := templates.NewTemplatesHandler()
 	if err != nil {
 		return nil, err
 	}
 
+	// Initialize sessions handler
+	sessionsHandler, err := sessions.NewSessionsHandler()
+	if err != nil {
+		return nil, err
+	}
+
+	// Route chat completions and embeddings to providers by model
+	router, err := provider.NewRouterFromConfig()
+	if err != nil {
+		return nil, err
+	}
+	healthHandler.router = router
+
+	// Limit concurrency per route group; health reports the limiters' load
+	limiters := newConcurrencyLimiters(GetRegisteredRoutes())
+	healthHandler.limiters = limiters
+
 	registry := &HandlerRegistry{
-		healthHandler: healthHandler,
-		docsHandler:   docsHandler,
-		mux:           http.NewServeMux(),
+		healthHandler:    healthHandler,
+		versionHandler:   versionHandler,
+		docsHandler:      docsHandler,
+		jobsHandler:      jobsHandler,
+		templatesHandler: templatesHandler,
+		sessionsHandler:  sessionsHandler,
+		router:           router,
+		mux:              http.NewServeMux(),
+		idempotency:      NewMemoryIdempotencyStore(idempotencyTTL()),
+		limiters:         limiters,
+	}
+
+	if err := ValidateRoutePatterns(GetRegisteredRoutes()); err != nil {
+		return nil, err
 	}
 
 	registry.RegisterHandlers(registry.mux)
@@ -54,7 +113,13 @@ func (hr *HandlerRegistry) RegisterHandlers(mux *http.ServeMux) {
 	routes := GetRegisteredRoutes()
 	for _, route := range routes {
 		if route.Handler != nil {
-			mux.HandleFunc(route.Path, route.Handler)
+			limited := WithConcurrencyLimit(route, hr.limiters[route.ConcurrencyGroup], WithETag(route, route.Handler))
+			handler := tracing.WithTracing(route, WithRequiredHeaders(route, WithIdempotency(route, hr.idempotency, limited)))
+			if err := mountRoute(mux, routePattern(route), handler); err != nil {
+				logging.Error("Skipping route %s %s from %s module: %v", route.Method, route.Path, route.Module, err)
+				continue
+			}
+			hr.mounted = append(hr.mounted, route)
 			logging.Debug("Registered %s %s from %s module", route.Method, route.Path, route.Module)
 		} else {
 			logging.Warn("Skipping route %s %s - handler is nil", route.Method, route.Path)
@@ -69,11 +134,40 @@ func (hr *HandlerRegistry) GetServeMux() *http.ServeMux {
 	return hr.mux
 }
 
+// GetMountedRoutes returns the routes that were registered on the mux with a handler
+func (hr *HandlerRegistry) GetMountedRoutes() []types.RouteInfo {
+	return hr.mounted
+}
+
+// ConcurrencyStats returns the in-flight requests and queue depth of each route
+// group with a configured concurrency limit
+func (hr *HandlerRegistry) ConcurrencyStats() map[string]ConcurrencyStats {
+	return concurrencyStats(hr.limiters)
+}
+
 // GetHealthHandler returns the health handler instance for direct access if needed
 func (hr *HandlerRegistry) GetHealthHandler() *HealthHandler {
 	return hr.healthHandler
 }
 
+// Close releases resources held by the handlers, such as the docs rate limiter's
+// refill ticker and the job worker pool; call it once the server has shut down
+func (hr *HandlerRegistry) Close() {
+	if hr.docsHandler != nil {
+		hr.docsHandler.Close()
+	}
+	if hr.jobsHandler != nil {
+		hr.jobsHandler.Close()
+	}
+}
+
+// limitedDocsRoute wraps a rate limited docs handler with request logging, security
+// headers and metrics. Metrics wrap the rate limiter so rejected requests count.
+func (hr *HandlerRegistry) limitedDocsRoute(endpoint string, next http.HandlerFunc) http.HandlerFunc {
+	h := hr.docsHandler
+	return h.WithRequestLog(docs.WithSecurityHeaders(h.WithMetrics(endpoint, h.WithRateLimit(next))))
+}
+
 // updateRouteHandlers updates the RouteInfo registry with actual handler function references
 func (hr *HandlerRegistry) updateRouteHandlers() {
 	routes := GetRegisteredRoutes()
@@ -83,17 +177,50 @@ func (hr *HandlerRegistry) updateRouteHandlers() {
 			if hr.healthHandler != nil {
 				routes[i].Handler = hr.healthHandler.ServeHTTP
 			}
+		case "/metrics":
+			if hr.healthHandler != nil {
+				routes[i].Handler = hr.healthHandler.ServeMetrics
+			}
+		case "/version":
+			if hr.versionHandler != nil {
+				routes[i].Handler = hr.versionHandler.ServeHTTP
+			}
 		case "/swagger":
 			if hr.docsHandler != nil {
-				routes[i].Handler = hr.docsHandler.ServeSwaggerUI
+				routes[i].Handler = hr.limitedDocsRoute(docs.MetricSwaggerUI, hr.docsHandler.ServeSwaggerUI)
+			}
+		case "/docs/openapi.yaml", "/docs/openapi.json", "/docs/openapi":
+			if hr.docsHandler != nil {
+				routes[i].Handler = hr.limitedDocsRoute(docs.MetricOpenAPISpec, hr.docsHandler.ServeOpenAPISpec)
+			}
+		case "/docs/healthz":
+			if hr.docsHandler != nil {
+				routes[i].Handler = hr.docsHandler.WithRequestLog(docs.WithSecurityHeaders(hr.docsHandler.Healthz))
 			}
-		case "/docs/openapi.yaml":
+		case "/docs/metrics":
 			if hr.docsHandler != nil {
-				routes[i].Handler = hr.docsHandler.ServeOpenAPISpec
+				routes[i].Handler = hr.docsHandler.WithRequestLog(docs.WithSecurityHeaders(hr.docsHandler.ServeMetricsEndpoint))
+			}
+		case "/docs/routes.json":
+			if hr.docsHandler != nil {
+				routes[i].Handler = hr.limitedDocsRoute(docs.MetricRouteIndex, hr.docsHandler.ServeRouteIndex)
 			}
 		case "/docs":
 			if hr.docsHandler != nil {
-				routes[i].Handler = hr.docsHandler.ServeDocs
+				routes[i].Handler = hr.limitedDocsRoute(docs.MetricDocs, hr.docsHandler.ServeDocs)
+			}
+		default:
+			if route.Module == jobs.Module && hr.jobsHandler != nil {
+				routes[i].Handler = hr.jobsHandler.HandlerFor(route)
+			}
+			if route.Module == templates.Module && hr.templatesHandler != nil {
+				routes[i].Handler = hr.templatesHandler.HandlerFor(route)
+			}
+			if route.Module == sessions.Module && hr.sessionsHandler != nil {
+				routes[i].Handler = hr.sessionsHandler.HandlerFor(route)
+			}
+			if route.Module == provider.Module && hr.router != nil {
+				routes[i].Handler = hr.router.ServeHTTP
 			}
 		}
 	}
@@ -0,0 +1,37 @@
+package handler
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// WithRequiredHeaders rejects requests missing any of the route's required header
+// parameters with 400, listing every missing header. Routes without required
+// headers are served by next unchanged.
+func WithRequiredHeaders(route types.RouteInfo, next http.HandlerFunc) http.HandlerFunc {
+	var required []string
+	for _, header := range route.HeaderParams {
+		if header.Required {
+			required = append(required, header.Name)
+		}
+	}
+	if len(required) == 0 {
+		return next
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		var missing []string
+		for _, name := range required {
+			if strings.TrimSpace(r.Header.Get(name)) == "" {
+				missing = append(missing, name)
+			}
+		}
+		if len(missing) > 0 {
+			types.WriteError(w, http.StatusBadRequest, "missing required headers: "+strings.Join(missing, ", "))
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+var headerRoute = types.RouteInfo{
+	Method: "GET",
+	Path:   "/v1/models",
+	HeaderParams: []types.HeaderParam{
+		{Name: "X-Org-ID", Required: true},
+		{Name: "Accept-Language", Required: true},
+		{Name: "X-Trace-Sample", Type: "boolean"},
+	},
+}
+
+func TestWithRequiredHeaders_RejectsMissing(t *testing.T) {
+	var called bool
+	handler := WithRequiredHeaders(headerRoute, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/models", nil)
+	req.Header.Set("Accept-Language", "en")
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+
+	if called {
+		t.Fatal("Handler should not run when a required header is missing")
+	}
+	if rec.Code != http.StatusBadRequest {
+		t.Fatalf("Expected 400, got %d", rec.Code)
+	}
+	body := rec.Body.String()
+	if !strings.Contains(body, "X-Org-ID") {
+		t.Errorf("Error should list the missing header, got %s", body)
+	}
+	if strings.Contains(body, "Accept-Language") || strings.Contains(body, "X-Trace-Sample") {
+		t.Errorf("Error should only list missing required headers, got %s", body)
+	}
+}
+
+func TestWithRequiredHeaders_ListsAllMissing(t *testing.T) {
+	handler := WithRequiredHeaders(headerRoute, func(w http.ResponseWriter, r *http.Request) {})
+
+	rec := httptest.NewRecorder()
+	handler(rec, httptest.NewRequest(http.MethodGet, "/v1/models", nil))
+
+	if !strings.Contains(rec.Body.String(), "X-Org-ID, Accept-Language") {
+		t.Errorf("Error should list every missing header, got %s", rec.Body.String())
+	}
+}
+
+func TestWithRequiredHeaders_Present(t *testing.T) {
+	var called bool
+	handler := WithRequiredHeaders(headerRoute, func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/models", nil)
+	req.Header.Set("X-Org-Id", "org-1")
+	req.Header.Set("Accept-Language", "en")
+	handler(httptest.NewRecorder(), req)
+
+	if !called {
+		t.Error("Handler should run when required headers are present")
+	}
+}
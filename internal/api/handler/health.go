@@ -5,15 +5,23 @@ import (
 	"net/http"
 
 	"github.com/JerkyTreats/llm/internal/logging"
+	"github.com/JerkyTreats/llm/internal/provider"
 )
 
 // HealthResponse represents the JSON response for health checks
 type HealthResponse struct {
 	Status string `json:"status"`
+	// Limits reports the load of each concurrency-limited route group
+	Limits map[string]ConcurrencyStats `json:"limits,omitempty"`
 }
 
 // HealthHandler handles health check requests
-type HealthHandler struct{}
+type HealthHandler struct {
+	// limiters are the route group concurrency limiters reported in the response
+	limiters map[string]*ConcurrencyLimiter
+	// router serves the completion routes; its provider counters are exported as metrics
+	router *provider.Router
+}
 
 // NewHealthHandler creates a new health handler
 func NewHealthHandler() (*HealthHandler, error) {
@@ -31,6 +39,7 @@ func (h *HealthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	response := HealthResponse{
 		Status: "HEALTHY",
+		Limits: concurrencyStats(h.limiters),
 	}
 
 	w.Header().Set("Content-Type", "application/json")
@@ -4,17 +4,44 @@ import (
 	"reflect"
 
 	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/version"
 )
 
 func init() {
 	// Register health check endpoint
 	types.RegisterRoute(types.RouteInfo{
-		Method:       "GET",
-		Path:         "/health",
-		Handler:      nil, // Will be set during handler initialization
-		RequestType:  nil, // GET request has no body
-		ResponseType: reflect.TypeOf(HealthResponse{}),
-		Module:       "health",
-		Summary:      "Health check endpoint returning service status",
+		Method:          "GET",
+		Path:            "/health",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "github.com/JerkyTreats/llm/internal/api/handler.HealthHandler.ServeHTTP",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    reflect.TypeOf(HealthResponse{}),
+		Module:          "health",
+		Summary:         "Health check endpoint returning service status",
+	})
+
+	// Register build information endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "GET",
+		Path:            "/version",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "github.com/JerkyTreats/llm/internal/api/handler.VersionHandler.ServeHTTP",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    reflect.TypeOf(version.BuildInfo{}),
+		Module:          "health",
+		Summary:         "Build information of the running server",
+	})
+	// Register the concurrency limiter metrics endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "GET",
+		Path:            "/metrics",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "github.com/JerkyTreats/llm/internal/api/handler.HealthHandler.ServeMetrics",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    nil, // Returns Prometheus text exposition format
+		Module:          "health",
+		Summary:         "Concurrency limiter load per route group in Prometheus text format",
+
+		ResponseContentType: "text/plain",
 	})
 }
@@ -0,0 +1,267 @@
+package handler
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
+	"github.com/JerkyTreats/llm/internal/logging"
+)
+
+// Idempotency headers and defaults used when the config does not override them
+const (
+	IdempotencyKeyHeader      = "Idempotency-Key"
+	IdempotencyReplayedHeader = "Idempotency-Replayed"
+
+	defaultIdempotencyTTL = 24 * time.Hour
+
+	// Concurrent duplicates either wait for the first request or are rejected with 409
+	IdempotencyConcurrentBlock  = "block"
+	IdempotencyConcurrentReject = "reject"
+)
+
+// replayedHeaders lists the response headers stored and replayed for duplicate requests
+var replayedHeaders = []string{"Content-Type", "Content-Language", "Location", "ETag", "Cache-Control"}
+
+func init() {
+	config.RegisterKey("idempotency.ttl", "")
+	config.RegisterKey("idempotency.concurrent", "")
+}
+
+// StoredResponse is the first response recorded for an idempotency key
+type StoredResponse struct {
+	Status int
+	Header http.Header
+	Body   []byte
+}
+
+// IdempotencyStore records responses by idempotency key
+type IdempotencyStore interface {
+	// Begin returns the stored response for key, or reserves key for the caller when
+	// reserved is true. When another request holds the reservation, wait is closed
+	// once that request completes or aborts.
+	Begin(key string) (stored *StoredResponse, wait <-chan struct{}, reserved bool)
+	// Complete stores resp for a reserved key and releases waiting requests
+	Complete(key string, resp *StoredResponse)
+	// Abort drops a reservation without storing a response so the key can be retried
+	Abort(key string)
+}
+
+// idempotencyEntry is a reserved or completed key in MemoryIdempotencyStore
+type idempotencyEntry struct {
+	done     chan struct{}
+	response *StoredResponse
+	expires  time.Time
+}
+
+// MemoryIdempotencyStore is an in-memory IdempotencyStore. Completed responses are
+// evicted once their TTL elapses; in-flight reservations never expire.
+type MemoryIdempotencyStore struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*idempotencyEntry
+	now     func() time.Time
+}
+
+// NewMemoryIdempotencyStore creates an in-memory store that keeps responses for ttl
+func NewMemoryIdempotencyStore(ttl time.Duration) *MemoryIdempotencyStore {
+	if ttl <= 0 {
+		ttl = defaultIdempotencyTTL
+	}
+	return &MemoryIdempotencyStore{
+		ttl:     ttl,
+		entries: make(map[string]*idempotencyEntry),
+		now:     time.Now,
+	}
+}
+
+// Begin implements IdempotencyStore
+func (s *MemoryIdempotencyStore) Begin(key string) (*StoredResponse, <-chan struct{}, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired()
+
+	if entry, exists := s.entries[key]; exists {
+		if entry.response != nil {
+			return entry.response, nil, false
+		}
+		return nil, entry.done, false
+	}
+
+	s.entries[key] = &idempotencyEntry{done: make(chan struct{})}
+	return nil, nil, true
+}
+
+// Complete implements IdempotencyStore
+func (s *MemoryIdempotencyStore) Complete(key string, resp *StoredResponse) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.entries[key]
+	if !exists || entry.response != nil {
+		return
+	}
+	entry.response = resp
+	entry.expires = s.now().Add(s.ttl)
+	close(entry.done)
+}
+
+// Abort implements IdempotencyStore
+func (s *MemoryIdempotencyStore) Abort(key string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	entry, exists := s.entries[key]
+	if !exists || entry.response != nil {
+		return
+	}
+	delete(s.entries, key)
+	close(entry.done)
+}
+
+// Len returns the number of reserved and stored keys
+func (s *MemoryIdempotencyStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.entries)
+}
+
+// evictExpired drops completed entries past their TTL; callers must hold s.mu
+func (s *MemoryIdempotencyStore) evictExpired() {
+	now := s.now()
+	for key, entry := range s.entries {
+		if entry.response != nil && now.After(entry.expires) {
+			delete(s.entries, key)
+		}
+	}
+}
+
+// idempotencyTTL reads idempotency.ttl as a Go duration, falling back to 24h
+func idempotencyTTL() time.Duration {
+	raw := config.GetString("idempotency.ttl")
+	if raw == "" {
+		return defaultIdempotencyTTL
+	}
+	ttl, err := time.ParseDuration(raw)
+	if err != nil || ttl <= 0 {
+		logging.Warn("Invalid idempotency.ttl %q, using %s", raw, defaultIdempotencyTTL)
+		return defaultIdempotencyTTL
+	}
+	return ttl
+}
+
+// WithIdempotency wraps an idempotent route's handler so requests carrying an
+// Idempotency-Key header run once per key, route and caller identity. Duplicates
+// within the store's TTL receive the first response with Idempotency-Replayed: true.
+// Concurrent duplicates wait for the first request when idempotency.concurrent is
+// "block" and are rejected with 409 otherwise. Server errors are not stored so the
+// client can retry them. Streaming routes are returned unchanged.
+func WithIdempotency(route types.RouteInfo, store IdempotencyStore, next http.HandlerFunc) http.HandlerFunc {
+	if !route.Idempotent || route.Streaming || store == nil {
+		return next
+	}
+
+	block := config.GetString("idempotency.concurrent") == IdempotencyConcurrentBlock
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		key := r.Header.Get(IdempotencyKeyHeader)
+		if key == "" {
+			next(w, r)
+			return
+		}
+		storeKey := idempotencyStoreKey(route, requestIdentity(r), key)
+
+		for {
+			stored, wait, reserved := store.Begin(storeKey)
+			if stored != nil {
+				replayResponse(w, stored)
+				return
+			}
+			if reserved {
+				break
+			}
+			if !block {
+				http.Error(w, "A request with this Idempotency-Key is already in progress", http.StatusConflict)
+				return
+			}
+			select {
+			case <-wait:
+				// The first request finished or aborted; look the key up again
+			case <-r.Context().Done():
+				return
+			}
+		}
+
+		buffered := &bufferedResponse{header: make(http.Header)}
+		completed := false
+		defer func() {
+			// A panicking handler must not leave the key reserved forever
+			if !completed {
+				store.Abort(storeKey)
+			}
+		}()
+		next(buffered, r)
+
+		if buffered.status == 0 {
+			buffered.status = http.StatusOK
+		}
+		if buffered.status >= http.StatusInternalServerError {
+			store.Abort(storeKey)
+		} else {
+			resp := &StoredResponse{
+				Status: buffered.status,
+				Header: make(http.Header),
+				Body:   append([]byte(nil), buffered.body.Bytes()...),
+			}
+			for _, name := range replayedHeaders {
+				if values := buffered.header.Values(name); len(values) > 0 {
+					resp.Header[name] = append([]string(nil), values...)
+				}
+			}
+			store.Complete(storeKey, resp)
+		}
+		completed = true
+
+		for name, values := range buffered.header {
+			w.Header()[name] = values
+		}
+		w.WriteHeader(buffered.status)
+		w.Write(buffered.body.Bytes())
+	}
+}
+
+// replayResponse writes a stored response marked as replayed
+func replayResponse(w http.ResponseWriter, stored *StoredResponse) {
+	for name, values := range stored.Header {
+		w.Header()[name] = append([]string(nil), values...)
+	}
+	w.Header().Set(IdempotencyReplayedHeader, "true")
+	w.WriteHeader(stored.Status)
+	w.Write(stored.Body)
+}
+
+// requestIdentity identifies the caller by its Authorization header, falling back
+// to the client address for anonymous requests
+func requestIdentity(r *http.Request) string {
+	if auth := r.Header.Get("Authorization"); auth != "" {
+		return "auth:" + auth
+	}
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	return "addr:" + host
+}
+
+// idempotencyStoreKey hashes the key, route and identity so credentials are never
+// held in the store and keys cannot collide across routes or callers
+func idempotencyStoreKey(route types.RouteInfo, identity, key string) string {
+	sum := sha256.Sum256([]byte(route.Method + " " + route.Path + "\x00" + identity + "\x00" + key))
+	return hex.EncodeToString(sum[:])
+}
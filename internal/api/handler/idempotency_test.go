@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
+)
+
+var idempotentRoute = types.RouteInfo{Method: "POST", Path: "/v1/completions", Idempotent: true}
+
+// countingHandler returns a handler that numbers each call it actually runs
+func countingHandler(calls *int32) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		fmt.Fprintf(w, `{"call":%d}`, n)
+	}
+}
+
+func postWithKey(handler http.HandlerFunc, key, auth string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/v1/completions", nil)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+	if auth != "" {
+		req.Header.Set("Authorization", auth)
+	}
+	rec := httptest.NewRecorder()
+	handler(rec, req)
+	return rec
+}
+
+func TestWithIdempotency_Replay(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	var calls int32
+	handler := WithIdempotency(idempotentRoute, NewMemoryIdempotencyStore(time.Hour), countingHandler(&calls))
+
+	first := postWithKey(handler, "abc", "Bearer one")
+	second := postWithKey(handler, "abc", "Bearer one")
+
+	if calls != 1 {
+		t.Fatalf("Handler should run once for a duplicate key, ran %d times", calls)
+	}
+	if first.Header().Get(IdempotencyReplayedHeader) != "" {
+		t.Error("First response should not be marked as replayed")
+	}
+	if second.Header().Get(IdempotencyReplayedHeader) != "true" {
+		t.Error("Duplicate response should carry Idempotency-Replayed: true")
+	}
+	if second.Code != http.StatusCreated || second.Body.String() != `{"call":1}` {
+		t.Errorf("Duplicate should replay status and body, got %d %q", second.Code, second.Body.String())
+	}
+	if got := second.Header().Get("Content-Type"); got != "application/json" {
+		t.Errorf("Duplicate should replay Content-Type, got %q", got)
+	}
+
+	// Requests without a key are never deduplicated
+	postWithKey(handler, "", "Bearer one")
+	postWithKey(handler, "", "Bearer one")
+	if calls != 3 {
+		t.Errorf("Requests without Idempotency-Key should always run, got %d calls", calls)
+	}
+}
+
+func TestWithIdempotency_Expiry(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryIdempotencyStore(time.Minute)
+	store.now = func() time.Time { return now }
+
+	var calls int32
+	handler := WithIdempotency(idempotentRoute, store, countingHandler(&calls))
+
+	postWithKey(handler, "abc", "")
+	now = now.Add(30 * time.Second)
+	if rec := postWithKey(handler, "abc", ""); rec.Header().Get(IdempotencyReplayedHeader) != "true" {
+		t.Error("Duplicate within the TTL should be replayed")
+	}
+
+	now = now.Add(time.Minute)
+	rec := postWithKey(handler, "abc", "")
+	if rec.Header().Get(IdempotencyReplayedHeader) != "" || calls != 2 {
+		t.Errorf("Key past its TTL should run the handler again, got %d calls", calls)
+	}
+	if store.Len() != 1 {
+		t.Errorf("Expired entry should be evicted, store holds %d keys", store.Len())
+	}
+}
+
+func TestWithIdempotency_Identities(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	var calls int32
+	handler := WithIdempotency(idempotentRoute, NewMemoryIdempotencyStore(time.Hour), countingHandler(&calls))
+
+	postWithKey(handler, "abc", "Bearer one")
+	rec := postWithKey(handler, "abc", "Bearer two")
+
+	if calls != 2 {
+		t.Errorf("Different identities should not share keys, got %d calls", calls)
+	}
+	if rec.Header().Get(IdempotencyReplayedHeader) != "" {
+		t.Error("Response for a different identity should not be replayed")
+	}
+}
+
+func TestWithIdempotency_ServerErrorNotStored(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	var calls int32
+	handler := WithIdempotency(idempotentRoute, NewMemoryIdempotencyStore(time.Hour), func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	})
+
+	postWithKey(handler, "abc", "")
+	postWithKey(handler, "abc", "")
+	if calls != 2 {
+		t.Errorf("Server errors should not be replayed, got %d calls", calls)
+	}
+}
+
+// inFlight runs a request for key whose handler blocks until release is closed and
+// returns once the handler has started
+func inFlight(t *testing.T, handler func(http.HandlerFunc) http.HandlerFunc, release chan struct{}) <-chan *httptest.ResponseRecorder {
+	t.Helper()
+
+	started := make(chan struct{})
+	wrapped := handler(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+		w.Write([]byte("first"))
+	})
+
+	done := make(chan *httptest.ResponseRecorder, 1)
+	go func() { done <- postWithKey(wrapped, "abc", "") }()
+	<-started
+	return done
+}
+
+func TestWithIdempotency_ConcurrentReject(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	store := NewMemoryIdempotencyStore(time.Hour)
+	wrap := func(next http.HandlerFunc) http.HandlerFunc { return WithIdempotency(idempotentRoute, store, next) }
+
+	release := make(chan struct{})
+	done := inFlight(t, wrap, release)
+
+	duplicate := postWithKey(wrap(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("Concurrent duplicate should not run the handler")
+	}), "abc", "")
+	if duplicate.Code != http.StatusConflict {
+		t.Errorf("Concurrent duplicate should get 409, got %d", duplicate.Code)
+	}
+
+	close(release)
+	if first := <-done; first.Code != http.StatusOK {
+		t.Errorf("First request should succeed, got %d", first.Code)
+	}
+}
+
+func TestWithIdempotency_ConcurrentBlock(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+	config.SetForTest("idempotency.concurrent", IdempotencyConcurrentBlock)
+
+	store := NewMemoryIdempotencyStore(time.Hour)
+	wrap := func(next http.HandlerFunc) http.HandlerFunc { return WithIdempotency(idempotentRoute, store, next) }
+
+	release := make(chan struct{})
+	done := inFlight(t, wrap, release)
+
+	duplicateDone := make(chan *httptest.ResponseRecorder, 1)
+	go func() {
+		duplicateDone <- postWithKey(wrap(func(w http.ResponseWriter, r *http.Request) {
+			t.Error("Blocked duplicate should not run the handler")
+		}), "abc", "")
+	}()
+
+	select {
+	case <-duplicateDone:
+		t.Fatal("Duplicate should block while the first request is in flight")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(release)
+	<-done
+	duplicate := <-duplicateDone
+	if duplicate.Header().Get(IdempotencyReplayedHeader) != "true" || duplicate.Body.String() != "first" {
+		t.Errorf("Blocked duplicate should receive the replayed first response, got %q", duplicate.Body.String())
+	}
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/docs"
+	"github.com/JerkyTreats/llm/internal/moderation"
+)
+
+// ServeMetrics reports the in-flight requests, queue depth, limits and rejections
+// of each concurrency-limited route group, labelled by group, the requests the
+// completion routes sent to each provider, and the content the moderation policy
+// flagged or blocked, in the Prometheus text exposition format
+func (h *HealthHandler) ServeMetrics(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	stats := concurrencyStats(h.limiters)
+	groups := make([]string, 0, len(stats))
+	for group := range stats {
+		groups = append(groups, group)
+	}
+	slices.Sort(groups)
+
+	// samples formats one sample per group from the field value selects
+	samples := func(name string, value func(ConcurrencyStats) int) []string {
+		lines := make([]string, 0, len(groups))
+		for _, group := range groups {
+			lines = append(lines, fmt.Sprintf("%s{group=%q} %d", name, group, value(stats[group])))
+		}
+		return lines
+	}
+
+	var b strings.Builder
+	docs.WriteMetric(&b, "llm_concurrency_in_flight", "gauge", "Requests currently being served per route group.",
+		samples("llm_concurrency_in_flight", func(s ConcurrencyStats) int { return s.InFlight })...)
+	docs.WriteMetric(&b, "llm_concurrency_queued", "gauge", "Requests waiting for a slot per route group.",
+		samples("llm_concurrency_queued", func(s ConcurrencyStats) int { return s.Queued })...)
+	docs.WriteMetric(&b, "llm_concurrency_max_concurrent", "gauge", "Requests a route group serves at once before queueing.",
+		samples("llm_concurrency_max_concurrent", func(s ConcurrencyStats) int { return s.MaxConcurrent })...)
+	docs.WriteMetric(&b, "llm_concurrency_queue_size", "gauge", "Requests a route group allows to wait before rejecting.",
+		samples("llm_concurrency_queue_size", func(s ConcurrencyStats) int { return s.QueueSize })...)
+	docs.WriteMetric(&b, "llm_concurrency_rejected_total", "counter", "Requests a route group rejected with 503.",
+		samples("llm_concurrency_rejected_total", func(s ConcurrencyStats) int { return s.Rejected })...)
+
+	var providerSamples []string
+	if h.router != nil {
+		providerStats := h.router.Stats()
+		for _, name := range slices.Sorted(maps.Keys(providerStats)) {
+			providerSamples = append(providerSamples,
+				fmt.Sprintf("llm_provider_requests_total{provider=%q,outcome=\"served\"} %d", name, providerStats[name].Served),
+				fmt.Sprintf("llm_provider_requests_total{provider=%q,outcome=\"failed\"} %d", name, providerStats[name].Failed))
+		}
+	}
+	docs.WriteMetric(&b, "llm_provider_requests_total", "counter", "Completion and embeddings requests per provider, served or failed over to the next provider.",
+		providerSamples...)
+
+	decisions := moderation.Decisions()
+	decisionSamples := make([]string, 0, len(decisions))
+	for _, d := range decisions {
+		decisionSamples = append(decisionSamples, fmt.Sprintf("llm_moderation_decisions_total{action=%q,source=%q,category=%q} %d",
+			d.Action, d.Source, d.Category, d.Count))
+	}
+	docs.WriteMetric(&b, "llm_moderation_decisions_total", "counter", "Content the moderation policy flagged or blocked per category.",
+		decisionSamples...)
+
+	w.Header().Set("Content-Type", docs.PrometheusContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(b.String()))
+}
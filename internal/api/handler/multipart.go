@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// UploadedFile is a file part of a multipart request
+type UploadedFile struct {
+	Filename string
+	Size     int64
+	io.Reader
+}
+
+// MultipartForm holds the parsed values and files of a multipart/form-data request
+type MultipartForm struct {
+	Values map[string]string
+	Files  map[string]*UploadedFile
+
+	form      *multipart.Form
+	openFiles []io.Closer
+}
+
+// ParseMultipartRequest parses a multipart/form-data request body, rejecting bodies
+// larger than the route's MaxBodyBytes. Callers must Close the returned form.
+func ParseMultipartRequest(w http.ResponseWriter, r *http.Request, route types.RouteInfo) (*MultipartForm, error) {
+	limit := route.MaxBodyBytes
+	if limit <= 0 {
+		limit = types.DefaultMaxBodyBytes
+	}
+
+	r.Body = http.MaxBytesReader(w, r.Body, limit)
+	if err := r.ParseMultipartForm(limit); err != nil {
+		return nil, fmt.Errorf("failed to parse multipart form: %w", err)
+	}
+
+	parsed := &MultipartForm{
+		Values: make(map[string]string),
+		Files:  make(map[string]*UploadedFile),
+		form:   r.MultipartForm,
+	}
+
+	for name, values := range r.MultipartForm.Value {
+		if len(values) > 0 {
+			parsed.Values[name] = values[0]
+		}
+	}
+
+	for name, headers := range r.MultipartForm.File {
+		if len(headers) == 0 {
+			continue
+		}
+		file, err := headers[0].Open()
+		if err != nil {
+			parsed.Close()
+			return nil, fmt.Errorf("failed to open uploaded file %s: %w", name, err)
+		}
+		parsed.openFiles = append(parsed.openFiles, file)
+		parsed.Files[name] = &UploadedFile{
+			Filename: headers[0].Filename,
+			Size:     headers[0].Size,
+			Reader:   file,
+		}
+	}
+
+	return parsed, nil
+}
+
+// Close releases open files and removes any temporary files backing the form
+func (f *MultipartForm) Close() error {
+	for _, file := range f.openFiles {
+		file.Close()
+	}
+	f.openFiles = nil
+
+	if f.form != nil {
+		return f.form.RemoveAll()
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// multipartBody builds a multipart body with one string field and one file
+func multipartBody(t *testing.T, fileContent string) (*bytes.Buffer, string) {
+	t.Helper()
+
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	if err := writer.WriteField("purpose", "fine-tune"); err != nil {
+		t.Fatalf("WriteField() error = %v", err)
+	}
+	part, err := writer.CreateFormFile("file", "train.jsonl")
+	if err != nil {
+		t.Fatalf("CreateFormFile() error = %v", err)
+	}
+	part.Write([]byte(fileContent))
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	return body, writer.FormDataContentType()
+}
+
+func TestParseMultipartRequest(t *testing.T) {
+	route := types.RouteInfo{
+		Method:             "POST",
+		Path:               "/files",
+		RequestContentType: types.ContentTypeMultipartForm,
+		FormFields:         map[string]string{"purpose": "string", "file": types.FormFieldBinary},
+		MaxBodyBytes:       1 << 20,
+	}
+
+	var purpose, filename, content string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		form, err := ParseMultipartRequest(w, r, route)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		defer form.Close()
+
+		purpose = form.Values["purpose"]
+		filename = form.Files["file"].Filename
+		data, _ := io.ReadAll(form.Files["file"])
+		content = string(data)
+	}
+
+	body, contentType := multipartBody(t, `{"prompt": "hi"}`)
+	server := httptest.NewServer(http.HandlerFunc(handler))
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, contentType, body)
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", resp.StatusCode)
+	}
+	if purpose != "fine-tune" || filename != "train.jsonl" || content != `{"prompt": "hi"}` {
+		t.Errorf("Unexpected parsed form: purpose=%q filename=%q content=%q", purpose, filename, content)
+	}
+}
+
+func TestParseMultipartRequest_TooLarge(t *testing.T) {
+	route := types.RouteInfo{Method: "POST", Path: "/files", MaxBodyBytes: 64}
+
+	body, contentType := multipartBody(t, strings.Repeat("x", 1024))
+	req := httptest.NewRequest(http.MethodPost, "/files", body)
+	req.Header.Set("Content-Type", contentType)
+	rec := httptest.NewRecorder()
+
+	if _, err := ParseMultipartRequest(rec, req, route); err == nil {
+		t.Error("ParseMultipartRequest() should reject bodies over MaxBodyBytes")
+	}
+}
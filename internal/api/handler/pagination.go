@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/config"
+)
+
+// Pagination defaults used when the config does not set bounds
+const (
+	defaultPageLimit = 20
+	defaultMaxLimit  = 100
+)
+
+func init() {
+	config.RegisterDefault("pagination.default_limit", defaultPageLimit)
+	config.RegisterDefault("pagination.max_limit", defaultMaxLimit)
+}
+
+// PageParams holds the parsed limit and offset of a list request
+type PageParams struct {
+	Limit  int
+	Offset int
+}
+
+// ParsePagination reads the limit and offset query parameters. A missing limit uses
+// pagination.default_limit and larger limits are clamped to pagination.max_limit.
+func ParsePagination(r *http.Request) (PageParams, error) {
+	maxLimit := config.GetInt("pagination.max_limit")
+	if maxLimit <= 0 {
+		maxLimit = defaultMaxLimit
+	}
+	params := PageParams{Limit: config.GetInt("pagination.default_limit")}
+	if params.Limit <= 0 {
+		params.Limit = defaultPageLimit
+	}
+
+	query := r.URL.Query()
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 {
+			return PageParams{}, fmt.Errorf("limit must be a positive integer, got %q", raw)
+		}
+		params.Limit = limit
+	}
+	if raw := query.Get("offset"); raw != "" {
+		offset, err := strconv.Atoi(raw)
+		if err != nil || offset < 0 {
+			return PageParams{}, fmt.Errorf("offset must be a non-negative integer, got %q", raw)
+		}
+		params.Offset = offset
+	}
+
+	if params.Limit > maxLimit {
+		params.Limit = maxLimit
+	}
+	return params, nil
+}
+
+// SetPaginationLinks writes an RFC 5988 Link header with next and prev relations
+// for the page described by params, omitting relations past either end
+func SetPaginationLinks(w http.ResponseWriter, r *http.Request, params PageParams, total int) {
+	var links []string
+
+	if next := params.Offset + params.Limit; next < total {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, pageURL(r, params.Limit, next)))
+	}
+	if params.Offset > 0 {
+		prev := params.Offset - params.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, pageURL(r, params.Limit, prev)))
+	}
+
+	if len(links) > 0 {
+		w.Header().Set("Link", strings.Join(links, ", "))
+	}
+}
+
+// pageURL returns the request URL with its limit and offset replaced
+func pageURL(r *http.Request, limit, offset int) string {
+	query := r.URL.Query()
+	query.Set("limit", strconv.Itoa(limit))
+	query.Set("offset", strconv.Itoa(offset))
+
+	u := *r.URL
+	u.RawQuery = query.Encode()
+	return u.RequestURI()
+}
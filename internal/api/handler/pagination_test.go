@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/config"
+)
+
+func TestParsePagination(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected PageParams
+		wantErr  bool
+	}{
+		{"defaults", "", PageParams{Limit: defaultPageLimit, Offset: 0}, false},
+		{"explicit values", "?limit=5&offset=10", PageParams{Limit: 5, Offset: 10}, false},
+		{"limit clamped to max", "?limit=1000", PageParams{Limit: defaultMaxLimit, Offset: 0}, false},
+		{"zero limit", "?limit=0", PageParams{}, true},
+		{"negative offset", "?offset=-1", PageParams{}, true},
+		{"non-numeric limit", "?limit=ten", PageParams{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/models"+tt.query, nil)
+
+			params, err := ParsePagination(req)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("ParsePagination() should return an error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParsePagination() error = %v", err)
+			}
+			if params != tt.expected {
+				t.Errorf("ParsePagination() = %+v, want %+v", params, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParsePagination_ConfigBounds(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+	config.SetForTest("pagination.default_limit", 7)
+	config.SetForTest("pagination.max_limit", 25)
+
+	req := httptest.NewRequest(http.MethodGet, "/models", nil)
+	if params, _ := ParsePagination(req); params.Limit != 7 {
+		t.Errorf("Expected configured default limit 7, got %d", params.Limit)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/models?limit=50", nil)
+	if params, _ := ParsePagination(req); params.Limit != 25 {
+		t.Errorf("Expected limit clamped to configured max 25, got %d", params.Limit)
+	}
+}
+
+func TestSetPaginationLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		params   PageParams
+		total    int
+		expected string
+	}{
+		{"first page", PageParams{Limit: 10, Offset: 0}, 25, `</models?limit=10&offset=10&q=x>; rel="next"`},
+		{"middle page", PageParams{Limit: 10, Offset: 10}, 25, `</models?limit=10&offset=20&q=x>; rel="next", </models?limit=10&offset=0&q=x>; rel="prev"`},
+		{"last page", PageParams{Limit: 10, Offset: 20}, 25, `</models?limit=10&offset=10&q=x>; rel="prev"`},
+		{"prev clamped at zero", PageParams{Limit: 10, Offset: 5}, 15, `</models?limit=10&offset=0&q=x>; rel="prev"`},
+		{"single page", PageParams{Limit: 10, Offset: 0}, 10, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/models?q=x", nil)
+			rec := httptest.NewRecorder()
+
+			SetPaginationLinks(rec, req, tt.params, tt.total)
+
+			if got := rec.Header().Get("Link"); got != tt.expected {
+				t.Errorf("Link header = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// routePattern returns the ServeMux pattern a route is mounted under. Method-qualified
+// patterns let several methods share a path such as /v1/jobs/{id}.
+func routePattern(route types.RouteInfo) string {
+	return strings.ToUpper(route.Method) + " " + route.Path
+}
+
+// ValidateRoutePatterns reports routes whose path templates the ServeMux cannot tell
+// apart. A literal segment such as /v1/jobs/stats is more specific than a wildcard
+// such as /v1/jobs/{id}, so the two coexist and the literal one wins. Patterns that
+// match the same requests with neither more specific, like /v1/{kind}/latest and
+// /v1/jobs/{id}, or the same pattern registered twice, are rejected.
+func ValidateRoutePatterns(routes []types.RouteInfo) error {
+	mux := http.NewServeMux()
+	var conflicts []string
+	for _, route := range routes {
+		if err := mountRoute(mux, routePattern(route), http.NotFound); err != nil {
+			conflicts = append(conflicts, fmt.Sprintf("%s %s (%s module): %v", route.Method, route.Path, route.Module, err))
+		}
+	}
+	if len(conflicts) > 0 {
+		return fmt.Errorf("ambiguous route registrations:\n  %s", strings.Join(conflicts, "\n  "))
+	}
+	return nil
+}
+
+// mountRoute registers handler under pattern, returning the error ServeMux panics
+// with for invalid or conflicting patterns
+func mountRoute(mux *http.ServeMux, pattern string, handler http.HandlerFunc) (err error) {
+	defer func() {
+		if recovered := recover(); recovered != nil {
+			err = fmt.Errorf("%v", recovered)
+		}
+	}()
+	mux.HandleFunc(pattern, handler)
+	return nil
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// pathErrorResponse matches the ErrorResponse schema handlers write for bad path parameters
+type pathErrorResponse struct {
+	Error   bool   `json:"error"`
+	Message string `json:"message"`
+	Status  int    `json:"status"`
+}
+
+// widgetHandler echoes the route template and the typed {id} parameter, answering
+// a malformed id with the shared 400 ErrorResponse
+func widgetHandler(parse func(r *http.Request) (interface{}, error)) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, err := parse(r)
+		if err != nil {
+			var paramErr *types.PathParamError
+			if !errors.As(err, &paramErr) {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+			types.WritePathParamError(w, err)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"route": types.RouteTemplate(r), "id": id})
+	}
+}
+
+// serveWidget mounts routes on a fresh ServeMux and serves a GET for path
+func serveWidget(t *testing.T, routes []types.RouteInfo, path string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	mux := http.NewServeMux()
+	for _, route := range routes {
+		if err := mountRoute(mux, routePattern(route), route.Handler); err != nil {
+			t.Fatalf("mountRoute(%s) error = %v", route.Path, err)
+		}
+	}
+	w := httptest.NewRecorder()
+	mux.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+	return w
+}
+
+func TestRoutes_ExactMatchPreferredOverParameter(t *testing.T) {
+	routes := []types.RouteInfo{
+		{Method: "GET", Path: "/v1/widgets/{id}", Handler: widgetHandler(func(r *http.Request) (interface{}, error) {
+			return types.PathValue(r, "id"), nil
+		})},
+		{Method: "GET", Path: "/v1/widgets/stats", Handler: widgetHandler(func(r *http.Request) (interface{}, error) {
+			return "stats", nil
+		})},
+	}
+	if err := ValidateRoutePatterns(routes); err != nil {
+		t.Fatalf("ValidateRoutePatterns() error = %v", err)
+	}
+
+	tests := []struct {
+		path          string
+		expectedRoute string
+		expectedID    string
+	}{
+		{"/v1/widgets/stats", "/v1/widgets/stats", "stats"},
+		{"/v1/widgets/w-42", "/v1/widgets/{id}", "w-42"},
+	}
+	for _, tt := range tests {
+		w := serveWidget(t, routes, tt.path)
+
+		var body map[string]interface{}
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("%s: invalid response %q: %v", tt.path, w.Body.String(), err)
+		}
+		if body["route"] != tt.expectedRoute || body["id"] != tt.expectedID {
+			t.Errorf("%s matched route %v with id %v, expected %s with id %s", tt.path, body["route"], body["id"], tt.expectedRoute, tt.expectedID)
+		}
+	}
+}
+
+func TestValidateRoutePatterns_RejectsAmbiguous(t *testing.T) {
+	tests := []struct {
+		name   string
+		routes []types.RouteInfo
+	}{
+		{"overlapping wildcards", []types.RouteInfo{
+			{Method: "GET", Path: "/v1/{kind}/latest", Module: "a"},
+			{Method: "GET", Path: "/v1/jobs/{id}", Module: "b"},
+		}},
+		{"duplicate registration", []types.RouteInfo{
+			{Method: "GET", Path: "/v1/jobs/{id}", Module: "a"},
+			{Method: "GET", Path: "/v1/jobs/{job}", Module: "b"},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRoutePatterns(tt.routes)
+			if err == nil {
+				t.Fatal("ValidateRoutePatterns() should reject ambiguous routes")
+			}
+			if !strings.Contains(err.Error(), "GET /v1/jobs/") || !strings.Contains(err.Error(), "(b module)") {
+				t.Errorf("Expected the error to name the conflicting route, got %v", err)
+			}
+		})
+	}
+
+	methods := []types.RouteInfo{
+		{Method: "GET", Path: "/v1/jobs/{id}"},
+		{Method: "DELETE", Path: "/v1/jobs/{id}"},
+	}
+	if err := ValidateRoutePatterns(methods); err != nil {
+		t.Errorf("Routes differing by method should not conflict, got %v", err)
+	}
+}
+
+func TestRoutes_TypedPathParamFailures(t *testing.T) {
+	pathID := func(r *http.Request) (interface{}, error) { return types.PathID(r, "id", "wid_") }
+	tests := []struct {
+		name    string
+		parse   func(r *http.Request) (interface{}, error)
+		path    string
+		message string
+	}{
+		{"integer", func(r *http.Request) (interface{}, error) { return types.PathInt(r, "id") }, "/v1/widgets/abc", `path parameter id must be an integer, got "abc"`},
+		{"uuid", func(r *http.Request) (interface{}, error) { return types.PathUUID(r, "id") }, "/v1/widgets/42", `path parameter id must be a UUID, got "42"`},
+		{"wrong prefix", pathID, "/v1/widgets/job_" + strings.Repeat("0", 32), `path parameter id must be a wid_ identifier, got "job_00000000000000000000000000000000"`},
+		{"short", pathID, "/v1/widgets/wid_42", `path parameter id must be a wid_ identifier, got "wid_42"`},
+		{"not hex", pathID, "/v1/widgets/wid_" + strings.Repeat("z", 32), `path parameter id must be a wid_ identifier, got "wid_zzzzzzzzzzzzzzzzzzzzzzzzzzzzzzzz"`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routes := []types.RouteInfo{{Method: "GET", Path: "/v1/widgets/{id}", Handler: widgetHandler(tt.parse)}}
+			w := serveWidget(t, routes, tt.path)
+
+			if w.Code != http.StatusBadRequest {
+				t.Fatalf("Expected status 400, got %d", w.Code)
+			}
+			var body pathErrorResponse
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Expected an ErrorResponse body, got %q", w.Body.String())
+			}
+			expected := pathErrorResponse{Error: true, Message: tt.message, Status: http.StatusBadRequest}
+			if body != expected {
+				t.Errorf("ErrorResponse = %+v, expected %+v", body, expected)
+			}
+		})
+	}
+}
+
+func TestRoutes_TypedPathParams(t *testing.T) {
+	routes := []types.RouteInfo{{Method: "GET", Path: "/v1/widgets/{id}", Handler: widgetHandler(func(r *http.Request) (interface{}, error) {
+		return types.PathID(r, "id", "wid_")
+	})}}
+	id := "wid_" + strings.Repeat("ab", 16)
+	if w := serveWidget(t, routes, "/v1/widgets/"+id); !strings.Contains(w.Body.String(), `"id":"`+id+`"`) {
+		t.Errorf("Expected PathID to return %s, got %s", id, w.Body.String())
+	}
+
+	routes[0].Handler = widgetHandler(func(r *http.Request) (interface{}, error) { return types.PathInt(r, "id") })
+	if w := serveWidget(t, routes, "/v1/widgets/42"); !strings.Contains(w.Body.String(), `"id":42`) {
+		t.Errorf("Expected PathInt to return 42, got %s", w.Body.String())
+	}
+
+	routes[0].Handler = widgetHandler(func(r *http.Request) (interface{}, error) { return types.PathUUID(r, "id") })
+	uuid := "6ba7b810-9dad-11d1-80b4-00c04fd430c8"
+	if w := serveWidget(t, routes, "/v1/widgets/"+uuid); !strings.Contains(w.Body.String(), `"id":"`+uuid+`"`) {
+		t.Errorf("Expected PathUUID to return %s, got %s", uuid, w.Body.String())
+	}
+}
+
+func TestRouteTemplate_Unmatched(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/v1/widgets/42", nil)
+	if got := types.RouteTemplate(r); got != "/v1/widgets/42" {
+		t.Errorf("RouteTemplate() = %q, expected the URL path for a request no mux matched", got)
+	}
+}
@@ -0,0 +1,27 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/JerkyTreats/llm/internal/logging"
+	"github.com/JerkyTreats/llm/internal/version"
+)
+
+// VersionHandler reports the build the server is running
+type VersionHandler struct{}
+
+// NewVersionHandler creates a new version handler
+func NewVersionHandler() (*VersionHandler, error) {
+	return &VersionHandler{}, nil
+}
+
+// ServeHTTP returns the version, git commit and build date of the running server
+func (h *VersionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+
+	if err := json.NewEncoder(w).Encode(version.Get()); err != nil {
+		logging.Error("Failed to encode version response: %v", err)
+	}
+}
@@ -0,0 +1,48 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/version"
+)
+
+// stubVersion stamps the build information for the duration of a test
+func stubVersion(t *testing.T, v, commit, date string) {
+	t.Helper()
+	previous := [3]string{version.Version, version.Commit, version.BuildDate}
+	version.Version, version.Commit, version.BuildDate = v, commit, date
+	t.Cleanup(func() {
+		version.Version, version.Commit, version.BuildDate = previous[0], previous[1], previous[2]
+	})
+}
+
+func TestVersionHandler_ReturnsBuildInfo(t *testing.T) {
+	stubVersion(t, "1.4.0", "0123abcd", "2025-01-15T10:30:00Z")
+
+	h, err := NewVersionHandler()
+	if err != nil {
+		t.Fatalf("NewVersionHandler() error = %v", err)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/version", nil))
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	if contentType := w.Header().Get("Content-Type"); contentType != "application/json" {
+		t.Errorf("Content-Type = %q, expected application/json", contentType)
+	}
+
+	var body version.BuildInfo
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid version response %q: %v", w.Body.String(), err)
+	}
+	expected := version.BuildInfo{Version: "1.4.0", Commit: "0123abcd", BuildDate: "2025-01-15T10:30:00Z", GoVersion: runtime.Version()}
+	if body != expected {
+		t.Errorf("Version response = %+v, expected %+v", body, expected)
+	}
+}
@@ -0,0 +1,37 @@
+package types
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/JerkyTreats/llm/internal/logging"
+)
+
+// ErrorResponse is the error body matching the ErrorResponse schema documented for
+// every route. Categories is set only for content blocked by the moderation
+// policy, naming the policy categories it matched.
+type ErrorResponse struct {
+	Error      bool     `json:"error"`
+	Message    string   `json:"message"`
+	Status     int      `json:"status"`
+	Categories []string `json:"categories,omitempty"`
+}
+
+// NewErrorResponse returns the ErrorResponse for status with message
+func NewErrorResponse(status int, message string) ErrorResponse {
+	return ErrorResponse{Error: true, Message: message, Status: status}
+}
+
+// WriteError writes an ErrorResponse body with status and message
+func WriteError(w http.ResponseWriter, status int, message string) {
+	WriteErrorResponse(w, NewErrorResponse(status, message))
+}
+
+// WriteErrorResponse writes body as a JSON response with body.Status
+func WriteErrorResponse(w http.ResponseWriter, body ErrorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(body.Status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logging.Error("Failed to encode error response: %v", err)
+	}
+}
@@ -0,0 +1,34 @@
+package types
+
+// Paginated is the shared response wrapper for list endpoints
+type Paginated[T any] struct {
+	Items      []T  `json:"items"`
+	Total      int  `json:"total"`
+	Limit      int  `json:"limit"`
+	Offset     int  `json:"offset"`
+	NextOffset *int `json:"next_offset,omitempty"` // Omitted on the last page
+}
+
+// PaginatedResponse is the page returned by routes documenting a Pagination style
+type PaginatedResponse[T any] struct {
+	Data  []T `json:"data"`
+	Total int `json:"total"`
+
+	// NextCursor is passed back as the cursor or after parameter by cursor and
+	// keyset paginated lists; omitted on the last page and by offset lists
+	NextCursor string `json:"nextCursor,omitempty"`
+}
+
+// NewPaginated wraps a page of items, computing the next offset from the total
+func NewPaginated[T any](items []T, total, limit, offset int) Paginated[T] {
+	page := Paginated[T]{
+		Items:  items,
+		Total:  total,
+		Limit:  limit,
+		Offset: offset,
+	}
+	if next := offset + limit; limit > 0 && next < total {
+		page.NextOffset = &next
+	}
+	return page
+}
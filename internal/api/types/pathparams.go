@@ -0,0 +1,83 @@
+package types
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// PathParamError reports a path parameter that does not parse as the type a
+// handler expects. Handlers respond to it with a 400 ErrorResponse.
+type PathParamError struct {
+	Name     string // Parameter name from the path template, e.g. id
+	Value    string // Raw value matched in the request path
+	Expected string // Expected type, e.g. "an integer"
+}
+
+func (e *PathParamError) Error() string {
+	return fmt.Sprintf("path parameter %s must be %s, got %q", e.Name, e.Expected, e.Value)
+}
+
+// PathValue returns the value matched by the {name} wildcard of the route's path
+// template, or "" when the template has no such wildcard
+func PathValue(r *http.Request, name string) string {
+	return r.PathValue(name)
+}
+
+// PathInt returns the {name} path parameter as an integer
+func PathInt(r *http.Request, name string) (int, error) {
+	raw := r.PathValue(name)
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return 0, &PathParamError{Name: name, Value: raw, Expected: "an integer"}
+	}
+	return value, nil
+}
+
+// PathUUID returns the {name} path parameter as a UUID
+func PathUUID(r *http.Request, name string) (uuid.UUID, error) {
+	raw := r.PathValue(name)
+	value, err := uuid.Parse(raw)
+	if err != nil {
+		return uuid.Nil, &PathParamError{Name: name, Value: raw, Expected: "a UUID"}
+	}
+	return value, nil
+}
+
+// PathID returns the {name} path parameter as an identifier minted with prefix,
+// such as job_ followed by 32 hex digits
+func PathID(r *http.Request, name, prefix string) (string, error) {
+	raw := r.PathValue(name)
+	digits, ok := strings.CutPrefix(raw, prefix)
+	if !ok || len(digits) != 32 {
+		return "", &PathParamError{Name: name, Value: raw, Expected: "a " + prefix + " identifier"}
+	}
+	if _, err := hex.DecodeString(digits); err != nil {
+		return "", &PathParamError{Name: name, Value: raw, Expected: "a " + prefix + " identifier"}
+	}
+	return raw, nil
+}
+
+// WritePathParamError answers a request whose path parameter did not parse with
+// a 400 ErrorResponse naming the parameter
+func WritePathParamError(w http.ResponseWriter, err error) {
+	WriteError(w, http.StatusBadRequest, err.Error())
+}
+
+// RouteTemplate returns the path template of the route that matched r, such as
+// /v1/sessions/{id}, so logs and metrics group requests by route rather than by
+// raw path. Requests that did not come through a ServeMux return their URL path.
+func RouteTemplate(r *http.Request) string {
+	if r.Pattern == "" {
+		return r.URL.Path
+	}
+	// Patterns are "[METHOD ][HOST]/path"; only the path is the template
+	if i := strings.Index(r.Pattern, "/"); i >= 0 {
+		return r.Pattern[i:]
+	}
+	return r.Pattern
+}
@@ -4,6 +4,7 @@ import (
 	"net/http"
 	"reflect"
 	"sync"
+	"time"
 
 	"github.com/JerkyTreats/llm/internal/logging"
 )
@@ -15,10 +16,136 @@ type RouteInfo struct {
 	Handler      http.HandlerFunc // Handler function
 	RequestType  reflect.Type     // Request body type (nil for GET)
 	ResponseType reflect.Type     // Success response type
+	QueryType    reflect.Type     // Struct whose query-tagged fields are query parameters, e.g. query:"tags,style=form,explode=false"
+	HeaderParams []HeaderParam    // Request headers the route reads; required ones are enforced before the handler runs
 	Module       string           // Module name for documentation grouping
 	Summary      string           // Optional operation summary
+	Tags         []string         // Optional operation tags (defaults to Module)
+	Security     []string         // Security schemes, any one of which authorizes the route, e.g. "BearerAuth"
+	Deprecated   bool             // Marks the operation as deprecated
+	SunsetDate   string           // Planned removal date in RFC 3339 full-date format (2025-12-31)
+
+	RequestContentType   string                  // Request body media type (defaults to application/json)
+	FormFields           map[string]string       // multipart/form-data fields by name: "string", "integer", "number", "boolean" or "binary" for files
+	MaxBodyBytes         int64                   // Request body size limit for multipart parsing (0 uses DefaultMaxBodyBytes)
+	Paginated            bool                    // Documents the limit and offset query parameters of list endpoints
+	Pagination           *PaginationInfo         // Pagination style; wraps ResponseType items in a PaginatedResponse unless ResponseType already is a page
+	RateLimit            *RateLimitInfo          // Optional rate-limiting policy to document
+	Cacheable            bool                    // Enables ETag and If-None-Match handling for GET responses
+	CacheMaxAge          time.Duration           // Cache-Control max-age for cacheable routes (0 sends no-cache)
+	Streaming            bool                    // Streams its response; never buffered for ETag computation
+	Idempotent           bool                    // Accepts an Idempotency-Key header and replays the first response for duplicate keys
+	ConcurrencyGroup     string                  // Shares the limits.<group> concurrency limit with other routes, e.g. "completions"
+	ResponseFormat       string                  // Success response format: ResponseFormatJSON (default), ResponseFormatFile, ResponseFormatCSV or ResponseFormatImage
+	ResponseContentType  string                  // Success media type, e.g. "text/csv" or "text/plain"; overrides ResponseFormat's (defaults to application/json)
+	Callbacks            map[string]CallbackInfo // Out-of-band requests the API sends to client-supplied URLs, keyed by callback name such as "jobCompleted"
+	Visibility           string                  // VisibilityPublic (default) or VisibilityInternal
+	SuccessStatus        int                     // Status code of the success response (defaults to 200)
+	ErrorStatuses        []int                   // Additional error statuses returned with the route's error body (plain text for documentation routes)
+	ErrorResponseType    reflect.Type            // Error body type for error responses (defaults to ErrorResponse)
+	Examples             map[string]ExampleValue // Named request body examples, e.g. "admin-user"
+	ResponseExamples     map[string]ExampleValue // Named success response examples
+	ResponseLinks        map[string]Link         // Operations reachable with values from the success response, by link name
+	DocumentationRoute   bool                    // Serves docs content with plain-text errors; only the success response is documented
+	ResponseContentTypes []string                // Success media types of a DocumentationRoute, which ignores ResponseFormat and ResponseContentType (defaults to text/html)
+	HandlerFuncName      string                  // Handler whose doc comment documents the route: "pkg.Func" or "pkg.Type.Method" under internal/, or an import path such as "example.com/app/users.GetUser"
+}
+
+// Pagination styles supported by PaginationInfo
+const (
+	PaginationOffset = "offset" // page and pageSize parameters
+	PaginationCursor = "cursor" // opaque cursor and limit parameters
+	PaginationKeyset = "keyset" // after, before and limit parameters
+)
+
+// ConcurrencyGroupCompletions groups the routes that call the upstream provider,
+// limited by limits.completions
+const ConcurrencyGroupCompletions = "completions"
+
+// Route visibilities; internal routes are left out of the public spec
+const (
+	VisibilityPublic   = "public"
+	VisibilityInternal = "internal"
+)
+
+// Response formats supported by RouteInfo.ResponseFormat
+const (
+	ResponseFormatJSON  = "json"  // JSON body described by ResponseType
+	ResponseFormatFile  = "file"  // Arbitrary binary download (application/octet-stream)
+	ResponseFormatCSV   = "csv"   // CSV export (text/csv)
+	ResponseFormatImage = "image" // Image of any type (image/*)
+)
+
+// HeaderParam describes a request header a route reads, such as X-Org-ID
+type HeaderParam struct {
+	Name        string // Header name, e.g. "X-Org-ID"
+	Type        string // Value type: "string" (default), "integer", "number" or "boolean"
+	Required    bool   // Requests without the header are rejected with 400
+	Description string // Optional description of what the header controls
+}
+
+// PaginationInfo describes how a list route pages through its results
+type PaginationInfo struct {
+	Style string // One of PaginationOffset, PaginationCursor or PaginationKeyset
+}
+
+// CallbackInfo describes the requests the API makes back to the client, such as a
+// job completion notification sent to a callback URL from the original request
+type CallbackInfo struct {
+	Expression string   // Runtime expression for the target URL, e.g. "{$request.body#/callback_url}"
+	PathItem   PathItem // Requests sent to the target URL
+}
+
+// PathItem describes the requests sent to a callback URL by HTTP method
+type PathItem struct {
+	Get    *CallbackOperation
+	Post   *CallbackOperation
+	Put    *CallbackOperation
+	Delete *CallbackOperation
+}
+
+// CallbackOperation describes one request the API sends to a callback URL
+type CallbackOperation struct {
+	Summary      string         // Optional description of when the callback fires
+	HeaderParams []HeaderParam  // Headers sent with the request, such as a signature
+	RequestType  reflect.Type   // JSON body sent to the callback URL
+	Responses    map[int]string // Statuses the receiver may answer with, to their descriptions (defaults to any 2XX)
+}
+
+// ExampleValue is a named example of a request or response body
+type ExampleValue struct {
+	Summary     string      // Short label shown in example pickers
+	Description string      // Optional longer explanation
+	Value       interface{} // Example body, encoded with its JSON field names
+}
+
+// Link describes how values from a route's response feed another operation, such as
+// the id returned by createUser becoming the id parameter of getUser
+type Link struct {
+	OperationID string            // operationId of the target operation
+	Parameters  map[string]string // Target parameter name to runtime expression, e.g. "id": "$response.body#/id"
+	Description string            // Optional explanation of the relationship
 }
 
+// RateLimitInfo describes the rate-limiting policy applied to a route
+type RateLimitInfo struct {
+	Requests  int    // Requests allowed per window
+	Per       string // Window unit, e.g. "second" or "minute"
+	BurstSize int    // Requests allowed in a burst above the steady rate
+}
+
+// Request body media types and form field types understood by the spec generator
+const (
+	ContentTypeJSON          = "application/json"
+	ContentTypeMultipartForm = "multipart/form-data"
+
+	// FormFieldBinary marks a multipart form field as a file upload
+	FormFieldBinary = "binary"
+
+	// DefaultMaxBodyBytes limits request bodies for routes that do not set MaxBodyBytes
+	DefaultMaxBodyBytes int64 = 10 << 20
+)
+
 var (
 	// routeRegistry holds all registered routes
 	routeRegistry []RouteInfo
@@ -48,6 +175,20 @@ func GetRegisteredRoutes() []RouteInfo {
 	return routes
 }
 
+// GetRoutesByModule returns a copy of all registered routes belonging to module
+func GetRoutesByModule(module string) []RouteInfo {
+	registryMutex.RLock()
+	defer registryMutex.RUnlock()
+
+	var routes []RouteInfo
+	for _, route := range routeRegistry {
+		if route.Module == module {
+			routes = append(routes, route)
+		}
+	}
+	return routes
+}
+
 // UpdateRouteRegistry updates the entire route registry (used by handler registry)
 func UpdateRouteRegistry(routes []RouteInfo) {
 	registryMutex.Lock()
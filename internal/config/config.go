@@ -3,7 +3,10 @@
 package config
 
 import (
+	"fmt"
 	"os" // Added for ToUpper
+	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/spf13/viper"
@@ -14,10 +17,18 @@ const (
 	LogLevelKey = "log_level"
 )
 
+// Config file formats accepted by SetConfigFormat
+const (
+	FormatJSON = "json"
+	FormatYAML = "yaml"
+	FormatTOML = "toml"
+)
+
 var (
 	config            *viper.Viper
 	configOnce        sync.Once
 	configPath        string
+	configFormat      string
 	requiredKeys      []string
 	requiredKeysMutex sync.Mutex
 	// Replace global variable with a slice to track missing required keys
@@ -29,17 +40,71 @@ func SetConfigPath(path string) {
 	configPath = path
 }
 
+// SetConfigFormat parses the config file as format (json, yaml or toml) instead of
+// inferring it from the file extension; "" restores inference. Files without an
+// extension are read as JSON.
+func SetConfigFormat(format string) error {
+	switch strings.ToLower(format) {
+	case "":
+		configFormat = ""
+	case FormatJSON:
+		configFormat = FormatJSON
+	case FormatYAML, "yml":
+		configFormat = FormatYAML
+	case FormatTOML:
+		configFormat = FormatTOML
+	default:
+		return fmt.Errorf("unsupported config format %q, expected json, yaml or toml", format)
+	}
+	return nil
+}
+
+// defaultConfigFile returns the extensionless $HOME/.llm/config, read as JSON, when
+// that directory holds no config file with an extension. Viper only searches for
+// config.{json,yaml,yml,toml} on its own.
+func defaultConfigFile() string {
+	dir := os.ExpandEnv("$HOME/.llm")
+	for _, ext := range viper.SupportedExts {
+		if _, err := os.Stat(filepath.Join(dir, "config."+ext)); err == nil {
+			return ""
+		}
+	}
+	file := filepath.Join(dir, "config")
+	if info, err := os.Stat(file); err != nil || info.IsDir() {
+		return ""
+	}
+	return file
+}
+
+// configType returns the viper config type for file, or "" to infer it from the
+// extension. Without a file, config.{json,yaml,yml,toml} is searched for.
+func configType(file string) string {
+	if configFormat != "" {
+		return configFormat
+	}
+	if file != "" && filepath.Ext(file) == "" {
+		return FormatJSON
+	}
+	return ""
+}
+
 // loadConfig initializes viper and loads config from file and env.
 func loadConfig() (*viper.Viper, error) {
 	v := viper.New()
-	v.SetConfigType("json")
 	v.SetConfigName("config")
 	v.AddConfigPath(os.ExpandEnv("$HOME/.llm"))
-	if configPath != "" {
-		v.SetConfigFile(configPath)
+	file := configPath
+	if file == "" {
+		file = defaultConfigFile()
+	}
+	if file != "" {
+		v.SetConfigFile(file)
+	}
+	if format := configType(file); format != "" {
+		v.SetConfigType(format)
 	}
 	v.AutomaticEnv()
-	v.SetDefault(LogLevelKey, "INFO")
+	applyDefaults(v)
 	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
 			// File not found: return viper instance with defaults
@@ -104,6 +169,15 @@ func GetBool(key string) bool {
 	return config.GetBool(key)
 }
 
+// GetFloat64 returns a float64 config value.
+func GetFloat64(key string) float64 {
+	_ = initConfig()
+	if config == nil {
+		return 0
+	}
+	return config.GetFloat64(key)
+}
+
 // GetStringMapString returns a map[string]string config value.
 func GetStringMapString(key string) map[string]string {
 	_ = initConfig()
@@ -113,6 +187,15 @@ func GetStringMapString(key string) map[string]string {
 	return config.GetStringMapString(key)
 }
 
+// UnmarshalKey decodes the config value at key into out, leaving out untouched if the key is unset.
+func UnmarshalKey(key string, out interface{}) error {
+	_ = initConfig()
+	if config == nil || !config.IsSet(key) {
+		return nil
+	}
+	return config.UnmarshalKey(key, out)
+}
+
 // RegisterRequiredKey adds a key to the list of required configuration items.
 // This should be called during the init() phase of packages that require specific configurations.
 func RegisterRequiredKey(key string) {
@@ -140,6 +223,23 @@ func HasKey(key string) bool {
 	return config.IsSet(key)
 }
 
+// ConfigFileUsed returns the path of the loaded config file, or "" when the
+// configuration comes only from defaults and the environment.
+func ConfigFileUsed() string {
+	_ = initConfig()
+	if config == nil {
+		return ""
+	}
+	file := config.ConfigFileUsed()
+	if file == "" {
+		return ""
+	}
+	if _, err := os.Stat(file); err != nil {
+		return ""
+	}
+	return file
+}
+
 // SetForTest sets a configuration value for testing purposes only.
 func SetForTest(key string, value interface{}) {
 	_ = initConfig()
@@ -154,6 +254,7 @@ func ResetForTest() {
 	config = nil
 	configOnce = sync.Once{}
 	configPath = ""
+	configFormat = ""
 	requiredKeysMutex.Lock()
 	requiredKeys = nil
 	requiredKeysMutex.Unlock()
@@ -110,3 +110,175 @@ func TestConfigNotFound(t *testing.T) {
 	assert.False(t, GetBool("nonexistent"))
 	assert.Empty(t, GetStringMapString("nonexistent"))
 }
+
+func TestUnmarshalKey(t *testing.T) {
+	// Reset config before test
+	ResetForTest()
+
+	// Create a temporary config file with a list of objects
+	tmpDir := t.TempDir()
+	configFile := filepath.Join(tmpDir, "config.json")
+	configContent := `{"servers": [{"url": "http://a", "description": "A"}, {"url": "http://b", "description": "B"}]}`
+	err := os.WriteFile(configFile, []byte(configContent), 0644)
+	assert.NoError(t, err)
+
+	SetConfigPath(configFile)
+
+	type server struct {
+		URL         string
+		Description string
+	}
+
+	var servers []server
+	assert.NoError(t, UnmarshalKey("servers", &servers))
+	assert.Equal(t, []server{{"http://a", "A"}, {"http://b", "B"}}, servers)
+
+	// Unset keys leave the target untouched
+	var missing []server
+	assert.NoError(t, UnmarshalKey("missing", &missing))
+	assert.Nil(t, missing)
+}
+
+// registerTestSchema registers defaults for a schema section and removes them when the test ends
+func registerTestSchema(t *testing.T) {
+	t.Helper()
+
+	RegisterDefault("schema_test.port", 8080)
+	RegisterDefault("schema_test.host", "localhost")
+	RegisterDefault("schema_test.debug", false)
+	RegisterDefault("schema_test.ratio", 0.5)
+	RegisterKey("schema_test.limit", 0)
+	RegisterKey("schema_test.address", "")
+	t.Cleanup(func() {
+		defaultsMutex.Lock()
+		defer defaultsMutex.Unlock()
+		for key := range schema {
+			if section(key) == "schema_test" {
+				delete(schema, key)
+				delete(defaults, key)
+			}
+		}
+	})
+}
+
+// writeConfigFile writes content to name in a temporary directory and returns its path
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	assert.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
+
+func TestConfigFormats_EquivalentValues(t *testing.T) {
+	files := map[string]string{
+		"config.json": `{"log_level": "WARN", "schema_test": {"port": 9090, "host": "api.internal", "debug": true, "ratio": 0.25}}`,
+		"config.yaml": "log_level: WARN\nschema_test:\n  port: 9090\n  host: api.internal\n  debug: true\n  ratio: 0.25\n",
+		"config.toml": "log_level = \"WARN\"\n\n[schema_test]\nport = 9090\nhost = \"api.internal\"\ndebug = true\nratio = 0.25\n",
+	}
+
+	for name, content := range files {
+		t.Run(name, func(t *testing.T) {
+			ResetForTest()
+			t.Cleanup(ResetForTest)
+			SetConfigPath(writeConfigFile(t, name, content))
+			registerTestSchema(t)
+
+			assert.Equal(t, "WARN", GetString(LogLevelKey))
+			assert.Equal(t, 9090, GetInt("schema_test.port"))
+			assert.Equal(t, "api.internal", GetString("schema_test.host"))
+			assert.True(t, GetBool("schema_test.debug"))
+			assert.Equal(t, 0.25, GetFloat64("schema_test.ratio"))
+
+			warnings, err := Validate()
+			assert.NoError(t, err)
+			assert.Empty(t, warnings)
+		})
+	}
+}
+
+func TestSetConfigFormat(t *testing.T) {
+	ResetForTest()
+	t.Cleanup(ResetForTest)
+
+	// A TOML file without a .toml extension parses only with the format set explicitly
+	SetConfigPath(writeConfigFile(t, "llm.conf", "log_level = \"ERROR\"\n"))
+	assert.NoError(t, SetConfigFormat("TOML"))
+	assert.Equal(t, "ERROR", GetString(LogLevelKey))
+
+	assert.Error(t, SetConfigFormat("ini"))
+}
+
+func TestDefaultConfigLocation(t *testing.T) {
+	ResetForTest()
+	t.Cleanup(ResetForTest)
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	assert.NoError(t, os.MkdirAll(filepath.Join(home, ".llm"), 0755))
+
+	// The extensionless default file is read as JSON
+	assert.NoError(t, os.WriteFile(filepath.Join(home, ".llm", "config"), []byte(`{"server_port": 9999}`), 0644))
+	assert.Equal(t, "9999", GetString("server_port"))
+
+	// A config file with an extension takes precedence
+	assert.NoError(t, os.WriteFile(filepath.Join(home, ".llm", "config.yaml"), []byte("server_port: 8081\n"), 0644))
+	assert.NoError(t, Reload())
+	assert.Equal(t, "8081", GetString("server_port"))
+}
+
+func TestRegisterDefault(t *testing.T) {
+	ResetForTest()
+	t.Cleanup(ResetForTest)
+	SetConfigPath("/nonexistent/path/config.json")
+	registerTestSchema(t)
+
+	assert.Equal(t, 8080, GetInt("schema_test.port"))
+	assert.Equal(t, "localhost", GetString("schema_test.host"))
+}
+
+func TestValidate_UnknownKeyWarning(t *testing.T) {
+	ResetForTest()
+	t.Cleanup(ResetForTest)
+	SetConfigPath(writeConfigFile(t, "config.yaml", "schema_test:\n  prot: 9090\nunregistered_section:\n  anything: 1\n"))
+	registerTestSchema(t)
+
+	warnings, err := Validate()
+	assert.NoError(t, err)
+	if assert.Len(t, warnings, 1) {
+		assert.Contains(t, warnings[0], `"schema_test.prot"`)
+	}
+}
+
+func TestValidate_TypeMismatch(t *testing.T) {
+	ResetForTest()
+	t.Cleanup(ResetForTest)
+	SetConfigPath(writeConfigFile(t, "config.toml", "[schema_test]\nport = \"eighty\"\ndebug = true\n"))
+	registerTestSchema(t)
+
+	_, err := Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "schema_test.port must be an integer")
+	}
+}
+
+func TestRegisterKey_NoDefault(t *testing.T) {
+	ResetForTest()
+	t.Cleanup(ResetForTest)
+	SetConfigPath("/nonexistent/path/config.json")
+	registerTestSchema(t)
+
+	assert.False(t, HasKey("schema_test.limit"), "RegisterKey should not give the key a default")
+	assert.True(t, HasKey("schema_test.port"), "RegisterDefault should give the key a default")
+}
+
+func TestValidate_NumberForStringKey(t *testing.T) {
+	ResetForTest()
+	t.Cleanup(ResetForTest)
+	SetConfigPath(writeConfigFile(t, "config.toml", "[schema_test]\naddress = 8080\n"))
+	registerTestSchema(t)
+
+	_, err := Validate()
+	assert.NoError(t, err, "numbers should be accepted for string keys read with GetString")
+	assert.Equal(t, "8080", GetString("schema_test.address"))
+}
@@ -0,0 +1,184 @@
+package config
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/spf13/viper"
+)
+
+var (
+	// schema maps registered keys to a value of the type Validate expects config
+	// files to set them to; defaults holds the keys registered with a default
+	schema        = map[string]interface{}{LogLevelKey: ""}
+	defaults      = map[string]interface{}{LogLevelKey: "INFO"}
+	defaultsMutex sync.Mutex
+)
+
+// RegisterKey registers key as read by the caller without giving it a default, so
+// HasKey stays false until the config sets it. example's type is the key's
+// schema: Validate rejects config files setting the key to another type, and
+// warns about unregistered keys in the sections registered keys belong to. Map
+// and list examples accept any value nested under the key.
+// This should be called during the init() phase of packages that read the key.
+func RegisterKey(key string, example interface{}) {
+	defaultsMutex.Lock()
+	defer defaultsMutex.Unlock()
+
+	schema[strings.ToLower(key)] = example
+}
+
+// RegisterDefault registers key like RegisterKey and makes value its default
+func RegisterDefault(key string, value interface{}) {
+	RegisterKey(key, value)
+
+	defaultsMutex.Lock()
+	defaults[strings.ToLower(key)] = value
+	defaultsMutex.Unlock()
+
+	_ = initConfig()
+	if config != nil {
+		config.SetDefault(key, value)
+	}
+}
+
+// applyDefaults sets every registered default on v
+func applyDefaults(v *viper.Viper) {
+	defaultsMutex.Lock()
+	defer defaultsMutex.Unlock()
+
+	for key, value := range defaults {
+		v.SetDefault(key, value)
+	}
+}
+
+// Validate checks the loaded config file against the registered keys before
+// the server starts. Keys set to a value of a different type than registered
+// are errors. Unregistered keys are returned as warnings, since they are likely
+// typos, but only in top-level sections holding a registered key (top-level
+// scalars count as one section); sections no package registers are not checked.
+func Validate() ([]string, error) {
+	file := ConfigFileUsed()
+	if file == "" {
+		return nil, nil
+	}
+
+	v := viper.New()
+	v.SetConfigFile(file)
+	if format := configType(file); format != "" {
+		v.SetConfigType(format)
+	}
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", file, err)
+	}
+
+	defaultsMutex.Lock()
+	defer defaultsMutex.Unlock()
+
+	sections := make(map[string]bool)
+	for key := range schema {
+		sections[section(key)] = true
+	}
+
+	var warnings, mismatches []string
+	keys := v.AllKeys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		registered, ok := registeredDefault(key)
+		if !ok {
+			if sections[section(key)] {
+				warnings = append(warnings, fmt.Sprintf("unknown config key %q in %s", key, file))
+			}
+			continue
+		}
+		if value := v.Get(key); !sameKind(registered, value) {
+			mismatches = append(mismatches, fmt.Sprintf("%s must be %s, got %v (%T)", key, kindName(registered), value, value))
+		}
+	}
+
+	if len(mismatches) > 0 {
+		return warnings, fmt.Errorf("invalid config %s: %s", file, strings.Join(mismatches, "; "))
+	}
+	return warnings, nil
+}
+
+// section returns the top-level section of a dotted key, or "" for top-level scalars
+func section(key string) string {
+	if head, _, found := strings.Cut(key, "."); found {
+		return head
+	}
+	return ""
+}
+
+// registeredDefault returns the example registered for key, or for the registered
+// map or list key it is nested under. defaultsMutex must be held.
+func registeredDefault(key string) (interface{}, bool) {
+	for candidate := key; ; {
+		if value, ok := schema[candidate]; ok {
+			if candidate == key {
+				return value, true
+			}
+			// Values nested under a registered key are part of that key's value
+			return nil, true
+		}
+		i := strings.LastIndex(candidate, ".")
+		if i < 0 {
+			return nil, false
+		}
+		candidate = candidate[:i]
+	}
+}
+
+// sameKind reports whether a value parsed from a config file has the type of a
+// registered example. JSON numbers parse as floats, so integral floats satisfy
+// integer keys; string keys accept numbers, which GetString converts, such as
+// server_port: 8080; a nil example accepts anything.
+func sameKind(registered, value interface{}) bool {
+	if registered == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(value)
+	switch reflect.TypeOf(registered).Kind() {
+	case reflect.String:
+		return rv.Kind() == reflect.String || rv.CanFloat() || rv.CanInt() || rv.CanUint()
+	case reflect.Bool:
+		return rv.Kind() == reflect.Bool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if rv.CanFloat() {
+			return rv.Float() == math.Trunc(rv.Float())
+		}
+		return rv.CanInt() || rv.CanUint()
+	case reflect.Float32, reflect.Float64:
+		return rv.CanFloat() || rv.CanInt() || rv.CanUint()
+	case reflect.Slice, reflect.Array:
+		return rv.Kind() == reflect.Slice || rv.Kind() == reflect.Array
+	case reflect.Map, reflect.Struct:
+		return rv.Kind() == reflect.Map
+	default:
+		return true
+	}
+}
+
+// kindName describes the type of a registered example for error messages
+func kindName(registered interface{}) string {
+	switch reflect.TypeOf(registered).Kind() {
+	case reflect.String:
+		return "a string"
+	case reflect.Bool:
+		return "a boolean"
+	case reflect.Float32, reflect.Float64:
+		return "a number"
+	case reflect.Slice, reflect.Array:
+		return "a list"
+	case reflect.Map, reflect.Struct:
+		return "a table"
+	default:
+		return "an integer"
+	}
+}
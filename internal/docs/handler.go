@@ -1,19 +1,121 @@
 package docs
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"net"
 	"net/http"
+	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 
+	"github.com/JerkyTreats/llm/internal/api/types"
 	"github.com/JerkyTreats/llm/internal/config"
-	"github.com/JerkyTreats/llm/internal/logging"
+	"github.com/JerkyTreats/llm/internal/openapi/oauth"
+	"gopkg.in/yaml.v3"
+)
+
+// specFilePath is the generated OpenAPI spec location relative to the working directory
+const specFilePath = "docs/api/openapi.yaml"
+
+// docsContentTypes overrides extension-based content type detection for static docs
+var docsContentTypes = map[string]string{
+	".yaml": "application/x-yaml",
+	".yml":  "application/x-yaml",
+	".json": "application/json",
+	".md":   "text/markdown; charset=utf-8",
+}
+
+// swaggerConfigPathKey names the config key pointing at the SwaggerConfig YAML file
+const swaggerConfigPathKey = "swagger.config_path"
+
+// defaultSwaggerConfigFile is read when swagger.config_path is not set
+const defaultSwaggerConfigFile = "swagger.yaml"
+
+// docsRootConfigKey overrides the directory ServeDocs serves static files from
+const docsRootConfigKey = "docs.root"
+
+func init() {
+	config.RegisterKey(swaggerConfigPathKey, "")
+	config.RegisterKey(docsRootConfigKey, "")
+	config.RegisterKey(preauthKeyNameConfigKey, "")
+	config.RegisterKey(preauthKeyPlaceholderConfigKey, "")
+	config.RegisterKey(maxRequestsPerSecondConfigKey, 0.0)
+	config.RegisterKey(metricsEnabledConfigKey, false)
+	config.RegisterKey("server.host", "")
+	config.RegisterKey("server.port", 0)
+}
+
+// Swagger UI assets, loaded from SwaggerConfig.AssetsURL and shared by the HTML
+// template, HTTP/2 push and the Content-Security-Policy
+const (
+	defaultSwaggerUIAssetsURL = "https://unpkg.com/swagger-ui-dist@5.9.0"
+	swaggerUICSS              = "swagger-ui.css"
+	swaggerUIBundle           = "swagger-ui-bundle.js"
+	swaggerUIStandalone       = "swagger-ui-standalone-preset.js"
 )
 
 // DocsHandler serves Swagger UI and OpenAPI specifications
 type DocsHandler struct {
 	swaggerConfig SwaggerConfig
+
+	// oauth2 configures Swagger UI sign-in from the same docs.oauth2 block the
+	// generator emits the OAuth2 security scheme from
+	oauth2 oauth.Config
+
+	// specCache holds the spec file bytes and when they were read; specJSONCache
+	// holds their JSON conversion once a JSON request needs it
+	specCache     []byte
+	specJSONCache []byte
+	specCachedAt  time.Time
+	specMutex     sync.Mutex
+
+	// customEndpoints maps URL paths registered with RegisterCustomEndpoint to files
+	customEndpoints map[string]customEndpoint
+	customMutex     sync.RWMutex
+
+	// metrics counts requests, latency and spec cache lookups per endpoint for
+	// Metrics and ServeMetricsEndpoint
+	metrics requestMetrics
+	// recorder receives the same requests and spec cache hits for export; nil discards them
+	recorder MetricsRecorder
+
+	// logger receives the handler's log entries; set with WithLogger
+	logger Logger
+
+	// limiter enforces MaxRequestsPerSecond; nil when requests are unlimited
+	limiter *rateLimiter
+}
+
+// customEndpoint is a file served at a custom URL path
+type customEndpoint struct {
+	filePath    string
+	contentType string
+}
+
+// DocsHealthResponse represents the JSON response for docs subsystem health checks
+type DocsHealthResponse struct {
+	SpecPresent bool   `json:"spec_present"`
+	Routes      int    `json:"routes"`
+	Error       string `json:"error,omitempty"`
+}
+
+// RouteIndexEntry describes one registered route in the JSON route index
+type RouteIndexEntry struct {
+	Method     string `json:"method"`
+	Path       string `json:"path"`
+	Module     string `json:"module"`
+	Summary    string `json:"summary"`
+	Deprecated bool   `json:"deprecated"`
 }
 
 // SwaggerConfig represents the swagger configuration
@@ -23,21 +125,153 @@ type SwaggerConfig struct {
 	SpecPath  string `yaml:"spec_path"`
 	UITitle   string `yaml:"ui.title"`
 	Theme     string `yaml:"ui.theme"`
+	Push      bool   `yaml:"push"`
+	DocsRoot  string `yaml:"docs_root"`
+
+	// AssetsURL is where the Swagger UI CSS and JavaScript are loaded from: a CDN
+	// by default, or a path such as /docs/swagger-ui for assets served by this
+	// server. The page's Content-Security-Policy allows only this origin.
+	AssetsURL string `yaml:"ui.assets_url"`
+
+	// PersistAuthorization keeps entered credentials across page reloads
+	PersistAuthorization bool `yaml:"ui.persist_authorization"`
+	// WithCredentials sends cookies and auth headers on cross-origin try-it-out requests
+	WithCredentials bool `yaml:"ui.with_credentials"`
+
+	// RequestInterceptorJS and ResponseInterceptorJS are JavaScript function bodies
+	// run on every try-it-out request (as req) and response (as res); each must
+	// return its argument. They are emitted verbatim apart from neutralising
+	// "</script", so callers are responsible for their safety and must never
+	// populate them from untrusted input.
+	RequestInterceptorJS  string `yaml:"ui.request_interceptor_js"`
+	ResponseInterceptorJS string `yaml:"ui.response_interceptor_js"`
+
+	// AdditionalSpecs adds specs to the Swagger UI spec selector next to this
+	// server's spec, which is listed first under UITitle and selected on load
+	AdditionalSpecs []SpecEntry `yaml:"ui.additional_specs"`
+
+	// PreauthKeyName names an API key security scheme to fill in when the page
+	// loads, using PreauthKeyPlaceholder as the value. The placeholder is
+	// rendered into the page, so it must never be a real key.
+	PreauthKeyName        string `yaml:"ui.preauth_key_name"`
+	PreauthKeyPlaceholder string `yaml:"ui.preauth_key_placeholder"`
+
+	// SpecCacheTTL is how long the spec file is served from memory; zero disables caching
+	SpecCacheTTL time.Duration `yaml:"spec_cache_ttl"`
+
+	// MaxRequestsPerSecond limits requests across the docs endpoints, allowing
+	// bursts of up to one second's worth; zero or less disables the limit
+	MaxRequestsPerSecond float64 `yaml:"max_requests_per_second"`
+
+	// MetricsEnabled serves spec request, cache and latency metrics in the
+	// Prometheus text format at /docs/metrics
+	MetricsEnabled bool `yaml:"metrics_enabled"`
+}
+
+// SpecEntry is a spec listed in the Swagger UI spec selector. URLs starting with
+// "/" are resolved against the host the docs page was requested from.
+type SpecEntry struct {
+	Name string `yaml:"name" json:"name"`
+	URL  string `yaml:"url" json:"url"`
 }
 
-// NewDocsHandler creates a new documentation handler
-func NewDocsHandler() (*DocsHandler, error) {
-	swaggerConfig := SwaggerConfig{
+// NewDocsHandler creates a new documentation handler configured from the file named
+// by swagger.config_path (swagger.yaml by default) that reports its requests to
+// recorder, as WithMetricsRecorder does; a nil recorder discards them
+func NewDocsHandler(recorder MetricsRecorder, opts ...HandlerOption) (*DocsHandler, error) {
+	path := config.GetString(swaggerConfigPathKey)
+	if path == "" {
+		path = defaultSwaggerConfigFile
+	}
+	return NewDocsHandlerFromFile(path, append([]HandlerOption{WithMetricsRecorder(recorder)}, opts...)...)
+}
+
+// NewDocsHandlerFromFile creates a documentation handler whose SwaggerConfig is read
+// from the YAML file at path. Fields the file leaves out keep their defaults, and
+// a missing file uses the defaults for everything.
+func NewDocsHandlerFromFile(path string, opts ...HandlerOption) (*DocsHandler, error) {
+	h := &DocsHandler{logger: newDefaultLogger()}
+	for _, opt := range opts {
+		opt(h)
+	}
+
+	swaggerConfig, err := loadSwaggerConfig(path, h.logger)
+	if err != nil {
+		return nil, err
+	}
+
+	if root := config.GetString(docsRootConfigKey); root != "" {
+		swaggerConfig.DocsRoot = root
+	}
+	if name := config.GetString(preauthKeyNameConfigKey); name != "" {
+		swaggerConfig.PreauthKeyName = name
+	}
+	if placeholder := config.GetString(preauthKeyPlaceholderConfigKey); placeholder != "" {
+		swaggerConfig.PreauthKeyPlaceholder = placeholder
+	}
+	if config.HasKey(maxRequestsPerSecondConfigKey) {
+		swaggerConfig.MaxRequestsPerSecond = config.GetFloat64(maxRequestsPerSecondConfigKey)
+	}
+	if config.HasKey(metricsEnabledConfigKey) {
+		swaggerConfig.MetricsEnabled = config.GetBool(metricsEnabledConfigKey)
+	}
+
+	oauth2, err := oauth.Load()
+	if err != nil {
+		return nil, err
+	}
+
+	h.swaggerConfig = swaggerConfig
+	h.oauth2 = oauth2
+	if swaggerConfig.MaxRequestsPerSecond > 0 {
+		h.limiter = newRateLimiter(swaggerConfig.MaxRequestsPerSecond)
+	}
+	return h, nil
+}
+
+// defaultSwaggerConfig returns the SwaggerConfig used when no config file sets a field
+func defaultSwaggerConfig() SwaggerConfig {
+	return SwaggerConfig{
 		Enabled:  true,
 		Path:     "/swagger",
 		SpecPath: "/docs/openapi.yaml",
 		UITitle:  "LLM API Documentation",
 		Theme:    "dark",
+		Push:     false,
+		DocsRoot: "docs",
+
+		AssetsURL: defaultSwaggerUIAssetsURL,
+
+		// Keep entered API keys across reloads; credentials stay same-origin
+		PersistAuthorization: true,
+		WithCredentials:      false,
+
+		// Caching disabled by default so spec regeneration is picked up immediately
+		SpecCacheTTL: 0,
+	}
+}
+
+// loadSwaggerConfig reads a SwaggerConfig file over the defaults. Unknown keys are
+// rejected so typos do not silently fall back to defaults.
+func loadSwaggerConfig(path string, logger Logger) (SwaggerConfig, error) {
+	swaggerConfig := defaultSwaggerConfig()
+
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		logger.Debug("Swagger config file not found, using defaults", "config_path", path)
+		return swaggerConfig, nil
+	}
+	if err != nil {
+		return SwaggerConfig{}, fmt.Errorf("failed to read swagger config %s: %w", path, err)
 	}
 
-	return &DocsHandler{
-		swaggerConfig: swaggerConfig,
-	}, nil
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&swaggerConfig); err != nil && !errors.Is(err, io.EOF) {
+		return SwaggerConfig{}, fmt.Errorf("failed to parse swagger config %s: %w", path, err)
+	}
+
+	return swaggerConfig, nil
 }
 
 // ServeSwaggerUI serves the Swagger UI interface
@@ -47,12 +281,24 @@ func (h *DocsHandler) ServeSwaggerUI(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	logging.Debug("Serving Swagger UI for path: %s", r.URL.Path)
+	h.requestLog(r).Debug("Serving Swagger UI")
+
+	// Generate Swagger UI HTML; the nonce lets only its inline script run
+	nonce := rand.Text()
+	html, err := h.generateSwaggerHTML(r, nonce)
+	if err != nil {
+		h.requestLog(r).Error("Failed to generate Swagger UI", "error", err)
+		http.Error(w, "Failed to generate Swagger UI", http.StatusInternalServerError)
+		return
+	}
 
-	// Generate Swagger UI HTML
-	html := h.generateSwaggerHTML(r)
+	// Push static assets ahead of the HTML when served over HTTP/2
+	if h.swaggerConfig.Push {
+		h.pushSwaggerAssets(w)
+	}
 	
 	w.Header().Set("Content-Type", "text/html; charset=utf-8")
+	w.Header().Set("Content-Security-Policy", h.swaggerUIPolicy(nonce))
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte(html))
 }
@@ -64,46 +310,315 @@ func (h *DocsHandler) ServeOpenAPISpec(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	logging.Debug("Serving OpenAPI spec for path: %s", r.URL.Path)
+	format := specFormat(r)
+	h.requestLog(r).Debug("Serving OpenAPI spec", "format", format)
 
-	// Find the OpenAPI spec file
-	specPath := "docs/api/openapi.yaml"
-	
-	// Check if file exists
-	if _, err := os.Stat(specPath); os.IsNotExist(err) {
-		logging.Warn("OpenAPI spec file not found: %s", specPath)
-		http.Error(w, "OpenAPI specification not found", http.StatusNotFound)
+	// Serve a spec filtered to a single module when requested
+	if module := r.URL.Query().Get("module"); module != "" {
+		h.serveModuleSpec(w, r, module, format)
+		return
+	}
+
+	content, ok := h.readSpec(w, r)
+	if !ok {
 		return
 	}
 
-	// Read and serve the file
+	if format == specFormatJSON {
+		var err error
+		content, err = h.specJSON(content)
+		if err != nil {
+			h.requestLog(r).Error("Failed to convert OpenAPI spec to JSON", "spec_path", h.resolveSpecPath(), "error", err)
+			http.Error(w, "Failed to convert OpenAPI specification", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	writeSpec(w, format, content)
+}
+
+// readSpec returns the spec file content, from the cache while it is fresh. When
+// the file cannot be read it writes the error response and returns false.
+func (h *DocsHandler) readSpec(w http.ResponseWriter, r *http.Request) ([]byte, bool) {
+	content, cached := h.cachedSpec()
+	h.recordCacheLookup(r, cached)
+	if cached {
+		return content, true
+	}
+
+	specPath := h.resolveSpecPath()
 	content, err := os.ReadFile(specPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		h.requestLog(r).Warn("OpenAPI spec file not found", "spec_path", specPath)
+		http.Error(w, "OpenAPI specification not found", http.StatusNotFound)
+		return nil, false
+	}
 	if err != nil {
-		logging.Error("Failed to read OpenAPI spec: %v", err)
+		h.requestLog(r).Error("Failed to read OpenAPI spec", "spec_path", specPath, "error", err)
 		http.Error(w, "Failed to read OpenAPI specification", http.StatusInternalServerError)
+		return nil, false
+	}
+
+	h.storeSpec(content)
+	return content, true
+}
+
+// resolveSpecPath returns the path of the generated OpenAPI spec file
+func (h *DocsHandler) resolveSpecPath() string {
+	return specFilePath
+}
+
+// Healthz reports whether the OpenAPI spec file is present and parseable
+func (h *DocsHandler) Healthz(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
 		return
 	}
 
-	w.Header().Set("Content-Type", "application/x-yaml")
-	w.Header().Set("Access-Control-Allow-Origin", "*") // Allow CORS for Swagger UI
+	response := DocsHealthResponse{}
+	status := http.StatusOK
+
+	routes, err := countSpecOperations(h.resolveSpecPath())
+	if err != nil {
+		h.requestLog(r).Warn("Docs health check failed", "error", err)
+		response.Error = err.Error()
+		status = http.StatusServiceUnavailable
+	} else {
+		response.SpecPresent = true
+		response.Routes = routes
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		h.requestLog(r).Error("Failed to encode docs health response", "error", err)
+	}
+}
+
+// ServeRouteIndex returns every registered route as a JSON array, a lighter
+// alternative to parsing the full OpenAPI spec
+func (h *DocsHandler) ServeRouteIndex(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	routes := types.GetRegisteredRoutes()
+	index := make([]RouteIndexEntry, 0, len(routes))
+	for _, route := range routes {
+		index = append(index, RouteIndexEntry{
+			Method:     route.Method,
+			Path:       route.Path,
+			Module:     route.Module,
+			Summary:    route.Summary,
+			Deprecated: route.Deprecated,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
-	w.Write(content)
+
+	if err := json.NewEncoder(w).Encode(index); err != nil {
+		h.requestLog(r).Error("Failed to encode route index", "error", err)
+	}
+}
+
+// countSpecOperations parses the spec file and returns the number of operations it documents
+func countSpecOperations(specPath string) (int, error) {
+	content, err := os.ReadFile(specPath)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read OpenAPI spec: %w", err)
+	}
+
+	var spec struct {
+		Paths map[string]map[string]interface{} `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(content, &spec); err != nil {
+		return 0, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+
+	// Path items also hold parameters, summary, servers and extensions; only
+	// method keys are operations
+	operations := 0
+	for _, pathItem := range spec.Paths {
+		for key := range pathItem {
+			if httpMethods[strings.ToLower(key)] {
+				operations++
+			}
+		}
+	}
+	return operations, nil
+}
+
+// httpMethods are the path item keys holding an operation
+var httpMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
 }
 
-// generateSwaggerHTML generates the Swagger UI HTML page
-func (h *DocsHandler) generateSwaggerHTML(r *http.Request) string {
+// cachedSpec returns the cached spec bytes if caching is enabled and the entry has not expired
+func (h *DocsHandler) cachedSpec() ([]byte, bool) {
+	if h.swaggerConfig.SpecCacheTTL <= 0 {
+		return nil, false
+	}
+
+	h.specMutex.Lock()
+	defer h.specMutex.Unlock()
+
+	if h.specCache == nil || time.Since(h.specCachedAt) >= h.swaggerConfig.SpecCacheTTL {
+		return nil, false
+	}
+
+	h.logger.Debug("Serving OpenAPI spec from cache")
+	return h.specCache, true
+}
+
+// storeSpec caches the spec bytes when caching is enabled
+func (h *DocsHandler) storeSpec(content []byte) {
+	if h.swaggerConfig.SpecCacheTTL <= 0 {
+		return
+	}
+
+	h.specMutex.Lock()
+	defer h.specMutex.Unlock()
+
+	h.specCache = content
+	h.specJSONCache = nil
+	h.specCachedAt = time.Now()
+}
+
+// specJSON returns the JSON conversion of the spec bytes content. With caching
+// enabled the conversion is kept alongside the cached YAML, so it runs once per
+// cached spec. The cache may be flushed or refilled between a request reading
+// content and converting it, so the JSON is only stored or served while content
+// is still the cached YAML.
+func (h *DocsHandler) specJSON(content []byte) ([]byte, error) {
+	if h.swaggerConfig.SpecCacheTTL <= 0 {
+		return specToJSON(content)
+	}
+
+	h.specMutex.Lock()
+	defer h.specMutex.Unlock()
+
+	current := h.specCache != nil && bytes.Equal(h.specCache, content)
+	if current && h.specJSONCache != nil {
+		return h.specJSONCache, nil
+	}
+
+	converted, err := specToJSON(content)
+	if err != nil {
+		return nil, err
+	}
+	if current {
+		h.specJSONCache = converted
+	}
+	return converted, nil
+}
+
+// FlushSpecCache discards the cached spec so the next request re-reads it from disk
+func (h *DocsHandler) FlushSpecCache() {
+	h.specMutex.Lock()
+	defer h.specMutex.Unlock()
+
+	h.specCache = nil
+	h.specJSONCache = nil
+	h.specCachedAt = time.Time{}
+}
+
+// serveModuleSpec serves the spec file filtered to the operations of module. The
+// spec is filtered rather than regenerated so the server does not link the generator.
+func (h *DocsHandler) serveModuleSpec(w http.ResponseWriter, r *http.Request, module, format string) {
+	routes := types.GetRoutesByModule(module)
+	if len(routes) == 0 {
+		h.requestLog(r).Warn("No routes registered for module", "module", module)
+		http.Error(w, "Module not found", http.StatusNotFound)
+		return
+	}
+
+	content, ok := h.readSpec(w, r)
+	if !ok {
+		return
+	}
+
+	content, err := filterSpecByTags(content, moduleTags(module, routes))
+	if err != nil {
+		h.requestLog(r).Error("Failed to filter OpenAPI spec for module", "module", module, "error", err)
+		http.Error(w, "Failed to filter OpenAPI specification", http.StatusInternalServerError)
+		return
+	}
+
+	if format == specFormatJSON {
+		if content, err = specToJSON(content); err != nil {
+			h.requestLog(r).Error("Failed to convert OpenAPI spec for module to JSON", "module", module, "error", err)
+			http.Error(w, "Failed to convert OpenAPI specification", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	writeSpec(w, format, content)
+}
+
+// pushSwaggerAssets pushes the Swagger UI CSS and JS assets if the writer supports HTTP/2 push
+func (h *DocsHandler) pushSwaggerAssets(w http.ResponseWriter) {
+	pusher, ok := w.(http.Pusher)
+	if !ok {
+		h.logger.Debug("Response writer does not support HTTP/2 push, skipping asset push")
+		return
+	}
+
+	for _, asset := range h.swaggerAssets() {
+		err := pusher.Push(asset, nil)
+		if errors.Is(err, http.ErrNotSupported) {
+			// Wrapped writers expose Push even when the connection cannot push
+			h.logger.Debug("Response writer does not support HTTP/2 push, skipping asset push")
+			return
+		}
+		if err != nil {
+			h.logger.Debug("Failed to push Swagger UI asset", "asset", asset, "error", err)
+		}
+	}
+}
+
+// swaggerAssets returns the URLs of the Swagger UI CSS, bundle and standalone preset
+func (h *DocsHandler) swaggerAssets() []string {
+	return []string{h.assetURL(swaggerUICSS), h.assetURL(swaggerUIBundle), h.assetURL(swaggerUIStandalone)}
+}
+
+// assetURL returns the URL of a Swagger UI asset file under AssetsURL
+func (h *DocsHandler) assetURL(name string) string {
+	return strings.TrimRight(h.assetsURL(), "/") + "/" + name
+}
+
+// assetsURL returns the configured AssetsURL, defaulting to the CDN
+func (h *DocsHandler) assetsURL() string {
+	if h.swaggerConfig.AssetsURL == "" {
+		return defaultSwaggerUIAssetsURL
+	}
+	return h.swaggerConfig.AssetsURL
+}
+
+// generateSwaggerHTML generates the Swagger UI HTML page. nonce must match the
+// page's Content-Security-Policy for its inline script to run.
+func (h *DocsHandler) generateSwaggerHTML(r *http.Request, nonce string) (string, error) {
 	// Determine the current protocol from the request
 	var baseURL string
 	
 	// Log for debugging to understand what's happening with the request
-	logging.Debug("Swagger HTML generation - Host: %s, TLS: %v, URL: %s, X-Forwarded-Proto: %s", 
-		r.Host, r.TLS != nil, r.URL.String(), r.Header.Get("X-Forwarded-Proto"))
+	h.requestLog(r).Debug("Swagger HTML generation", "host", r.Host, "tls", r.TLS != nil,
+		"url", r.URL.String(), "x_forwarded_proto", r.Header.Get("X-Forwarded-Proto"))
 	
-	// Use request host, but provide fallback if empty
-	host := r.Host
+	// Prefer the host the client used when a proxy rewrote Host, then the request host,
+	// and finally the server config
+	host := forwardedHost(r)
+	if host != "" {
+		h.requestLog(r).Debug("Using forwarded host", "host", host)
+	} else {
+		host = r.Host
+	}
 	if host == "" {
 		// Fallback: construct from server config
-		logging.Warn("Request Host header is empty, falling back to server config")
+		h.requestLog(r).Warn("Request Host header is empty, falling back to server config")
 		serverHost := config.GetString("server.host")
 		serverPort := config.GetInt("server.port")
 		
@@ -112,7 +627,7 @@ func (h *DocsHandler) generateSwaggerHTML(r *http.Request) string {
 		} else {
 			host = fmt.Sprintf("%s:%d", serverHost, serverPort)
 		}
-		logging.Debug("Using fallback host: %s", host)
+		h.requestLog(r).Debug("Using fallback host", "host", host)
 	}
 	
 	// Determine if request was made over HTTPS
@@ -120,11 +635,12 @@ func (h *DocsHandler) generateSwaggerHTML(r *http.Request) string {
 	isHTTPS := r.TLS != nil ||
 		r.Header.Get("X-Forwarded-Proto") == "https" ||
 		r.Header.Get("X-Forwarded-Scheme") == "https" ||
-		strings.ToLower(r.Header.Get("X-Forwarded-Ssl")) == "on"
+		strings.ToLower(r.Header.Get("X-Forwarded-Ssl")) == "on" ||
+		strings.ToLower(forwardedParam(r, "proto")) == "https"
 	
-	logging.Debug("HTTPS detection - TLS: %v, X-Forwarded-Proto: %s, X-Forwarded-Scheme: %s, X-Forwarded-Ssl: %s, Final isHTTPS: %v",
-		r.TLS != nil, r.Header.Get("X-Forwarded-Proto"), r.Header.Get("X-Forwarded-Scheme"), 
-		r.Header.Get("X-Forwarded-Ssl"), isHTTPS)
+	h.requestLog(r).Debug("HTTPS detection", "tls", r.TLS != nil,
+		"x_forwarded_proto", r.Header.Get("X-Forwarded-Proto"), "x_forwarded_scheme", r.Header.Get("X-Forwarded-Scheme"),
+		"x_forwarded_ssl", r.Header.Get("X-Forwarded-Ssl"), "forwarded", r.Header.Get("Forwarded"), "https", isHTTPS)
 	
 	if isHTTPS {
 		// Request came via HTTPS, use HTTPS for spec URL
@@ -134,66 +650,120 @@ func (h *DocsHandler) generateSwaggerHTML(r *http.Request) string {
 		baseURL = fmt.Sprintf("http://%s", host)
 	}
 	
-	logging.Debug("Swagger using base URL: %s", baseURL)
-
-	return fmt.Sprintf(`<!DOCTYPE html>
-<html lang="en">
-<head>
-    <meta charset="UTF-8">
-    <title>%s</title>
-    <link rel="stylesheet" type="text/css" href="https://unpkg.com/swagger-ui-dist@5.9.0/swagger-ui.css" />
-    <style>
-        html {
-            box-sizing: border-box;
-            overflow: -moz-scrollbars-vertical;
-            overflow-y: scroll;
-        }
-        *, *:before, *:after {
-            box-sizing: inherit;
-        }
-        body {
-            margin:0;
-            background: #fafafa;
-        }
-        %s
-    </style>
-</head>
-<body>
-    <div id="swagger-ui"></div>
-    <script src="https://unpkg.com/swagger-ui-dist@5.9.0/swagger-ui-bundle.js"></script>
-    <script src="https://unpkg.com/swagger-ui-dist@5.9.0/swagger-ui-standalone-preset.js"></script>
-    <script>
-        window.onload = function() {
-            const ui = SwaggerUIBundle({
-                url: '%s/docs/openapi.yaml',
-                dom_id: '#swagger-ui',
-                deepLinking: true,
-                presets: [
-                    SwaggerUIBundle.presets.apis,
-                    SwaggerUIStandalonePreset
-                ],
-                plugins: [
-                    SwaggerUIBundle.plugins.DownloadUrl
-                ],
-                layout: "StandaloneLayout",
-                docExpansion: "list",
-                defaultModelsExpandDepth: 3,
-                defaultModelExpandDepth: 3,
-                displayRequestDuration: true,
-                filter: true,
-                tryItOutEnabled: true,
-                supportedSubmitMethods: ['get', 'post', 'put', 'delete', 'patch'],
-                onComplete: function() {
-                    console.log('Swagger UI loaded successfully');
-                },
-                onFailure: function(error) {
-                    console.error('Swagger UI failed to load:', error);
-                }
-            });
-        };
-    </script>
-</body>
-</html>`, h.swaggerConfig.UITitle, h.getThemeCSS(), baseURL)
+	h.requestLog(r).Debug("Swagger using base URL", "base_url", baseURL)
+
+	specs := h.specURLs(baseURL)
+	page := swaggerPage{
+		Title:                h.swaggerConfig.UITitle,
+		CSSURL:               h.assetURL(swaggerUICSS),
+		BundleURL:            h.assetURL(swaggerUIBundle),
+		StandaloneURL:        h.assetURL(swaggerUIStandalone),
+		Nonce:                nonce,
+		ThemeCSS:             template.CSS(h.getThemeCSS()),
+		URL:                  specs[0].URL,
+		PersistAuthorization: h.swaggerConfig.PersistAuthorization,
+		WithCredentials:      h.swaggerConfig.WithCredentials,
+		RequestInterceptor:   template.JS(sanitizeInlineJS(h.swaggerConfig.RequestInterceptorJS)),
+		ResponseInterceptor:  template.JS(sanitizeInlineJS(h.swaggerConfig.ResponseInterceptorJS)),
+		OAuth:                newOAuthInit(h.oauth2),
+	}
+	if len(specs) > 1 {
+		page.URLs, page.PrimaryName = specs, specs[0].Name
+	}
+	if name := h.swaggerConfig.PreauthKeyName; name != "" {
+		placeholder := h.swaggerConfig.PreauthKeyPlaceholder
+		if placeholder == "" {
+			placeholder = defaultPreauthKeyPlaceholder
+		}
+		page.Preauthorize = &preauthorizedKey{Name: name, Placeholder: placeholder}
+	}
+
+	var html bytes.Buffer
+	if err := swaggerUITemplate.Execute(&html, page); err != nil {
+		return "", fmt.Errorf("failed to render Swagger UI: %w", err)
+	}
+	return html.String(), nil
+}
+
+// specURLs returns the specs listed in the Swagger UI spec selector, starting with
+// this server's spec; it is the only entry unless additional specs are configured
+func (h *DocsHandler) specURLs(baseURL string) []SpecEntry {
+	primary := SpecEntry{Name: h.swaggerConfig.UITitle, URL: baseURL + "/docs/openapi.yaml"}
+	if len(h.swaggerConfig.AdditionalSpecs) == 0 {
+		return []SpecEntry{primary}
+	}
+	if primary.Name == "" {
+		primary.Name = "API"
+	}
+
+	entries := []SpecEntry{primary}
+	for _, spec := range h.swaggerConfig.AdditionalSpecs {
+		if spec.Name == "" || spec.URL == "" {
+			h.logger.Warn("Skipping additional spec without a name or URL", "name", spec.Name, "url", spec.URL)
+			continue
+		}
+		if strings.HasPrefix(spec.URL, "/") && !strings.HasPrefix(spec.URL, "//") {
+			spec.URL = baseURL + spec.URL
+		}
+		entries = append(entries, spec)
+	}
+	return entries
+}
+
+// sanitizeInlineJS trims a configured script fragment and breaks up any closing
+// script tag so the fragment cannot end the surrounding <script> element. The
+// JavaScript itself is not escaped.
+func sanitizeInlineJS(body string) string {
+	body = strings.TrimSpace(strings.ReplaceAll(body, "\x00", ""))
+
+	const closing = "</script"
+	var b strings.Builder
+	for i := 0; i < len(body); i++ {
+		if i+len(closing) <= len(body) && strings.EqualFold(body[i:i+len(closing)], closing) {
+			b.WriteString(`<\/`)
+			i++ // Skip the slash; the tag name is copied as is
+			continue
+		}
+		b.WriteByte(body[i])
+	}
+	return b.String()
+}
+
+// forwardedParam returns a parameter from the first element of the RFC 7239 Forwarded
+// header that sets it, e.g. "https" for "Forwarded: for=192.0.2.60;proto=https"
+func forwardedParam(r *http.Request, name string) string {
+	for _, header := range r.Header.Values("Forwarded") {
+		for _, element := range strings.Split(header, ",") {
+			for _, pair := range strings.Split(element, ";") {
+				key, value, found := strings.Cut(strings.TrimSpace(pair), "=")
+				if found && strings.EqualFold(key, name) {
+					return strings.Trim(value, `"`)
+				}
+			}
+		}
+	}
+	return ""
+}
+
+// forwardedHost returns the client-facing host from X-Forwarded-Host, adding the
+// X-Forwarded-Port when the host does not already carry a port
+func forwardedHost(r *http.Request) string {
+	// Proxies append to the list, so the first entry is the original host
+	host, _, _ := strings.Cut(r.Header.Get("X-Forwarded-Host"), ",")
+	host = strings.TrimSpace(host)
+	if host == "" {
+		return ""
+	}
+
+	port, _, _ := strings.Cut(r.Header.Get("X-Forwarded-Port"), ",")
+	port = strings.TrimSpace(port)
+	if port == "" {
+		return host
+	}
+	if _, _, err := net.SplitHostPort(host); err == nil {
+		return host
+	}
+	return net.JoinHostPort(strings.Trim(host, "[]"), port)
 }
 
 // getThemeCSS returns CSS for the configured theme
@@ -228,21 +798,112 @@ func (h *DocsHandler) ServeDocs(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Security check: prevent directory traversal
-	if strings.Contains(requestPath, "..") {
+	// Security check: the resolved file must stay inside the docs root
+	filePath, err := h.resolveDocsPath(requestPath)
+	if err != nil {
+		h.requestLog(r).Warn("Rejected docs path", "error", err)
 		http.Error(w, "Invalid path", http.StatusBadRequest)
 		return
 	}
 
-	// Construct file path
-	filePath := filepath.Join("docs", strings.TrimPrefix(requestPath, "/"))
-	
 	// Check if file exists
 	if _, err := os.Stat(filePath); os.IsNotExist(err) {
 		http.Error(w, "File not found", http.StatusNotFound)
 		return
 	}
 
-	// Serve the file
+	// Set types http.ServeFile would guess wrong or not at all, then serve the file
+	if contentType, ok := docsContentTypes[strings.ToLower(filepath.Ext(filePath))]; ok {
+		w.Header().Set("Content-Type", contentType)
+	}
 	http.ServeFile(w, r, filePath)
-}
\ No newline at end of file
+}
+
+// resolveDocsPath maps a request path onto a file under the configured docs root,
+// rejecting encoded traversal, absolute paths and anything that escapes the root
+func (h *DocsHandler) resolveDocsPath(requestPath string) (string, error) {
+	return resolveUnder(h.swaggerConfig.DocsRoot, requestPath, "docs root")
+}
+
+// resolveUnder maps a slash-separated path onto a file under root, rejecting
+// encoded traversal, absolute paths and anything that escapes root
+func resolveUnder(rootDir, requestPath, rootName string) (string, error) {
+	// net/http decodes the path once; decode again to catch double-encoded sequences
+	decoded, err := url.PathUnescape(strings.TrimPrefix(requestPath, "/"))
+	if err != nil {
+		return "", fmt.Errorf("invalid path encoding: %w", err)
+	}
+
+	relative := filepath.FromSlash(decoded)
+	if filepath.IsAbs(relative) || strings.HasPrefix(decoded, "/") || filepath.VolumeName(relative) != "" {
+		return "", fmt.Errorf("absolute paths are not allowed")
+	}
+
+	root, err := filepath.Abs(rootDir)
+	if err != nil {
+		return "", fmt.Errorf("invalid %s: %w", rootName, err)
+	}
+
+	filePath := filepath.Join(root, filepath.Clean(relative))
+	rel, err := filepath.Rel(root, filePath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("path escapes the %s", rootName)
+	}
+
+	return filePath, nil
+}
+
+// RegisterCustomEndpoint serves filePath, relative to the working directory, at the
+// URL path through ServeCustom, e.g. /changelog for CHANGELOG.md. Files outside the
+// working directory are rejected like traversal in ServeDocs. An empty contentType
+// is detected from the file.
+func (h *DocsHandler) RegisterCustomEndpoint(path, filePath, contentType string) error {
+	if !strings.HasPrefix(path, "/") {
+		return fmt.Errorf("custom endpoint path %q must start with /", path)
+	}
+
+	if filepath.IsAbs(filePath) {
+		return fmt.Errorf("custom endpoint %s: absolute paths are not allowed", path)
+	}
+	resolved, err := resolveUnder(".", filepath.ToSlash(filePath), "working directory")
+	if err != nil {
+		return fmt.Errorf("custom endpoint %s: %w", path, err)
+	}
+
+	h.customMutex.Lock()
+	defer h.customMutex.Unlock()
+
+	if h.customEndpoints == nil {
+		h.customEndpoints = make(map[string]customEndpoint)
+	}
+	h.customEndpoints[path] = customEndpoint{filePath: resolved, contentType: contentType}
+	return nil
+}
+
+// ServeCustom serves the file registered for the request path with
+// RegisterCustomEndpoint, or 404 for unregistered paths
+func (h *DocsHandler) ServeCustom(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	h.customMutex.RLock()
+	endpoint, exists := h.customEndpoints[r.URL.Path]
+	h.customMutex.RUnlock()
+	if !exists {
+		http.NotFound(w, r)
+		return
+	}
+
+	if _, err := os.Stat(endpoint.filePath); err != nil {
+		h.requestLog(r).Warn("Custom docs endpoint file unavailable", "file_path", endpoint.filePath, "error", err)
+		http.Error(w, "File not found", http.StatusNotFound)
+		return
+	}
+
+	if endpoint.contentType != "" {
+		w.Header().Set("Content-Type", endpoint.contentType)
+	}
+	http.ServeFile(w, r, endpoint.filePath)
+}
@@ -0,0 +1,937 @@
+package docs
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
+	"github.com/JerkyTreats/llm/internal/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+	"gopkg.in/yaml.v3"
+)
+
+// pushRecorder is a ResponseRecorder that also implements http.Pusher
+type pushRecorder struct {
+	*httptest.ResponseRecorder
+	pushed []string
+}
+
+func (p *pushRecorder) Push(target string, opts *http.PushOptions) error {
+	p.pushed = append(p.pushed, target)
+	return nil
+}
+
+func newTestDocsHandler(t *testing.T) *DocsHandler {
+	t.Helper()
+
+	h, err := NewDocsHandler(nil, WithLogger(NopLogger{}))
+	if err != nil {
+		t.Fatalf("NewDocsHandler() error = %v", err)
+	}
+	return h
+}
+
+func TestServeSwaggerUI_PushWithoutPusher(t *testing.T) {
+	h := newTestDocsHandler(t)
+	h.swaggerConfig.Push = true
+
+	req := httptest.NewRequest(http.MethodGet, "/swagger", nil)
+	rec := httptest.NewRecorder()
+
+	h.ServeSwaggerUI(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+}
+
+func TestServeSwaggerUI_PushAssets(t *testing.T) {
+	h := newTestDocsHandler(t)
+	h.swaggerConfig.Push = true
+
+	req := httptest.NewRequest(http.MethodGet, "/swagger", nil)
+	rec := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	h.ServeSwaggerUI(rec, req)
+
+	expected := []string{
+		defaultSwaggerUIAssetsURL + "/swagger-ui.css",
+		defaultSwaggerUIAssetsURL + "/swagger-ui-bundle.js",
+		defaultSwaggerUIAssetsURL + "/swagger-ui-standalone-preset.js",
+	}
+	if len(rec.pushed) != len(expected) {
+		t.Fatalf("Expected %d pushes, got %d: %v", len(expected), len(rec.pushed), rec.pushed)
+	}
+	for i, url := range expected {
+		if rec.pushed[i] != url {
+			t.Errorf("Push %d: expected %s, got %s", i, url, rec.pushed[i])
+		}
+	}
+}
+
+func TestServeSwaggerUI_PushDisabled(t *testing.T) {
+	h := newTestDocsHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/swagger", nil)
+	rec := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+
+	h.ServeSwaggerUI(rec, req)
+
+	if len(rec.pushed) != 0 {
+		t.Errorf("Expected no pushes when Push is disabled, got %v", rec.pushed)
+	}
+}
+
+// useTestRegistry replaces the global route registry for the duration of a test
+func useTestRegistry(t *testing.T, routes []types.RouteInfo) {
+	t.Helper()
+
+	original := types.GetRegisteredRoutes()
+	t.Cleanup(func() { types.UpdateRouteRegistry(original) })
+
+	types.ClearRegistry()
+	for _, route := range routes {
+		types.RegisterRoute(route)
+	}
+}
+
+// moduleSpec lists an alpha and a beta operation, each with its own schemas
+const moduleSpec = `openapi: 3.0.3
+info:
+    title: Test
+    version: 1.0.0
+paths:
+    /alpha:
+        get:
+            tags:
+                - alpha
+            responses:
+                "200":
+                    description: OK
+                    content:
+                        application/json:
+                            schema:
+                                $ref: '#/components/schemas/alphaResponse'
+    /beta:
+        parameters:
+            - name: verbose
+              in: query
+              schema:
+                type: boolean
+        get:
+            tags:
+                - beta
+            responses:
+                "200":
+                    description: OK
+                    content:
+                        application/json:
+                            schema:
+                                $ref: '#/components/schemas/betaResponse'
+components:
+    schemas:
+        alphaItem:
+            type: string
+        alphaResponse:
+            type: object
+            properties:
+                items:
+                    type: array
+                    items:
+                        $ref: '#/components/schemas/alphaItem'
+        betaResponse:
+            type: object
+    securitySchemes:
+        BearerAuth:
+            type: http
+            scheme: bearer
+tags:
+    - name: alpha
+    - name: beta
+`
+
+func TestServeOpenAPISpec_ModuleFilter(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeSpecFile(t, moduleSpec)
+	useTestRegistry(t, []types.RouteInfo{
+		{Method: "GET", Path: "/alpha", Module: "alpha", Summary: "Alpha"},
+		{Method: "GET", Path: "/beta", Module: "beta", Summary: "Beta"},
+	})
+
+	h := newTestDocsHandler(t)
+	req := httptest.NewRequest(http.MethodGet, "/docs/openapi.yaml?module=alpha", nil)
+	rec := httptest.NewRecorder()
+
+	h.WithMetrics(MetricOpenAPISpec, h.ServeOpenAPISpec)(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var spec struct {
+		Paths      map[string]interface{} `yaml:"paths"`
+		Components struct {
+			Schemas         map[string]interface{} `yaml:"schemas"`
+			SecuritySchemes map[string]interface{} `yaml:"securitySchemes"`
+		} `yaml:"components"`
+		Tags []struct {
+			Name string `yaml:"name"`
+		} `yaml:"tags"`
+	}
+	if err := yaml.Unmarshal(rec.Body.Bytes(), &spec); err != nil {
+		t.Fatalf("Module spec is not valid YAML: %v", err)
+	}
+
+	if _, exists := spec.Paths["/alpha"]; !exists {
+		t.Error("Module spec should contain '/alpha'")
+	}
+	if _, exists := spec.Paths["/beta"]; exists {
+		t.Error("Module spec should not contain '/beta' from another module")
+	}
+	for _, name := range []string{"alphaResponse", "alphaItem"} {
+		if _, exists := spec.Components.Schemas[name]; !exists {
+			t.Errorf("Module spec should keep the %s schema its operations reference", name)
+		}
+	}
+	if _, exists := spec.Components.Schemas["betaResponse"]; exists {
+		t.Error("Module spec should not contain schemas from another module")
+	}
+	if _, exists := spec.Components.SecuritySchemes["BearerAuth"]; !exists {
+		t.Error("Module spec should keep the security schemes")
+	}
+	if len(spec.Tags) != 1 || spec.Tags[0].Name != "alpha" {
+		t.Errorf("Module spec tags = %+v, expected only alpha", spec.Tags)
+	}
+}
+
+func TestServeOpenAPISpec_UnknownModule(t *testing.T) {
+	useTestRegistry(t, []types.RouteInfo{
+		{Method: "GET", Path: "/alpha", Module: "alpha"},
+	})
+
+	h := newTestDocsHandler(t)
+	req := httptest.NewRequest(http.MethodGet, "/docs/openapi.yaml?module=missing", nil)
+	rec := httptest.NewRecorder()
+
+	h.WithMetrics(MetricOpenAPISpec, h.ServeOpenAPISpec)(rec, req)
+
+	if rec.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown module, got %d", rec.Code)
+	}
+}
+
+// writeSpecFile writes content to docs/api/openapi.yaml under the current directory
+func writeSpecFile(t *testing.T, content string) {
+	t.Helper()
+
+	if err := os.MkdirAll(filepath.Join("docs", "api"), 0755); err != nil {
+		t.Fatalf("Failed to create spec directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join("docs", "api", "openapi.yaml"), []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write spec file: %v", err)
+	}
+}
+
+// fetchSpec requests the OpenAPI spec and returns the response body
+func fetchSpec(t *testing.T, h *DocsHandler) string {
+	t.Helper()
+
+	req := httptest.NewRequest(http.MethodGet, "/docs/openapi.yaml", nil)
+	rec := httptest.NewRecorder()
+
+	h.WithMetrics(MetricOpenAPISpec, h.ServeOpenAPISpec)(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	return rec.Body.String()
+}
+
+func TestServeOpenAPISpec_CacheDisabled(t *testing.T) {
+	t.Chdir(t.TempDir())
+	h := newTestDocsHandler(t)
+
+	writeSpecFile(t, "version: one")
+	fetchSpec(t, h)
+
+	writeSpecFile(t, "version: two")
+	if body := fetchSpec(t, h); body != "version: two" {
+		t.Errorf("Expected spec to be re-read without caching, got %q", body)
+	}
+}
+
+func TestServeOpenAPISpec_CacheWithinTTL(t *testing.T) {
+	t.Chdir(t.TempDir())
+	h := newTestDocsHandler(t)
+	h.swaggerConfig.SpecCacheTTL = time.Hour
+
+	writeSpecFile(t, "version: one")
+	fetchSpec(t, h)
+
+	writeSpecFile(t, "version: two")
+	if body := fetchSpec(t, h); body != "version: one" {
+		t.Errorf("Expected cached spec within TTL, got %q", body)
+	}
+}
+
+func TestServeOpenAPISpec_CacheExpired(t *testing.T) {
+	t.Chdir(t.TempDir())
+	h := newTestDocsHandler(t)
+	h.swaggerConfig.SpecCacheTTL = time.Minute
+
+	writeSpecFile(t, "version: one")
+	fetchSpec(t, h)
+
+	// Age the cache entry past the TTL
+	h.specCachedAt = time.Now().Add(-2 * time.Minute)
+
+	writeSpecFile(t, "version: two")
+	if body := fetchSpec(t, h); body != "version: two" {
+		t.Errorf("Expected spec to be re-read after TTL expiry, got %q", body)
+	}
+}
+
+func TestFlushSpecCache(t *testing.T) {
+	t.Chdir(t.TempDir())
+	h := newTestDocsHandler(t)
+	h.swaggerConfig.SpecCacheTTL = time.Hour
+
+	writeSpecFile(t, "version: one")
+	fetchSpec(t, h)
+
+	writeSpecFile(t, "version: two")
+	h.FlushSpecCache()
+
+	if body := fetchSpec(t, h); body != "version: two" {
+		t.Errorf("Expected spec to be re-read after FlushSpecCache, got %q", body)
+	}
+}
+
+func TestHealthz_Healthy(t *testing.T) {
+	t.Chdir(t.TempDir())
+	h := newTestDocsHandler(t)
+
+	writeSpecFile(t, `
+openapi: 3.0.3
+paths:
+  /health:
+    get: {}
+  /users:
+    summary: Users
+    parameters: []
+    x-internal: false
+    get: {}
+    post: {}
+`)
+
+	req := httptest.NewRequest(http.MethodGet, "/docs/healthz", nil)
+	rec := httptest.NewRecorder()
+
+	h.Healthz(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", rec.Code, rec.Body.String())
+	}
+
+	var response DocsHealthResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Response is not valid JSON: %v", err)
+	}
+	if !response.SpecPresent || response.Routes != 3 {
+		t.Errorf("Expected spec_present=true and routes=3, got %+v", response)
+	}
+}
+
+func TestHealthz_MissingSpec(t *testing.T) {
+	t.Chdir(t.TempDir())
+	h := newTestDocsHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/docs/healthz", nil)
+	rec := httptest.NewRecorder()
+
+	h.Healthz(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Fatalf("Expected status 503, got %d", rec.Code)
+	}
+
+	var response DocsHealthResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Response is not valid JSON: %v", err)
+	}
+	if response.SpecPresent {
+		t.Error("Expected spec_present=false when the spec file is missing")
+	}
+}
+
+func TestHealthz_UnparseableSpec(t *testing.T) {
+	t.Chdir(t.TempDir())
+	h := newTestDocsHandler(t)
+
+	writeSpecFile(t, "paths: [unterminated")
+
+	req := httptest.NewRequest(http.MethodGet, "/docs/healthz", nil)
+	rec := httptest.NewRecorder()
+
+	h.Healthz(rec, req)
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503 for an unparseable spec, got %d", rec.Code)
+	}
+}
+
+func TestGenerateSwaggerHTML_ForwardedProto(t *testing.T) {
+	h := newTestDocsHandler(t)
+
+	tests := []struct {
+		name      string
+		forwarded string
+		expected  string
+	}{
+		{"proto https", "proto=https", "https://docs.example.com/docs/openapi.yaml"},
+		{"quoted with other params", `for="192.0.2.60";proto="HTTPS";by=203.0.113.43`, "https://docs.example.com/docs/openapi.yaml"},
+		{"first hop wins", "proto=http, proto=https", "http://docs.example.com/docs/openapi.yaml"},
+		{"proto http", "proto=http", "http://docs.example.com/docs/openapi.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/swagger", nil)
+			req.Host = "docs.example.com"
+			req.Header.Set("Forwarded", tt.forwarded)
+
+			html := swaggerHTML(t, h, req)
+
+			if !strings.Contains(html, `url: "`+tt.expected+`"`) {
+				t.Errorf("Expected spec URL %s in Swagger HTML", tt.expected)
+			}
+		})
+	}
+}
+
+func TestGenerateSwaggerHTML_AuthOptions(t *testing.T) {
+	for _, enabled := range []bool{true, false} {
+		h := newTestDocsHandler(t)
+		h.swaggerConfig.PersistAuthorization = enabled
+		h.swaggerConfig.WithCredentials = enabled
+
+		html := swaggerHTML(t, h, httptest.NewRequest(http.MethodGet, "/swagger", nil))
+
+		for _, option := range []string{"persistAuthorization", "withCredentials"} {
+			expected := fmt.Sprintf("%s: %t,", option, enabled)
+			if !strings.Contains(html, expected) {
+				t.Errorf("Expected %q in Swagger HTML", expected)
+			}
+			if strings.Contains(html, fmt.Sprintf("%s: %t,", option, !enabled)) {
+				t.Errorf("Swagger HTML should not contain %s: %t", option, !enabled)
+			}
+		}
+	}
+}
+
+func TestGenerateSwaggerHTML_Interceptors(t *testing.T) {
+	h := newTestDocsHandler(t)
+
+	html := swaggerHTML(t, h, httptest.NewRequest(http.MethodGet, "/swagger", nil))
+	if strings.Contains(html, "requestInterceptor") || strings.Contains(html, "responseInterceptor") {
+		t.Error("Swagger HTML should not include interceptors when none are configured")
+	}
+
+	h.swaggerConfig.RequestInterceptorJS = `req.headers['X-CSRF-Token'] = document.cookie.split('csrf=')[1]; return req;`
+	h.swaggerConfig.ResponseInterceptorJS = "\n  console.log(res.status, res.url);\n  return res;\n"
+
+	html = swaggerHTML(t, h, httptest.NewRequest(http.MethodGet, "/swagger", nil))
+	for _, expected := range []string{
+		`requestInterceptor: function(req) { req.headers['X-CSRF-Token'] = document.cookie.split('csrf=')[1]; return req; },`,
+		"responseInterceptor: function(res) { console.log(res.status, res.url);\n  return res; },",
+	} {
+		if !strings.Contains(html, expected) {
+			t.Errorf("Expected %q in Swagger HTML", expected)
+		}
+	}
+}
+
+func TestGenerateSwaggerHTML_SingleSpecURL(t *testing.T) {
+	h := newTestDocsHandler(t)
+
+	html := swaggerHTML(t, h, httptest.NewRequest(http.MethodGet, "http://example.com/swagger", nil))
+	if !strings.Contains(html, `url: "http://example.com/docs/openapi.yaml",`) {
+		t.Error("Expected a single url option for the server's spec")
+	}
+	if strings.Contains(html, "urls") {
+		t.Error("Swagger HTML should not include urls when no additional specs are configured")
+	}
+}
+
+func TestGenerateSwaggerHTML_AdditionalSpecs(t *testing.T) {
+	h := newTestDocsHandler(t)
+	h.swaggerConfig.UITitle = "v1 API"
+	h.swaggerConfig.AdditionalSpecs = []SpecEntry{
+		{Name: "v2 API", URL: "/docs/v2/openapi.yaml"},
+		{Name: "Admin API", URL: "https://admin.example.com/openapi.yaml"},
+		{Name: "", URL: "/ignored.yaml"},
+	}
+
+	html := swaggerHTML(t, h, httptest.NewRequest(http.MethodGet, "http://example.com/swagger", nil))
+
+	expected := `urls: [{"name":"v1 API","url":"http://example.com/docs/openapi.yaml"},` +
+		`{"name":"v2 API","url":"http://example.com/docs/v2/openapi.yaml"},` +
+		`{"name":"Admin API","url":"https://admin.example.com/openapi.yaml"}],` +
+		"\n                \"urls.primaryName\": \"v1 API\","
+	if !strings.Contains(html, expected) {
+		t.Errorf("Expected %q in Swagger HTML", expected)
+	}
+	if strings.Contains(html, "url: ") {
+		t.Error("Swagger HTML should not include the single url option alongside urls")
+	}
+}
+
+func TestSanitizeInlineJS(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"  return req;  ", "return req;"},
+		{"return req;\x00", "return req;"},
+		{`console.log("</script><script>alert(1)"); return req;`, `console.log("<\/script><script>alert(1)"); return req;`},
+		{`console.log("</SCRIPT >"); return req;`, `console.log("<\/SCRIPT >"); return req;`},
+		{`if (a < b && "é" !== "/") return req;`, `if (a < b && "é" !== "/") return req;`},
+	}
+
+	for _, tt := range tests {
+		if got := sanitizeInlineJS(tt.input); got != tt.expected {
+			t.Errorf("sanitizeInlineJS(%q) = %q, want %q", tt.input, got, tt.expected)
+		}
+	}
+}
+
+func TestGenerateSwaggerHTML_ForwardedHost(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+	config.SetForTest("server.host", "0.0.0.0")
+	config.SetForTest("server.port", 8080)
+
+	h := newTestDocsHandler(t)
+
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		host     string
+		expected string
+	}{
+		{"forwarded host", map[string]string{"X-Forwarded-Host": "docs.example.com"}, "internal:8080", "http://docs.example.com/docs/openapi.yaml"},
+		{"forwarded host and port", map[string]string{"X-Forwarded-Host": "docs.example.com", "X-Forwarded-Port": "8443"}, "internal:8080", "http://docs.example.com:8443/docs/openapi.yaml"},
+		{"forwarded host keeps its port", map[string]string{"X-Forwarded-Host": "docs.example.com:9000", "X-Forwarded-Port": "8443"}, "internal:8080", "http://docs.example.com:9000/docs/openapi.yaml"},
+		{"first forwarded host wins", map[string]string{"X-Forwarded-Host": "docs.example.com, proxy.internal"}, "internal:8080", "http://docs.example.com/docs/openapi.yaml"},
+		{"request host", nil, "internal:8080", "http://internal:8080/docs/openapi.yaml"},
+		{"config fallback", nil, "", "http://localhost:8080/docs/openapi.yaml"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/swagger", nil)
+			req.Host = tt.host
+			for key, value := range tt.headers {
+				req.Header.Set(key, value)
+			}
+
+			html := swaggerHTML(t, h, req)
+
+			if !strings.Contains(html, `url: "`+tt.expected+`"`) {
+				t.Errorf("Expected spec URL %s in Swagger HTML", tt.expected)
+			}
+		})
+	}
+}
+
+func TestServeDocs_Sandboxed(t *testing.T) {
+	base := t.TempDir()
+	root := filepath.Join(base, "site")
+	if err := os.MkdirAll(root, 0755); err != nil {
+		t.Fatalf("Failed to create docs root: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(root, "guide.md"), []byte("# Guide"), 0644); err != nil {
+		t.Fatalf("Failed to write docs file: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(base, "secret.txt"), []byte("secret"), 0644); err != nil {
+		t.Fatalf("Failed to write secret file: %v", err)
+	}
+
+	h := newTestDocsHandler(t)
+	h.swaggerConfig.DocsRoot = root
+
+	tests := []struct {
+		name     string
+		target   string
+		expected int
+	}{
+		{"file inside root", "/docs/guide.md", http.StatusOK},
+		{"missing file", "/docs/missing.md", http.StatusNotFound},
+		{"encoded traversal", "/docs/%2e%2e/secret.txt", http.StatusBadRequest},
+		{"double-encoded traversal", "/docs/%252e%252e/secret.txt", http.StatusBadRequest},
+		{"encoded slash traversal", "/docs/..%2fsecret.txt", http.StatusBadRequest},
+		{"absolute path", "/docs/" + filepath.ToSlash(filepath.Join(base, "secret.txt")), http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			rec := httptest.NewRecorder()
+
+			h.ServeDocs(rec, req)
+
+			if rec.Code != tt.expected {
+				t.Errorf("Expected status %d for %s, got %d", tt.expected, tt.target, rec.Code)
+			}
+			if strings.Contains(rec.Body.String(), "secret") {
+				t.Error("Response should never expose files outside the docs root")
+			}
+		})
+	}
+}
+
+func TestServeDocs_ContentType(t *testing.T) {
+	root := t.TempDir()
+	for name, content := range map[string]string{"api.yaml": "openapi: 3.0.3", "guide.md": "# Guide"} {
+		if err := os.WriteFile(filepath.Join(root, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	h := newTestDocsHandler(t)
+	h.swaggerConfig.DocsRoot = root
+
+	tests := []struct {
+		target   string
+		expected string
+	}{
+		{"/docs/api.yaml", "application/x-yaml"},
+		{"/docs/guide.md", "text/markdown; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.target, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			rec := httptest.NewRecorder()
+
+			h.ServeDocs(rec, req)
+
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Expected status 200, got %d", rec.Code)
+			}
+			if got := rec.Header().Get("Content-Type"); got != tt.expected {
+				t.Errorf("Expected Content-Type %s, got %s", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestServeRouteIndex(t *testing.T) {
+	useTestRegistry(t, []types.RouteInfo{
+		{Method: "GET", Path: "/alpha", Module: "alpha", Summary: "Alpha"},
+		{Method: "POST", Path: "/v1/beta", Module: "beta", Summary: "Beta", Deprecated: true},
+	})
+
+	h := newTestDocsHandler(t)
+	req := httptest.NewRequest(http.MethodGet, "/docs/routes.json", nil)
+	rec := httptest.NewRecorder()
+
+	h.ServeRouteIndex(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+
+	var index []RouteIndexEntry
+	if err := json.Unmarshal(rec.Body.Bytes(), &index); err != nil {
+		t.Fatalf("Response is not valid JSON: %v", err)
+	}
+
+	expected := []RouteIndexEntry{
+		{Method: "GET", Path: "/alpha", Module: "alpha", Summary: "Alpha"},
+		{Method: "POST", Path: "/v1/beta", Module: "beta", Summary: "Beta", Deprecated: true},
+	}
+	if !reflect.DeepEqual(index, expected) {
+		t.Errorf("Expected route index %+v, got %+v", expected, index)
+	}
+}
+
+func TestServeCustom(t *testing.T) {
+	base := t.TempDir()
+	work := filepath.Join(base, "work")
+	if err := os.MkdirAll(work, 0755); err != nil {
+		t.Fatalf("Failed to create working directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(work, "CHANGELOG.md"), []byte("## v1.0.0\n- Initial release\n"), 0644); err != nil {
+		t.Fatalf("Failed to write changelog: %v", err)
+	}
+	t.Chdir(work)
+
+	h := newTestDocsHandler(t)
+	if err := h.RegisterCustomEndpoint("/changelog", "CHANGELOG.md", "text/plain"); err != nil {
+		t.Fatalf("RegisterCustomEndpoint() error = %v", err)
+	}
+
+	t.Run("registered endpoint", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeCustom(rec, httptest.NewRequest(http.MethodGet, "/changelog", nil))
+
+		if rec.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d", rec.Code)
+		}
+		if got := rec.Header().Get("Content-Type"); got != "text/plain" {
+			t.Errorf("Expected Content-Type text/plain, got %q", got)
+		}
+		if rec.Body.String() != "## v1.0.0\n- Initial release\n" {
+			t.Errorf("Unexpected body %q", rec.Body.String())
+		}
+	})
+
+	t.Run("unregistered path", func(t *testing.T) {
+		rec := httptest.NewRecorder()
+		h.ServeCustom(rec, httptest.NewRequest(http.MethodGet, "/license", nil))
+
+		if rec.Code != http.StatusNotFound {
+			t.Errorf("Expected status 404, got %d", rec.Code)
+		}
+	})
+
+	t.Run("traversal rejected", func(t *testing.T) {
+		for _, filePath := range []string{"../secret.txt", "/etc/passwd", "%2e%2e/secret.txt"} {
+			if err := h.RegisterCustomEndpoint("/secret", filePath, ""); err == nil {
+				t.Errorf("RegisterCustomEndpoint(%q) should reject paths outside the working directory", filePath)
+			}
+		}
+	})
+}
+
+func TestWithRequestLog_StructuredFields(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logging.SetLoggerForTest(zap.New(core))
+	t.Cleanup(logging.ResetForTest)
+
+	// The default Logger writes through the logging package
+	h, err := NewDocsHandler(nil)
+	if err != nil {
+		t.Fatalf("NewDocsHandler() error = %v", err)
+	}
+	h.swaggerConfig.DocsRoot = t.TempDir()
+
+	req := httptest.NewRequest(http.MethodGet, "/docs/missing.md", nil)
+	req.RemoteAddr = "203.0.113.7:51234"
+	h.WithRequestLog(h.ServeDocs)(httptest.NewRecorder(), req)
+
+	served := logs.FilterMessage("Served docs request").All()
+	if len(served) != 1 {
+		t.Fatalf("Expected one request log entry, got %d", len(served))
+	}
+
+	fields := served[0].ContextMap()
+	expected := map[string]interface{}{
+		"method":      "GET",
+		"path":        "/docs/missing.md",
+		"route":       "/docs/missing.md",
+		"remote_addr": "203.0.113.7:51234",
+		"status":      int64(http.StatusNotFound),
+	}
+	for key, value := range expected {
+		if fields[key] != value {
+			t.Errorf("Field %s = %v (%T), want %v", key, fields[key], fields[key], value)
+		}
+	}
+	if _, ok := fields["duration_ms"]; !ok {
+		t.Error("Expected a duration_ms field")
+	}
+}
+
+func TestWithRequestLog_RouteTemplate(t *testing.T) {
+	logger := newCapturingLogger()
+	h, err := NewDocsHandler(nil, WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewDocsHandler() error = %v", err)
+	}
+	h.swaggerConfig.DocsRoot = t.TempDir()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /docs/{page...}", h.WithRequestLog(h.ServeDocs))
+	mux.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/docs/guides/missing.md", nil))
+
+	served := logger.withMessage("Served docs request")
+	if len(served) != 1 {
+		t.Fatalf("Expected one request log entry through the pluggable Logger, got %d", len(served))
+	}
+	fields := served[0].fields
+	if fields["route"] != "/docs/{page...}" {
+		t.Errorf("Field route = %v, expected the /docs/{page...} template", fields["route"])
+	}
+	if fields["path"] != "/docs/guides/missing.md" {
+		t.Errorf("Field path = %v, expected the raw request path", fields["path"])
+	}
+}
+
+func TestWithRequestLog_KeepsPush(t *testing.T) {
+	h := newTestDocsHandler(t)
+	h.swaggerConfig.Push = true
+
+	rec := &pushRecorder{ResponseRecorder: httptest.NewRecorder()}
+	h.WithRequestLog(h.ServeSwaggerUI)(rec, httptest.NewRequest(http.MethodGet, "/swagger", nil))
+
+	if len(rec.pushed) != 3 {
+		t.Errorf("Expected Swagger UI assets to be pushed through the request log wrapper, got %v", rec.pushed)
+	}
+}
+
+func TestMetrics_CountsDocsRequests(t *testing.T) {
+	t.Chdir(t.TempDir())
+	h := newTestDocsHandler(t)
+	writeSpecFile(t, "version: one")
+
+	if metrics := h.Metrics(); len(metrics) != 0 {
+		t.Errorf("Expected no metrics before any request, got %v", metrics)
+	}
+
+	h.WithMetrics(MetricSwaggerUI, h.ServeSwaggerUI)(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/swagger", nil))
+	fetchSpec(t, h)
+	fetchSpec(t, h)
+	// Rejected requests are counted too
+	h.WithMetrics(MetricOpenAPISpec, h.ServeOpenAPISpec)(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/docs/openapi.yaml", nil))
+
+	metrics := h.Metrics()
+	if got := metrics[MetricSwaggerUI].Requests; got != 1 {
+		t.Errorf("Expected 1 Swagger UI request, got %d", got)
+	}
+	spec := metrics[MetricOpenAPISpec]
+	if spec.Requests != 3 {
+		t.Errorf("Expected 3 OpenAPI spec requests, got %d", spec.Requests)
+	}
+	if spec.TotalDuration <= 0 || spec.MaxDuration > spec.TotalDuration || spec.AverageDuration() > spec.MaxDuration {
+		t.Errorf("Inconsistent latency metrics %+v", spec)
+	}
+}
+
+func TestNewDocsHandlerFromFile(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	path := filepath.Join(t.TempDir(), "swagger.yaml")
+	content := `enabled: false
+path: /api-docs
+spec_path: /api-docs/openapi.yaml
+ui.title: Internal API
+ui.theme: light
+push: true
+docs_root: site
+ui.persist_authorization: false
+ui.with_credentials: true
+ui.request_interceptor_js: return req;
+ui.response_interceptor_js: return res;
+spec_cache_ttl: 30s
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	h, err := NewDocsHandlerFromFile(path)
+	if err != nil {
+		t.Fatalf("NewDocsHandlerFromFile() error = %v", err)
+	}
+
+	expected := SwaggerConfig{
+		Enabled:               false,
+		Path:                  "/api-docs",
+		SpecPath:              "/api-docs/openapi.yaml",
+		UITitle:               "Internal API",
+		Theme:                 "light",
+		Push:                  true,
+		DocsRoot:              "site",
+		AssetsURL:             defaultSwaggerUIAssetsURL,
+		PersistAuthorization:  false,
+		WithCredentials:       true,
+		RequestInterceptorJS:  "return req;",
+		ResponseInterceptorJS: "return res;",
+		SpecCacheTTL:          30 * time.Second,
+	}
+	if !reflect.DeepEqual(h.swaggerConfig, expected) {
+		t.Errorf("SwaggerConfig = %+v, want %+v", h.swaggerConfig, expected)
+	}
+}
+
+func TestNewDocsHandlerFromFile_MissingFileUsesDefaults(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	h, err := NewDocsHandlerFromFile(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil {
+		t.Fatalf("NewDocsHandlerFromFile() error = %v", err)
+	}
+	if !reflect.DeepEqual(h.swaggerConfig, defaultSwaggerConfig()) {
+		t.Errorf("SwaggerConfig = %+v, want defaults", h.swaggerConfig)
+	}
+}
+
+func TestNewDocsHandlerFromFile_PartialFileKeepsDefaults(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	path := filepath.Join(t.TempDir(), "swagger.yaml")
+	if err := os.WriteFile(path, []byte("ui.title: Partner API\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+
+	h, err := NewDocsHandlerFromFile(path)
+	if err != nil {
+		t.Fatalf("NewDocsHandlerFromFile() error = %v", err)
+	}
+
+	expected := defaultSwaggerConfig()
+	expected.UITitle = "Partner API"
+	if !reflect.DeepEqual(h.swaggerConfig, expected) {
+		t.Errorf("SwaggerConfig = %+v, want %+v", h.swaggerConfig, expected)
+	}
+}
+
+func TestNewDocsHandlerFromFile_Malformed(t *testing.T) {
+	for name, content := range map[string]string{
+		"invalid yaml": "ui.title: [unterminated\n",
+		"wrong type":   "push: sometimes\n",
+		"unknown key":  "ui.titel: Typo\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "swagger.yaml")
+			if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+				t.Fatalf("Failed to write config: %v", err)
+			}
+
+			if _, err := NewDocsHandlerFromFile(path); err == nil {
+				t.Error("Expected an error for a malformed config file")
+			}
+		})
+	}
+}
+
+func TestNewDocsHandler_ConfigPath(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	path := filepath.Join(t.TempDir(), "docs-ui.yaml")
+	if err := os.WriteFile(path, []byte("ui.theme: light\n"), 0644); err != nil {
+		t.Fatalf("Failed to write config: %v", err)
+	}
+	config.SetForTest("swagger.config_path", path)
+
+	h := newTestDocsHandler(t)
+	if h.swaggerConfig.Theme != "light" {
+		t.Errorf("Expected swagger.config_path to be loaded, got theme %q", h.swaggerConfig.Theme)
+	}
+}
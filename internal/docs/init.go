@@ -1,40 +1,127 @@
 package docs
 
 import (
+	"net/http"
+	"reflect"
+
 	"github.com/JerkyTreats/llm/internal/api/types"
 )
 
 func init() {
 	// Register Swagger UI endpoint
 	types.RegisterRoute(types.RouteInfo{
-		Method:       "GET",
-		Path:         "/swagger",
-		Handler:      nil, // Will be set during handler initialization
-		RequestType:  nil, // GET request has no body
-		ResponseType: nil, // Returns HTML, not JSON
-		Module:       "docs",
-		Summary:      "Swagger UI for API documentation",
+		Method:          "GET",
+		Path:            "/swagger",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "docs.DocsHandler.ServeSwaggerUI",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    nil, // Returns HTML, not JSON
+		Module:          "docs",
+		Summary:         "Swagger UI for API documentation",
+
+		DocumentationRoute:   true,
+		ResponseContentTypes: []string{"text/html"},
 	})
 
 	// Register OpenAPI spec endpoint
 	types.RegisterRoute(types.RouteInfo{
-		Method:       "GET",
-		Path:         "/docs/openapi.yaml",
-		Handler:      nil, // Will be set during handler initialization
-		RequestType:  nil, // GET request has no body
-		ResponseType: nil, // Returns YAML, not JSON
-		Module:       "docs",
-		Summary:      "OpenAPI specification file",
+		Method:          "GET",
+		Path:            "/docs/openapi.yaml",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "docs.DocsHandler.ServeOpenAPISpec",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    nil, // Returns YAML, not JSON
+		Module:          "docs",
+		Summary:         "OpenAPI specification file",
+
+		DocumentationRoute:   true,
+		ResponseContentTypes: []string{"application/x-yaml"},
+	})
+
+	// Register the JSON conversion of the OpenAPI spec
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "GET",
+		Path:            "/docs/openapi.json",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "docs.DocsHandler.ServeOpenAPISpec",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    nil, // Returns the spec document, not a modelled type
+		Module:          "docs",
+		Summary:         "OpenAPI specification as JSON",
+
+		DocumentationRoute:   true,
+		ResponseContentTypes: []string{"application/json"},
+	})
+
+	// Register the OpenAPI spec endpoint negotiating YAML or JSON from Accept
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "GET",
+		Path:            "/docs/openapi",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "docs.DocsHandler.ServeOpenAPISpec",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    nil, // Returns YAML or JSON by content negotiation
+		Module:          "docs",
+		Summary:         "OpenAPI specification in the format named by the Accept header",
+
+		DocumentationRoute:   true,
+		ResponseContentTypes: []string{"application/x-yaml", "application/json"},
+	})
+
+	// Register docs subsystem health endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "GET",
+		Path:            "/docs/healthz",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "docs.DocsHandler.Healthz",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    reflect.TypeOf(DocsHealthResponse{}),
+		Module:          "docs",
+		Summary:         "Docs subsystem health reporting spec presence and route count",
+	})
+
+	// Register machine-readable route index endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "GET",
+		Path:            "/docs/routes.json",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "docs.DocsHandler.ServeRouteIndex",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    reflect.TypeOf([]RouteIndexEntry{}),
+		Module:          "docs",
+		Summary:         "JSON index of all registered routes",
+	})
+
+	// Register the Prometheus metrics endpoint; it answers 404 unless metrics are
+	// enabled, so it is kept out of the public spec
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "GET",
+		Path:            "/docs/metrics",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "docs.DocsHandler.ServeMetricsEndpoint",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    nil, // Returns Prometheus text exposition format
+		Module:          "docs",
+		Summary:         "Docs spec serving metrics in Prometheus text format",
+
+		DocumentationRoute:   true,
+		ResponseContentTypes: []string{"text/plain"},
+		ErrorStatuses:        []int{http.StatusNotFound}, // Metrics are disabled
+		Visibility:           types.VisibilityInternal,
 	})
 
 	// Register docs directory handler (for any additional static files)
 	types.RegisterRoute(types.RouteInfo{
-		Method:       "GET",
-		Path:         "/docs",
-		Handler:      nil, // Will be set during handler initialization
-		RequestType:  nil, // GET request has no body
-		ResponseType: nil, // Returns various file types
-		Module:       "docs",
-		Summary:      "Documentation static files",
+		Method:          "GET",
+		Path:            "/docs",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "docs.DocsHandler.ServeDocs",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    nil, // Returns various file types
+		Module:          "docs",
+		Summary:         "Documentation static files",
+
+		DocumentationRoute:   true,
+		ResponseContentTypes: []string{"*/*"}, // Serves any static file type
 	})
 }
\ No newline at end of file
@@ -0,0 +1,67 @@
+package docs
+
+import "github.com/JerkyTreats/llm/internal/logging"
+
+// Logger receives a DocsHandler's log entries at four levels. Each entry is a
+// message followed by alternating key/value fields, as in the logging package.
+type Logger interface {
+	Debug(msg string, keysAndValues ...interface{})
+	Info(msg string, keysAndValues ...interface{})
+	Warn(msg string, keysAndValues ...interface{})
+	Error(msg string, keysAndValues ...interface{})
+
+	// With returns a Logger adding keysAndValues to every entry
+	With(keysAndValues ...interface{}) Logger
+}
+
+// defaultLogger writes to the service log through the logging package, honouring
+// the configured log_level
+type defaultLogger struct {
+	fields *logging.Logger
+}
+
+func newDefaultLogger() Logger {
+	return defaultLogger{fields: logging.WithFields()}
+}
+
+func (l defaultLogger) Debug(msg string, keysAndValues ...interface{}) {
+	l.fields.Debug(msg, keysAndValues...)
+}
+
+func (l defaultLogger) Info(msg string, keysAndValues ...interface{}) {
+	l.fields.Info(msg, keysAndValues...)
+}
+
+func (l defaultLogger) Warn(msg string, keysAndValues ...interface{}) {
+	l.fields.Warn(msg, keysAndValues...)
+}
+
+func (l defaultLogger) Error(msg string, keysAndValues ...interface{}) {
+	l.fields.Error(msg, keysAndValues...)
+}
+
+func (l defaultLogger) With(keysAndValues ...interface{}) Logger {
+	return defaultLogger{fields: l.fields.WithFields(keysAndValues...)}
+}
+
+// NopLogger discards every entry, for tests that do not inspect logs
+type NopLogger struct{}
+
+func (NopLogger) Debug(msg string, keysAndValues ...interface{}) {}
+func (NopLogger) Info(msg string, keysAndValues ...interface{})  {}
+func (NopLogger) Warn(msg string, keysAndValues ...interface{})  {}
+func (NopLogger) Error(msg string, keysAndValues ...interface{}) {}
+func (NopLogger) With(keysAndValues ...interface{}) Logger       { return NopLogger{} }
+
+// HandlerOption configures a DocsHandler created with NewDocsHandler or
+// NewDocsHandlerFromFile
+type HandlerOption func(*DocsHandler)
+
+// WithLogger sends the handler's log entries to logger instead of the service log
+func WithLogger(logger Logger) HandlerOption {
+	return func(h *DocsHandler) {
+		if logger != nil {
+			h.logger = logger
+		}
+	}
+}
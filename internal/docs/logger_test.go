@@ -0,0 +1,115 @@
+package docs
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// logEntry is one entry a capturingLogger received
+type logEntry struct {
+	level   string
+	message string
+	fields  map[string]interface{}
+}
+
+// capturingLogger is a Logger keeping every entry; loggers returned by With
+// share the parent's entries
+type capturingLogger struct {
+	mu      *sync.Mutex
+	entries *[]logEntry
+	fields  []interface{}
+}
+
+func newCapturingLogger() *capturingLogger {
+	return &capturingLogger{mu: &sync.Mutex{}, entries: &[]logEntry{}}
+}
+
+func (c *capturingLogger) log(level, msg string, keysAndValues []interface{}) {
+	fields := make(map[string]interface{})
+	all := append(append([]interface{}{}, c.fields...), keysAndValues...)
+	for i := 0; i+1 < len(all); i += 2 {
+		fields[fmt.Sprint(all[i])] = all[i+1]
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	*c.entries = append(*c.entries, logEntry{level, msg, fields})
+}
+
+func (c *capturingLogger) Debug(msg string, kv ...interface{}) { c.log("debug", msg, kv) }
+func (c *capturingLogger) Info(msg string, kv ...interface{})  { c.log("info", msg, kv) }
+func (c *capturingLogger) Warn(msg string, kv ...interface{})  { c.log("warn", msg, kv) }
+func (c *capturingLogger) Error(msg string, kv ...interface{}) { c.log("error", msg, kv) }
+
+func (c *capturingLogger) With(kv ...interface{}) Logger {
+	fields := append(append([]interface{}{}, c.fields...), kv...)
+	return &capturingLogger{mu: c.mu, entries: c.entries, fields: fields}
+}
+
+// withMessage returns the entries logged with message
+func (c *capturingLogger) withMessage(message string) []logEntry {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var entries []logEntry
+	for _, entry := range *c.entries {
+		if entry.message == message {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+// byLevel returns the messages logged at level
+func (c *capturingLogger) byLevel(level string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	var messages []string
+	for _, entry := range *c.entries {
+		if entry.level == level {
+			messages = append(messages, entry.message)
+		}
+	}
+	return messages
+}
+
+func TestWithLogger_MissingSpecWarns(t *testing.T) {
+	t.Chdir(t.TempDir())
+	logger := newCapturingLogger()
+	h, err := NewDocsHandler(nil, WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewDocsHandler() error = %v", err)
+	}
+
+	w := httptest.NewRecorder()
+	h.ServeOpenAPISpec(w, httptest.NewRequest(http.MethodGet, "/docs/openapi.yaml", nil))
+	if w.Code != http.StatusNotFound {
+		t.Fatalf("Expected status 404, got %d", w.Code)
+	}
+
+	expected := []string{"OpenAPI spec file not found"}
+	if warnings := logger.byLevel("warn"); len(warnings) != 1 || warnings[0] != expected[0] {
+		t.Errorf("Warn entries = %q, expected %q", warnings, expected)
+	}
+	entry := logger.withMessage(expected[0])[0]
+	if entry.fields["spec_path"] != specFilePath || entry.fields["path"] != "/docs/openapi.yaml" {
+		t.Errorf("Warn fields = %v, expected spec_path and request fields", entry.fields)
+	}
+	if errors := logger.byLevel("error"); len(errors) != 0 {
+		t.Errorf("Expected no errors for a missing spec, got %q", errors)
+	}
+}
+
+func TestWithLogger_ConfigFileNotFound(t *testing.T) {
+	logger := newCapturingLogger()
+	if _, err := NewDocsHandlerFromFile("missing-swagger.yaml", WithLogger(logger)); err != nil {
+		t.Fatalf("NewDocsHandlerFromFile() error = %v", err)
+	}
+
+	entries := logger.withMessage("Swagger config file not found, using defaults")
+	if len(entries) != 1 || entries[0].fields["config_path"] != "missing-swagger.yaml" {
+		t.Errorf("Expected one debug entry with config_path, got %v", entries)
+	}
+}
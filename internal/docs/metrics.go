@@ -0,0 +1,209 @@
+package docs
+
+import (
+	"math"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// Endpoint names passed to DocsHandler.WithMetrics and reported by DocsHandler.Metrics
+const (
+	MetricSwaggerUI   = "swagger_ui"
+	MetricOpenAPISpec = "openapi_spec"
+	MetricRouteIndex  = "route_index"
+	MetricDocs        = "docs"
+)
+
+// MetricsRecorder receives observations from a DocsHandler, for exporting them to
+// a metrics backend. Paths are route templates such as /docs/{path...} when the
+// request was routed by a pattern, keeping label cardinality bounded.
+type MetricsRecorder interface {
+	// RecordRequest is called once a request wrapped by WithMetrics has been
+	// answered, including requests WithRateLimit rejected
+	RecordRequest(path, method string, statusCode int, durationMs float64)
+	// RecordCacheHit is called when the OpenAPI spec is served from the spec cache
+	RecordCacheHit(path string)
+}
+
+// WithMetricsRecorder reports the requests wrapped by WithMetrics and spec cache
+// hits to recorder
+func WithMetricsRecorder(recorder MetricsRecorder) HandlerOption {
+	return func(h *DocsHandler) {
+		h.recorder = recorder
+	}
+}
+
+// recordCacheLookup counts a full spec request served from the spec cache or
+// read from disk, and reports hits to the MetricsRecorder, if any
+func (h *DocsHandler) recordCacheLookup(r *http.Request, hit bool) {
+	h.metrics.cacheLookup(MetricOpenAPISpec, hit)
+	if hit && h.recorder != nil {
+		h.recorder.RecordCacheHit(types.RouteTemplate(r))
+	}
+}
+
+// WithMetrics is the docs handlers' single instrumentation point: it counts each
+// request to next under endpoint for Metrics and reports it to the
+// MetricsRecorder, if any. It wraps WithRateLimit so rejected requests are
+// counted with their 429 status.
+func (h *DocsHandler) WithMetrics(endpoint string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		next(recorder, r)
+
+		h.metrics.observe(endpoint, start)
+		if h.recorder == nil {
+			return
+		}
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		duration := float64(time.Since(start)) / float64(time.Millisecond)
+		h.recorder.RecordRequest(types.RouteTemplate(r), r.Method, status, duration)
+	}
+}
+
+// EndpointMetrics counts the requests a docs endpoint has served and how long they
+// took. CacheHits and CacheMisses count spec cache lookups, made only by the
+// OpenAPI spec endpoint.
+type EndpointMetrics struct {
+	Requests      int64
+	TotalDuration time.Duration
+	MaxDuration   time.Duration
+	CacheHits     int64
+	CacheMisses   int64
+}
+
+// AverageDuration returns the mean request latency, or zero before any request
+func (m EndpointMetrics) AverageDuration() time.Duration {
+	if m.Requests == 0 {
+		return 0
+	}
+	return m.TotalDuration / time.Duration(m.Requests)
+}
+
+// latencyWindowSize is how many recent latencies each endpoint keeps for quantiles
+const latencyWindowSize = 1024
+
+// latencyWindow is a ring of an endpoint's most recent request latencies
+type latencyWindow struct {
+	samples []time.Duration
+	next    int
+}
+
+// add records a latency, overwriting the oldest once the window is full
+func (l *latencyWindow) add(d time.Duration) {
+	if len(l.samples) < latencyWindowSize {
+		l.samples = append(l.samples, d)
+		return
+	}
+	l.samples[l.next] = d
+	l.next = (l.next + 1) % latencyWindowSize
+}
+
+// quantile returns the q-quantile (0 < q <= 1) of the window by nearest rank, or
+// zero when it is empty
+func (l *latencyWindow) quantile(q float64) time.Duration {
+	if len(l.samples) == 0 {
+		return 0
+	}
+	sorted := slices.Clone(l.samples)
+	slices.Sort(sorted)
+	rank := int(math.Ceil(q*float64(len(sorted)))) - 1
+	return sorted[max(rank, 0)]
+}
+
+// requestMetrics accumulates EndpointMetrics per endpoint; the zero value is ready to use
+type requestMetrics struct {
+	mu        sync.Mutex
+	endpoints map[string]*EndpointMetrics
+	// recent holds the latest latencies per endpoint for quantiles
+	recent map[string]*latencyWindow
+}
+
+// observe records one request to endpoint that started at start
+func (m *requestMetrics) observe(endpoint string, start time.Time) {
+	elapsed := time.Since(start)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	metrics := m.endpoint(endpoint)
+	metrics.Requests++
+	metrics.TotalDuration += elapsed
+	if elapsed > metrics.MaxDuration {
+		metrics.MaxDuration = elapsed
+	}
+
+	if m.recent == nil {
+		m.recent = make(map[string]*latencyWindow)
+	}
+	window, ok := m.recent[endpoint]
+	if !ok {
+		window = &latencyWindow{}
+		m.recent[endpoint] = window
+	}
+	window.add(elapsed)
+}
+
+// cacheLookup records a spec cache hit or miss for endpoint
+func (m *requestMetrics) cacheLookup(endpoint string, hit bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if hit {
+		m.endpoint(endpoint).CacheHits++
+	} else {
+		m.endpoint(endpoint).CacheMisses++
+	}
+}
+
+// endpoint returns endpoint's metrics, creating them on first use; m.mu must be held
+func (m *requestMetrics) endpoint(endpoint string) *EndpointMetrics {
+	if m.endpoints == nil {
+		m.endpoints = make(map[string]*EndpointMetrics)
+	}
+	metrics, ok := m.endpoints[endpoint]
+	if !ok {
+		metrics = &EndpointMetrics{}
+		m.endpoints[endpoint] = metrics
+	}
+	return metrics
+}
+
+// quantile returns the q-quantile latency of endpoint's recent requests
+func (m *requestMetrics) quantile(endpoint string, q float64) time.Duration {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	window, ok := m.recent[endpoint]
+	if !ok {
+		return 0
+	}
+	return window.quantile(q)
+}
+
+// snapshot returns a copy of the metrics recorded so far
+func (m *requestMetrics) snapshot() map[string]EndpointMetrics {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshot := make(map[string]EndpointMetrics, len(m.endpoints))
+	for endpoint, metrics := range m.endpoints {
+		snapshot[endpoint] = *metrics
+	}
+	return snapshot
+}
+
+// Metrics returns request counts and latencies per endpoint wrapped by
+// WithMetrics, keyed by the endpoint name such as MetricSwaggerUI. Endpoints that
+// have not served a request are absent.
+func (h *DocsHandler) Metrics() map[string]EndpointMetrics {
+	return h.metrics.snapshot()
+}
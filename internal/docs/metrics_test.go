@@ -0,0 +1,158 @@
+package docs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordedRequest is one RecordRequest call
+type recordedRequest struct {
+	path       string
+	method     string
+	statusCode int
+	durationMs float64
+}
+
+// collectingRecorder is a MetricsRecorder keeping every call it receives
+type collectingRecorder struct {
+	mu        sync.Mutex
+	requests  []recordedRequest
+	cacheHits []string
+}
+
+func (c *collectingRecorder) RecordRequest(path, method string, statusCode int, durationMs float64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = append(c.requests, recordedRequest{path, method, statusCode, durationMs})
+}
+
+func (c *collectingRecorder) RecordCacheHit(path string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cacheHits = append(c.cacheHits, path)
+}
+
+// newRecordedDocsHandler returns a docs handler reporting to a collectingRecorder,
+// serving static docs from a docs root holding guide.md
+func newRecordedDocsHandler(t *testing.T) (*DocsHandler, *collectingRecorder) {
+	t.Helper()
+	t.Chdir(t.TempDir())
+
+	recorder := &collectingRecorder{}
+	h, err := NewDocsHandler(recorder)
+	if err != nil {
+		t.Fatalf("NewDocsHandler() error = %v", err)
+	}
+
+	root := t.TempDir()
+	if err := os.WriteFile(filepath.Join(root, "guide.md"), []byte("# Guide"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	h.swaggerConfig.DocsRoot = root
+	return h, recorder
+}
+
+func TestMetricsRecorder_StatusCodes(t *testing.T) {
+	h, recorder := newRecordedDocsHandler(t)
+
+	tests := []struct {
+		name    string
+		handler http.HandlerFunc
+		method  string
+		path    string
+		status  int
+	}{
+		{"swagger ui", h.WithMetrics(MetricSwaggerUI, h.ServeSwaggerUI), http.MethodGet, "/swagger", http.StatusOK},
+		{"swagger ui wrong method", h.WithMetrics(MetricSwaggerUI, h.ServeSwaggerUI), http.MethodPost, "/swagger", http.StatusMethodNotAllowed},
+		{"missing spec", h.WithMetrics(MetricOpenAPISpec, h.ServeOpenAPISpec), http.MethodGet, "/docs/openapi.yaml", http.StatusNotFound},
+		{"spec wrong method", h.WithMetrics(MetricOpenAPISpec, h.ServeOpenAPISpec), http.MethodDelete, "/docs/openapi.yaml", http.StatusMethodNotAllowed},
+		{"docs file", h.WithMetrics(MetricDocs, h.ServeDocs), http.MethodGet, "/docs/guide.md", http.StatusOK},
+		{"missing docs file", h.WithMetrics(MetricDocs, h.ServeDocs), http.MethodGet, "/docs/missing.md", http.StatusNotFound},
+		{"docs wrong method", h.WithMetrics(MetricDocs, h.ServeDocs), http.MethodPut, "/docs/guide.md", http.StatusMethodNotAllowed},
+	}
+
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			tt.handler(w, httptest.NewRequest(tt.method, tt.path, nil))
+			if w.Code != tt.status {
+				t.Fatalf("Expected status %d, got %d", tt.status, w.Code)
+			}
+
+			if len(recorder.requests) != i+1 {
+				t.Fatalf("Expected one recorded request per response, got %v", recorder.requests)
+			}
+			got := recorder.requests[i]
+			if got.path != tt.path || got.method != tt.method || got.statusCode != tt.status {
+				t.Errorf("Recorded %+v, expected %s %s with status %d", got, tt.method, tt.path, tt.status)
+			}
+			if got.durationMs < 0 {
+				t.Errorf("Recorded a negative duration %v", got.durationMs)
+			}
+		})
+	}
+}
+
+func TestMetricsRecorder_CacheHits(t *testing.T) {
+	h, recorder := newRecordedDocsHandler(t)
+	h.swaggerConfig.SpecCacheTTL = time.Hour
+	writeSpecFile(t, "openapi: 3.0.3\n")
+
+	fetchSpec(t, h)
+	if len(recorder.cacheHits) != 0 {
+		t.Fatalf("Expected the first request to miss the cache, got %v", recorder.cacheHits)
+	}
+	fetchSpec(t, h)
+	fetchSpec(t, h)
+	if len(recorder.cacheHits) != 2 || recorder.cacheHits[0] != "/docs/openapi.yaml" {
+		t.Errorf("Expected two cache hits for /docs/openapi.yaml, got %v", recorder.cacheHits)
+	}
+}
+
+func TestMetricsRecorder_NilIsNoop(t *testing.T) {
+	t.Chdir(t.TempDir())
+	h := newTestDocsHandler(t)
+
+	w := httptest.NewRecorder()
+	h.ServeDocs(w, httptest.NewRequest(http.MethodPost, "/docs/guide.md", nil))
+	if w.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405 without a recorder, got %d", w.Code)
+	}
+}
+
+func TestWithMetrics_CountsRateLimitedRequests(t *testing.T) {
+	h, recorder := newRecordedDocsHandler(t)
+	h.limiter = newRateLimiter(1)
+	t.Cleanup(h.Close)
+
+	handler := h.WithMetrics(MetricDocs, h.WithRateLimit(h.ServeDocs))
+	for range 2 {
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/docs/guide.md", nil))
+	}
+
+	if got := h.Metrics()[MetricDocs].Requests; got != 2 {
+		t.Errorf("Expected both docs requests counted, got %d", got)
+	}
+	if len(recorder.requests) != 2 || recorder.requests[1].statusCode != http.StatusTooManyRequests {
+		t.Errorf("Expected the rejected request recorded with status 429, got %v", recorder.requests)
+	}
+}
+
+func TestNewDocsHandlerFromFile_WithMetricsRecorder(t *testing.T) {
+	t.Chdir(t.TempDir())
+	recorder := &collectingRecorder{}
+	h, err := NewDocsHandlerFromFile("missing-swagger.yaml", WithMetricsRecorder(recorder), WithLogger(NopLogger{}))
+	if err != nil {
+		t.Fatalf("NewDocsHandlerFromFile() error = %v", err)
+	}
+
+	h.WithMetrics(MetricRouteIndex, h.ServeRouteIndex)(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/docs/routes.json", nil))
+	if len(recorder.requests) != 1 || recorder.requests[0].statusCode != http.StatusOK {
+		t.Errorf("Expected the route index request recorded, got %v", recorder.requests)
+	}
+}
@@ -0,0 +1,206 @@
+package docs
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"gopkg.in/yaml.v3"
+)
+
+// moduleTags returns the tags a module's operations are listed under: the module
+// name, which routes default to, plus any tags its routes set
+func moduleTags(module string, routes []types.RouteInfo) map[string]bool {
+	tags := map[string]bool{module: true}
+	for _, route := range routes {
+		for _, tag := range route.Tags {
+			tags[tag] = true
+		}
+	}
+	return tags
+}
+
+// filterSpecByTags returns the spec with only the operations listed under one of
+// tags. Top-level tags and reusable components no remaining operation refers to,
+// directly or through other components, are dropped; security schemes are kept.
+func filterSpecByTags(content []byte, tags map[string]bool) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(content, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+	if len(doc.Content) == 0 || doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("OpenAPI spec is not a mapping")
+	}
+	root := doc.Content[0]
+
+	if paths := mappingValue(root, "paths"); paths != nil {
+		filterMapping(paths, func(_ string, pathItem *yaml.Node) bool {
+			filterMapping(pathItem, func(key string, operation *yaml.Node) bool {
+				return !httpMethods[strings.ToLower(key)] || operationTagged(operation, tags)
+			})
+			return hasOperation(pathItem)
+		})
+	}
+
+	if specTags := mappingValue(root, "tags"); specTags != nil && specTags.Kind == yaml.SequenceNode {
+		kept := specTags.Content[:0]
+		for _, tag := range specTags.Content {
+			if name := mappingValue(tag, "name"); name != nil && tags[name.Value] {
+				kept = append(kept, tag)
+			}
+		}
+		specTags.Content = kept
+	}
+
+	pruneUnreferenced(root)
+
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(4)
+	if err := encoder.Encode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI spec: %w", err)
+	}
+	if err := encoder.Close(); err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI spec: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+// operationTagged reports whether an operation lists one of tags
+func operationTagged(operation *yaml.Node, tags map[string]bool) bool {
+	opTags := mappingValue(operation, "tags")
+	if opTags == nil {
+		return false
+	}
+	for _, tag := range opTags.Content {
+		if tags[tag.Value] {
+			return true
+		}
+	}
+	return false
+}
+
+// hasOperation reports whether a path item still holds an operation
+func hasOperation(pathItem *yaml.Node) bool {
+	for i := 0; i+1 < len(pathItem.Content); i += 2 {
+		if httpMethods[strings.ToLower(pathItem.Content[i].Value)] {
+			return true
+		}
+	}
+	return false
+}
+
+// pruneUnreferenced drops the components and $defs entries that nothing outside
+// them refers to, following references between entries. Security schemes are
+// referenced by name rather than $ref, so they are always kept.
+func pruneUnreferenced(root *yaml.Node) {
+	// Entries by their JSON pointer, such as "#/components/schemas/User"
+	entries := map[string]*yaml.Node{}
+	if components := mappingValue(root, "components"); components != nil {
+		for i := 0; i+1 < len(components.Content); i += 2 {
+			kind := components.Content[i].Value
+			if kind == "securitySchemes" {
+				continue
+			}
+			collectEntries(entries, "#/components/"+kind+"/", components.Content[i+1])
+		}
+	}
+	collectEntries(entries, "#/$defs/", mappingValue(root, "$defs"))
+
+	// Walk everything outside the pruned sections, then each entry as it is reached
+	used := map[string]bool{}
+	var pending []string
+	visit := func(node *yaml.Node) {
+		collectRefs(node, func(ref string) {
+			if _, ok := entries[ref]; ok && !used[ref] {
+				used[ref] = true
+				pending = append(pending, ref)
+			}
+		})
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		switch root.Content[i].Value {
+		case "components":
+			if schemes := mappingValue(root.Content[i+1], "securitySchemes"); schemes != nil {
+				visit(schemes)
+			}
+		case "$defs":
+		default:
+			visit(root.Content[i+1])
+		}
+	}
+	for len(pending) > 0 {
+		ref := pending[len(pending)-1]
+		pending = pending[:len(pending)-1]
+		visit(entries[ref])
+	}
+
+	keep := func(prefix string) func(string, *yaml.Node) bool {
+		return func(name string, _ *yaml.Node) bool { return used[prefix+name] }
+	}
+	if components := mappingValue(root, "components"); components != nil {
+		filterMapping(components, func(kind string, section *yaml.Node) bool {
+			if kind == "securitySchemes" {
+				return true
+			}
+			filterMapping(section, keep("#/components/"+kind+"/"))
+			return len(section.Content) > 0
+		})
+	}
+	if defs := mappingValue(root, "$defs"); defs != nil {
+		filterMapping(defs, keep("#/$defs/"))
+	}
+}
+
+// collectEntries records the entries of a components section under prefix
+func collectEntries(entries map[string]*yaml.Node, prefix string, section *yaml.Node) {
+	if section == nil || section.Kind != yaml.MappingNode {
+		return
+	}
+	for i := 0; i+1 < len(section.Content); i += 2 {
+		entries[prefix+section.Content[i].Value] = section.Content[i+1]
+	}
+}
+
+// collectRefs calls fn with every local reference under node. Besides $ref
+// values this covers discriminator mappings, whose values are references too.
+func collectRefs(node *yaml.Node, fn func(string)) {
+	if node == nil {
+		return
+	}
+	if node.Kind == yaml.ScalarNode && strings.HasPrefix(node.Value, "#/") {
+		fn(node.Value)
+		return
+	}
+	for _, child := range node.Content {
+		collectRefs(child, fn)
+	}
+}
+
+// filterMapping removes the entries of a mapping node for which keep returns false
+func filterMapping(node *yaml.Node, keep func(key string, value *yaml.Node) bool) {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return
+	}
+	kept := node.Content[:0]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if keep(node.Content[i].Value, node.Content[i+1]) {
+			kept = append(kept, node.Content[i], node.Content[i+1])
+		}
+	}
+	node.Content = kept
+}
+
+// mappingValue returns the value of key in a mapping node, or nil
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	if node == nil || node.Kind != yaml.MappingNode {
+		return nil
+	}
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,70 @@
+package docs
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// metricsEnabledConfigKey overrides SwaggerConfig.MetricsEnabled
+const metricsEnabledConfigKey = "docs.metrics_enabled"
+
+// PrometheusContentType is the media type of the Prometheus text exposition format
+const PrometheusContentType = "text/plain; version=0.0.4; charset=utf-8"
+
+// ServeMetricsEndpoint reports OpenAPI spec request counts, spec cache hits and
+// misses, p50/p99 spec latency, rate limited docs requests and when the spec
+// file was last regenerated, in the Prometheus text exposition format. It
+// answers 404 unless MetricsEnabled.
+func (h *DocsHandler) ServeMetricsEndpoint(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	if !h.swaggerConfig.MetricsEnabled {
+		http.Error(w, "Metrics are disabled", http.StatusNotFound)
+		return
+	}
+
+	spec := h.metrics.snapshot()[MetricOpenAPISpec]
+
+	var b strings.Builder
+	WriteMetric(&b, "docs_spec_requests_total", "counter", "OpenAPI spec requests served.",
+		fmt.Sprintf("docs_spec_requests_total %d", spec.Requests))
+	WriteMetric(&b, "docs_spec_cache_hits_total", "counter", "OpenAPI spec requests served from the spec cache.",
+		fmt.Sprintf("docs_spec_cache_hits_total %d", spec.CacheHits))
+	WriteMetric(&b, "docs_spec_cache_misses_total", "counter", "OpenAPI spec requests that read the spec file from disk.",
+		fmt.Sprintf("docs_spec_cache_misses_total %d", spec.CacheMisses))
+	WriteMetric(&b, "docs_spec_request_duration_seconds", "summary", "OpenAPI spec request latency; quantiles cover recent requests.",
+		fmt.Sprintf(`docs_spec_request_duration_seconds{quantile="0.5"} %g`, h.metrics.quantile(MetricOpenAPISpec, 0.5).Seconds()),
+		fmt.Sprintf(`docs_spec_request_duration_seconds{quantile="0.99"} %g`, h.metrics.quantile(MetricOpenAPISpec, 0.99).Seconds()),
+		fmt.Sprintf("docs_spec_request_duration_seconds_sum %g", spec.TotalDuration.Seconds()),
+		fmt.Sprintf("docs_spec_request_duration_seconds_count %d", spec.Requests))
+
+	WriteMetric(&b, "docs_rate_limited_requests_total", "counter", "Docs requests rejected by the rate limit.",
+		fmt.Sprintf("docs_rate_limited_requests_total %d", h.rateLimited()))
+
+	// The generator rewrites the spec file, so its modification time is the last regeneration
+	var regenerated float64
+	if info, err := os.Stat(h.resolveSpecPath()); err == nil {
+		regenerated = float64(info.ModTime().UnixNano()) / float64(time.Second)
+	}
+	WriteMetric(&b, "docs_spec_last_regeneration_timestamp_seconds", "gauge", "Unix time the OpenAPI spec file was last written, or 0 when it is missing.",
+		fmt.Sprintf("docs_spec_last_regeneration_timestamp_seconds %g", regenerated))
+
+	w.Header().Set("Content-Type", PrometheusContentType)
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(b.String()))
+}
+
+// WriteMetric writes one metric family's HELP and TYPE lines followed by its
+// samples, for the Prometheus endpoints of this and other packages
+func WriteMetric(b *strings.Builder, name, metricType, help string, samples ...string) {
+	fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s %s\n", name, help, name, metricType)
+	for _, sample := range samples {
+		b.WriteString(sample)
+		b.WriteByte('\n')
+	}
+}
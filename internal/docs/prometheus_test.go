@@ -0,0 +1,120 @@
+package docs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
+)
+
+// fetchMetrics requests the metrics endpoint and returns the recorded response
+func fetchMetrics(h *DocsHandler) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeMetricsEndpoint(rec, httptest.NewRequest(http.MethodGet, "/docs/metrics", nil))
+	return rec
+}
+
+func TestServeMetricsEndpoint_PrometheusFormat(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeSpecFile(t, "openapi: 3.0.3\n")
+
+	h := newTestDocsHandler(t)
+	h.swaggerConfig.MetricsEnabled = true
+	h.swaggerConfig.SpecCacheTTL = time.Minute
+
+	fetchSpec(t, h)
+	fetchSpec(t, h)
+	fetchSpec(t, h)
+
+	rec := fetchMetrics(h)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	if contentType := rec.Header().Get("Content-Type"); contentType != PrometheusContentType {
+		t.Errorf("Content-Type = %q, expected %q", contentType, PrometheusContentType)
+	}
+
+	body := rec.Body.String()
+	for _, expected := range []string{
+		"# TYPE docs_spec_requests_total counter\n",
+		"docs_spec_requests_total 3\n",
+		"# TYPE docs_spec_cache_hits_total counter\n",
+		"docs_spec_cache_hits_total 2\n",
+		"# TYPE docs_spec_cache_misses_total counter\n",
+		"docs_spec_cache_misses_total 1\n",
+		"# TYPE docs_spec_request_duration_seconds summary\n",
+		`docs_spec_request_duration_seconds{quantile="0.5"} `,
+		`docs_spec_request_duration_seconds{quantile="0.99"} `,
+		"docs_spec_request_duration_seconds_sum ",
+		"docs_spec_request_duration_seconds_count 3\n",
+		"# TYPE docs_spec_last_regeneration_timestamp_seconds gauge\n",
+		"docs_spec_last_regeneration_timestamp_seconds ",
+		"# TYPE docs_rate_limited_requests_total counter\n",
+		"docs_rate_limited_requests_total 0\n",
+	} {
+		if !strings.Contains(body, expected) {
+			t.Errorf("Metrics should contain %q, got:\n%s", expected, body)
+		}
+	}
+	if strings.Contains(body, "docs_spec_last_regeneration_timestamp_seconds 0\n") {
+		t.Error("Regeneration time should come from the spec file")
+	}
+}
+
+func TestServeMetricsEndpoint_Disabled(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	h := newTestDocsHandler(t)
+	if rec := fetchMetrics(h); rec.Code != http.StatusNotFound {
+		t.Errorf("Expected 404 when metrics are disabled, got %d", rec.Code)
+	}
+}
+
+func TestMetricsRoute_Internal(t *testing.T) {
+	for _, route := range types.GetRegisteredRoutes() {
+		if route.Path == "/docs/metrics" {
+			if route.Visibility != types.VisibilityInternal {
+				t.Errorf("/docs/metrics should be internal so the public spec leaves it out, got %q", route.Visibility)
+			}
+			return
+		}
+	}
+	t.Fatal("/docs/metrics should be registered")
+}
+
+func TestNewDocsHandler_MetricsEnabledConfigKey(t *testing.T) {
+	t.Chdir(t.TempDir())
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+	config.SetForTest(metricsEnabledConfigKey, true)
+
+	h := newTestDocsHandler(t)
+	if !h.swaggerConfig.MetricsEnabled {
+		t.Error("docs.metrics_enabled should enable metrics")
+	}
+	if rec := fetchMetrics(h); rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200 once docs.metrics_enabled is set after init, got %d", rec.Code)
+	}
+}
+
+func TestLatencyWindow_Quantile(t *testing.T) {
+	var window latencyWindow
+	if got := window.quantile(0.5); got != 0 {
+		t.Errorf("Empty window quantile = %v, expected 0", got)
+	}
+
+	for i := 1; i <= latencyWindowSize+100; i++ {
+		window.add(time.Duration(i) * time.Millisecond)
+	}
+	// The first 100 samples were overwritten, leaving 101ms through 1124ms
+	if got := window.quantile(0.5); got != 612*time.Millisecond {
+		t.Errorf("p50 = %v, expected 612ms", got)
+	}
+	if got := window.quantile(0.99); got != 1114*time.Millisecond {
+		t.Errorf("p99 = %v, expected 1114ms", got)
+	}
+}
@@ -0,0 +1,149 @@
+package docs
+
+import (
+	"math"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// maxRequestsPerSecondConfigKey overrides SwaggerConfig.MaxRequestsPerSecond
+const maxRequestsPerSecondConfigKey = "docs.max_requests_per_second"
+
+// Token bucket tuning. Tokens are counted in thousandths so fractional rates such
+// as 0.5 requests per second refill evenly; the ticker never fires faster than
+// minRefillInterval, adding several tokens per tick at high rates.
+const (
+	tokenScale        = 1000
+	minRefillInterval = 10 * time.Millisecond
+)
+
+// rejectionLogInterval is the least time between rate limit warnings; rejections
+// in between are only counted
+const rejectionLogInterval = 10 * time.Second
+
+// rateLimiter is a token bucket holding up to one second of requests, refilled by
+// a ticker. Requests take a token with a compare-and-swap, so the request path
+// never blocks on a lock.
+type rateLimiter struct {
+	tokens   atomic.Int64
+	capacity int64
+	perTick  int64
+
+	ticker   *time.Ticker
+	done     chan struct{}
+	stopOnce sync.Once
+
+	// rejected counts requests refused; loggedRejected is its value and
+	// lastLogged the Unix nanoseconds of the last warning
+	rejected       atomic.Int64
+	loggedRejected atomic.Int64
+	lastLogged     atomic.Int64
+}
+
+// newRateLimiter starts a full bucket allowing perSecond requests per second
+func newRateLimiter(perSecond float64) *rateLimiter {
+	interval := time.Duration(float64(time.Second) / perSecond)
+	if interval < minRefillInterval {
+		interval = minRefillInterval
+	}
+
+	l := &rateLimiter{
+		capacity: int64(math.Max(1, perSecond) * tokenScale),
+		perTick:  int64(math.Max(1, perSecond*interval.Seconds()*tokenScale)),
+		ticker:   time.NewTicker(interval),
+		done:     make(chan struct{}),
+	}
+	l.tokens.Store(l.capacity)
+
+	go func() {
+		for {
+			select {
+			case <-l.ticker.C:
+				l.refill()
+			case <-l.done:
+				return
+			}
+		}
+	}()
+	return l
+}
+
+// allow takes a token, reporting false when the bucket is empty
+func (l *rateLimiter) allow() bool {
+	for {
+		tokens := l.tokens.Load()
+		if tokens < tokenScale {
+			return false
+		}
+		if l.tokens.CompareAndSwap(tokens, tokens-tokenScale) {
+			return true
+		}
+	}
+}
+
+// reject counts a refused request and reports whether to log it, at most once
+// per rejectionLogInterval, along with how many rejections that warning covers
+func (l *rateLimiter) reject(now time.Time) (bool, int64) {
+	rejected := l.rejected.Add(1)
+	last := l.lastLogged.Load()
+	if now.UnixNano()-last < int64(rejectionLogInterval) || !l.lastLogged.CompareAndSwap(last, now.UnixNano()) {
+		return false, 0
+	}
+	return true, rejected - l.loggedRejected.Swap(rejected)
+}
+
+// refill adds one tick's tokens without exceeding the bucket's capacity
+func (l *rateLimiter) refill() {
+	for {
+		tokens := l.tokens.Load()
+		next := min(tokens+l.perTick, l.capacity)
+		if next == tokens || l.tokens.CompareAndSwap(tokens, next) {
+			return
+		}
+	}
+}
+
+// stop stops the refill ticker
+func (l *rateLimiter) stop() {
+	l.stopOnce.Do(func() {
+		l.ticker.Stop()
+		close(l.done)
+	})
+}
+
+// WithRateLimit rejects requests beyond SwaggerConfig.MaxRequestsPerSecond with
+// 429 Too Many Requests and Retry-After: 1. All wrapped docs handlers share one
+// budget; the handler is unlimited when no limit is configured. Rejections are
+// counted for ServeMetricsEndpoint and logged at most once per
+// rejectionLogInterval, so a flood of requests does not flood the log.
+func (h *DocsHandler) WithRateLimit(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if h.limiter != nil && !h.limiter.allow() {
+			if log, rejected := h.limiter.reject(time.Now()); log {
+				h.requestLog(r).Warn("Docs rate limit exceeded",
+					"max_requests_per_second", h.swaggerConfig.MaxRequestsPerSecond, "rejected", rejected)
+			}
+			w.Header().Set("Retry-After", "1")
+			http.Error(w, "Too many requests", http.StatusTooManyRequests)
+			return
+		}
+		next(w, r)
+	}
+}
+
+// rateLimited returns how many requests WithRateLimit has rejected
+func (h *DocsHandler) rateLimited() int64 {
+	if h.limiter == nil {
+		return 0
+	}
+	return h.limiter.rejected.Load()
+}
+
+// Close stops the rate limiter's refill ticker once the handler is no longer used
+func (h *DocsHandler) Close() {
+	if h.limiter != nil {
+		h.limiter.stop()
+	}
+}
@@ -0,0 +1,131 @@
+package docs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/config"
+)
+
+// newRateLimitedDocsHandler returns a docs handler limited to perSecond requests
+// per second by the docs.max_requests_per_second config key
+func newRateLimitedDocsHandler(t *testing.T, perSecond float64) *DocsHandler {
+	t.Helper()
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+	config.SetForTest(maxRequestsPerSecondConfigKey, perSecond)
+
+	h := newTestDocsHandler(t)
+	t.Cleanup(h.Close)
+	return h
+}
+
+func TestWithRateLimit_TightLoop(t *testing.T) {
+	const limit = 20.0
+	t.Chdir(t.TempDir())
+	h := newRateLimitedDocsHandler(t, limit)
+	writeSpecFile(t, "openapi: 3.0.3\n")
+	handler := h.WithRateLimit(h.ServeOpenAPISpec)
+
+	var allowed, limited int
+	start := time.Now()
+	for time.Since(start) < 500*time.Millisecond {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/docs/openapi.yaml", nil))
+
+		switch w.Code {
+		case http.StatusOK:
+			allowed++
+		case http.StatusTooManyRequests:
+			limited++
+			if retryAfter := w.Header().Get("Retry-After"); retryAfter != "1" {
+				t.Fatalf("Retry-After = %q, expected 1", retryAfter)
+			}
+		default:
+			t.Fatalf("Unexpected status %d", w.Code)
+		}
+	}
+	elapsed := time.Since(start)
+
+	if allowed == 0 || limited == 0 {
+		t.Fatalf("Expected both 200 and 429 responses, got %d allowed and %d limited", allowed, limited)
+	}
+	// A full bucket allows one second's burst, then the refill rate
+	if ceiling := int(limit + limit*elapsed.Seconds() + 1); allowed > ceiling {
+		t.Errorf("Allowed %d requests in %v, expected at most %d at %.0f per second", allowed, elapsed, ceiling, limit)
+	}
+}
+
+func TestWithRateLimit_Refills(t *testing.T) {
+	h := newRateLimitedDocsHandler(t, 50)
+	handler := h.WithRateLimit(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	serve := func() int {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+		return w.Code
+	}
+
+	for serve() == http.StatusOK {
+	}
+	time.Sleep(100 * time.Millisecond)
+	if code := serve(); code != http.StatusOK {
+		t.Errorf("Expected the bucket to refill after 100ms at 50 per second, got %d", code)
+	}
+}
+
+func TestWithRateLimit_DisabledByDefault(t *testing.T) {
+	h := newTestDocsHandler(t)
+	if h.limiter != nil {
+		t.Fatal("Expected no rate limiter without MaxRequestsPerSecond")
+	}
+
+	handler := h.WithRateLimit(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	for i := 0; i < 1000; i++ {
+		w := httptest.NewRecorder()
+		handler(w, httptest.NewRequest(http.MethodGet, "/docs", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Request %d got status %d without a limit", i, w.Code)
+		}
+	}
+}
+
+func TestWithRateLimit_SamplesRejectionWarnings(t *testing.T) {
+	t.Chdir(t.TempDir())
+	logger := newCapturingLogger()
+	h, err := NewDocsHandler(nil, WithLogger(logger))
+	if err != nil {
+		t.Fatalf("NewDocsHandler() error = %v", err)
+	}
+	h.limiter = newRateLimiter(1)
+	t.Cleanup(h.Close)
+	handler := h.WithRateLimit(h.ServeRouteIndex)
+
+	for range 10 {
+		handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/docs/routes.json", nil))
+	}
+
+	if got := h.rateLimited(); got != 9 {
+		t.Errorf("Expected 9 rejections counted, got %d", got)
+	}
+	warnings := logger.withMessage("Docs rate limit exceeded")
+	if len(warnings) != 1 {
+		t.Fatalf("Expected one sampled warning for a burst of rejections, got %d", len(warnings))
+	}
+	if warnings[0].fields["rejected"] != int64(1) {
+		t.Errorf("First warning should cover one rejection, got %v", warnings[0].fields["rejected"])
+	}
+
+	// The next warning reports every rejection since the last one
+	h.limiter.lastLogged.Add(-int64(rejectionLogInterval))
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/docs/routes.json", nil))
+	warnings = logger.withMessage("Docs rate limit exceeded")
+	if len(warnings) != 2 || warnings[1].fields["rejected"] != int64(9) {
+		t.Errorf("Expected a second warning covering 9 rejections, got %v", warnings)
+	}
+}
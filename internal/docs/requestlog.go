@@ -0,0 +1,58 @@
+package docs
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// requestLog returns the handler's logger carrying the request's method, path,
+// route template and remote address
+func (h *DocsHandler) requestLog(r *http.Request) Logger {
+	return h.logger.With("method", r.Method, "path", r.URL.Path, "route", types.RouteTemplate(r), "remote_addr", r.RemoteAddr)
+}
+
+// statusRecorder remembers the status code a docs handler writes
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (s *statusRecorder) WriteHeader(status int) {
+	if s.status == 0 {
+		s.status = status
+	}
+	s.ResponseWriter.WriteHeader(status)
+}
+
+func (s *statusRecorder) Write(p []byte) (int, error) {
+	if s.status == 0 {
+		s.status = http.StatusOK
+	}
+	return s.ResponseWriter.Write(p)
+}
+
+// Push passes through to the underlying writer so Swagger UI assets can still be pushed
+func (s *statusRecorder) Push(target string, opts *http.PushOptions) error {
+	if pusher, ok := s.ResponseWriter.(http.Pusher); ok {
+		return pusher.Push(target, opts)
+	}
+	return http.ErrNotSupported
+}
+
+// WithRequestLog logs each request a docs handler serves with structured method,
+// path, route, remote_addr, status and duration_ms fields through the handler's Logger
+func (h *DocsHandler) WithRequestLog(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w}
+		next(recorder, r)
+
+		status := recorder.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+		h.requestLog(r).Debug("Served docs request", "status", status, "duration_ms", time.Since(start).Milliseconds())
+	}
+}
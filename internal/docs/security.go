@@ -0,0 +1,119 @@
+package docs
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WithSecurityHeaders sets headers that stop browsers from MIME sniffing docs
+// responses, leaking docs URLs through the Referer header or framing the docs.
+// The Swagger UI page adds its own Content-Security-Policy.
+func WithSecurityHeaders(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Header().Set("X-Frame-Options", "DENY")
+		next(w, r)
+	}
+}
+
+// swaggerUIPolicy returns the Content-Security-Policy for the Swagger UI page.
+// Scripts, styles, images and fonts load only from the asset origin, which is
+// 'self' when assets are served by this server; the inline bootstrap script is
+// allowed by nonce. Swagger UI sets inline styles, so style-src permits them.
+func (h *DocsHandler) swaggerUIPolicy(nonce string) string {
+	assets := h.assetSource()
+
+	return strings.Join([]string{
+		"default-src 'none'",
+		"script-src " + assets + " 'nonce-" + nonce + "'",
+		"style-src " + assets + " 'unsafe-inline'",
+		"img-src " + assets + " data:",
+		"font-src " + assets,
+		"connect-src " + strings.Join(h.connectSources(), " "),
+		"base-uri 'none'",
+		"form-action 'self'",
+		"frame-ancestors 'none'",
+	}, "; ")
+}
+
+// assetSource returns the CSP source the Swagger UI assets load from: the origin of
+// an absolute AssetsURL such as a CDN, or 'self' for assets served by this server
+func (h *DocsHandler) assetSource() string {
+	if origin := urlOrigin(h.assetsURL()); origin != "" {
+		return origin
+	}
+	return "'self'"
+}
+
+// connectSources returns the CSP sources Swagger UI fetches from: this server for
+// the spec, the spec's servers for try-it-out requests, plus the origins of
+// additional specs and the OAuth2 token endpoint
+func (h *DocsHandler) connectSources() []string {
+	origins := make(map[string]bool)
+	for _, server := range h.specServerURLs() {
+		if origin := urlOrigin(server); origin != "" {
+			origins[origin] = true
+		}
+	}
+	for _, spec := range h.swaggerConfig.AdditionalSpecs {
+		if origin := urlOrigin(spec.URL); origin != "" {
+			origins[origin] = true
+		}
+	}
+	if origin := urlOrigin(h.oauth2.TokenURL); origin != "" {
+		origins[origin] = true
+	}
+
+	sources := make([]string, 0, len(origins))
+	for origin := range origins {
+		sources = append(sources, origin)
+	}
+	sort.Strings(sources)
+	return append([]string{"'self'"}, sources...)
+}
+
+// specServerURLs returns the server URLs listed in the spec file, which Swagger UI
+// sends try-it-out requests to. URLs with server variables cannot be resolved to
+// an origin and are skipped, as is a spec that is missing or does not parse.
+func (h *DocsHandler) specServerURLs() []string {
+	content, err := os.ReadFile(h.resolveSpecPath())
+	if err != nil {
+		return nil
+	}
+
+	var spec struct {
+		Servers []struct {
+			URL string `yaml:"url"`
+		} `yaml:"servers"`
+	}
+	if err := yaml.Unmarshal(content, &spec); err != nil {
+		return nil
+	}
+
+	urls := make([]string, 0, len(spec.Servers))
+	for _, server := range spec.Servers {
+		if !strings.Contains(server.URL, "{") {
+			urls = append(urls, server.URL)
+		}
+	}
+	return urls
+}
+
+// urlOrigin returns the scheme://host origin of an absolute URL, or "" for
+// relative URLs and URLs that do not parse
+func urlOrigin(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return ""
+	}
+	if u.Scheme == "" {
+		return u.Host // Protocol-relative URLs load over the page's scheme
+	}
+	return u.Scheme + "://" + u.Host
+}
@@ -0,0 +1,156 @@
+package docs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/openapi/oauth"
+)
+
+// serveSwaggerUI serves the Swagger UI page through WithSecurityHeaders
+func serveSwaggerUI(t *testing.T, h *DocsHandler) *httptest.ResponseRecorder {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	WithSecurityHeaders(h.ServeSwaggerUI)(w, httptest.NewRequest(http.MethodGet, "http://example.com/swagger", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", w.Code)
+	}
+	return w
+}
+
+// cspDirectives splits a Content-Security-Policy into its directives keyed by name
+func cspDirectives(policy string) map[string][]string {
+	directives := make(map[string][]string)
+	for _, directive := range strings.Split(policy, ";") {
+		fields := strings.Fields(directive)
+		if len(fields) > 0 {
+			directives[fields[0]] = fields[1:]
+		}
+	}
+	return directives
+}
+
+func TestWithSecurityHeaders(t *testing.T) {
+	w := httptest.NewRecorder()
+	WithSecurityHeaders(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})(w, httptest.NewRequest(http.MethodGet, "/docs/openapi.yaml", nil))
+
+	expected := map[string]string{
+		"X-Content-Type-Options": "nosniff",
+		"Referrer-Policy":        "no-referrer",
+		"X-Frame-Options":        "DENY",
+	}
+	for header, value := range expected {
+		if got := w.Header().Get(header); got != value {
+			t.Errorf("%s = %q, expected %q", header, got, value)
+		}
+	}
+}
+
+func TestServeSwaggerUI_SecurityHeaders(t *testing.T) {
+	w := serveSwaggerUI(t, newTestDocsHandler(t))
+
+	for _, header := range []string{"X-Content-Type-Options", "Referrer-Policy", "X-Frame-Options", "Content-Security-Policy"} {
+		if w.Header().Get(header) == "" {
+			t.Errorf("Expected %s header on the Swagger UI page", header)
+		}
+	}
+}
+
+func TestServeSwaggerUI_CSPAllowsAssetOriginOnly(t *testing.T) {
+	tests := []struct {
+		name      string
+		assetsURL string
+		expected  string
+	}{
+		{"default CDN", "", "https://unpkg.com"},
+		{"custom CDN", "https://cdn.example.com/swagger-ui/5.9.0/", "https://cdn.example.com"},
+		{"served locally", "/docs/swagger-ui", "'self'"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := newTestDocsHandler(t)
+			h.swaggerConfig.AssetsURL = tt.assetsURL
+
+			w := serveSwaggerUI(t, h)
+			directives := cspDirectives(w.Header().Get("Content-Security-Policy"))
+
+			if got := directives["default-src"]; len(got) != 1 || got[0] != "'none'" {
+				t.Errorf("default-src = %v, expected 'none'", got)
+			}
+			for _, name := range []string{"script-src", "style-src", "img-src", "font-src"} {
+				sources := directives[name]
+				if len(sources) == 0 || sources[0] != tt.expected {
+					t.Errorf("%s = %v, expected it to start with %s", name, sources, tt.expected)
+				}
+				for _, source := range sources[1:] {
+					if strings.Contains(source, "://") || source == "'self'" || source == "*" {
+						t.Errorf("%s allows %s besides the asset origin", name, source)
+					}
+				}
+			}
+
+			body := w.Body.String()
+			if !strings.Contains(body, `src="`+h.assetURL(swaggerUIBundle)+`"`) {
+				t.Errorf("Expected the bundle to load from %s", h.assetURL(swaggerUIBundle))
+			}
+		})
+	}
+}
+
+func TestServeSwaggerUI_CSPNonceMatchesInlineScript(t *testing.T) {
+	w := serveSwaggerUI(t, newTestDocsHandler(t))
+
+	match := regexp.MustCompile(`'nonce-([^']+)'`).FindStringSubmatch(w.Header().Get("Content-Security-Policy"))
+	if match == nil {
+		t.Fatal("Expected a nonce in script-src")
+	}
+	if !strings.Contains(w.Body.String(), `<script nonce="`+match[1]+`">`) {
+		t.Error("Expected the inline script to carry the policy's nonce")
+	}
+
+	other := serveSwaggerUI(t, newTestDocsHandler(t))
+	if strings.Contains(other.Header().Get("Content-Security-Policy"), match[0]) {
+		t.Error("Expected a fresh nonce for every page")
+	}
+}
+
+func TestSwaggerUIPolicy_ConnectSources(t *testing.T) {
+	t.Chdir(t.TempDir())
+	h := newTestDocsHandler(t)
+	h.swaggerConfig.AdditionalSpecs = []SpecEntry{
+		{Name: "v2", URL: "/docs/v2/openapi.yaml"},
+		{Name: "Admin", URL: "https://admin.example.com/openapi.yaml"},
+	}
+	h.oauth2 = oauth.Config{TokenURL: "https://idp.example.com/oauth/token"}
+
+	got := strings.Join(cspDirectives(h.swaggerUIPolicy("n"))["connect-src"], " ")
+	expected := "'self' https://admin.example.com https://idp.example.com"
+	if got != expected {
+		t.Errorf("connect-src = %q, expected %q", got, expected)
+	}
+}
+
+func TestSwaggerUIPolicy_ConnectSourcesIncludeSpecServers(t *testing.T) {
+	t.Chdir(t.TempDir())
+	writeSpecFile(t, `openapi: 3.0.3
+servers:
+  - url: https://api.example.com/v1
+  - url: https://api.example.com/v2
+  - url: /relative
+  - url: https://{region}.example.com
+`)
+	h := newTestDocsHandler(t)
+
+	got := strings.Join(cspDirectives(h.swaggerUIPolicy("n"))["connect-src"], " ")
+	expected := "'self' https://api.example.com"
+	if got != expected {
+		t.Errorf("connect-src = %q, expected %q", got, expected)
+	}
+}
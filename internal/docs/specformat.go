@@ -0,0 +1,178 @@
+package docs
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Representations the OpenAPI spec is served in
+const (
+	specFormatYAML = "yaml"
+	specFormatJSON = "json"
+)
+
+// specContentTypes maps each spec format to its response content type
+var specContentTypes = map[string]string{
+	specFormatYAML: "application/x-yaml",
+	specFormatJSON: "application/json",
+}
+
+// specFormat picks the representation of the spec for a request. The .yaml and
+// .json paths are fixed; any other path, such as /docs/openapi, negotiates with
+// the Accept header and falls back to YAML.
+func specFormat(r *http.Request) string {
+	switch {
+	case strings.HasSuffix(r.URL.Path, ".json"):
+		return specFormatJSON
+	case strings.HasSuffix(r.URL.Path, ".yaml"), strings.HasSuffix(r.URL.Path, ".yml"):
+		return specFormatYAML
+	default:
+		return negotiateSpecFormat(r.Header.Get("Accept"))
+	}
+}
+
+// Specificity of the Accept media ranges matching a spec format, from least to
+// most specific
+const (
+	matchNone = iota
+	matchAnyType
+	matchAnySubtype
+	matchExact
+)
+
+// acceptPreference is the weight an Accept header gives a spec format: the q of
+// its most specific matching media range
+type acceptPreference struct {
+	q           float64
+	specificity int
+}
+
+// match weighs a media range matching the format; a more specific range
+// overrides less specific ones regardless of q, as RFC 9110 prescribes
+func (p *acceptPreference) match(q float64, specificity int) {
+	if specificity > p.specificity || (specificity == p.specificity && q > p.q) {
+		p.q, p.specificity = q, specificity
+	}
+}
+
+// negotiateSpecFormat returns JSON when the Accept header weighs a JSON media
+// type above every YAML one, or equally but through a more specific media range,
+// as in "application/json, */*". It returns YAML otherwise.
+func negotiateSpecFormat(accept string) string {
+	var jsonPref, yamlPref acceptPreference
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if raw, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(raw, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		switch {
+		case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+			jsonPref.match(q, matchExact)
+		case strings.HasSuffix(mediaType, "yaml"):
+			yamlPref.match(q, matchExact)
+		case mediaType == "application/*":
+			jsonPref.match(q, matchAnySubtype)
+			yamlPref.match(q, matchAnySubtype)
+		case mediaType == "*/*":
+			jsonPref.match(q, matchAnyType)
+			yamlPref.match(q, matchAnyType)
+		}
+	}
+
+	if jsonPref.q > yamlPref.q || (jsonPref.q == yamlPref.q && jsonPref.q > 0 && jsonPref.specificity > yamlPref.specificity) {
+		return specFormatJSON
+	}
+	return specFormatYAML
+}
+
+// writeSpec writes spec bytes in format. The negotiated endpoint varies with the
+// Accept header, so every response says so for shared caches.
+func writeSpec(w http.ResponseWriter, format string, content []byte) {
+	w.Header().Set("Content-Type", specContentTypes[format])
+	w.Header().Set("Access-Control-Allow-Origin", "*") // Allow CORS for Swagger UI
+	w.Header().Add("Vary", "Accept")
+	w.WriteHeader(http.StatusOK)
+	w.Write(content)
+}
+
+// specToJSON converts a YAML spec to JSON structurally, so numbers and booleans
+// keep their types and strings are escaped by the JSON encoder
+func specToJSON(content []byte) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(content, &root); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+
+	spec, err := nodeValue(&root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAPI spec: %w", err)
+	}
+
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(spec); err != nil {
+		return nil, fmt.Errorf("failed to encode OpenAPI spec as JSON: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// nodeValue converts a YAML node to a value encoding/json accepts. Mapping keys
+// become strings, so unquoted status codes such as 200 stay valid JSON keys, and
+// timestamps keep their original text instead of being reformatted as time.Time.
+func nodeValue(node *yaml.Node) (interface{}, error) {
+	switch node.Kind {
+	case yaml.DocumentNode:
+		if len(node.Content) == 0 {
+			return nil, nil
+		}
+		return nodeValue(node.Content[0])
+	case yaml.AliasNode:
+		return nodeValue(node.Alias)
+	case yaml.MappingNode:
+		mapping := make(map[string]interface{}, len(node.Content)/2)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			value, err := nodeValue(node.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			mapping[node.Content[i].Value] = value
+		}
+		return mapping, nil
+	case yaml.SequenceNode:
+		sequence := make([]interface{}, 0, len(node.Content))
+		for _, child := range node.Content {
+			value, err := nodeValue(child)
+			if err != nil {
+				return nil, err
+			}
+			sequence = append(sequence, value)
+		}
+		return sequence, nil
+	default:
+		if node.ShortTag() == "!!timestamp" {
+			return node.Value, nil
+		}
+		var value interface{}
+		if err := node.Decode(&value); err != nil {
+			return nil, err
+		}
+		return value, nil
+	}
+}
@@ -0,0 +1,206 @@
+package docs
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+const negotiationSpec = `openapi: 3.0.3
+info:
+  title: Test API
+  version: 1.2.0
+paths:
+  /items:
+    get:
+      deprecated: true
+      responses:
+        "200":
+          description: Success <ok>
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  count:
+                    type: integer
+                    minimum: 0
+                    maximum: 255
+                    example: 42
+                  ratio:
+                    type: number
+                    example: 0.5
+                  created:
+                    type: string
+                    example: 2024-01-01
+`
+
+// serveSpecAt requests path with an optional Accept header
+func serveSpecAt(t *testing.T, h *DocsHandler, path, accept string) *httptest.ResponseRecorder {
+	t.Helper()
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	rec := httptest.NewRecorder()
+	h.ServeOpenAPISpec(rec, req)
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET %s: expected status 200, got %d", path, rec.Code)
+	}
+	return rec
+}
+
+func TestServeOpenAPISpec_JSONMatchesYAML(t *testing.T) {
+	t.Chdir(t.TempDir())
+	h := newTestDocsHandler(t)
+	writeSpecFile(t, negotiationSpec)
+
+	yamlRec := serveSpecAt(t, h, "/docs/openapi.yaml", "")
+	jsonRec := serveSpecAt(t, h, "/docs/openapi.json", "")
+
+	if ct := jsonRec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("Expected application/json, got %q", ct)
+	}
+
+	var fromYAML, fromJSON interface{}
+	if err := yaml.Unmarshal(yamlRec.Body.Bytes(), &fromYAML); err != nil {
+		t.Fatalf("YAML spec does not parse: %v", err)
+	}
+	if err := json.Unmarshal(jsonRec.Body.Bytes(), &fromJSON); err != nil {
+		t.Fatalf("JSON spec does not parse: %v", err)
+	}
+
+	// Normalize both through JSON so YAML's int and JSON's float64 compare equal,
+	// which still catches numbers or booleans that became strings
+	roundTrip := func(v interface{}) interface{} {
+		data, err := json.Marshal(v)
+		if err != nil {
+			t.Fatalf("Failed to re-encode spec: %v", err)
+		}
+		var out interface{}
+		json.Unmarshal(data, &out)
+		return out
+	}
+	// YAML decodes the unquoted date as time.Time, which the JSON keeps as written
+	schemaOf := func(spec interface{}) map[string]interface{} {
+		paths := spec.(map[string]interface{})["paths"].(map[string]interface{})
+		response := paths["/items"].(map[string]interface{})["get"].(map[string]interface{})["responses"].(map[string]interface{})["200"]
+		content := response.(map[string]interface{})["content"].(map[string]interface{})["application/json"]
+		return content.(map[string]interface{})["schema"].(map[string]interface{})["properties"].(map[string]interface{})
+	}
+	created := schemaOf(fromJSON)["created"].(map[string]interface{})
+	if created["example"] != "2024-01-01" {
+		t.Errorf("Expected the date example kept as written, got %v", created["example"])
+	}
+	schemaOf(fromYAML)["created"].(map[string]interface{})["example"] = "2024-01-01"
+
+	if !reflect.DeepEqual(roundTrip(fromYAML), roundTrip(fromJSON)) {
+		t.Errorf("JSON spec differs from YAML spec\nYAML: %v\nJSON: %v", fromYAML, fromJSON)
+	}
+
+	count := schemaOf(fromJSON)["count"].(map[string]interface{})
+	if count["example"] != float64(42) || count["maximum"] != float64(255) {
+		t.Errorf("Expected numeric fields to stay numbers, got %v", count)
+	}
+	get := fromJSON.(map[string]interface{})["paths"].(map[string]interface{})["/items"].(map[string]interface{})["get"].(map[string]interface{})
+	if get["deprecated"] != true {
+		t.Errorf("Expected deprecated to stay a boolean, got %#v", get["deprecated"])
+	}
+}
+
+func TestServeOpenAPISpec_NegotiatesAccept(t *testing.T) {
+	t.Chdir(t.TempDir())
+	h := newTestDocsHandler(t)
+	writeSpecFile(t, negotiationSpec)
+
+	tests := []struct {
+		accept string
+		want   string
+	}{
+		{"", "application/x-yaml"},
+		{"*/*", "application/x-yaml"},
+		{"application/json", "application/json"},
+		{"application/yaml", "application/x-yaml"},
+		{"application/yaml;q=0.5, application/json", "application/json"},
+		{"application/json;q=0.2, text/yaml", "application/x-yaml"},
+		{"text/html, */*;q=0.8", "application/x-yaml"},
+		{"application/json, */*", "application/json"},
+		{"application/json, application/*", "application/json"},
+		{"*/*, application/yaml", "application/x-yaml"},
+		{"application/json;q=0.8, */*;q=0.8", "application/json"},
+		{"application/json;q=0, */*", "application/x-yaml"},
+	}
+
+	for _, tt := range tests {
+		rec := serveSpecAt(t, h, "/docs/openapi", tt.accept)
+		if ct := rec.Header().Get("Content-Type"); ct != tt.want {
+			t.Errorf("Accept %q: expected %s, got %s", tt.accept, tt.want, ct)
+		}
+		if rec.Header().Get("Vary") != "Accept" {
+			t.Errorf("Accept %q: expected Vary: Accept", tt.accept)
+		}
+	}
+
+	// The fixed paths ignore Accept
+	if ct := serveSpecAt(t, h, "/docs/openapi.yaml", "application/json").Header().Get("Content-Type"); ct != "application/x-yaml" {
+		t.Errorf("/docs/openapi.yaml should always serve YAML, got %s", ct)
+	}
+}
+
+func TestServeOpenAPISpec_JSONCachedWithYAML(t *testing.T) {
+	t.Chdir(t.TempDir())
+	h := newTestDocsHandler(t)
+	h.swaggerConfig.SpecCacheTTL = time.Hour
+
+	writeSpecFile(t, "openapi: 3.0.3\ninfo:\n  version: one\n")
+	first := serveSpecAt(t, h, "/docs/openapi.json", "").Body.String()
+	if h.specJSONCache == nil {
+		t.Fatal("Expected the JSON conversion to be cached")
+	}
+
+	writeSpecFile(t, "openapi: 3.0.3\ninfo:\n  version: two\n")
+	if body := serveSpecAt(t, h, "/docs/openapi.json", "").Body.String(); body != first {
+		t.Errorf("Expected the cached JSON within the TTL, got %s", body)
+	}
+
+	h.FlushSpecCache()
+	if body := serveSpecAt(t, h, "/docs/openapi.json", "").Body.String(); body == first {
+		t.Error("Expected the JSON to be converted again after a flush")
+	}
+}
+
+func TestSpecJSON_IgnoresStaleContent(t *testing.T) {
+	t.Chdir(t.TempDir())
+	h := newTestDocsHandler(t)
+	h.swaggerConfig.SpecCacheTTL = time.Hour
+
+	stale := []byte("openapi: 3.0.3\ninfo:\n  version: one\n")
+	fresh := []byte("openapi: 3.0.3\ninfo:\n  version: two\n")
+	h.storeSpec(fresh)
+
+	// A request that read the spec before the cache was refilled converts its own
+	// content and leaves the cache alone
+	converted, err := h.specJSON(stale)
+	if err != nil {
+		t.Fatalf("specJSON() error = %v", err)
+	}
+	if !strings.Contains(string(converted), `"one"`) {
+		t.Errorf("Expected the stale content's own conversion, got %s", converted)
+	}
+	if h.specJSONCache != nil {
+		t.Error("JSON of stale content should not be cached alongside the current YAML")
+	}
+
+	if converted, _ := h.specJSON(fresh); !strings.Contains(string(converted), `"two"`) || h.specJSONCache == nil {
+		t.Errorf("Expected the current YAML's conversion to be cached, got %s", converted)
+	}
+	if converted, _ := h.specJSON(stale); !strings.Contains(string(converted), `"one"`) {
+		t.Errorf("Cached JSON should only be served for the YAML it came from, got %s", converted)
+	}
+}
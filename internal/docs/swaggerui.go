@@ -0,0 +1,146 @@
+package docs
+
+import (
+	"html/template"
+	"sort"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/openapi/oauth"
+)
+
+// Config keys that preauthorize an API key scheme in Swagger UI. The value is a
+// placeholder shown in the Authorize dialog, never a real credential.
+const (
+	preauthKeyNameConfigKey        = "docs.ui.preauth_key_name"
+	preauthKeyPlaceholderConfigKey = "docs.ui.preauth_key_placeholder"
+)
+
+// defaultPreauthKeyPlaceholder is used when a preauthorized scheme has no placeholder
+const defaultPreauthKeyPlaceholder = "YOUR_API_KEY"
+
+// swaggerUITemplate renders the Swagger UI page. html/template escapes every value
+// for its context, so config such as the title cannot break out of the markup or
+// the inline script; only ThemeCSS and the interceptors are trusted fragments.
+var swaggerUITemplate = template.Must(template.New("swagger-ui").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+    <meta charset="UTF-8">
+    <title>{{.Title}}</title>
+    <link rel="stylesheet" type="text/css" href="{{.CSSURL}}" />
+    <style>
+        html {
+            box-sizing: border-box;
+            overflow: -moz-scrollbars-vertical;
+            overflow-y: scroll;
+        }
+        *, *:before, *:after {
+            box-sizing: inherit;
+        }
+        body {
+            margin:0;
+            background: #fafafa;
+        }
+        {{.ThemeCSS}}
+    </style>
+</head>
+<body>
+    <div id="swagger-ui"></div>
+    <script src="{{.BundleURL}}"></script>
+    <script src="{{.StandaloneURL}}"></script>
+    <script nonce="{{.Nonce}}">
+        window.onload = function() {
+            const ui = SwaggerUIBundle({
+                {{if .URLs}}urls: {{.URLs}},
+                "urls.primaryName": {{.PrimaryName}},{{else}}url: {{.URL}},{{end}}
+                dom_id: '#swagger-ui',
+                deepLinking: true,
+                presets: [
+                    SwaggerUIBundle.presets.apis,
+                    SwaggerUIStandalonePreset
+                ],
+                plugins: [
+                    SwaggerUIBundle.plugins.DownloadUrl
+                ],
+                layout: "StandaloneLayout",
+                docExpansion: "list",
+                defaultModelsExpandDepth: 3,
+                defaultModelExpandDepth: 3,
+                displayRequestDuration: true,
+                filter: true,
+                tryItOutEnabled: true,
+                persistAuthorization: {{if .PersistAuthorization}}true{{else}}false{{end}},
+                withCredentials: {{if .WithCredentials}}true{{else}}false{{end}},{{with .RequestInterceptor}}
+                requestInterceptor: function(req) { {{.}} },{{end}}{{with .ResponseInterceptor}}
+                responseInterceptor: function(res) { {{.}} },{{end}}
+                supportedSubmitMethods: ['get', 'post', 'put', 'delete', 'patch'],
+                onComplete: function() {
+                    {{with .Preauthorize}}ui.preauthorizeApiKey({{.Name}}, {{.Placeholder}});
+                    {{end}}console.log('Swagger UI loaded successfully');
+                },
+                onFailure: function(error) {
+                    console.error('Swagger UI failed to load:', error);
+                }
+            });{{with .OAuth}}
+            ui.initOAuth({{.}});{{end}}
+        };
+    </script>
+</body>
+</html>`))
+
+// swaggerPage holds the values rendered into swaggerUITemplate
+type swaggerPage struct {
+	Title         string
+	CSSURL        string
+	BundleURL     string
+	StandaloneURL string
+	ThemeCSS      template.CSS
+	// Nonce allows the inline script under the page's Content-Security-Policy
+	Nonce string
+
+	// URL is the single spec to load; URLs and PrimaryName replace it when the
+	// spec selector lists more than one spec
+	URL         string
+	URLs        []SpecEntry
+	PrimaryName string
+
+	PersistAuthorization bool
+	WithCredentials      bool
+	RequestInterceptor   template.JS
+	ResponseInterceptor  template.JS
+
+	Preauthorize *preauthorizedKey
+	OAuth        *oauthInit
+}
+
+// preauthorizedKey is an API key scheme filled in when Swagger UI loads
+type preauthorizedKey struct {
+	Name        string
+	Placeholder string
+}
+
+// oauthInit is the argument to Swagger UI's initOAuth
+type oauthInit struct {
+	ClientID string `json:"clientId"`
+	Scopes   string `json:"scopes,omitempty"`
+	UsePKCE  bool   `json:"usePkceWithAuthorizationCodeGrant"`
+}
+
+// newOAuthInit returns the initOAuth options for an OAuth2 config, requesting every
+// configured scope, or nil when no client id is configured
+func newOAuthInit(oauth2 oauth.Config) *oauthInit {
+	if oauth2.ClientID == "" {
+		return nil
+	}
+
+	scopes := make([]string, 0, len(oauth2.Scopes))
+	for scope := range oauth2.Scopes {
+		scopes = append(scopes, scope)
+	}
+	sort.Strings(scopes)
+
+	return &oauthInit{
+		ClientID: oauth2.ClientID,
+		Scopes:   strings.Join(scopes, " "),
+		UsePKCE:  oauth2.UsePKCE,
+	}
+}
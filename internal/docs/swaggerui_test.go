@@ -0,0 +1,145 @@
+package docs
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/cmd/generate-openapi/analyzer"
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
+	"gopkg.in/yaml.v3"
+)
+
+// swaggerHTML renders the Swagger UI page for r, failing the test on error
+func swaggerHTML(t *testing.T, h *DocsHandler, r *http.Request) string {
+	t.Helper()
+
+	html, err := h.generateSwaggerHTML(r, "test-nonce")
+	if err != nil {
+		t.Fatalf("generateSwaggerHTML() error = %v", err)
+	}
+	return html
+}
+
+func TestGenerateSwaggerHTML_OptionsBlock(t *testing.T) {
+	h := newTestDocsHandler(t)
+
+	html := swaggerHTML(t, h, httptest.NewRequest(http.MethodGet, "http://example.com/swagger", nil))
+
+	expected := `            const ui = SwaggerUIBundle({
+                url: "http://example.com/docs/openapi.yaml",
+                dom_id: '#swagger-ui',`
+	if !strings.Contains(html, expected) {
+		t.Errorf("Expected options block to start with %q", expected)
+	}
+	for _, option := range []string{"persistAuthorization: true,", "withCredentials: false,"} {
+		if !strings.Contains(html, option) {
+			t.Errorf("Expected %q in Swagger HTML", option)
+		}
+	}
+	for _, absent := range []string{"preauthorizeApiKey", "initOAuth"} {
+		if strings.Contains(html, absent) {
+			t.Errorf("Swagger HTML should not call %s when it is not configured", absent)
+		}
+	}
+}
+
+func TestGenerateSwaggerHTML_EscapesTitle(t *testing.T) {
+	h := newTestDocsHandler(t)
+	h.swaggerConfig.UITitle = `</title><script>alert("x")</script>`
+	h.swaggerConfig.AdditionalSpecs = []SpecEntry{{Name: "v2", URL: "/docs/v2/openapi.yaml"}}
+
+	html := swaggerHTML(t, h, httptest.NewRequest(http.MethodGet, "/swagger", nil))
+
+	if strings.Contains(html, "<script>alert") {
+		t.Fatal("Hostile title was rendered unescaped")
+	}
+	if !strings.Contains(html, "<title>&lt;/title&gt;&lt;script&gt;alert(&#34;x&#34;)&lt;/script&gt;</title>") {
+		t.Error("Expected the title to be HTML escaped")
+	}
+	// The title is also the primary spec name inside the inline script
+	if !strings.Contains(html, `"urls.primaryName": "\u003c/title\u003e\u003cscript\u003ealert(\"x\")\u003c/script\u003e",`) {
+		t.Error("Expected the primary spec name to be JavaScript escaped")
+	}
+}
+
+func TestGenerateSwaggerHTML_PreauthorizeAPIKey(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+	config.SetForTest(preauthKeyNameConfigKey, "ApiKeyAuth")
+
+	h := newTestDocsHandler(t)
+	html := swaggerHTML(t, h, httptest.NewRequest(http.MethodGet, "/swagger", nil))
+	if !strings.Contains(html, `ui.preauthorizeApiKey("ApiKeyAuth", "YOUR_API_KEY");`) {
+		t.Error("Expected the API key scheme to be preauthorized with the default placeholder")
+	}
+
+	config.SetForTest(preauthKeyPlaceholderConfigKey, "sk-'demo'")
+	h = newTestDocsHandler(t)
+	html = swaggerHTML(t, h, httptest.NewRequest(http.MethodGet, "/swagger", nil))
+	if !strings.Contains(html, `ui.preauthorizeApiKey("ApiKeyAuth", "sk-'demo'");`) {
+		t.Error("Expected the configured placeholder to be JavaScript escaped")
+	}
+}
+
+func TestGenerateSwaggerHTML_OAuth2(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+	config.SetForTest("docs.oauth2", map[string]interface{}{
+		"authorization_url": "https://idp.example.com/authorize",
+		"token_url":         "https://idp.example.com/token",
+		"client_id":         "docs-ui",
+		"use_pkce":          true,
+		"scopes": map[string]interface{}{
+			"models:read": "List models",
+			"chat":        "Create chat completions",
+		},
+	})
+
+	h := newTestDocsHandler(t)
+	html := swaggerHTML(t, h, httptest.NewRequest(http.MethodGet, "/swagger", nil))
+
+	expected := `ui.initOAuth({"clientId":"docs-ui","scopes":"chat models:read","usePkceWithAuthorizationCodeGrant":true});`
+	if !strings.Contains(html, expected) {
+		t.Errorf("Expected %q in Swagger HTML", expected)
+	}
+}
+
+func TestDocsRoutes_SpecDescribesContentTypes(t *testing.T) {
+	spec, err := analyzer.NewGenerator().GenerateSpecForRoutes(types.GetRoutesByModule("docs"))
+	if err != nil {
+		t.Fatalf("GenerateSpecForRoutes() error = %v", err)
+	}
+
+	linter, err := analyzer.NewLinter([]byte(spec))
+	if err != nil {
+		t.Fatalf("NewLinter() error = %v", err)
+	}
+	if issues := linter.Lint(); analyzer.HasErrors(issues) {
+		t.Errorf("Docs routes spec has lint errors: %v", issues)
+	}
+
+	var parsed struct {
+		Paths map[string]map[string]struct {
+			Responses map[string]struct {
+				Content map[string]interface{} `yaml:"content"`
+			} `yaml:"responses"`
+		} `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal([]byte(spec), &parsed); err != nil {
+		t.Fatalf("Generated spec is not valid YAML: %v", err)
+	}
+
+	swagger := parsed.Paths["/swagger"]["get"].Responses
+	if _, ok := swagger["200"].Content["text/html"]; !ok || len(swagger["200"].Content) != 1 {
+		t.Errorf("/swagger 200 content = %v, expected text/html only", swagger["200"].Content)
+	}
+	if len(swagger) != 1 {
+		t.Errorf("/swagger should document only its 200 response, got %v", swagger)
+	}
+	if _, ok := parsed.Paths["/docs/openapi.yaml"]["get"].Responses["200"].Content["application/x-yaml"]; !ok {
+		t.Error("/docs/openapi.yaml should document an application/x-yaml response")
+	}
+}
@@ -0,0 +1,180 @@
+package jobs
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
+	"github.com/JerkyTreats/llm/internal/logging"
+	"github.com/JerkyTreats/llm/internal/webhook"
+)
+
+// JobsHandler serves the job submission, status, result and cancellation routes
+type JobsHandler struct {
+	manager *Manager
+}
+
+func init() {
+	config.RegisterKey("jobs.retention", "")
+	config.RegisterKey("jobs.workers", 0)
+	config.RegisterKey("jobs.queue_size", 0)
+}
+
+// NewJobsHandler creates a jobs handler whose worker pool size, queue size and job
+// retention come from jobs.workers, jobs.queue_size and jobs.retention. Callback
+// notifications are signed with webhook.secret and refused while it is unset.
+func NewJobsHandler() (*JobsHandler, error) {
+	retention := defaultRetention
+	if raw := config.GetString("jobs.retention"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			logging.Warn("Invalid jobs.retention %q, using %s", raw, defaultRetention)
+		} else {
+			retention = parsed
+		}
+	}
+
+	manager := NewManager(NewMemoryStore(retention), config.GetInt("jobs.workers"), config.GetInt("jobs.queue_size"))
+	if secret := config.GetString("webhook.secret"); secret != "" {
+		manager.SetWebhooks(webhook.NewDispatcher(secret))
+	} else {
+		logging.Info("webhook.secret is not set; job callback URLs will be refused")
+	}
+	return NewJobsHandlerWithManager(manager), nil
+}
+
+// NewJobsHandlerWithManager creates a jobs handler backed by manager
+func NewJobsHandlerWithManager(manager *Manager) *JobsHandler {
+	return &JobsHandler{manager: manager}
+}
+
+// HandlerFor returns the handler serving a route of the jobs module
+func (h *JobsHandler) HandlerFor(route types.RouteInfo) http.HandlerFunc {
+	switch {
+	case route.Method == "GET" && route.Path == "/v1/jobs/{id}":
+		return h.Status
+	case route.Method == "DELETE" && route.Path == "/v1/jobs/{id}":
+		return h.Cancel
+	case route.Method == "GET" && route.Path == "/v1/jobs/{id}/result":
+		return h.Result
+	case route.Method == "POST" && strings.HasPrefix(route.Path, "/v1/jobs/"):
+		return h.Submit(strings.TrimPrefix(route.Path, "/v1/jobs/"))
+	default:
+		return nil
+	}
+}
+
+// Submit returns a handler that decodes the job type's payload and queues the job,
+// to be announced to the callback_url query parameter once it finishes
+func (h *JobsHandler) Submit(typeName string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		jobType, ok := lookupType(typeName)
+		if !ok {
+			types.WriteError(w, http.StatusNotFound, "unknown job type "+typeName)
+			return
+		}
+
+		var payload interface{}
+		if jobType.PayloadType != nil {
+			value := reflect.New(jobType.PayloadType)
+			if err := json.NewDecoder(r.Body).Decode(value.Interface()); err != nil {
+				types.WriteError(w, http.StatusBadRequest, "invalid job payload: "+err.Error())
+				return
+			}
+			payload = value.Elem().Interface()
+		}
+
+		job, err := h.manager.SubmitWithCallback(typeName, payload, r.URL.Query().Get("callback_url"))
+		switch {
+		case errors.Is(err, ErrCallbacksDisabled), errors.Is(err, ErrInvalidCallback):
+			types.WriteError(w, http.StatusBadRequest, err.Error())
+			return
+		case errors.Is(err, ErrQueueFull), errors.Is(err, ErrClosed):
+			w.Header().Set("Retry-After", "1")
+			types.WriteError(w, http.StatusServiceUnavailable, err.Error())
+			return
+		case err != nil:
+			types.WriteError(w, http.StatusInternalServerError, err.Error())
+			return
+		}
+
+		w.Header().Set("Location", "/v1/jobs/"+job.ID)
+		writeJSON(w, http.StatusAccepted, job)
+	}
+}
+
+// Close cancels outstanding jobs and stops the worker pool
+func (h *JobsHandler) Close() {
+	h.manager.Close()
+}
+
+// Status reports a job's status and progress
+func (h *JobsHandler) Status(w http.ResponseWriter, r *http.Request) {
+	id, err := types.PathID(r, "id", jobIDPrefix)
+	if err != nil {
+		types.WritePathParamError(w, err)
+		return
+	}
+	job, err := h.manager.Get(id)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// Result returns a succeeded job's result, or 409 while it has not finished
+func (h *JobsHandler) Result(w http.ResponseWriter, r *http.Request) {
+	id, err := types.PathID(r, "id", jobIDPrefix)
+	if err != nil {
+		types.WritePathParamError(w, err)
+		return
+	}
+	result, err := h.manager.Result(id)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+// Cancel stops a queued or running job, or returns 409 if it already finished
+func (h *JobsHandler) Cancel(w http.ResponseWriter, r *http.Request) {
+	id, err := types.PathID(r, "id", jobIDPrefix)
+	if err != nil {
+		types.WritePathParamError(w, err)
+		return
+	}
+	job, err := h.manager.Cancel(id)
+	if err != nil {
+		writeManagerError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, job)
+}
+
+// writeManagerError maps Manager errors to HTTP statuses
+func writeManagerError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		types.WriteError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrNotFinished), errors.Is(err, ErrFinished):
+		types.WriteError(w, http.StatusConflict, err.Error())
+	default:
+		types.WriteError(w, http.StatusInternalServerError, err.Error())
+	}
+}
+
+// writeJSON encodes body as the JSON response
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logging.Error("Failed to encode jobs response: %v", err)
+	}
+}
@@ -0,0 +1,51 @@
+package jobs
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+func init() {
+	// Submit routes are registered per job type by RegisterType
+
+	// Register job status endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "GET",
+		Path:            "/v1/jobs/{id}",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "jobs.JobsHandler.Status",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    reflect.TypeOf(Job{}),
+		Module:          Module,
+		Summary:         "Job status and progress",
+		ErrorStatuses:   []int{http.StatusNotFound},
+	})
+
+	// Register job cancellation endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "DELETE",
+		Path:            "/v1/jobs/{id}",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "jobs.JobsHandler.Cancel",
+		RequestType:     nil, // DELETE request has no body
+		ResponseType:    reflect.TypeOf(Job{}),
+		Module:          Module,
+		Summary:         "Cancel a queued or running job",
+		ErrorStatuses:   []int{http.StatusNotFound, http.StatusConflict},
+	})
+
+	// Register job result endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "GET",
+		Path:            "/v1/jobs/{id}/result",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "jobs.JobsHandler.Result",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    reflect.TypeOf(JobResult{}),
+		Module:          Module,
+		Summary:         "Result of a succeeded job",
+		ErrorStatuses:   []int{http.StatusNotFound, http.StatusConflict},
+	})
+}
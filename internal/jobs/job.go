@@ -0,0 +1,158 @@
+// Package jobs runs long-running work such as batch embeddings asynchronously.
+// Clients submit a typed payload, poll the job's status and fetch its result
+// once it finishes.
+package jobs
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"reflect"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/webhook"
+)
+
+// Module groups the job routes in the registry and the generated spec
+const Module = "jobs"
+
+// Job statuses
+const (
+	StatusQueued    = "queued"
+	StatusRunning   = "running"
+	StatusSucceeded = "succeeded"
+	StatusFailed    = "failed"
+	StatusCancelled = "cancelled"
+)
+
+// Job describes a submitted job and its progress
+type Job struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Status    string    `json:"status"`
+	Progress  float64   `json:"progress"`
+	Error     string    `json:"error,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Finished reports whether the job has reached a terminal status
+func (j Job) Finished() bool {
+	switch j.Status {
+	case StatusSucceeded, StatusFailed, StatusCancelled:
+		return true
+	default:
+		return false
+	}
+}
+
+// SubmitQuery holds the query parameters of a job submission
+type SubmitQuery struct {
+	CallbackURL string `query:"callback_url" example:"https://example.com/hooks/jobs" description:"Public http or https URL sent the job once it finishes; refused unless webhook.secret is set"`
+}
+
+// Output is the result of a job; its concrete type is the job type's ResultType
+type Output interface{}
+
+// JobResult is the response body of a finished job's result
+type JobResult struct {
+	ID     string `json:"id"`
+	Type   string `json:"type"`
+	Result Output `json:"result"`
+}
+
+// Runner performs a job. It must return promptly once ctx is cancelled and may
+// report progress between 0 and 1.
+type Runner func(ctx context.Context, payload interface{}, progress func(float64)) (interface{}, error)
+
+// Type describes a kind of job clients can submit
+type Type struct {
+	Name        string       // Path segment in POST /v1/jobs/{type}
+	Summary     string       // Optional summary of the submit operation
+	PayloadType reflect.Type // Request body decoded and passed to Run
+	ResultType  reflect.Type // Result returned by Run
+	Run         Runner
+}
+
+var (
+	// typeRegistry maps job type names to their definitions
+	typeRegistry = make(map[string]Type)
+	// typeMutex protects concurrent access to typeRegistry
+	typeMutex sync.RWMutex
+)
+
+// RegisterType makes a job type available and registers its submit route so the
+// spec documents its payload. It should be called from init().
+func RegisterType(jobType Type) {
+	typeMutex.Lock()
+	typeRegistry[jobType.Name] = jobType
+	typeMutex.Unlock()
+
+	summary := jobType.Summary
+	if summary == "" {
+		summary = fmt.Sprintf("Submit a %s job", jobType.Name)
+	}
+
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "POST",
+		Path:            "/v1/jobs/" + jobType.Name,
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "jobs.JobsHandler.Submit",
+		RequestType:     jobType.PayloadType,
+		ResponseType:    reflect.TypeOf(Job{}),
+		Module:          Module,
+		Summary:         summary,
+		QueryType:       reflect.TypeOf(SubmitQuery{}),
+		SuccessStatus:   http.StatusAccepted,
+		ErrorStatuses:   []int{http.StatusServiceUnavailable}, // Job queue is full
+		Callbacks: map[string]types.CallbackInfo{
+			"jobFinished": {
+				Expression: "{$request.query.callback_url}",
+				PathItem: types.PathItem{
+					Post: &types.CallbackOperation{
+						Summary: "Sent once the job succeeds, fails or is cancelled, signed with webhook.secret",
+						HeaderParams: []types.HeaderParam{
+							{Name: webhook.EventHeader, Required: true, Description: "Event name, " + JobFinishedEvent},
+							{Name: webhook.TimestampHeader, Required: true, Description: "Unix time the delivery was signed at"},
+							{Name: webhook.SignatureHeader, Required: true, Description: "sha256= followed by the hex HMAC-SHA256 of the timestamp, a period and the body, keyed with webhook.secret"},
+						},
+						RequestType: reflect.TypeOf(Job{}),
+					},
+				},
+			},
+		},
+	})
+}
+
+// lookupType returns the registered job type with the given name
+func lookupType(name string) (Type, bool) {
+	typeMutex.RLock()
+	defer typeMutex.RUnlock()
+
+	jobType, ok := typeRegistry[name]
+	return jobType, ok
+}
+
+// ResultTypes returns the result types of all registered job types, ordered by
+// type name, so the spec can document JobResult.Result as a union
+func ResultTypes() []reflect.Type {
+	typeMutex.RLock()
+	defer typeMutex.RUnlock()
+
+	names := make([]string, 0, len(typeRegistry))
+	for name := range typeRegistry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var results []reflect.Type
+	for _, name := range names {
+		if resultType := typeRegistry[name].ResultType; resultType != nil {
+			results = append(results, resultType)
+		}
+	}
+	return results
+}
@@ -0,0 +1,367 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/webhook"
+)
+
+type slowPayload struct {
+	Input string `json:"input"`
+}
+
+type slowResult struct {
+	Output string `json:"output"`
+}
+
+// registerSlowJob registers a job type that runs until release is closed or it
+// is cancelled, and removes it and its submit route when the test ends
+func registerSlowJob(t *testing.T) (release chan struct{}, started chan struct{}) {
+	t.Helper()
+
+	routes := types.GetRegisteredRoutes()
+	release = make(chan struct{})
+	started = make(chan struct{}, 8)
+
+	RegisterType(Type{
+		Name:        "slow",
+		PayloadType: reflect.TypeOf(slowPayload{}),
+		ResultType:  reflect.TypeOf(slowResult{}),
+		Run: func(ctx context.Context, payload interface{}, progress func(float64)) (interface{}, error) {
+			started <- struct{}{}
+			progress(0.5)
+			select {
+			case <-release:
+				return slowResult{Output: strings.ToUpper(payload.(slowPayload).Input)}, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		},
+	})
+
+	t.Cleanup(func() {
+		typeMutex.Lock()
+		delete(typeRegistry, "slow")
+		typeMutex.Unlock()
+		types.UpdateRouteRegistry(routes)
+	})
+	return release, started
+}
+
+// newTestServer serves every jobs route through a manager with the given workers
+// and the default queue size
+func newTestServer(t *testing.T, workers int) (*httptest.Server, *Manager) {
+	t.Helper()
+	return newQueueTestServer(t, workers, 0)
+}
+
+// newQueueTestServer is newTestServer with a queue of queueSize jobs
+func newQueueTestServer(t *testing.T, workers, queueSize int) (*httptest.Server, *Manager) {
+	t.Helper()
+
+	manager := NewManager(NewMemoryStore(time.Hour), workers, queueSize)
+	t.Cleanup(manager.Close)
+	handler := NewJobsHandlerWithManager(manager)
+
+	mux := http.NewServeMux()
+	for _, route := range types.GetRoutesByModule(Module) {
+		mux.HandleFunc(route.Method+" "+route.Path, handler.HandlerFor(route))
+	}
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server, manager
+}
+
+func doRequest(t *testing.T, method, url, body string, out interface{}) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("Failed to create request: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+
+	if out != nil {
+		if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+			t.Fatalf("Failed to decode %s %s response: %v", method, url, err)
+		}
+	}
+	return resp
+}
+
+// waitForStatus polls the job until it reaches status
+func waitForStatus(t *testing.T, baseURL, id, status string) Job {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		var job Job
+		doRequest(t, "GET", baseURL+"/v1/jobs/"+id, "", &job)
+		if job.Status == status {
+			return job
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("Job %s status = %s, want %s", id, job.Status, status)
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestJobLifecycle(t *testing.T) {
+	release, started := registerSlowJob(t)
+	server, manager := newTestServer(t, 1)
+
+	var job Job
+	resp := doRequest(t, "POST", server.URL+"/v1/jobs/slow", `{"input":"hello"}`, &job)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("Submit status = %d, want 202", resp.StatusCode)
+	}
+	if job.ID == "" || job.Status != StatusQueued || job.CreatedAt.IsZero() {
+		t.Errorf("Unexpected submitted job: %+v", job)
+	}
+	if got := resp.Header.Get("Location"); got != "/v1/jobs/"+job.ID {
+		t.Errorf("Location = %q, want /v1/jobs/%s", got, job.ID)
+	}
+
+	<-started
+	running := waitForStatus(t, server.URL, job.ID, StatusRunning)
+	if running.Progress != 0.5 {
+		t.Errorf("Running progress = %v, want 0.5", running.Progress)
+	}
+
+	resp = doRequest(t, "GET", server.URL+"/v1/jobs/"+job.ID+"/result", "", nil)
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("Result of a running job status = %d, want 409", resp.StatusCode)
+	}
+
+	close(release)
+	manager.Wait()
+
+	done := waitForStatus(t, server.URL, job.ID, StatusSucceeded)
+	if done.Progress != 1 {
+		t.Errorf("Succeeded progress = %v, want 1", done.Progress)
+	}
+
+	var result struct {
+		ID     string     `json:"id"`
+		Type   string     `json:"type"`
+		Result slowResult `json:"result"`
+	}
+	resp = doRequest(t, "GET", server.URL+"/v1/jobs/"+job.ID+"/result", "", &result)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Result status = %d, want 200", resp.StatusCode)
+	}
+	if result.ID != job.ID || result.Type != "slow" || result.Result.Output != "HELLO" {
+		t.Errorf("Unexpected result: %+v", result)
+	}
+
+	resp = doRequest(t, "DELETE", server.URL+"/v1/jobs/"+job.ID, "", nil)
+	if resp.StatusCode != http.StatusConflict {
+		t.Errorf("Cancelling a finished job status = %d, want 409", resp.StatusCode)
+	}
+}
+
+func TestJobCancellation(t *testing.T) {
+	_, started := registerSlowJob(t)
+	server, manager := newTestServer(t, 1)
+
+	var running, queued Job
+	doRequest(t, "POST", server.URL+"/v1/jobs/slow", `{"input":"a"}`, &running)
+	<-started
+	doRequest(t, "POST", server.URL+"/v1/jobs/slow", `{"input":"b"}`, &queued)
+
+	// The single worker is busy, so the second job stays queued
+	if job := waitForStatus(t, server.URL, queued.ID, StatusQueued); job.Progress != 0 {
+		t.Errorf("Queued progress = %v, want 0", job.Progress)
+	}
+
+	for _, id := range []string{queued.ID, running.ID} {
+		var cancelled Job
+		resp := doRequest(t, "DELETE", server.URL+"/v1/jobs/"+id, "", &cancelled)
+		if resp.StatusCode != http.StatusOK || cancelled.Status != StatusCancelled {
+			t.Errorf("Cancel %s = %d %s, want 200 cancelled", id, resp.StatusCode, cancelled.Status)
+		}
+	}
+	manager.Wait()
+
+	for _, id := range []string{queued.ID, running.ID} {
+		waitForStatus(t, server.URL, id, StatusCancelled)
+
+		resp := doRequest(t, "GET", server.URL+"/v1/jobs/"+id+"/result", "", nil)
+		if resp.StatusCode != http.StatusConflict {
+			t.Errorf("Result of a cancelled job status = %d, want 409", resp.StatusCode)
+		}
+		resp = doRequest(t, "DELETE", server.URL+"/v1/jobs/"+id, "", nil)
+		if resp.StatusCode != http.StatusConflict {
+			t.Errorf("Cancelling twice status = %d, want 409", resp.StatusCode)
+		}
+	}
+}
+
+func TestJobQueueFull(t *testing.T) {
+	_, started := registerSlowJob(t)
+	server, manager := newQueueTestServer(t, 1, 1)
+
+	var running, queued Job
+	doRequest(t, "POST", server.URL+"/v1/jobs/slow", `{"input":"a"}`, &running)
+	<-started
+	doRequest(t, "POST", server.URL+"/v1/jobs/slow", `{"input":"b"}`, &queued)
+
+	var body types.ErrorResponse
+	resp := doRequest(t, "POST", server.URL+"/v1/jobs/slow", `{"input":"c"}`, &body)
+	if resp.StatusCode != http.StatusServiceUnavailable || resp.Header.Get("Retry-After") == "" {
+		t.Errorf("Submit with a full queue = %d %+v, want 503 with Retry-After", resp.StatusCode, body)
+	}
+
+	// Closing cancels the running and queued jobs and rejects new ones
+	manager.Close()
+	for _, id := range []string{running.ID, queued.ID} {
+		if job, _ := manager.Get(id); job.Status != StatusCancelled {
+			t.Errorf("Job %s status after Close = %s, want cancelled", id, job.Status)
+		}
+	}
+	if _, err := manager.Submit("slow", slowPayload{Input: "d"}); !errors.Is(err, ErrClosed) {
+		t.Errorf("Submit after Close error = %v, want ErrClosed", err)
+	}
+}
+
+// missingJobID is a well-formed job identifier no job has
+var missingJobID = jobIDPrefix + strings.Repeat("0", 32)
+
+func TestJobErrors(t *testing.T) {
+	registerSlowJob(t)
+	server, _ := newTestServer(t, 1)
+
+	for _, tt := range []struct {
+		method, path, body string
+		status             int
+	}{
+		{"GET", "/v1/jobs/" + missingJobID, "", http.StatusNotFound},
+		{"GET", "/v1/jobs/" + missingJobID + "/result", "", http.StatusNotFound},
+		{"DELETE", "/v1/jobs/" + missingJobID, "", http.StatusNotFound},
+		{"GET", "/v1/jobs/missing", "", http.StatusBadRequest},
+		{"DELETE", "/v1/jobs/missing", "", http.StatusBadRequest},
+		{"POST", "/v1/jobs/slow", "{not json", http.StatusBadRequest},
+		// Callbacks are refused without a signing secret
+		{"POST", "/v1/jobs/slow?callback_url=https://example.com/hooks", `{"input":"a"}`, http.StatusBadRequest},
+	} {
+		var body types.ErrorResponse
+		resp := doRequest(t, tt.method, server.URL+tt.path, tt.body, &body)
+		if resp.StatusCode != tt.status || body.Status != tt.status || !body.Error {
+			t.Errorf("%s %s = %d %+v, want %d", tt.method, tt.path, resp.StatusCode, body, tt.status)
+		}
+	}
+}
+
+func TestJobCallback(t *testing.T) {
+	release, _ := registerSlowJob(t)
+	server, manager := newTestServer(t, 1)
+	dispatcher := webhook.NewDispatcher("secret")
+	manager.SetWebhooks(dispatcher)
+
+	received := make(chan *http.Request, 1)
+	bodies := make(chan []byte, 1)
+	hook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		received <- r
+		bodies <- body
+	}))
+	defer hook.Close()
+
+	for _, callbackURL := range []string{hook.URL + "/jobs", "http://169.254.169.254/latest", "ftp://example.com", "/relative"} {
+		var body types.ErrorResponse
+		resp := doRequest(t, "POST", server.URL+"/v1/jobs/slow?callback_url="+url.QueryEscape(callbackURL), `{"input":"a"}`, &body)
+		if resp.StatusCode != http.StatusBadRequest {
+			t.Errorf("Submit with callback_url %s = %d, want 400", callbackURL, resp.StatusCode)
+		}
+	}
+
+	// The test receiver listens on loopback, which only an allowed host may use
+	dispatcher.AllowedHosts = []string{"127.0.0.1"}
+
+	var job Job
+	resp := doRequest(t, "POST", server.URL+"/v1/jobs/slow?callback_url="+url.QueryEscape(hook.URL+"/jobs"), `{"input":"hi"}`, &job)
+	if resp.StatusCode != http.StatusAccepted {
+		t.Fatalf("Submit status = %d, want 202", resp.StatusCode)
+	}
+	close(release)
+
+	select {
+	case r := <-received:
+		body := <-bodies
+		if r.URL.Path != "/jobs" || r.Header.Get(webhook.EventHeader) != JobFinishedEvent {
+			t.Errorf("Unexpected callback %s with event %q", r.URL.Path, r.Header.Get(webhook.EventHeader))
+		}
+		timestamp := r.Header.Get(webhook.TimestampHeader)
+		if got, want := r.Header.Get(webhook.SignatureHeader), webhook.Sign([]byte("secret"), timestamp, body); got != want {
+			t.Errorf("Signature = %q, want %q", got, want)
+		}
+		var finished Job
+		if err := json.Unmarshal(body, &finished); err != nil {
+			t.Fatalf("Failed to decode callback body: %v", err)
+		}
+		if finished.ID != job.ID || finished.Status != StatusSucceeded {
+			t.Errorf("Callback job = %s %s, want %s succeeded", finished.ID, finished.Status, job.ID)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Callback was not delivered")
+	}
+}
+
+func TestRegisterType_RegistersSubmitRoute(t *testing.T) {
+	registerSlowJob(t)
+
+	var submit *types.RouteInfo
+	for _, route := range types.GetRoutesByModule(Module) {
+		if route.Method == "POST" && route.Path == "/v1/jobs/slow" {
+			submit = &route
+		}
+	}
+	if submit == nil {
+		t.Fatal("RegisterType should register POST /v1/jobs/slow")
+	}
+	if submit.RequestType != reflect.TypeOf(slowPayload{}) || submit.SuccessStatus != http.StatusAccepted {
+		t.Errorf("Unexpected submit route: %+v", submit)
+	}
+	if got := ResultTypes(); len(got) != 1 || got[0] != reflect.TypeOf(slowResult{}) {
+		t.Errorf("ResultTypes() = %v, want [slowResult]", got)
+	}
+}
+
+func TestMemoryStore_EvictsExpiredJobs(t *testing.T) {
+	store := NewMemoryStore(time.Minute)
+	now := time.Unix(1700000000, 0)
+	store.now = func() time.Time { return now }
+
+	store.Put(Record{Job: Job{ID: "done", Status: StatusSucceeded, UpdatedAt: now}})
+	store.Put(Record{Job: Job{ID: "running", Status: StatusRunning, UpdatedAt: now}})
+
+	now = now.Add(2 * time.Minute)
+	store.Put(Record{Job: Job{ID: "new", Status: StatusQueued, UpdatedAt: now}})
+
+	if _, ok := store.Get("done"); ok {
+		t.Error("Finished job past retention should be evicted")
+	}
+	if _, ok := store.Get("running"); !ok {
+		t.Error("Unfinished jobs should never be evicted")
+	}
+	if store.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", store.Len())
+	}
+}
@@ -0,0 +1,353 @@
+package jobs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/logging"
+	"github.com/JerkyTreats/llm/internal/webhook"
+)
+
+// Manager defaults used when the config does not override them
+const (
+	defaultWorkers   = 4
+	defaultQueueSize = 100
+	defaultRetention = time.Hour
+)
+
+// Errors returned by Manager
+var (
+	ErrUnknownType = errors.New("unknown job type")
+	ErrNotFound    = errors.New("job not found")
+	ErrNotFinished = errors.New("job has not finished")
+	ErrFinished    = errors.New("job has already finished")
+	ErrQueueFull   = errors.New("job queue is full")
+	ErrClosed      = errors.New("job manager is closed")
+	// ErrCallbacksDisabled is returned for callback URLs when no signing secret
+	// is configured, since receivers could not verify the deliveries
+	ErrCallbacksDisabled = errors.New("job callbacks are disabled because webhook.secret is not set")
+	ErrInvalidCallback   = errors.New("invalid callback URL")
+)
+
+// JobFinishedEvent is the webhook event sent to a job's callback URL, with the Job
+// as payload, once it succeeds, fails or is cancelled
+const JobFinishedEvent = "job.finished"
+
+// queuedJob is a submitted job waiting for a worker
+type queuedJob struct {
+	ctx     context.Context
+	id      string
+	jobType Type
+	payload interface{}
+}
+
+// Manager runs submitted jobs on a fixed pool of workers reading a bounded queue
+type Manager struct {
+	store Store
+	queue chan queuedJob
+	now   func() time.Time
+
+	// webhooks delivers JobFinishedEvent to callback URLs; deliveries still
+	// retrying when the manager closes are cancelled with stopWebhooks
+	webhooks     *webhook.Dispatcher
+	webhookCtx   context.Context
+	stopWebhooks context.CancelFunc
+
+	mu            sync.Mutex
+	queued        int // Jobs submitted but not yet taken by a worker; never exceeds cap(queue)
+	closed        bool
+	webhooksDone  bool
+	cancels       map[string]context.CancelFunc
+	jobs          sync.WaitGroup // Submitted jobs that have not stopped
+	workers       sync.WaitGroup
+	notifications sync.WaitGroup // Webhook deliveries in flight
+}
+
+// NewManager creates a manager running at most workers jobs at once, with up to
+// queueSize more waiting. Submit rejects jobs with ErrQueueFull beyond that.
+func NewManager(store Store, workers, queueSize int) *Manager {
+	if workers <= 0 {
+		workers = defaultWorkers
+	}
+	if queueSize <= 0 {
+		queueSize = defaultQueueSize
+	}
+	webhookCtx, stopWebhooks := context.WithCancel(context.Background())
+	m := &Manager{
+		store:        store,
+		queue:        make(chan queuedJob, queueSize),
+		now:          time.Now,
+		webhookCtx:   webhookCtx,
+		stopWebhooks: stopWebhooks,
+		cancels:      make(map[string]context.CancelFunc),
+	}
+
+	m.workers.Add(workers)
+	for i := 0; i < workers; i++ {
+		go m.work()
+	}
+	return m
+}
+
+// SetWebhooks makes the manager send JobFinishedEvent through dispatcher to the
+// callback URL of each job submitted with one. Without it callback URLs are refused.
+func (m *Manager) SetWebhooks(dispatcher *webhook.Dispatcher) {
+	m.webhooks = dispatcher
+}
+
+// Submit queues a job of the named type and returns it without waiting for it to
+// run. It returns ErrQueueFull when the queue has no room.
+func (m *Manager) Submit(typeName string, payload interface{}) (Job, error) {
+	return m.SubmitWithCallback(typeName, payload, "")
+}
+
+// SubmitWithCallback queues a job like Submit and notifies callbackURL with
+// JobFinishedEvent once it finishes; an empty callbackURL sends nothing. It
+// returns ErrCallbacksDisabled without webhooks and ErrInvalidCallback for URLs
+// the dispatcher refuses, such as private addresses.
+func (m *Manager) SubmitWithCallback(typeName string, payload interface{}, callbackURL string) (Job, error) {
+	jobType, ok := lookupType(typeName)
+	if !ok {
+		return Job{}, fmt.Errorf("%w %q", ErrUnknownType, typeName)
+	}
+	if callbackURL != "" {
+		if m.webhooks == nil {
+			return Job{}, ErrCallbacksDisabled
+		}
+		if err := m.webhooks.CheckURL(callbackURL); err != nil {
+			return Job{}, fmt.Errorf("%w: %w", ErrInvalidCallback, err)
+		}
+	}
+
+	now := m.now()
+	job := Job{
+		ID:        newJobID(),
+		Type:      typeName,
+		Status:    StatusQueued,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	m.mu.Lock()
+	switch {
+	case m.closed:
+		m.mu.Unlock()
+		cancel()
+		return Job{}, ErrClosed
+	case m.queued == cap(m.queue):
+		m.mu.Unlock()
+		cancel()
+		return Job{}, ErrQueueFull
+	}
+	// The reserved place guarantees the send below cannot block
+	m.queued++
+	m.cancels[job.ID] = cancel
+	m.jobs.Add(1)
+	m.store.Put(Record{Job: job, Payload: payload, CallbackURL: callbackURL})
+	m.queue <- queuedJob{ctx: ctx, id: job.ID, jobType: jobType, payload: payload}
+	m.mu.Unlock()
+
+	return job, nil
+}
+
+// work runs queued jobs until the queue is closed
+func (m *Manager) work() {
+	defer m.workers.Done()
+	for queued := range m.queue {
+		m.mu.Lock()
+		m.queued--
+		m.mu.Unlock()
+
+		m.run(queued.ctx, queued.id, queued.jobType, queued.payload)
+	}
+}
+
+// run runs a job taken from the queue and records its outcome
+func (m *Manager) run(ctx context.Context, id string, jobType Type, payload interface{}) {
+	defer m.jobs.Done()
+	defer m.release(id)
+
+	if ctx.Err() != nil {
+		// Cancelled while queued, or the manager closed before it ran
+		m.transition(id, func(record *Record) { record.Job.Status = StatusCancelled })
+		return
+	}
+
+	if _, ok := m.transition(id, func(record *Record) {
+		record.Job.Status = StatusRunning
+	}); !ok {
+		return
+	}
+
+	progress := func(p float64) {
+		if p < 0 {
+			p = 0
+		} else if p > 1 {
+			p = 1
+		}
+		m.transition(id, func(record *Record) { record.Job.Progress = p })
+	}
+
+	result, err := jobType.Run(ctx, payload, progress)
+
+	m.transition(id, func(record *Record) {
+		if ctx.Err() != nil {
+			// Stopped by Close; Cancel has already recorded its own cancellations
+			record.Job.Status = StatusCancelled
+			return
+		}
+		if err != nil {
+			record.Job.Status = StatusFailed
+			record.Job.Error = err.Error()
+			return
+		}
+		record.Job.Status = StatusSucceeded
+		record.Job.Progress = 1
+		record.Result = result
+	})
+	if err != nil && ctx.Err() == nil {
+		logging.Warn("Job %s (%s) failed: %v", id, jobType.Name, err)
+	}
+}
+
+// transition updates an unfinished job; finished jobs, including cancelled ones,
+// are left untouched. The transition that finishes a job notifies its callback URL.
+func (m *Manager) transition(id string, fn func(*Record)) (Record, bool) {
+	changed := false
+	record, _ := m.store.Update(id, func(record *Record) {
+		if record.Job.Finished() {
+			return
+		}
+		fn(record)
+		record.Job.UpdatedAt = m.now()
+		changed = true
+	})
+	if changed && record.Job.Finished() {
+		m.notify(record)
+	}
+	return record, changed
+}
+
+// notify sends JobFinishedEvent to a finished job's callback URL in the
+// background; failed deliveries end up in the dispatcher's dead-letter log
+func (m *Manager) notify(record Record) {
+	if record.CallbackURL == "" || m.webhooks == nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.webhooksDone {
+		return
+	}
+	m.notifications.Add(1)
+	go func() {
+		defer m.notifications.Done()
+		m.webhooks.Send(m.webhookCtx, record.CallbackURL, JobFinishedEvent, record.Job)
+	}()
+}
+
+// release drops the job's cancel function once it can no longer run
+func (m *Manager) release(id string) {
+	m.mu.Lock()
+	cancel := m.cancels[id]
+	delete(m.cancels, id)
+	m.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// Get returns the current state of a job
+func (m *Manager) Get(id string) (Job, error) {
+	record, ok := m.store.Get(id)
+	if !ok {
+		return Job{}, ErrNotFound
+	}
+	return record.Job, nil
+}
+
+// Result returns the result of a succeeded job. Jobs that are still running,
+// failed or were cancelled return ErrNotFinished.
+func (m *Manager) Result(id string) (JobResult, error) {
+	record, ok := m.store.Get(id)
+	if !ok {
+		return JobResult{}, ErrNotFound
+	}
+	if record.Job.Status != StatusSucceeded {
+		return JobResult{}, fmt.Errorf("%w: status is %s", ErrNotFinished, record.Job.Status)
+	}
+	return JobResult{ID: record.Job.ID, Type: record.Job.Type, Result: record.Result}, nil
+}
+
+// Cancel stops a queued or running job
+func (m *Manager) Cancel(id string) (Job, error) {
+	if _, ok := m.store.Get(id); !ok {
+		return Job{}, ErrNotFound
+	}
+
+	record, changed := m.transition(id, func(record *Record) {
+		record.Job.Status = StatusCancelled
+	})
+	if !changed {
+		return record.Job, ErrFinished
+	}
+
+	m.mu.Lock()
+	cancel := m.cancels[id]
+	m.mu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+
+	return record.Job, nil
+}
+
+// Wait blocks until every submitted job has stopped running
+func (m *Manager) Wait() {
+	m.jobs.Wait()
+}
+
+// Close stops accepting jobs, cancels the queued and running ones, waits for the
+// workers to exit and abandons webhook deliveries still being retried
+func (m *Manager) Close() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	for _, cancel := range m.cancels {
+		cancel()
+	}
+	close(m.queue)
+	m.mu.Unlock()
+
+	m.workers.Wait()
+
+	// Deliveries still in flight are abandoned and dead-lettered
+	m.mu.Lock()
+	m.webhooksDone = true
+	m.mu.Unlock()
+	m.stopWebhooks()
+	m.notifications.Wait()
+}
+
+// jobIDPrefix starts every job identifier
+const jobIDPrefix = "job_"
+
+// newJobID returns a random job identifier
+func newJobID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate job id: %v", err))
+	}
+	return jobIDPrefix + hex.EncodeToString(b)
+}
@@ -0,0 +1,96 @@
+package jobs
+
+import (
+	"sync"
+	"time"
+)
+
+// Record is a job with its submitted payload and, once succeeded, its result
+type Record struct {
+	Job         Job
+	Payload     interface{}
+	Result      interface{}
+	CallbackURL string // Notified with JobFinishedEvent once the job finishes
+}
+
+// Store persists job records
+type Store interface {
+	// Put adds a new record
+	Put(record Record)
+	// Get returns the record for id
+	Get(id string) (Record, bool)
+	// Update applies fn to the record for id atomically and returns the updated record
+	Update(id string, fn func(*Record)) (Record, bool)
+}
+
+// MemoryStore is an in-memory Store. Finished jobs are evicted once they have
+// been finished for longer than the retention period.
+type MemoryStore struct {
+	mu        sync.Mutex
+	retention time.Duration
+	records   map[string]*Record
+	now       func() time.Time
+}
+
+// NewMemoryStore creates an in-memory store keeping finished jobs for retention
+func NewMemoryStore(retention time.Duration) *MemoryStore {
+	if retention <= 0 {
+		retention = defaultRetention
+	}
+	return &MemoryStore{
+		retention: retention,
+		records:   make(map[string]*Record),
+		now:       time.Now,
+	}
+}
+
+// Put implements Store
+func (s *MemoryStore) Put(record Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired()
+	s.records[record.Job.ID] = &record
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(id string) (Record, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, exists := s.records[id]
+	if !exists {
+		return Record{}, false
+	}
+	return *record, true
+}
+
+// Update implements Store
+func (s *MemoryStore) Update(id string, fn func(*Record)) (Record, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	record, exists := s.records[id]
+	if !exists {
+		return Record{}, false
+	}
+	fn(record)
+	return *record, true
+}
+
+// Len returns the number of stored jobs
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.records)
+}
+
+// evictExpired drops finished jobs past the retention period; callers must hold s.mu
+func (s *MemoryStore) evictExpired() {
+	cutoff := s.now().Add(-s.retention)
+	for id, record := range s.records {
+		if record.Job.Finished() && record.Job.UpdatedAt.Before(cutoff) {
+			delete(s.records, id)
+		}
+	}
+}
@@ -74,6 +74,58 @@ func Warn(format string, args ...interface{}) {
 	logger.Warnf(format, args...)
 }
 
+// Logger logs entries carrying structured key/value fields.
+type Logger struct {
+	fields []interface{}
+}
+
+// WithFields returns a Logger that attaches the alternating key/value pairs to
+// every entry, e.g. WithFields("method", r.Method, "path", r.URL.Path).
+func WithFields(keysAndValues ...interface{}) *Logger {
+	return &Logger{fields: keysAndValues}
+}
+
+// WithFields returns a Logger with additional key/value pairs.
+func (l *Logger) WithFields(keysAndValues ...interface{}) *Logger {
+	fields := make([]interface{}, 0, len(l.fields)+len(keysAndValues))
+	fields = append(fields, l.fields...)
+	return &Logger{fields: append(fields, keysAndValues...)}
+}
+
+// Info logs an info-level message with the logger's fields.
+func (l *Logger) Info(msg string, keysAndValues ...interface{}) {
+	initLogger()
+	logger.Infow(msg, l.with(keysAndValues)...)
+}
+
+// Debug logs a debug-level message with the logger's fields.
+func (l *Logger) Debug(msg string, keysAndValues ...interface{}) {
+	initLogger()
+	logger.Debugw(msg, l.with(keysAndValues)...)
+}
+
+// Error logs an error-level message with the logger's fields.
+func (l *Logger) Error(msg string, keysAndValues ...interface{}) {
+	initLogger()
+	logger.Errorw(msg, l.with(keysAndValues)...)
+}
+
+// Warn logs a warning-level message with the logger's fields.
+func (l *Logger) Warn(msg string, keysAndValues ...interface{}) {
+	initLogger()
+	logger.Warnw(msg, l.with(keysAndValues)...)
+}
+
+// with returns the logger's fields followed by keysAndValues.
+func (l *Logger) with(keysAndValues []interface{}) []interface{} {
+	if len(keysAndValues) == 0 {
+		return l.fields
+	}
+	fields := make([]interface{}, 0, len(l.fields)+len(keysAndValues))
+	fields = append(fields, l.fields...)
+	return append(fields, keysAndValues...)
+}
+
 // Sync flushes any buffered log entries.
 func Sync() error {
 	if logger != nil {
@@ -87,3 +139,15 @@ func resetLogger() {
 	logger = nil
 	loggerOnce = sync.Once{}
 }
+
+// SetLoggerForTest replaces the logger singleton, e.g. with a zap observer core,
+// until ResetForTest is called. For test use only.
+func SetLoggerForTest(l *zap.Logger) {
+	loggerOnce.Do(func() {})
+	logger = l.Sugar()
+}
+
+// ResetForTest restores the default logger. For test use only.
+func ResetForTest() {
+	resetLogger()
+}
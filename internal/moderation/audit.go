@@ -0,0 +1,61 @@
+package moderation
+
+import (
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Actions a policy takes on flagged content, as recorded by Record
+const (
+	ActionFlag  = "flag"
+	ActionBlock = "block"
+)
+
+// DecisionCount is the number of times content from a source was flagged or
+// blocked for a category
+type DecisionCount struct {
+	Action   string
+	Source   string
+	Category string
+	Count    int64
+}
+
+type decisionKey struct {
+	action, source, category string
+}
+
+var (
+	decisionsMu sync.Mutex
+	decisions   = map[decisionKey]int64{}
+)
+
+// Record counts a flag or block of content from source, once per matched category
+func Record(action, source string, categories []string) {
+	decisionsMu.Lock()
+	defer decisionsMu.Unlock()
+	for _, category := range categories {
+		decisions[decisionKey{action, source, category}]++
+	}
+}
+
+// Decisions returns the recorded decision counts, sorted by action, source and category
+func Decisions() []DecisionCount {
+	decisionsMu.Lock()
+	counts := make([]DecisionCount, 0, len(decisions))
+	for key, count := range decisions {
+		counts = append(counts, DecisionCount{Action: key.action, Source: key.source, Category: key.category, Count: count})
+	}
+	decisionsMu.Unlock()
+
+	slices.SortFunc(counts, func(a, b DecisionCount) int {
+		if c := strings.Compare(a.Action, b.Action); c != 0 {
+			return c
+		}
+		if c := strings.Compare(a.Source, b.Source); c != 0 {
+			return c
+		}
+		return strings.Compare(a.Category, b.Category)
+	})
+	return counts
+}
@@ -0,0 +1,296 @@
+// Package moderation checks prompts and completions against content policy
+// before they reach a provider or a client.
+package moderation
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/JerkyTreats/llm/internal/config"
+)
+
+// Policy modes, set with moderation.mode
+const (
+	// ModeOff skips moderation entirely
+	ModeOff = "off"
+	// ModeFlag lets flagged text through and records the matched categories
+	ModeFlag = "flag"
+	// ModeBlock rejects flagged text
+	ModeBlock = "block"
+)
+
+// Decision is a moderator's verdict on a piece of text
+type Decision struct {
+	Flagged    bool
+	Categories []string // Matched policy categories, in configuration order
+}
+
+// Moderator checks text against content policy
+type Moderator interface {
+	Check(ctx context.Context, text string) (Decision, error)
+}
+
+// WindowedModerator is a Moderator whose matches never span more than Window
+// bytes. Streams use it to review only a chunk and the tail before it.
+type WindowedModerator interface {
+	Moderator
+	// Window returns the widest match in bytes, or -1 when matches are unbounded
+	Window() int
+}
+
+// BlockedError is returned by Policy.Review when it blocks flagged text
+type BlockedError struct {
+	Categories []string
+}
+
+func (e *BlockedError) Error() string {
+	return "content blocked by policy: " + strings.Join(e.Categories, ", ")
+}
+
+// Category is a named set of keywords and regular expressions. Keywords match
+// whole words case-insensitively; patterns are Go regular expressions used as written.
+type Category struct {
+	Name     string   `mapstructure:"name"`
+	Keywords []string `mapstructure:"keywords"`
+	Patterns []string `mapstructure:"patterns"`
+}
+
+// KeywordModerator flags text matching any configured category
+type KeywordModerator struct {
+	names    []string
+	matchers []*regexp.Regexp
+	window   int
+}
+
+// NewKeywordModerator compiles categories into a moderator
+func NewKeywordModerator(categories []Category) (*KeywordModerator, error) {
+	m := &KeywordModerator{}
+	for _, category := range categories {
+		if category.Name == "" {
+			return nil, fmt.Errorf("moderation category has no name")
+		}
+
+		var alternatives []string
+		if len(category.Keywords) > 0 {
+			quoted := make([]string, len(category.Keywords))
+			for i, keyword := range category.Keywords {
+				quoted[i] = regexp.QuoteMeta(keyword)
+			}
+			alternatives = append(alternatives, `(?i:\b(?:`+strings.Join(quoted, "|")+`)\b)`)
+		}
+		for _, pattern := range category.Patterns {
+			// Compile alone first so errors name the offending pattern
+			if _, err := regexp.Compile(pattern); err != nil {
+				return nil, fmt.Errorf("moderation category %s: invalid pattern %q: %w", category.Name, pattern, err)
+			}
+			alternatives = append(alternatives, "(?:"+pattern+")")
+		}
+		if len(alternatives) == 0 {
+			return nil, fmt.Errorf("moderation category %s has no keywords or patterns", category.Name)
+		}
+
+		expr := strings.Join(alternatives, "|")
+		m.names = append(m.names, category.Name)
+		m.matchers = append(m.matchers, regexp.MustCompile(expr))
+		m.window = widestWindow(m.window, expr)
+	}
+	return m, nil
+}
+
+// Window implements WindowedModerator
+func (m *KeywordModerator) Window() int {
+	return m.window
+}
+
+// widestWindow returns the larger of window and the widest match of expr,
+// or -1 when either is unbounded. One extra rune is allowed so word
+// boundaries at the start of a match see the character before it.
+func widestWindow(window int, expr string) int {
+	if window < 0 {
+		return -1
+	}
+	re, err := syntax.Parse(expr, syntax.Perl)
+	if err != nil {
+		return -1
+	}
+	width, bounded := matchWidth(re.Simplify())
+	if !bounded {
+		return -1
+	}
+	return max(window, width+utf8.UTFMax)
+}
+
+// matchWidth returns the most bytes re can match and whether that is bounded
+func matchWidth(re *syntax.Regexp) (int, bool) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		return len(re.Rune) * utf8.UTFMax, true
+	case syntax.OpCharClass, syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		return utf8.UTFMax, true
+	case syntax.OpCapture, syntax.OpQuest:
+		return matchWidth(re.Sub[0])
+	case syntax.OpRepeat:
+		if re.Max < 0 {
+			return 0, false
+		}
+		width, bounded := matchWidth(re.Sub[0])
+		return width * re.Max, bounded
+	case syntax.OpStar, syntax.OpPlus:
+		return 0, false
+	case syntax.OpConcat:
+		total := 0
+		for _, sub := range re.Sub {
+			width, bounded := matchWidth(sub)
+			if !bounded {
+				return 0, false
+			}
+			total += width
+		}
+		return total, true
+	case syntax.OpAlternate:
+		widest := 0
+		for _, sub := range re.Sub {
+			width, bounded := matchWidth(sub)
+			if !bounded {
+				return 0, false
+			}
+			widest = max(widest, width)
+		}
+		return widest, true
+	default:
+		// Empty matches, anchors and word boundaries consume nothing
+		return 0, true
+	}
+}
+
+// Check implements Moderator
+func (m *KeywordModerator) Check(ctx context.Context, text string) (Decision, error) {
+	var decision Decision
+	for i, matcher := range m.matchers {
+		if matcher.MatchString(text) {
+			decision.Flagged = true
+			decision.Categories = append(decision.Categories, m.names[i])
+		}
+	}
+	return decision, nil
+}
+
+// Policy applies a moderator in a mode. A nil Policy is off.
+type Policy struct {
+	Mode      string
+	Moderator Moderator
+}
+
+func init() {
+	config.RegisterKey("moderation.mode", "")
+	config.RegisterKey("moderation.categories", []interface{}{})
+}
+
+// NewPolicyFromConfig creates a policy from moderation.mode (off by default) and
+// a keyword moderator over moderation.categories
+func NewPolicyFromConfig() (*Policy, error) {
+	mode := config.GetString("moderation.mode")
+	switch mode {
+	case "", ModeOff:
+		return &Policy{Mode: ModeOff}, nil
+	case ModeFlag, ModeBlock:
+	default:
+		return nil, fmt.Errorf("invalid moderation.mode %q; want %s, %s or %s", mode, ModeOff, ModeFlag, ModeBlock)
+	}
+
+	var categories []Category
+	if err := config.UnmarshalKey("moderation.categories", &categories); err != nil {
+		return nil, fmt.Errorf("failed to read moderation.categories: %w", err)
+	}
+	moderator, err := NewKeywordModerator(categories)
+	if err != nil {
+		return nil, err
+	}
+	return &Policy{Mode: mode, Moderator: moderator}, nil
+}
+
+// Enabled reports whether the policy checks anything
+func (p *Policy) Enabled() bool {
+	return p != nil && p.Moderator != nil && (p.Mode == ModeFlag || p.Mode == ModeBlock)
+}
+
+// Review checks text under the policy. In block mode flagged text returns a
+// *BlockedError; in flag mode the flagged decision is returned for the caller to
+// record. A disabled policy returns immediately without allocating.
+func (p *Policy) Review(ctx context.Context, text string) (Decision, error) {
+	if !p.Enabled() {
+		return Decision{}, nil
+	}
+
+	decision, err := p.Moderator.Check(ctx, text)
+	if err != nil {
+		return Decision{}, fmt.Errorf("moderation check failed: %w", err)
+	}
+	if decision.Flagged && p.Mode == ModeBlock {
+		return decision, &BlockedError{Categories: decision.Categories}
+	}
+	return decision, nil
+}
+
+// Stream reviews streamed text incrementally. Each Append checks the new chunk
+// together with enough of the preceding text to hold the widest match, so
+// matches spanning chunks are caught without re-reviewing the whole stream.
+// Moderators without a bounded window are given everything received so far.
+type Stream struct {
+	policy  *Policy
+	window  int
+	text    strings.Builder
+	flagged bool
+}
+
+// NewStream starts reviewing a stream under policy
+func (p *Policy) NewStream() *Stream {
+	window := -1
+	if p.Enabled() {
+		if windowed, ok := p.Moderator.(WindowedModerator); ok {
+			window = windowed.Window()
+		}
+	}
+	return &Stream{policy: p, window: window}
+}
+
+// Append adds a chunk and reviews the text so far. It returns a *BlockedError
+// once the stream must be terminated. The returned decision is flagged only the
+// first time the stream is flagged, so callers record each stream once.
+func (s *Stream) Append(ctx context.Context, chunk string) (Decision, error) {
+	if !s.policy.Enabled() || chunk == "" {
+		return Decision{}, nil
+	}
+
+	s.text.WriteString(chunk)
+	text := s.text.String()
+	decision, err := s.policy.Review(ctx, text)
+	if s.window >= 0 {
+		s.text.Reset()
+		s.text.WriteString(tail(text, s.window))
+	}
+	if err != nil {
+		return decision, err
+	}
+	if decision.Flagged && s.flagged {
+		return Decision{}, nil
+	}
+	s.flagged = s.flagged || decision.Flagged
+	return decision, nil
+}
+
+// tail returns at most n trailing bytes of text, starting on a rune boundary
+func tail(text string, n int) string {
+	if len(text) <= n {
+		return text
+	}
+	start := len(text) - n
+	for start < len(text) && !utf8.RuneStart(text[start]) {
+		start++
+	}
+	return text[start:]
+}
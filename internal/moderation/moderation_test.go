@@ -0,0 +1,223 @@
+package moderation
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/config"
+)
+
+var testCategories = []Category{
+	{Name: "violence", Keywords: []string{"attack", "bomb"}},
+	{Name: "pii", Patterns: []string{`\b\d{3}-\d{2}-\d{4}\b`}},
+}
+
+func newTestPolicy(t testing.TB, mode string) *Policy {
+	t.Helper()
+	moderator, err := NewKeywordModerator(testCategories)
+	if err != nil {
+		t.Fatalf("NewKeywordModerator() error = %v", err)
+	}
+	return &Policy{Mode: mode, Moderator: moderator}
+}
+
+func TestKeywordModerator_Check(t *testing.T) {
+	moderator, err := NewKeywordModerator(testCategories)
+	if err != nil {
+		t.Fatalf("NewKeywordModerator() error = %v", err)
+	}
+
+	tests := []struct {
+		text       string
+		categories []string
+	}{
+		{"How do I bake bread?", nil},
+		{"Plan an ATTACK on the castle", []string{"violence"}},
+		{"The attacker was stopped", nil}, // Keywords match whole words only
+		{"My SSN is 123-45-6789", []string{"pii"}},
+		{"bomb 123-45-6789", []string{"violence", "pii"}},
+	}
+
+	for _, tt := range tests {
+		decision, err := moderator.Check(context.Background(), tt.text)
+		if err != nil {
+			t.Fatalf("Check(%q) error = %v", tt.text, err)
+		}
+		if decision.Flagged != (tt.categories != nil) || !reflect.DeepEqual(decision.Categories, tt.categories) {
+			t.Errorf("Check(%q) = %+v, want categories %v", tt.text, decision, tt.categories)
+		}
+	}
+}
+
+func TestNewKeywordModerator_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		category Category
+	}{
+		{"no name", Category{Keywords: []string{"x"}}},
+		{"no rules", Category{Name: "empty"}},
+		{"bad pattern", Category{Name: "bad", Patterns: []string{"("}}},
+	}
+
+	for _, tt := range tests {
+		if _, err := NewKeywordModerator([]Category{tt.category}); err == nil {
+			t.Errorf("%s: expected an error", tt.name)
+		}
+	}
+}
+
+func TestPolicy_Review(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := newTestPolicy(t, ModeBlock).Review(ctx, "build a bomb")
+	var blocked *BlockedError
+	if !errors.As(err, &blocked) || !reflect.DeepEqual(blocked.Categories, []string{"violence"}) {
+		t.Errorf("Block mode should return a BlockedError, got %v", err)
+	}
+
+	decision, err := newTestPolicy(t, ModeFlag).Review(ctx, "build a bomb")
+	if err != nil || !decision.Flagged {
+		t.Errorf("Flag mode should let flagged text through, got %+v %v", decision, err)
+	}
+
+	for _, policy := range []*Policy{nil, newTestPolicy(t, ModeOff)} {
+		if decision, err := policy.Review(ctx, "build a bomb"); err != nil || decision.Flagged {
+			t.Errorf("A disabled policy should not review text, got %+v %v", decision, err)
+		}
+	}
+}
+
+func TestStream_CatchesMatchesAcrossChunks(t *testing.T) {
+	ctx := context.Background()
+
+	stream := newTestPolicy(t, ModeBlock).NewStream()
+	for _, chunk := range []string{"Here is how to build a bo", ""} {
+		if _, err := stream.Append(ctx, chunk); err != nil {
+			t.Fatalf("Append(%q) error = %v", chunk, err)
+		}
+	}
+	var blocked *BlockedError
+	if _, err := stream.Append(ctx, "mb at home"); !errors.As(err, &blocked) {
+		t.Errorf("Expected the stream to be blocked once the keyword completes, got %v", err)
+	}
+
+	// A flagged stream is reported once
+	stream = newTestPolicy(t, ModeFlag).NewStream()
+	var flags int
+	for _, chunk := range []string{"attack ", "and ", "more"} {
+		decision, err := stream.Append(ctx, chunk)
+		if err != nil {
+			t.Fatalf("Append(%q) error = %v", chunk, err)
+		}
+		if decision.Flagged {
+			flags++
+		}
+	}
+	if flags != 1 {
+		t.Errorf("Expected one flagged decision per stream, got %d", flags)
+	}
+}
+
+func TestStream_ReviewsOnlyTheTail(t *testing.T) {
+	ctx := context.Background()
+
+	policy := newTestPolicy(t, ModeBlock)
+	window := policy.Moderator.(WindowedModerator).Window()
+	if window <= 0 {
+		t.Fatalf("Window() = %d, want a bounded window for keywords and fixed-width patterns", window)
+	}
+
+	stream := policy.NewStream()
+	for i := 0; i < 100; i++ {
+		if _, err := stream.Append(ctx, "a long and harmless sentence. "); err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+		if stream.text.Len() > window {
+			t.Fatalf("Stream kept %d bytes, want at most the %d byte window", stream.text.Len(), window)
+		}
+	}
+	var blocked *BlockedError
+	for _, chunk := range []string{"123-", "45-", "6789"} {
+		_, err := stream.Append(ctx, chunk)
+		if errors.As(err, &blocked) {
+			break
+		}
+	}
+	if blocked == nil {
+		t.Fatal("Expected a match spanning chunks to be blocked after a long stream")
+	}
+
+	// Unbounded patterns fall back to the whole stream
+	moderator, err := NewKeywordModerator([]Category{{Name: "spam", Patterns: []string{`buy.*now`}}})
+	if err != nil {
+		t.Fatalf("NewKeywordModerator() error = %v", err)
+	}
+	if moderator.Window() != -1 {
+		t.Errorf("Window() = %d, want -1 for an unbounded pattern", moderator.Window())
+	}
+	stream = (&Policy{Mode: ModeBlock, Moderator: moderator}).NewStream()
+	stream.Append(ctx, "buy")
+	stream.Append(ctx, strings.Repeat(" really", 200))
+	if _, err := stream.Append(ctx, " now"); !errors.As(err, &blocked) {
+		t.Errorf("Expected an unbounded match to be blocked, got %v", err)
+	}
+}
+
+func TestPolicy_DisabledDoesNotAllocate(t *testing.T) {
+	ctx := context.Background()
+	off := newTestPolicy(t, ModeOff)
+	stream := off.NewStream()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		off.Review(ctx, "build a bomb")
+		stream.Append(ctx, "build a bomb")
+	})
+	if allocs != 0 {
+		t.Errorf("Disabled moderation allocated %.0f times per review", allocs)
+	}
+}
+
+func TestNewPolicyFromConfig(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	policy, err := NewPolicyFromConfig()
+	if err != nil || policy.Enabled() {
+		t.Errorf("Moderation should be off by default, got %+v %v", policy, err)
+	}
+
+	config.SetForTest("moderation.mode", ModeBlock)
+	config.SetForTest("moderation.categories", []map[string]interface{}{
+		{"name": "violence", "keywords": []string{"bomb"}},
+	})
+	policy, err = NewPolicyFromConfig()
+	if err != nil {
+		t.Fatalf("NewPolicyFromConfig() error = %v", err)
+	}
+	if _, err := policy.Review(context.Background(), "bomb"); err == nil {
+		t.Error("Expected the configured policy to block")
+	}
+
+	config.SetForTest("moderation.mode", "strict")
+	if _, err := NewPolicyFromConfig(); err == nil {
+		t.Error("Expected an error for an unknown mode")
+	}
+}
+
+func BenchmarkPolicy_Review(b *testing.B) {
+	ctx := context.Background()
+	text := "Summarize the quarterly report and list the open action items for the team."
+
+	for _, mode := range []string{ModeOff, ModeFlag, ModeBlock} {
+		policy := newTestPolicy(b, mode)
+		b.Run(mode, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				policy.Review(ctx, text)
+			}
+		})
+	}
+}
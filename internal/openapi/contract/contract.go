@@ -0,0 +1,140 @@
+// Package contract provides a test harness that checks registered handlers
+// against the OpenAPI specification generated for their routes.
+package contract
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/cmd/generate-openapi/analyzer"
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// VerifyRoute sends a synthesized request for route to server and fails t if the
+// response does not match the generated specification
+func VerifyRoute(t testing.TB, route types.RouteInfo, server *httptest.Server) {
+	t.Helper()
+
+	if err := CheckRoute(route, server); err != nil {
+		t.Errorf("contract violation: %v", err)
+	}
+}
+
+// CheckRoute sends a synthesized request for route to server and returns an error
+// describing the first mismatch between the response and the generated specification
+func CheckRoute(route types.RouteInfo, server *httptest.Server) error {
+	routeName := fmt.Sprintf("route %s %s", strings.ToUpper(route.Method), route.Path)
+
+	gen := analyzer.NewGenerator()
+	if err := gen.LoadRoutes([]types.RouteInfo{route}); err != nil {
+		return fmt.Errorf("%s: %w", routeName, err)
+	}
+
+	req, err := buildRequest(route, server.URL)
+	if err != nil {
+		return fmt.Errorf("%s: failed to build request: %w", routeName, err)
+	}
+
+	resp, err := server.Client().Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: request failed: %w", routeName, err)
+	}
+	defer resp.Body.Close()
+
+	responses := gen.Responses(route)
+	status := strconv.Itoa(resp.StatusCode)
+	documented, ok := responses[status]
+	if !ok {
+		return fmt.Errorf("%s: status %s is not documented (documented: %s)", routeName, status, documentedCodes(responses))
+	}
+
+	// Negotiated responses are checked in the representation the server chose
+	if mediaType, _, err := mime.ParseMediaType(resp.Header.Get("Content-Type")); err == nil && mediaType != "application/json" {
+		if _, alternative := documented.Content[mediaType]; alternative {
+			return nil
+		}
+	}
+
+	media, ok := documented.Content["application/json"]
+	if !ok {
+		// Non-JSON or schemaless responses have no body contract to check
+		return nil
+	}
+
+	// Component types are referenced by name; primitives and maps are inlined
+	schemaName := "inline schema"
+	schema := media.Schema.Inline
+	if media.Schema.Ref != "" {
+		schemaName = strings.TrimPrefix(media.Schema.Ref, "#/components/schemas/")
+		schema, ok = gen.Schema(schemaName)
+		if !ok {
+			return fmt.Errorf("%s: schema %s referenced by status %s was not generated", routeName, schemaName, status)
+		}
+	}
+	if schema == nil {
+		return nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("%s: failed to read response body: %w", routeName, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value interface{}
+	if err := decoder.Decode(&value); err != nil {
+		return fmt.Errorf("%s: status %s response is not valid JSON: %w", routeName, status, err)
+	}
+
+	if err := ValidateRefs(schema, value, gen.Schema); err != nil {
+		return fmt.Errorf("%s: status %s response does not match schema %s: %w", routeName, status, schemaName, err)
+	}
+
+	return nil
+}
+
+// buildRequest synthesizes a request for route using zero values for the request body
+func buildRequest(route types.RouteInfo, baseURL string) (*http.Request, error) {
+	method := strings.ToUpper(route.Method)
+
+	var body io.Reader
+	if route.RequestType != nil && method != http.MethodGet {
+		payload, err := json.Marshal(reflect.New(route.RequestType).Elem().Interface())
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal zero value of %v: %w", route.RequestType, err)
+		}
+		body = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequest(method, baseURL+route.Path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	return req, nil
+}
+
+// documentedCodes returns the documented status codes as a sorted, comma-separated list
+func documentedCodes(responses map[string]analyzer.Response) string {
+	codes := make([]string, 0, len(responses))
+	for code := range responses {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return strings.Join(codes, ", ")
+}
@@ -0,0 +1,225 @@
+package contract
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/cmd/generate-openapi/analyzer"
+	"github.com/JerkyTreats/llm/internal/api/handler"
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+type countResponse struct {
+	Count int `json:"count"`
+}
+
+func TestAllRoutesMatchSpec(t *testing.T) {
+	// Handlers read docs relative to the repository root
+	t.Chdir("../../..")
+
+	registry, err := handler.NewHandlerRegistry()
+	if err != nil {
+		t.Fatalf("NewHandlerRegistry() error = %v", err)
+	}
+
+	server := httptest.NewServer(registry.GetServeMux())
+	defer server.Close()
+
+	routes := handler.GetRegisteredRoutes()
+	if len(routes) == 0 {
+		t.Fatal("Expected registered routes")
+	}
+
+	for _, route := range routes {
+		t.Run(route.Method+" "+route.Path, func(t *testing.T) {
+			VerifyRoute(t, route, server)
+		})
+	}
+}
+
+func TestCheckRoute_WrongResponseType(t *testing.T) {
+	route := types.RouteInfo{
+		Method:       "GET",
+		Path:         "/count",
+		ResponseType: reflect.TypeOf(countResponse{}),
+		Module:       "test",
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"count": "five"}`))
+	}))
+	defer server.Close()
+
+	err := CheckRoute(route, server)
+	if err == nil {
+		t.Fatal("CheckRoute() should fail when the handler returns a string for an integer field")
+	}
+
+	expected := "route GET /count: status 200 response does not match schema countResponse: $.count: expected integer, got string"
+	if err.Error() != expected {
+		t.Errorf("Unexpected error message:\n got: %s\nwant: %s", err.Error(), expected)
+	}
+}
+
+func TestCheckRoute_UndocumentedStatus(t *testing.T) {
+	route := types.RouteInfo{
+		Method:       "GET",
+		Path:         "/missing",
+		ResponseType: reflect.TypeOf(countResponse{}),
+		Module:       "test",
+	}
+
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	err := CheckRoute(route, server)
+	if err == nil {
+		t.Fatal("CheckRoute() should fail for an undocumented status code")
+	}
+
+	if !strings.Contains(err.Error(), "status 404 is not documented") {
+		t.Errorf("Error should mention the undocumented status, got: %v", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	schema := map[string]interface{}{
+		"type":     "object",
+		"required": []string{"name"},
+		"properties": map[string]interface{}{
+			"name": map[string]interface{}{"type": "string"},
+			"tags": map[string]interface{}{
+				"type":  "array",
+				"items": map[string]interface{}{"type": "string"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		value   interface{}
+		wantErr string
+	}{
+		{"valid", map[string]interface{}{"name": "a", "tags": []interface{}{"x"}}, ""},
+		{"missing required", map[string]interface{}{}, `$: missing required field "name"`},
+		{"wrong item type", map[string]interface{}{"name": "a", "tags": []interface{}{true}}, "$.tags[0]: expected string, got boolean"},
+		{"null array", map[string]interface{}{"name": "a", "tags": nil}, ""},
+		{"not an object", "text", "$: expected object, got string"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(schema, tt.value)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("Validate() unexpected error = %v", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %s", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+type chatContent interface{}
+
+type chatPart struct {
+	Text string `json:"text"`
+}
+
+type chatMessage struct {
+	Content chatContent `json:"content"`
+}
+
+func TestCheckRoute_UnionVariants(t *testing.T) {
+	analyzer.ClearUnions()
+	t.Cleanup(analyzer.ClearUnions)
+	analyzer.RegisterUnion(reflect.TypeOf((*chatContent)(nil)), reflect.TypeOf(""), reflect.TypeOf([]chatPart{}))
+
+	route := types.RouteInfo{
+		Method:       "GET",
+		Path:         "/message",
+		ResponseType: reflect.TypeOf(chatMessage{}),
+		Module:       "test",
+	}
+
+	tests := []struct {
+		name    string
+		body    string
+		wantErr bool
+	}{
+		{"string variant", `{"content": "hello"}`, false},
+		{"array variant", `{"content": [{"text": "hello"}]}`, false},
+		{"no matching variant", `{"content": 42}`, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/json")
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			err := CheckRoute(route, server)
+			if tt.wantErr && err == nil {
+				t.Error("CheckRoute() should fail when no union variant matches")
+			}
+			if !tt.wantErr && err != nil {
+				t.Errorf("CheckRoute() unexpected error = %v", err)
+			}
+		})
+	}
+}
+
+func TestValidateRefs_Discriminator(t *testing.T) {
+	components := map[string]map[string]interface{}{
+		"Text": {
+			"type":       "object",
+			"required":   []string{"kind", "text"},
+			"properties": map[string]interface{}{"kind": map[string]interface{}{"type": "string"}, "text": map[string]interface{}{"type": "string"}},
+		},
+		"Image": {
+			"type":       "object",
+			"required":   []string{"kind", "url"},
+			"properties": map[string]interface{}{"kind": map[string]interface{}{"type": "string"}, "url": map[string]interface{}{"type": "string"}},
+		},
+	}
+	resolve := func(name string) (map[string]interface{}, bool) {
+		schema, ok := components[name]
+		return schema, ok
+	}
+
+	schema := map[string]interface{}{
+		"oneOf": []interface{}{
+			map[string]interface{}{"$ref": "#/components/schemas/Text"},
+			map[string]interface{}{"$ref": "#/components/schemas/Image"},
+		},
+		"discriminator": map[string]interface{}{
+			"propertyName": "kind",
+			"mapping": map[string]string{
+				"text":  "#/components/schemas/Text",
+				"image": "#/components/schemas/Image",
+			},
+		},
+	}
+
+	if err := ValidateRefs(schema, map[string]interface{}{"kind": "text", "text": "hi"}, resolve); err != nil {
+		t.Errorf("Text variant should validate, got %v", err)
+	}
+	if err := ValidateRefs(schema, map[string]interface{}{"kind": "image", "url": "http://x"}, resolve); err != nil {
+		t.Errorf("Image variant should validate, got %v", err)
+	}
+	if err := ValidateRefs(schema, map[string]interface{}{"kind": "image", "text": "hi"}, resolve); err == nil {
+		t.Error("Image variant missing url should fail")
+	}
+	if err := ValidateRefs(schema, map[string]interface{}{"kind": "video"}, resolve); err == nil {
+		t.Error("Unknown discriminator value should fail")
+	}
+}
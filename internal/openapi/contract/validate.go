@@ -0,0 +1,252 @@
+package contract
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Resolver looks up a component schema by name for $ref resolution
+type Resolver func(name string) (map[string]interface{}, bool)
+
+// validator carries the component resolver through recursive validation
+type validator struct {
+	resolve Resolver
+}
+
+// Validate checks a decoded JSON value against a schema in the generator's map format.
+// It supports the subset of JSON Schema the generator emits: type, properties,
+// required, items and additionalProperties. Numbers must be decoded as json.Number.
+func Validate(schema map[string]interface{}, value interface{}) error {
+	return ValidateRefs(schema, value, nil)
+}
+
+// ValidateRefs is like Validate but also follows $ref, oneOf and discriminator
+// keywords, resolving component schemas through resolve
+func ValidateRefs(schema map[string]interface{}, value interface{}, resolve Resolver) error {
+	v := &validator{resolve: resolve}
+	return v.validateValue(schema, value, "$")
+}
+
+// validateValue recursively validates value against schema, reporting the JSON path on failure
+func (v *validator) validateValue(schema map[string]interface{}, value interface{}, path string) error {
+	if ref, ok := schema["$ref"].(string); ok {
+		resolved, err := v.resolveRef(ref, path)
+		if err != nil {
+			return err
+		}
+		return v.validateValue(resolved, value, path)
+	}
+
+	if oneOf, ok := schema["oneOf"].([]interface{}); ok {
+		return v.validateOneOf(schema, oneOf, value, path)
+	}
+
+	schemaType, _ := schema["type"].(string)
+
+	if value == nil {
+		// Go encodes nil slices, maps and pointers as null
+		if nullable, _ := schema["nullable"].(bool); nullable || schemaType == "object" || schemaType == "array" {
+			return nil
+		}
+		return fmt.Errorf("%s: expected %s, got null", path, schemaType)
+	}
+
+	switch schemaType {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return typeMismatch(path, schemaType, value)
+		}
+		return v.validateObject(schema, obj, path)
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return typeMismatch(path, schemaType, value)
+		}
+		items, ok := schema["items"].(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		for i, item := range arr {
+			if err := v.validateValue(items, item, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "string":
+		if _, ok := value.(string); !ok {
+			return typeMismatch(path, schemaType, value)
+		}
+		return nil
+	case "integer":
+		num, ok := value.(json.Number)
+		if !ok {
+			return typeMismatch(path, schemaType, value)
+		}
+		if _, err := num.Int64(); err != nil {
+			return fmt.Errorf("%s: expected integer, got number %s", path, num)
+		}
+		return nil
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return typeMismatch(path, schemaType, value)
+		}
+		return nil
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return typeMismatch(path, schemaType, value)
+		}
+		return nil
+	default:
+		// Schemas without a recognised type accept any value
+		return nil
+	}
+}
+
+// validateOneOf checks that value matches exactly one variant, or the variant selected
+// by the discriminator when one is declared
+func (v *validator) validateOneOf(schema map[string]interface{}, oneOf []interface{}, value interface{}, path string) error {
+	if discriminator, ok := schema["discriminator"].(map[string]interface{}); ok {
+		if obj, ok := value.(map[string]interface{}); ok {
+			return v.validateDiscriminated(discriminator, obj, path)
+		}
+	}
+
+	matches := 0
+	var failures []string
+	for i, variant := range oneOf {
+		variantSchema, ok := variant.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if err := v.validateValue(variantSchema, value, path); err != nil {
+			failures = append(failures, fmt.Sprintf("variant %d: %v", i, err))
+			continue
+		}
+		matches++
+	}
+
+	switch matches {
+	case 1:
+		return nil
+	case 0:
+		return fmt.Errorf("%s: value matches no oneOf variant (%s)", path, strings.Join(failures, "; "))
+	default:
+		return fmt.Errorf("%s: value matches %d oneOf variants, expected exactly one", path, matches)
+	}
+}
+
+// validateDiscriminated validates obj against the variant named by its discriminator property
+func (v *validator) validateDiscriminated(discriminator map[string]interface{}, obj map[string]interface{}, path string) error {
+	propertyName, _ := discriminator["propertyName"].(string)
+	selector, ok := obj[propertyName].(string)
+	if !ok {
+		return fmt.Errorf("%s: missing discriminator property %q", path, propertyName)
+	}
+
+	ref := "#/components/schemas/" + selector
+	switch mapping := discriminator["mapping"].(type) {
+	case map[string]string:
+		if mapped, exists := mapping[selector]; exists {
+			ref = mapped
+		}
+	case map[string]interface{}:
+		if mapped, ok := mapping[selector].(string); ok {
+			ref = mapped
+		}
+	}
+
+	resolved, err := v.resolveRef(ref, path)
+	if err != nil {
+		return fmt.Errorf("%s: unknown discriminator value %q", path, selector)
+	}
+	return v.validateValue(resolved, obj, path)
+}
+
+// resolveRef looks up the component schema a $ref points to
+func (v *validator) resolveRef(ref, path string) (map[string]interface{}, error) {
+	name := strings.TrimPrefix(ref, "#/components/schemas/")
+	if v.resolve == nil {
+		return nil, fmt.Errorf("%s: cannot resolve %s without a resolver", path, ref)
+	}
+	schema, ok := v.resolve(name)
+	if !ok {
+		return nil, fmt.Errorf("%s: unresolved reference %s", path, ref)
+	}
+	return schema, nil
+}
+
+// validateObject checks required fields and the types of known properties
+func (v *validator) validateObject(schema map[string]interface{}, obj map[string]interface{}, path string) error {
+	for _, name := range requiredFields(schema) {
+		if _, exists := obj[name]; !exists {
+			return fmt.Errorf("%s: missing required field %q", path, name)
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	// Iterate in a stable order so failures are reproducible
+	names := make([]string, 0, len(obj))
+	for name := range obj {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		propSchema, ok := properties[name].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if err := v.validateValue(propSchema, obj[name], path+"."+name); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// requiredFields reads the required list from either generated or YAML-decoded schemas
+func requiredFields(schema map[string]interface{}) []string {
+	switch required := schema["required"].(type) {
+	case []string:
+		return required
+	case []interface{}:
+		fields := make([]string, 0, len(required))
+		for _, field := range required {
+			if name, ok := field.(string); ok {
+				fields = append(fields, name)
+			}
+		}
+		return fields
+	default:
+		return nil
+	}
+}
+
+// typeMismatch builds an error describing the expected and actual JSON types
+func typeMismatch(path, expected string, value interface{}) error {
+	return fmt.Errorf("%s: expected %s, got %s", path, expected, jsonTypeName(value))
+}
+
+// jsonTypeName returns the JSON type name of a decoded value
+func jsonTypeName(value interface{}) string {
+	switch value.(type) {
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case json.Number, float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
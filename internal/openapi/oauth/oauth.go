@@ -0,0 +1,48 @@
+// Package oauth holds the OAuth2 config shared by the OpenAPI generator, which
+// emits the security scheme, and the docs handler, which configures Swagger UI
+// sign-in, so the server does not link the generator to read it.
+package oauth
+
+import (
+	"fmt"
+
+	"github.com/JerkyTreats/llm/internal/config"
+)
+
+// ConfigKey is the config key describing the identity provider API clients sign
+// in with. The spec and Swagger UI both read it, so they cannot disagree about
+// the flow.
+const ConfigKey = "docs.oauth2"
+
+func init() {
+	config.RegisterKey(ConfigKey, map[string]interface{}{})
+}
+
+// Config configures the OAuth2 authorization code flow. The client id is public;
+// confidential client secrets are deliberately not supported because they would
+// be rendered into the docs page.
+type Config struct {
+	AuthorizationURL string            `mapstructure:"authorization_url"`
+	TokenURL         string            `mapstructure:"token_url"`
+	RefreshURL       string            `mapstructure:"refresh_url"`
+	Scopes           map[string]string `mapstructure:"scopes"` // Scope name to description
+	ClientID         string            `mapstructure:"client_id"`
+	UsePKCE          bool              `mapstructure:"use_pkce"`
+	// Modules whose routes require the OAuth2 scheme, in addition to routes
+	// listing it in their Security
+	Modules []string `mapstructure:"modules"`
+}
+
+// Enabled reports whether the config describes a usable authorization code flow
+func (c Config) Enabled() bool {
+	return c.AuthorizationURL != "" && c.TokenURL != ""
+}
+
+// Load reads the OAuth2 config under ConfigKey; it is empty when the key is not set
+func Load() (Config, error) {
+	var cfg Config
+	if err := config.UnmarshalKey(ConfigKey, &cfg); err != nil {
+		return Config{}, fmt.Errorf("failed to read %s from config: %w", ConfigKey, err)
+	}
+	return cfg, nil
+}
@@ -0,0 +1,36 @@
+package provider
+
+// ChatMessage is one message of an OpenAI-compatible chat completion
+type ChatMessage struct {
+	Role    string `json:"role" example:"user" description:"Author of the message: system, user or assistant"`
+	Content string `json:"content" description:"Text of the message"`
+}
+
+// ChatRequest is an OpenAI-compatible chat completion request
+type ChatRequest struct {
+	Model    string        `json:"model" example:"gpt-4o" description:"Model the completion is routed by"`
+	Messages []ChatMessage `json:"messages" description:"Conversation to complete, oldest first"`
+}
+
+// ChatChoice is one generated completion
+type ChatChoice struct {
+	Index        int         `json:"index"`
+	Message      ChatMessage `json:"message"`
+	FinishReason string      `json:"finish_reason"`
+}
+
+// Usage reports the tokens a completion consumed
+type Usage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// ChatResponse is an OpenAI-compatible chat completion response
+type ChatResponse struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"`
+	Model   string       `json:"model"`
+	Choices []ChatChoice `json:"choices"`
+	Usage   Usage        `json:"usage"`
+}
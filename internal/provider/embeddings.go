@@ -0,0 +1,23 @@
+package provider
+
+// EmbeddingsRequest is an OpenAI-compatible embeddings request. Input is a string
+// or an array of strings.
+type EmbeddingsRequest struct {
+	Model string      `json:"model" example:"text-embedding-3-small" description:"Model the request is routed by"`
+	Input interface{} `json:"input" description:"Text to embed: a string or an array of strings"`
+}
+
+// Embedding is the vector for one input
+type Embedding struct {
+	Index     int       `json:"index"`
+	Object    string    `json:"object"`
+	Embedding []float64 `json:"embedding"`
+}
+
+// EmbeddingsResponse is an OpenAI-compatible embeddings response
+type EmbeddingsResponse struct {
+	Object string      `json:"object"`
+	Model  string      `json:"model"`
+	Data   []Embedding `json:"data"`
+	Usage  Usage       `json:"usage"`
+}
@@ -0,0 +1,48 @@
+package provider
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// Module groups the routes the Router serves by forwarding them to providers
+const Module = "completions"
+
+// embeddingsPath is the provider path embeddings are sent to
+const embeddingsPath = "/v1/embeddings"
+
+func init() {
+	// Register chat completion endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "POST",
+		Path:            chatCompletionsPath,
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "provider.Router.ServeHTTP",
+		RequestType:     reflect.TypeOf(ChatRequest{}),
+		ResponseType:    reflect.TypeOf(ChatResponse{}),
+		Module:          Module,
+		Summary:         "Complete a chat through the providers routed for its model",
+		ErrorStatuses:   []int{http.StatusNotFound, http.StatusRequestEntityTooLarge, http.StatusBadGateway, http.StatusServiceUnavailable},
+
+		// Completions call the upstream provider, whose concurrency is capped
+		ConcurrencyGroup: types.ConcurrencyGroupCompletions,
+	})
+
+	// Register embeddings endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "POST",
+		Path:            embeddingsPath,
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "provider.Router.ServeHTTP",
+		RequestType:     reflect.TypeOf(EmbeddingsRequest{}),
+		ResponseType:    reflect.TypeOf(EmbeddingsResponse{}),
+		Module:          Module,
+		Summary:         "Embed text through the providers routed for its model",
+		ErrorStatuses:   []int{http.StatusNotFound, http.StatusRequestEntityTooLarge, http.StatusBadGateway, http.StatusServiceUnavailable},
+
+		// Embeddings call the upstream provider too
+		ConcurrencyGroup: types.ConcurrencyGroupCompletions,
+	})
+}
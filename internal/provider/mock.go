@@ -0,0 +1,102 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+// MockProvider answers chat completions locally by echoing the last message, and
+// embeddings with one vector per input. It stands in for a real backend in tests
+// and local development.
+type MockProvider struct {
+	name string
+}
+
+// NewMockProvider creates a mock provider called name
+func NewMockProvider(name string) *MockProvider {
+	return &MockProvider{name: name}
+}
+
+// Name implements Provider
+func (p *MockProvider) Name() string {
+	return p.name
+}
+
+// Do implements Provider
+func (p *MockProvider) Do(ctx context.Context, path string, body []byte) (*http.Response, error) {
+	if path == embeddingsPath {
+		return p.embed(body)
+	}
+
+	var req ChatRequest
+	if err := json.Unmarshal(body, &req); err != nil {
+		return mockResponse(http.StatusBadRequest, types.NewErrorResponse(http.StatusBadRequest, "invalid chat request"))
+	}
+
+	var prompt string
+	if len(req.Messages) > 0 {
+		prompt = req.Messages[len(req.Messages)-1].Content
+	}
+	promptTokens := len(strings.Fields(prompt))
+
+	return mockResponse(http.StatusOK, ChatResponse{
+		ID:     "mock-" + p.name,
+		Object: "chat.completion",
+		Model:  req.Model,
+		Choices: []ChatChoice{{
+			Message:      ChatMessage{Role: "assistant", Content: prompt},
+			FinishReason: "stop",
+		}},
+		Usage: Usage{PromptTokens: promptTokens, CompletionTokens: promptTokens, TotalTokens: 2 * promptTokens},
+	})
+}
+
+// embed answers an embeddings request with a vector of each input's length in
+// characters and words
+func (p *MockProvider) embed(body []byte) (*http.Response, error) {
+	var req struct {
+		Model string          `json:"model"`
+		Input json.RawMessage `json:"input"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return mockResponse(http.StatusBadRequest, types.NewErrorResponse(http.StatusBadRequest, "invalid embeddings request"))
+	}
+	var inputs []string
+	if err := json.Unmarshal(req.Input, &inputs); err != nil {
+		var input string
+		if err := json.Unmarshal(req.Input, &input); err != nil {
+			return mockResponse(http.StatusBadRequest, types.NewErrorResponse(http.StatusBadRequest, "input must be a string or an array of strings"))
+		}
+		inputs = []string{input}
+	}
+
+	resp := EmbeddingsResponse{Object: "list", Model: req.Model, Data: make([]Embedding, len(inputs))}
+	for i, input := range inputs {
+		words := len(strings.Fields(input))
+		resp.Data[i] = Embedding{Index: i, Object: "embedding", Embedding: []float64{float64(len(input)), float64(words)}}
+		resp.Usage.PromptTokens += words
+	}
+	resp.Usage.TotalTokens = resp.Usage.PromptTokens
+	return mockResponse(http.StatusOK, resp)
+}
+
+// mockResponse encodes body as a JSON HTTP response
+func mockResponse(status int, body interface{}) (*http.Response, error) {
+	encoded, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode mock response: %w", err)
+	}
+	return &http.Response{
+		StatusCode: status,
+		Status:     fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(encoded)),
+	}, nil
+}
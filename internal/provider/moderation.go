@@ -0,0 +1,193 @@
+package provider
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/logging"
+	"github.com/JerkyTreats/llm/internal/moderation"
+)
+
+// ModerationHeader lists the policy categories a flagged prompt matched
+const ModerationHeader = "X-Moderation-Categories"
+
+// moderatePrompt reviews the prompt in a chat or embeddings request body. It
+// writes the error response and returns false when the request must not be
+// forwarded; flagged prompts are recorded and named in ModerationHeader.
+func (r *Router) moderatePrompt(ctx context.Context, w http.ResponseWriter, model string, body []byte) bool {
+	if !r.moderation.Enabled() {
+		return true
+	}
+
+	decision, err := r.moderation.Review(ctx, promptText(body))
+	var blocked *moderation.BlockedError
+	switch {
+	case errors.As(err, &blocked):
+		recordBlock(model, "prompt", blocked)
+		writePolicyError(w, blocked)
+		return false
+	case err != nil:
+		logging.Error("Failed to moderate prompt for model %s: %v", model, err)
+		types.WriteError(w, http.StatusInternalServerError, "failed to moderate request")
+		return false
+	}
+
+	if decision.Flagged {
+		recordFlag(model, "prompt", decision)
+		w.Header().Set(ModerationHeader, strings.Join(decision.Categories, ","))
+	}
+	return true
+}
+
+// relayModeratedStream copies a server-sent event stream to the client, reviewing
+// the completion text as it arrives. When the policy blocks it, the stream ends
+// with an error event in place of the offending chunk.
+func (r *Router) relayModeratedStream(ctx context.Context, w http.ResponseWriter, model, name string, body io.Reader) {
+	stream := r.moderation.NewStream()
+	flusher, _ := w.(http.Flusher)
+	reader := bufio.NewReader(body)
+
+	for {
+		line, readErr := reader.ReadString('\n')
+		if line != "" {
+			decision, err := stream.Append(ctx, streamDelta(line))
+			var blocked *moderation.BlockedError
+			switch {
+			case errors.As(err, &blocked):
+				recordBlock(model, "completion", blocked)
+				writeStreamPolicyError(w, blocked)
+				return
+			case err != nil:
+				logging.Error("Failed to moderate stream from provider %s: %v", name, err)
+				return
+			case decision.Flagged:
+				recordFlag(model, "completion", decision)
+			}
+
+			if _, err := io.WriteString(w, line); err != nil {
+				logging.Warn("Failed to relay stream from provider %s: %v", name, err)
+				return
+			}
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+		if readErr != nil {
+			if readErr != io.EOF {
+				logging.Warn("Failed to read stream from provider %s: %v", name, readErr)
+			}
+			return
+		}
+	}
+}
+
+// isEventStream reports whether a provider response is a server-sent event stream
+func isEventStream(resp *http.Response) bool {
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream")
+}
+
+// promptText returns the text a request sends upstream: the message contents of
+// a chat completion, or the input of an embeddings request
+func promptText(body []byte) string {
+	var payload struct {
+		Messages []ChatMessage   `json:"messages"`
+		Input    json.RawMessage `json:"input"`
+	}
+	if err := json.Unmarshal(body, &payload); err != nil {
+		return ""
+	}
+
+	text := messagesText(payload.Messages)
+	var input string
+	var inputs []string
+	switch {
+	case json.Unmarshal(payload.Input, &input) == nil:
+		text += "\n" + input
+	case json.Unmarshal(payload.Input, &inputs) == nil:
+		text += "\n" + strings.Join(inputs, "\n")
+	}
+	return text
+}
+
+// messagesText joins chat message contents with newlines
+func messagesText(messages []ChatMessage) string {
+	contents := make([]string, len(messages))
+	for i, message := range messages {
+		contents[i] = message.Content
+	}
+	return strings.Join(contents, "\n")
+}
+
+// streamDelta returns the completion text in one line of a chat completion event
+// stream, or "" for lines carrying none
+func streamDelta(line string) string {
+	data, ok := strings.CutPrefix(strings.TrimSpace(line), "data:")
+	if !ok {
+		return ""
+	}
+	data = strings.TrimSpace(data)
+	if data == "" || data == "[DONE]" {
+		return ""
+	}
+
+	var chunk struct {
+		Choices []struct {
+			Delta struct {
+				Content string `json:"content"`
+			} `json:"delta"`
+		} `json:"choices"`
+	}
+	if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+		return ""
+	}
+	var text strings.Builder
+	for _, choice := range chunk.Choices {
+		text.WriteString(choice.Delta.Content)
+	}
+	return text.String()
+}
+
+// recordFlag records content the moderation policy flagged but let through
+func recordFlag(model, source string, decision moderation.Decision) {
+	moderation.Record(moderation.ActionFlag, source, decision.Categories)
+	logging.WithFields("model", model, "source", source, "categories", decision.Categories).
+		Warn("Content flagged by moderation policy")
+}
+
+// recordBlock records content the moderation policy blocked
+func recordBlock(model, source string, blocked *moderation.BlockedError) {
+	moderation.Record(moderation.ActionBlock, source, blocked.Categories)
+	logging.WithFields("model", model, "source", source, "categories", blocked.Categories).
+		Warn("Content blocked by moderation policy")
+}
+
+// writePolicyError writes a 400 ErrorResponse naming the categories that blocked the request
+func writePolicyError(w http.ResponseWriter, blocked *moderation.BlockedError) {
+	types.WriteErrorResponse(w, policyError(blocked))
+}
+
+// writeStreamPolicyError ends an event stream with an error event for blocked content
+func writeStreamPolicyError(w http.ResponseWriter, blocked *moderation.BlockedError) {
+	data, err := json.Marshal(policyError(blocked))
+	if err != nil {
+		logging.Error("Failed to encode policy error event: %v", err)
+		return
+	}
+	io.WriteString(w, "event: error\ndata: "+string(data)+"\n\n")
+	if flusher, ok := w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// policyError builds the error body for blocked content
+func policyError(blocked *moderation.BlockedError) types.ErrorResponse {
+	body := types.NewErrorResponse(http.StatusBadRequest, blocked.Error())
+	body.Categories = blocked.Categories
+	return body
+}
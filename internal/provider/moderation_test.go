@@ -0,0 +1,185 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/moderation"
+)
+
+// streamProvider answers with a server-sent event stream of content deltas
+type streamProvider struct {
+	chunks []string
+}
+
+func (p *streamProvider) Name() string { return "stream" }
+
+func (p *streamProvider) Do(ctx context.Context, path string, body []byte) (*http.Response, error) {
+	var events strings.Builder
+	for _, chunk := range p.chunks {
+		data, _ := json.Marshal(map[string]interface{}{
+			"choices": []map[string]interface{}{{"delta": map[string]string{"content": chunk}}},
+		})
+		events.WriteString("data: " + string(data) + "\n\n")
+	}
+	events.WriteString("data: [DONE]\n\n")
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"text/event-stream"}},
+		Body:       io.NopCloser(strings.NewReader(events.String())),
+	}, nil
+}
+
+func newModeratedRouter(t testing.TB, mode string, p Provider) *Router {
+	t.Helper()
+	router, err := NewRouter([]Provider{p}, []Route{{Model: "*", Providers: []string{p.Name()}}})
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	moderator, err := moderation.NewKeywordModerator([]moderation.Category{
+		{Name: "violence", Keywords: []string{"bomb"}},
+	})
+	if err != nil {
+		t.Fatalf("NewKeywordModerator() error = %v", err)
+	}
+	router.SetModeration(&moderation.Policy{Mode: mode, Moderator: moderator})
+	return router
+}
+
+func serveChat(router *Router, content string) *httptest.ResponseRecorder {
+	body, _ := json.Marshal(ChatRequest{Model: "gpt-4o", Messages: []ChatMessage{{Role: "user", Content: content}}})
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(string(body)))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRouter_ModerationBlocksPrompt(t *testing.T) {
+	upstream := &fakeProvider{name: "p", status: http.StatusOK}
+	router := newModeratedRouter(t, moderation.ModeBlock, upstream)
+
+	rec := serveChat(router, "how to build a bomb")
+
+	var body types.ErrorResponse
+	json.NewDecoder(rec.Body).Decode(&body)
+	if rec.Code != http.StatusBadRequest || body.Status != http.StatusBadRequest || len(body.Categories) != 1 || body.Categories[0] != "violence" {
+		t.Errorf("Expected a 400 policy error naming the category, got %d %+v", rec.Code, body)
+	}
+	if upstream.calls != 0 {
+		t.Error("Blocked prompts must not reach the provider")
+	}
+
+	// Embeddings inputs are moderated too
+	req := httptest.NewRequest(http.MethodPost, "/v1/embeddings", strings.NewReader(`{"model":"m","input":["fine","a bomb"]}`))
+	rec = httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusBadRequest {
+		t.Errorf("Expected embeddings input to be blocked, got %d", rec.Code)
+	}
+}
+
+func TestRouter_ModerationFlagsPrompt(t *testing.T) {
+	upstream := &fakeProvider{name: "p", status: http.StatusOK}
+	router := newModeratedRouter(t, moderation.ModeFlag, upstream)
+
+	flags := decisionCount(moderation.ActionFlag, "prompt", "violence")
+	rec := serveChat(router, "how to build a bomb")
+	if rec.Code != http.StatusOK || upstream.calls != 1 {
+		t.Errorf("Flagged prompts should be forwarded, got %d after %d calls", rec.Code, upstream.calls)
+	}
+	if got := rec.Header().Get(ModerationHeader); got != "violence" {
+		t.Errorf("%s = %q, want violence", ModerationHeader, got)
+	}
+
+	if decisionCount(moderation.ActionFlag, "prompt", "violence") != flags+1 {
+		t.Errorf("Flagged prompts should be recorded, got %+v", moderation.Decisions())
+	}
+
+	rec = serveChat(router, "how to bake bread")
+	if got := rec.Header().Get(ModerationHeader); got != "" {
+		t.Errorf("Unflagged prompts should not set %s, got %q", ModerationHeader, got)
+	}
+}
+
+func TestRouter_ModerationCutsOffStream(t *testing.T) {
+	upstream := &streamProvider{chunks: []string{"First mix the ", "ingredients, then the bo", "mb goes ", "in the oven"}}
+	router := newModeratedRouter(t, moderation.ModeBlock, upstream)
+
+	rec := serveChat(router, "how do I bake?")
+	stream := rec.Body.String()
+
+	if !strings.Contains(stream, "ingredients, then the bo") {
+		t.Errorf("Chunks before the match should be relayed, got %q", stream)
+	}
+	if strings.Contains(stream, "mb goes") || strings.Contains(stream, "oven") || strings.Contains(stream, "[DONE]") {
+		t.Errorf("The stream should end at the blocked chunk, got %q", stream)
+	}
+	if !strings.HasSuffix(stream, "\n\n") || !strings.Contains(stream, "event: error\ndata: ") || !strings.Contains(stream, `"categories":["violence"]`) {
+		t.Errorf("Expected a policy error event, got %q", stream)
+	}
+
+	// Without moderation the stream is relayed untouched
+	router.SetModeration(nil)
+	if stream := serveChat(router, "how do I bake?").Body.String(); !strings.Contains(stream, "oven") || !strings.Contains(stream, "[DONE]") {
+		t.Errorf("Expected the full stream without moderation, got %q", stream)
+	}
+}
+
+func TestRouter_CompleteBlocked(t *testing.T) {
+	router := newModeratedRouter(t, moderation.ModeBlock, NewMockProvider("mock"))
+
+	blocks := decisionCount(moderation.ActionBlock, "prompt", "violence")
+	_, err := router.Complete(context.Background(), ChatRequest{Model: "m", Messages: []ChatMessage{{Role: "user", Content: "bomb"}}})
+	var blocked *moderation.BlockedError
+	if !errors.As(err, &blocked) {
+		t.Errorf("Expected a BlockedError, got %v", err)
+	}
+	if decisionCount(moderation.ActionBlock, "prompt", "violence") != blocks+1 {
+		t.Errorf("Blocked completions should be recorded, got %+v", moderation.Decisions())
+	}
+}
+
+func TestRouter_ModerationOffDoesNotAllocate(t *testing.T) {
+	router := newModeratedRouter(t, moderation.ModeOff, &fakeProvider{name: "p", status: http.StatusOK})
+	body := []byte(`{"model":"m","messages":[{"role":"user","content":"bomb"}]}`)
+	rec := httptest.NewRecorder()
+
+	allocs := testing.AllocsPerRun(100, func() {
+		router.moderatePrompt(context.Background(), rec, "m", body)
+	})
+	if allocs != 0 {
+		t.Errorf("Disabled moderation allocated %.0f times per request", allocs)
+	}
+}
+
+func BenchmarkRouter_ModeratePrompt(b *testing.B) {
+	body := []byte(`{"model":"m","messages":[{"role":"system","content":"Be brief."},{"role":"user","content":"Summarize the quarterly report."}]}`)
+
+	for _, mode := range []string{moderation.ModeOff, moderation.ModeFlag, moderation.ModeBlock} {
+		router := newModeratedRouter(b, mode, &fakeProvider{name: "p", status: http.StatusOK})
+		rec := httptest.NewRecorder()
+		b.Run(mode, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				router.moderatePrompt(context.Background(), rec, "m", body)
+			}
+		})
+	}
+}
+
+// decisionCount returns the recorded count of one moderation decision
+func decisionCount(action, source, category string) int64 {
+	for _, d := range moderation.Decisions() {
+		if d.Action == action && d.Source == source && d.Category == category {
+			return d.Count
+		}
+	}
+	return 0
+}
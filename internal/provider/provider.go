@@ -0,0 +1,99 @@
+// Package provider forwards LLM API requests to upstream backends such as hosted
+// APIs or a local inference server.
+package provider
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/tracing"
+)
+
+// defaultTimeout bounds a single upstream request
+const defaultTimeout = 2 * time.Minute
+
+// Provider sends API requests to one upstream LLM backend
+type Provider interface {
+	// Name identifies the provider in llm.routes and the X-Provider header
+	Name() string
+	// Do forwards a JSON request body to path, e.g. /v1/chat/completions
+	Do(ctx context.Context, path string, body []byte) (*http.Response, error)
+}
+
+// Provider types accepted in llm.providers
+const (
+	TypeHTTP = "http" // OpenAI-compatible HTTP API (default)
+	TypeMock = "mock" // MockProvider, for local development
+)
+
+// ProviderConfig describes an entry of llm.providers
+type ProviderConfig struct {
+	Name    string `mapstructure:"name"`
+	Type    string `mapstructure:"type"`
+	BaseURL string `mapstructure:"base_url"`
+	APIKey  string `mapstructure:"api_key"`
+}
+
+// NewFromConfig creates the provider an llm.providers entry describes
+func NewFromConfig(cfg ProviderConfig) (Provider, error) {
+	switch cfg.Type {
+	case "", TypeHTTP:
+		return NewHTTPProvider(cfg)
+	case TypeMock:
+		if cfg.Name == "" {
+			return nil, fmt.Errorf("provider name is required")
+		}
+		return NewMockProvider(cfg.Name), nil
+	default:
+		return nil, fmt.Errorf("provider %s has unknown type %q", cfg.Name, cfg.Type)
+	}
+}
+
+// HTTPProvider forwards requests to an OpenAI-compatible HTTP API
+type HTTPProvider struct {
+	Client *http.Client
+
+	name    string
+	baseURL string
+	apiKey  string
+}
+
+// NewHTTPProvider creates a provider for an OpenAI-compatible API at cfg.BaseURL.
+// Its requests become client spans of the caller's span when tracing is enabled.
+func NewHTTPProvider(cfg ProviderConfig) (*HTTPProvider, error) {
+	if cfg.Name == "" {
+		return nil, fmt.Errorf("provider name is required")
+	}
+	if cfg.BaseURL == "" {
+		return nil, fmt.Errorf("provider %s has no base_url", cfg.Name)
+	}
+
+	return &HTTPProvider{
+		Client:  &http.Client{Timeout: defaultTimeout, Transport: tracing.NewTransport(nil)},
+		name:    cfg.Name,
+		baseURL: strings.TrimSuffix(cfg.BaseURL, "/"),
+		apiKey:  cfg.APIKey,
+	}, nil
+}
+
+// Name implements Provider
+func (p *HTTPProvider) Name() string {
+	return p.name
+}
+
+// Do implements Provider
+func (p *HTTPProvider) Do(ctx context.Context, path string, body []byte) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+path, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if p.apiKey != "" {
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+	}
+	return p.Client.Do(req)
+}
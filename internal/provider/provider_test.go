@@ -0,0 +1,74 @@
+package provider
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/tracing"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// newTracedProvider installs an in-memory span exporter and returns an HTTPProvider
+// for an upstream answering every request with a completion using 3 and 5 tokens
+func newTracedProvider(t *testing.T) (*HTTPProvider, *tracetest.InMemoryExporter) {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	tracing.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	t.Cleanup(func() { tracing.SetTracerProvider(nil) })
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"model":"gpt-4o","choices":[],"usage":{"prompt_tokens":3,"completion_tokens":5,"total_tokens":8}}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	p, err := NewHTTPProvider(ProviderConfig{Name: "upstream", BaseURL: upstream.URL})
+	if err != nil {
+		t.Fatalf("NewHTTPProvider() error = %v", err)
+	}
+	return p, exporter
+}
+
+// assertTokenUsageSpan checks the exporter holds one client span carrying the
+// upstream's token usage
+func assertTokenUsageSpan(t *testing.T, exporter *tracetest.InMemoryExporter) {
+	t.Helper()
+	spans := exporter.GetSpans()
+	if len(spans) != 1 || spans[0].SpanKind != trace.SpanKindClient {
+		t.Fatalf("Expected one client span, got %v", spans)
+	}
+
+	usage := make(map[string]int64)
+	for _, kv := range spans[0].Attributes {
+		usage[string(kv.Key)] = kv.Value.AsInt64()
+	}
+	if usage["gen_ai.usage.input_tokens"] != 3 || usage["gen_ai.usage.output_tokens"] != 5 {
+		t.Errorf("Expected input and output tokens 3 and 5 on the client span, got %v", spans[0].Attributes)
+	}
+}
+
+func TestHTTPProvider_TracesCompleteTokenUsage(t *testing.T) {
+	p, exporter := newTracedProvider(t)
+	router := newTestRouter(t, []Route{{Model: "gpt-4o", Providers: []string{"upstream"}}}, p)
+
+	if _, err := router.Complete(context.Background(), ChatRequest{Model: "gpt-4o"}); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	assertTokenUsageSpan(t, exporter)
+}
+
+func TestHTTPProvider_TracesRelayedTokenUsage(t *testing.T) {
+	p, exporter := newTracedProvider(t)
+	router := newTestRouter(t, []Route{{Model: "gpt-4o", Providers: []string{"upstream"}}}, p)
+
+	rec := serve(router, "gpt-4o")
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"total_tokens":8`) {
+		t.Fatalf("Expected the completion relayed unchanged, got %d %s", rec.Code, rec.Body.String())
+	}
+	assertTokenUsageSpan(t, exporter)
+}
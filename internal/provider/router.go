@@ -0,0 +1,389 @@
+package provider
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
+	"github.com/JerkyTreats/llm/internal/logging"
+	"github.com/JerkyTreats/llm/internal/moderation"
+	"github.com/JerkyTreats/llm/internal/tracing"
+)
+
+// ProviderHeader names the provider that served a routed response
+const ProviderHeader = "X-Provider"
+
+// chatCompletionsPath is the provider path chat completions are sent to
+const chatCompletionsPath = "/v1/chat/completions"
+
+// Errors returned by Router.Forward
+var (
+	ErrUnknownModel    = errors.New("unknown model")
+	ErrProvidersFailed = errors.New("all providers failed")
+)
+
+// StatusError is returned by Router.Complete when a provider rejects a request
+type StatusError struct {
+	Provider string
+	Status   int
+	Body     string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("provider %s returned %d: %s", e.Provider, e.Status, e.Body)
+}
+
+// maxRequestBody bounds the request body buffered for replay to fallback providers
+const maxRequestBody = 10 << 20
+
+// Route sends models matching Model to Providers in order, falling through to the
+// next provider when one fails. Model is an exact name, a prefix ending in "*"
+// such as "llama3*", or "*" to match every model.
+type Route struct {
+	Model     string   `mapstructure:"model"`
+	Providers []string `mapstructure:"providers"`
+}
+
+// ProviderStats counts routed requests per provider
+type ProviderStats struct {
+	Served int64 // Requests the provider answered
+	Failed int64 // Retryable failures that fell through to the next provider
+}
+
+// Router picks the providers for a request's model and fails over between them.
+// It serves the chat completion and embeddings routes, and sessions and templates
+// complete their prompts through it.
+type Router struct {
+	providers  map[string]Provider
+	routes     []Route
+	moderation *moderation.Policy
+
+	mu    sync.Mutex
+	stats map[string]ProviderStats
+}
+
+// NewRouter creates a router over providers. Every provider a route names must
+// be among providers.
+func NewRouter(providers []Provider, routes []Route) (*Router, error) {
+	byName := make(map[string]Provider, len(providers))
+	for _, p := range providers {
+		if _, exists := byName[p.Name()]; exists {
+			return nil, fmt.Errorf("duplicate provider %s", p.Name())
+		}
+		byName[p.Name()] = p
+	}
+
+	for _, route := range routes {
+		if route.Model == "" {
+			return nil, fmt.Errorf("route has no model pattern")
+		}
+		if len(route.Providers) == 0 {
+			return nil, fmt.Errorf("route %s has no providers", route.Model)
+		}
+		for _, name := range route.Providers {
+			if _, exists := byName[name]; !exists {
+				return nil, fmt.Errorf("route %s uses unknown provider %s", route.Model, name)
+			}
+		}
+	}
+
+	return &Router{
+		providers: byName,
+		routes:    routes,
+		stats:     make(map[string]ProviderStats),
+	}, nil
+}
+
+func init() {
+	config.RegisterKey("llm.providers", []interface{}{})
+	config.RegisterKey("llm.routes", []interface{}{})
+}
+
+// NewRouterFromConfig creates a router from llm.providers and llm.routes, moderating
+// prompts with the policy configured under moderation
+func NewRouterFromConfig() (*Router, error) {
+	var configs []ProviderConfig
+	if err := config.UnmarshalKey("llm.providers", &configs); err != nil {
+		return nil, fmt.Errorf("failed to read llm.providers: %w", err)
+	}
+	var routes []Route
+	if err := config.UnmarshalKey("llm.routes", &routes); err != nil {
+		return nil, fmt.Errorf("failed to read llm.routes: %w", err)
+	}
+
+	providers := make([]Provider, 0, len(configs))
+	for _, cfg := range configs {
+		p, err := NewFromConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		providers = append(providers, p)
+	}
+
+	policy, err := moderation.NewPolicyFromConfig()
+	if err != nil {
+		return nil, err
+	}
+	router, err := NewRouter(providers, routes)
+	if err != nil {
+		return nil, err
+	}
+	router.SetModeration(policy)
+	return router, nil
+}
+
+// SetModeration reviews prompts and streamed completions with policy; nil disables moderation
+func (r *Router) SetModeration(policy *moderation.Policy) {
+	r.moderation = policy
+}
+
+// Match returns the providers for model, in fallback order. An exact pattern wins
+// over a prefix, the longest prefix wins over shorter ones, and "*" matches last.
+func (r *Router) Match(model string) ([]Provider, bool) {
+	var best *Route
+	bestRank := -1
+	for i := range r.routes {
+		rank := matchRank(r.routes[i].Model, model)
+		if rank > bestRank {
+			best, bestRank = &r.routes[i], rank
+		}
+	}
+	if best == nil || bestRank < 0 {
+		return nil, false
+	}
+
+	providers := make([]Provider, 0, len(best.Providers))
+	for _, name := range best.Providers {
+		providers = append(providers, r.providers[name])
+	}
+	return providers, true
+}
+
+// matchRank scores how specifically pattern matches model, or returns -1 if it
+// does not match: "*" scores 0, prefixes their length and exact names highest
+func matchRank(pattern, model string) int {
+	switch {
+	case pattern == model:
+		return len(model) + 1
+	case pattern == "*":
+		return 0
+	case strings.HasSuffix(pattern, "*"):
+		prefix := strings.TrimSuffix(pattern, "*")
+		if strings.HasPrefix(model, prefix) {
+			return len(prefix)
+		}
+	}
+	return -1
+}
+
+// Models returns the configured model patterns, sorted
+func (r *Router) Models() []string {
+	models := make([]string, 0, len(r.routes))
+	for _, route := range r.routes {
+		models = append(models, route.Model)
+	}
+	sort.Strings(models)
+	return models
+}
+
+// Stats returns a snapshot of the per-provider counters
+func (r *Router) Stats() map[string]ProviderStats {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stats := make(map[string]ProviderStats, len(r.stats))
+	for name, s := range r.stats {
+		stats[name] = s
+	}
+	return stats
+}
+
+// record updates a provider's counters
+func (r *Router) record(name string, served bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	s := r.stats[name]
+	if served {
+		s.Served++
+	} else {
+		s.Failed++
+	}
+	r.stats[name] = s
+}
+
+// ServeHTTP forwards the request to the providers routed for its "model" field,
+// trying each in order until one answers without a retryable failure
+func (r *Router) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	body, err := io.ReadAll(io.LimitReader(req.Body, maxRequestBody+1))
+	if err != nil {
+		types.WriteError(w, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+	if len(body) > maxRequestBody {
+		types.WriteError(w, http.StatusRequestEntityTooLarge, "request body too large")
+		return
+	}
+
+	var envelope struct {
+		Model string `json:"model"`
+	}
+	if err := json.Unmarshal(body, &envelope); err != nil || envelope.Model == "" {
+		types.WriteError(w, http.StatusBadRequest, "request body must be JSON with a model field")
+		return
+	}
+	if !r.moderatePrompt(req.Context(), w, envelope.Model, body) {
+		return
+	}
+
+	resp, name, err := r.Forward(req.Context(), req.URL.Path, envelope.Model, body)
+	switch {
+	case errors.Is(err, ErrUnknownModel):
+		types.WriteError(w, http.StatusNotFound, err.Error())
+	case errors.Is(err, ErrProvidersFailed):
+		types.WriteError(w, http.StatusBadGateway, err.Error())
+	case err != nil:
+		// The client went away; there is nobody to answer
+	default:
+		r.writeResponse(req.Context(), w, envelope.Model, name, resp)
+	}
+}
+
+// Forward sends body to path on the providers routed for model, in order, and
+// returns the first response that is not a retryable failure together with the
+// name of the provider that sent it. It returns ErrUnknownModel when no route
+// matches and ErrProvidersFailed when every provider failed.
+func (r *Router) Forward(ctx context.Context, path, model string, body []byte) (*http.Response, string, error) {
+	providers, ok := r.Match(model)
+	if !ok {
+		return nil, "", fmt.Errorf("%w %q; known models: %s", ErrUnknownModel, model, strings.Join(r.Models(), ", "))
+	}
+
+	var failures []string
+	for _, p := range providers {
+		resp, err := p.Do(ctx, path, body)
+		if err != nil && ctx.Err() != nil {
+			// The caller went away; trying other providers would be wasted work
+			return nil, "", ctx.Err()
+		}
+		if err == nil && !retryable(resp.StatusCode) {
+			r.record(p.Name(), true)
+			return resp, p.Name(), nil
+		}
+
+		reason := ""
+		if err != nil {
+			reason = err.Error()
+		} else {
+			reason = resp.Status
+			resp.Body.Close()
+		}
+		r.record(p.Name(), false)
+		failures = append(failures, p.Name()+": "+reason)
+		logging.Warn("Provider %s failed for model %s: %s", p.Name(), model, reason)
+	}
+
+	return nil, "", fmt.Errorf("%w for model %q: %s", ErrProvidersFailed, model, strings.Join(failures, "; "))
+}
+
+// Complete sends a chat completion through the providers routed for req.Model and
+// decodes the reply. Providers answering with a non-2xx status return a *StatusError;
+// prompts the moderation policy blocks return a *moderation.BlockedError.
+func (r *Router) Complete(ctx context.Context, req ChatRequest) (ChatResponse, error) {
+	if r.moderation.Enabled() {
+		decision, err := r.moderation.Review(ctx, messagesText(req.Messages))
+		var blocked *moderation.BlockedError
+		if errors.As(err, &blocked) {
+			recordBlock(req.Model, "prompt", blocked)
+		}
+		if err != nil {
+			return ChatResponse{}, err
+		}
+		if decision.Flagged {
+			recordFlag(req.Model, "prompt", decision)
+		}
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return ChatResponse{}, fmt.Errorf("failed to encode chat request: %w", err)
+	}
+
+	resp, name, err := r.Forward(ctx, chatCompletionsPath, req.Model, body)
+	if err != nil {
+		return ChatResponse{}, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+		return ChatResponse{}, &StatusError{Provider: name, Status: resp.StatusCode, Body: string(message)}
+	}
+
+	var completion ChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return ChatResponse{}, fmt.Errorf("failed to decode completion from provider %s: %w", name, err)
+	}
+	tracing.RecordTokenUsage(resp, completion.Usage.PromptTokens, completion.Usage.CompletionTokens)
+	return completion, nil
+}
+
+// retryable reports whether a provider status should fall through to the next provider
+func retryable(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// writeResponse relays a provider response, naming the provider in ProviderHeader.
+// Event streams are reviewed by the moderation policy as they are relayed.
+func (r *Router) writeResponse(ctx context.Context, w http.ResponseWriter, model, name string, resp *http.Response) {
+	defer resp.Body.Close()
+
+	for key, values := range resp.Header {
+		for _, value := range values {
+			w.Header().Add(key, value)
+		}
+	}
+	w.Header().Set(ProviderHeader, name)
+	w.WriteHeader(resp.StatusCode)
+
+	if r.moderation.Enabled() && isEventStream(resp) {
+		r.relayModeratedStream(ctx, w, model, name, resp.Body)
+		return
+	}
+
+	// Keep a copy of JSON completions to record their token usage on the trace
+	var relayed bytes.Buffer
+	body := io.Reader(resp.Body)
+	traceUsage := tracing.Enabled() && strings.HasPrefix(resp.Header.Get("Content-Type"), "application/json")
+	if traceUsage {
+		body = io.TeeReader(resp.Body, &relayed)
+	}
+	if _, err := io.Copy(w, body); err != nil {
+		logging.Warn("Failed to relay response from provider %s: %v", name, err)
+		return
+	}
+	if traceUsage {
+		recordUsage(resp, relayed.Bytes())
+	}
+}
+
+// recordUsage attaches the token usage of a relayed completion to its provider
+// client span; bodies without usage, such as errors, are ignored
+func recordUsage(resp *http.Response, body []byte) {
+	var completion struct {
+		Usage *Usage `json:"usage"`
+	}
+	if err := json.Unmarshal(body, &completion); err != nil || completion.Usage == nil {
+		return
+	}
+	tracing.RecordTokenUsage(resp, completion.Usage.PromptTokens, completion.Usage.CompletionTokens)
+}
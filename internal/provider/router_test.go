@@ -0,0 +1,290 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
+)
+
+// fakeProvider answers with a fixed status, or err when set
+type fakeProvider struct {
+	name   string
+	status int
+	err    error
+	calls  int
+	body   []byte
+}
+
+func (p *fakeProvider) Name() string { return p.name }
+
+func (p *fakeProvider) Do(ctx context.Context, path string, body []byte) (*http.Response, error) {
+	p.calls++
+	p.body = body
+	if p.err != nil {
+		return nil, p.err
+	}
+	return &http.Response{
+		StatusCode: p.status,
+		Status:     http.StatusText(p.status),
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       io.NopCloser(strings.NewReader(`{"served_by":"` + p.name + `"}`)),
+	}, nil
+}
+
+func newTestRouter(t *testing.T, routes []Route, providers ...Provider) *Router {
+	t.Helper()
+	router, err := NewRouter(providers, routes)
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	return router
+}
+
+func serve(router *Router, model string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(`{"model":"`+model+`"}`))
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	return rec
+}
+
+func TestRouter_Match(t *testing.T) {
+	router := newTestRouter(t, []Route{
+		{Model: "*", Providers: []string{"default"}},
+		{Model: "llama*", Providers: []string{"local"}},
+		{Model: "llama3*", Providers: []string{"local3"}},
+		{Model: "gpt-4o", Providers: []string{"openai"}},
+		{Model: "gpt-4o*", Providers: []string{"azure"}},
+	},
+		&fakeProvider{name: "default"}, &fakeProvider{name: "local"}, &fakeProvider{name: "local3"},
+		&fakeProvider{name: "openai"}, &fakeProvider{name: "azure"},
+	)
+
+	tests := []struct {
+		model    string
+		provider string
+	}{
+		{"gpt-4o", "openai"},     // Exact beats a matching prefix
+		{"gpt-4o-mini", "azure"}, // Prefix
+		{"llama3:8b", "local3"},  // Longest prefix wins
+		{"llama2", "local"},      // Shorter prefix
+		{"mistral", "default"},   // Wildcard
+	}
+
+	for _, tt := range tests {
+		providers, ok := router.Match(tt.model)
+		if !ok || len(providers) != 1 || providers[0].Name() != tt.provider {
+			t.Errorf("Match(%q) = %v, want %s", tt.model, providers, tt.provider)
+		}
+	}
+}
+
+func TestRouter_UnknownModel(t *testing.T) {
+	router := newTestRouter(t, []Route{
+		{Model: "gpt-4o", Providers: []string{"openai"}},
+		{Model: "llama3*", Providers: []string{"local"}},
+	}, &fakeProvider{name: "openai"}, &fakeProvider{name: "local"})
+
+	rec := serve(router, "claude")
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("Status = %d, want 404", rec.Code)
+	}
+
+	var body types.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Error body is not JSON: %v", err)
+	}
+	if !strings.Contains(body.Message, "gpt-4o, llama3*") {
+		t.Errorf("Error should list the known models, got %q", body.Message)
+	}
+}
+
+func TestRouter_Failover(t *testing.T) {
+	tests := []struct {
+		name    string
+		primary *fakeProvider
+	}{
+		{"server error", &fakeProvider{name: "primary", status: http.StatusServiceUnavailable}},
+		{"rate limited", &fakeProvider{name: "primary", status: http.StatusTooManyRequests}},
+		{"network error", &fakeProvider{name: "primary", err: errors.New("connection refused")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			secondary := &fakeProvider{name: "secondary", status: http.StatusOK}
+			router := newTestRouter(t, []Route{{Model: "gpt-4o", Providers: []string{"primary", "secondary"}}}, tt.primary, secondary)
+
+			rec := serve(router, "gpt-4o")
+			if rec.Code != http.StatusOK {
+				t.Fatalf("Status = %d, want 200", rec.Code)
+			}
+			if got := rec.Header().Get(ProviderHeader); got != "secondary" {
+				t.Errorf("%s = %q, want secondary", ProviderHeader, got)
+			}
+			if !strings.Contains(rec.Body.String(), `"served_by":"secondary"`) {
+				t.Errorf("Expected the secondary response body, got %s", rec.Body.String())
+			}
+			if string(secondary.body) != `{"model":"gpt-4o"}` {
+				t.Errorf("Fallback provider should receive the original body, got %s", secondary.body)
+			}
+
+			stats := router.Stats()
+			if stats["primary"].Failed != 1 || stats["secondary"].Served != 1 {
+				t.Errorf("Unexpected stats %+v", stats)
+			}
+		})
+	}
+}
+
+func TestRouter_ClientErrorDoesNotFailOver(t *testing.T) {
+	primary := &fakeProvider{name: "primary", status: http.StatusBadRequest}
+	secondary := &fakeProvider{name: "secondary", status: http.StatusOK}
+	router := newTestRouter(t, []Route{{Model: "*", Providers: []string{"primary", "secondary"}}}, primary, secondary)
+
+	rec := serve(router, "gpt-4o")
+	if rec.Code != http.StatusBadRequest || rec.Header().Get(ProviderHeader) != "primary" {
+		t.Errorf("Expected the primary's 400 to be relayed, got %d from %s", rec.Code, rec.Header().Get(ProviderHeader))
+	}
+	if secondary.calls != 0 {
+		t.Error("Client errors should not fall through to the next provider")
+	}
+}
+
+func TestRouter_Exhaustion(t *testing.T) {
+	router := newTestRouter(t, []Route{{Model: "gpt-4o", Providers: []string{"a", "b"}}},
+		&fakeProvider{name: "a", status: http.StatusInternalServerError},
+		&fakeProvider{name: "b", err: errors.New("timeout")},
+	)
+
+	rec := serve(router, "gpt-4o")
+	if rec.Code != http.StatusBadGateway {
+		t.Fatalf("Status = %d, want 502", rec.Code)
+	}
+
+	var body types.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&body); err != nil {
+		t.Fatalf("Error body is not JSON: %v", err)
+	}
+	if body.Status != http.StatusBadGateway || !strings.Contains(body.Message, "a: Internal Server Error") || !strings.Contains(body.Message, "b: timeout") {
+		t.Errorf("Unexpected error body %+v", body)
+	}
+	if stats := router.Stats(); stats["a"].Failed != 1 || stats["b"].Failed != 1 {
+		t.Errorf("Unexpected stats %+v", stats)
+	}
+}
+
+func TestNewRouter_UnknownProvider(t *testing.T) {
+	_, err := NewRouter([]Provider{&fakeProvider{name: "openai"}}, []Route{{Model: "llama3", Providers: []string{"local"}}})
+	if err == nil || !strings.Contains(err.Error(), "unknown provider local") {
+		t.Errorf("Expected an unknown provider error, got %v", err)
+	}
+}
+
+func TestNewRouterFromConfig(t *testing.T) {
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	var authorization string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authorization = r.Header.Get("Authorization")
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"path":"`+r.URL.Path+`"}`)
+	}))
+	defer upstream.Close()
+
+	config.SetForTest("llm.providers", []map[string]interface{}{
+		{"name": "local", "base_url": upstream.URL + "/", "api_key": "k"},
+	})
+	config.SetForTest("llm.routes", []map[string]interface{}{
+		{"model": "llama3*", "providers": []string{"local"}},
+	})
+
+	router, err := NewRouterFromConfig()
+	if err != nil {
+		t.Fatalf("NewRouterFromConfig() error = %v", err)
+	}
+
+	rec := serve(router, "llama3:8b")
+	if rec.Code != http.StatusOK || rec.Body.String() != `{"path":"/v1/chat/completions"}` {
+		t.Errorf("Unexpected response %d %s", rec.Code, rec.Body.String())
+	}
+	if authorization != "Bearer k" {
+		t.Errorf("Authorization = %q, want Bearer k", authorization)
+	}
+}
+
+func TestRouter_Complete(t *testing.T) {
+	router := newTestRouter(t, []Route{
+		{Model: "gpt-4o", Providers: []string{"down", "mock"}},
+		{Model: "strict", Providers: []string{"strict"}},
+	},
+		&fakeProvider{name: "down", status: http.StatusServiceUnavailable},
+		NewMockProvider("mock"),
+		&fakeProvider{name: "strict", status: http.StatusBadRequest},
+	)
+
+	completion, err := router.Complete(context.Background(), ChatRequest{
+		Model:    "gpt-4o",
+		Messages: []ChatMessage{{Role: "system", Content: "Be brief"}, {Role: "user", Content: "hello there"}},
+	})
+	if err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if completion.Model != "gpt-4o" || completion.Choices[0].Message.Content != "hello there" {
+		t.Errorf("Unexpected completion %+v", completion)
+	}
+
+	_, err = router.Complete(context.Background(), ChatRequest{Model: "strict"})
+	var statusErr *StatusError
+	if !errors.As(err, &statusErr) || statusErr.Status != http.StatusBadRequest || statusErr.Provider != "strict" {
+		t.Errorf("Expected a StatusError from the strict provider, got %v", err)
+	}
+
+	if _, err := router.Complete(context.Background(), ChatRequest{Model: "llama3"}); !errors.Is(err, ErrUnknownModel) {
+		t.Errorf("Expected ErrUnknownModel, got %v", err)
+	}
+}
+
+func TestRouter_ServesRegisteredRoutes(t *testing.T) {
+	router := newTestRouter(t, []Route{{Model: "*", Providers: []string{"mock"}}}, NewMockProvider("mock"))
+
+	mux := http.NewServeMux()
+	for _, route := range types.GetRoutesByModule(Module) {
+		mux.HandleFunc(route.Method+" "+route.Path, router.ServeHTTP)
+	}
+
+	tests := []struct {
+		path, body string
+		response   interface{}
+	}{
+		{chatCompletionsPath, `{"model":"m","messages":[{"role":"user","content":"hello there"}]}`, &ChatResponse{}},
+		{embeddingsPath, `{"model":"m","input":["hello there","hi"]}`, &EmbeddingsResponse{}},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body)))
+		if rec.Code != http.StatusOK || rec.Header().Get(ProviderHeader) != "mock" {
+			t.Fatalf("POST %s = %d from %q, expected 200 from mock: %s", tt.path, rec.Code, rec.Header().Get(ProviderHeader), rec.Body.String())
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), tt.response); err != nil {
+			t.Fatalf("POST %s returned an invalid body: %v", tt.path, err)
+		}
+	}
+
+	if chat := tests[0].response.(*ChatResponse); len(chat.Choices) != 1 || chat.Choices[0].Message.Content != "hello there" {
+		t.Errorf("Expected the mock to echo the prompt, got %+v", chat)
+	}
+	if embeddings := tests[1].response.(*EmbeddingsResponse); len(embeddings.Data) != 2 || embeddings.Usage.PromptTokens != 3 {
+		t.Errorf("Expected one embedding per input, got %+v", embeddings)
+	}
+	if stats := router.Stats()["mock"]; stats.Served != 2 {
+		t.Errorf("Expected both requests counted for mock, got %+v", stats)
+	}
+}
@@ -0,0 +1,174 @@
+package sessions
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
+	"github.com/JerkyTreats/llm/internal/logging"
+	"github.com/JerkyTreats/llm/internal/moderation"
+	"github.com/JerkyTreats/llm/internal/provider"
+)
+
+// SessionsHandler serves the session create, fetch, delete and message routes
+type SessionsHandler struct {
+	manager *Manager
+}
+
+func init() {
+	config.RegisterKey("sessions.ttl", "")
+	config.RegisterKey("sessions.context_window", 0)
+}
+
+// NewSessionsHandler creates a sessions handler storing sessions in memory for
+// sessions.ttl after their last message, trimming prompts to
+// sessions.context_window tokens and completing them through the provider
+// router configured by llm.providers and llm.routes
+func NewSessionsHandler() (*SessionsHandler, error) {
+	ttl := defaultTTL
+	if raw := config.GetString("sessions.ttl"); raw != "" {
+		parsed, err := time.ParseDuration(raw)
+		if err != nil || parsed <= 0 {
+			logging.Warn("Invalid sessions.ttl %q, using %s", raw, defaultTTL)
+		} else {
+			ttl = parsed
+		}
+	}
+
+	router, err := provider.NewRouterFromConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	manager := NewManager(NewMemoryStore(ttl), router, config.GetInt("sessions.context_window"))
+	return NewSessionsHandlerWithManager(manager), nil
+}
+
+// NewSessionsHandlerWithManager creates a sessions handler backed by manager
+func NewSessionsHandlerWithManager(manager *Manager) *SessionsHandler {
+	return &SessionsHandler{manager: manager}
+}
+
+// HandlerFor returns the handler serving a route of the sessions module
+func (h *SessionsHandler) HandlerFor(route types.RouteInfo) http.HandlerFunc {
+	switch {
+	case route.Method == "POST" && route.Path == "/v1/sessions":
+		return h.Create
+	case route.Method == "GET" && route.Path == "/v1/sessions/{id}":
+		return h.Get
+	case route.Method == "DELETE" && route.Path == "/v1/sessions/{id}":
+		return h.Delete
+	case route.Method == "POST" && route.Path == "/v1/sessions/{id}/messages":
+		return h.Append
+	default:
+		return nil
+	}
+}
+
+// Create starts a session
+func (h *SessionsHandler) Create(w http.ResponseWriter, r *http.Request) {
+	var req CreateSessionRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		types.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	session, err := h.manager.Create(req)
+	if err != nil {
+		types.WriteError(w, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	w.Header().Set("Location", "/v1/sessions/"+session.ID)
+	writeJSON(w, http.StatusCreated, session)
+}
+
+// Get returns a session's transcript
+func (h *SessionsHandler) Get(w http.ResponseWriter, r *http.Request) {
+	id, err := types.PathID(r, "id", sessionIDPrefix)
+	if err != nil {
+		types.WritePathParamError(w, err)
+		return
+	}
+	session, err := h.manager.Get(id)
+	if err != nil {
+		types.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	writeJSON(w, http.StatusOK, session)
+}
+
+// Delete removes a session
+func (h *SessionsHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id, err := types.PathID(r, "id", sessionIDPrefix)
+	if err != nil {
+		types.WritePathParamError(w, err)
+		return
+	}
+	if err := h.manager.Delete(id); err != nil {
+		types.WriteError(w, http.StatusNotFound, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// Append sends a user message to a session and returns the assistant's reply
+func (h *SessionsHandler) Append(w http.ResponseWriter, r *http.Request) {
+	id, err := types.PathID(r, "id", sessionIDPrefix)
+	if err != nil {
+		types.WritePathParamError(w, err)
+		return
+	}
+
+	var req AppendMessageRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		types.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+
+	resp, err := h.manager.Append(r.Context(), id, req.Content)
+	if err != nil {
+		status := appendErrorStatus(err)
+		if status >= http.StatusInternalServerError {
+			logging.Error("Failed to complete message for session %s: %v", id, err)
+		}
+		body := types.NewErrorResponse(status, err.Error())
+		var blocked *moderation.BlockedError
+		if errors.As(err, &blocked) {
+			body.Categories = blocked.Categories
+		}
+		types.WriteErrorResponse(w, body)
+		return
+	}
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// appendErrorStatus maps an Append error to its response status
+func appendErrorStatus(err error) int {
+	var statusErr *provider.StatusError
+	var blocked *moderation.BlockedError
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return http.StatusNotFound
+	case errors.As(err, &blocked):
+		return http.StatusBadRequest
+	case errors.Is(err, ErrEmptyMessage), errors.Is(err, ErrContextExceeded), errors.Is(err, provider.ErrUnknownModel):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, provider.ErrProvidersFailed), errors.Is(err, ErrNoChoices), errors.As(err, &statusErr):
+		return http.StatusBadGateway
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeJSON encodes body as the JSON response
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logging.Error("Failed to encode sessions response: %v", err)
+	}
+}
@@ -0,0 +1,66 @@
+package sessions
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+)
+
+func init() {
+	// Register session creation endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "POST",
+		Path:            "/v1/sessions",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "sessions.SessionsHandler.Create",
+		RequestType:     reflect.TypeOf(CreateSessionRequest{}),
+		ResponseType:    reflect.TypeOf(Session{}),
+		Module:          Module,
+		Summary:         "Create a conversation session",
+		SuccessStatus:   http.StatusCreated,
+	})
+
+	// Register session transcript endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "GET",
+		Path:            "/v1/sessions/{id}",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "sessions.SessionsHandler.Get",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    reflect.TypeOf(Session{}),
+		Module:          Module,
+		Summary:         "Get a session and its transcript",
+		ErrorStatuses:   []int{http.StatusNotFound},
+	})
+
+	// Register session deletion endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "DELETE",
+		Path:            "/v1/sessions/{id}",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "sessions.SessionsHandler.Delete",
+		RequestType:     nil, // DELETE request has no body
+		ResponseType:    nil, // 204 has no body
+		Module:          Module,
+		Summary:         "Delete a session",
+		SuccessStatus:   http.StatusNoContent,
+		ErrorStatuses:   []int{http.StatusNotFound},
+	})
+
+	// Register message endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "POST",
+		Path:            "/v1/sessions/{id}/messages",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "sessions.SessionsHandler.Append",
+		RequestType:     reflect.TypeOf(AppendMessageRequest{}),
+		ResponseType:    reflect.TypeOf(AppendMessageResponse{}),
+		Module:          Module,
+		Summary:         "Send a message to a session and get the reply",
+		ErrorStatuses:   []int{http.StatusNotFound, http.StatusBadGateway, http.StatusServiceUnavailable},
+
+		// Completions call the upstream provider, whose concurrency is capped
+		ConcurrencyGroup: types.ConcurrencyGroupCompletions,
+	})
+}
@@ -0,0 +1,221 @@
+package sessions
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/provider"
+	"github.com/JerkyTreats/llm/internal/tokenizer"
+)
+
+// Manager defaults used when the config does not override them
+const (
+	defaultTTL           = 24 * time.Hour
+	defaultContextWindow = 4096
+)
+
+// Errors returned by Manager
+var (
+	ErrNotFound        = errors.New("session not found")
+	ErrModelRequired   = errors.New("model is required")
+	ErrEmptyMessage    = errors.New("message content is required")
+	ErrContextExceeded = errors.New("message does not fit the model's context window")
+	ErrNoChoices       = errors.New("completion returned no choices")
+)
+
+// Completer runs a chat completion; *provider.Router implements it
+type Completer interface {
+	Complete(ctx context.Context, req provider.ChatRequest) (provider.ChatResponse, error)
+}
+
+// Manager creates sessions and completes messages appended to them
+type Manager struct {
+	store         Store
+	completer     Completer
+	contextWindow int
+	now           func() time.Time
+
+	mu    sync.Mutex
+	locks map[string]*sessionLock
+}
+
+// sessionLock serializes appends to one session; refs counts the callers
+// holding or waiting for it so the entry can be dropped once unused
+type sessionLock struct {
+	sync.Mutex
+	refs int
+}
+
+// NewManager creates a manager that keeps each completion's prompt within
+// contextWindow tokens
+func NewManager(store Store, completer Completer, contextWindow int) *Manager {
+	if contextWindow <= 0 {
+		contextWindow = defaultContextWindow
+	}
+	return &Manager{
+		store:         store,
+		completer:     completer,
+		contextWindow: contextWindow,
+		now:           time.Now,
+		locks:         make(map[string]*sessionLock),
+	}
+}
+
+// Create starts an empty session
+func (m *Manager) Create(req CreateSessionRequest) (Session, error) {
+	if req.Model == "" {
+		return Session{}, ErrModelRequired
+	}
+
+	now := m.now()
+	return m.store.Create(Session{
+		ID:           newSessionID(),
+		Model:        req.Model,
+		SystemPrompt: req.SystemPrompt,
+		Metadata:     req.Metadata,
+		Messages:     []Message{},
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}), nil
+}
+
+// Get returns a session with its transcript
+func (m *Manager) Get(id string) (Session, error) {
+	session, ok := m.store.Get(id)
+	if !ok {
+		return Session{}, ErrNotFound
+	}
+	return session, nil
+}
+
+// Delete removes a session
+func (m *Manager) Delete(id string) error {
+	if !m.store.Delete(id) {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// Append completes content with the session's history and stores both the user
+// message and the assistant reply. History that does not fit the context window
+// is left out of the prompt, oldest first, but stays in the transcript. Appends
+// to one session run one at a time so each sees the previous reply.
+func (m *Manager) Append(ctx context.Context, id, content string) (AppendMessageResponse, error) {
+	if content == "" {
+		return AppendMessageResponse{}, ErrEmptyMessage
+	}
+
+	unlock := m.lock(id)
+	defer unlock()
+
+	session, ok := m.store.Get(id)
+	if !ok {
+		return AppendMessageResponse{}, ErrNotFound
+	}
+
+	messages, trimmed, err := m.prompt(session, content)
+	if err != nil {
+		return AppendMessageResponse{}, err
+	}
+
+	completion, err := m.completer.Complete(ctx, provider.ChatRequest{Model: session.Model, Messages: messages})
+	if err != nil {
+		return AppendMessageResponse{}, err
+	}
+	if len(completion.Choices) == 0 {
+		return AppendMessageResponse{}, ErrNoChoices
+	}
+
+	now := m.now()
+	user := Message{Role: RoleUser, Content: content, CreatedAt: now}
+	reply := Message{Role: RoleAssistant, Content: completion.Choices[0].Message.Content, CreatedAt: now}
+	if _, ok := m.store.Update(id, func(s *Session) {
+		s.Messages = append(s.Messages, user, reply)
+		s.UpdatedAt = now
+	}); !ok {
+		// Deleted or expired while the completion ran
+		return AppendMessageResponse{}, ErrNotFound
+	}
+
+	return AppendMessageResponse{
+		SessionID:       id,
+		Message:         reply,
+		Usage:           completion.Usage,
+		TrimmedMessages: trimmed,
+	}, nil
+}
+
+// prompt builds the chat messages for a new user message: the system prompt,
+// as much recent history as fits the context window, then the message itself.
+// It returns the messages and how many history messages were left out.
+func (m *Manager) prompt(session Session, content string) ([]provider.ChatMessage, int, error) {
+	budget := m.contextWindow - tokenizer.CountMessage(content)
+	if session.SystemPrompt != "" {
+		budget -= tokenizer.CountMessage(session.SystemPrompt)
+	}
+	if budget < 0 {
+		return nil, 0, ErrContextExceeded
+	}
+
+	// Walk back from the newest message until the budget runs out
+	start := len(session.Messages)
+	for start > 0 {
+		cost := tokenizer.CountMessage(session.Messages[start-1].Content)
+		if cost > budget {
+			break
+		}
+		budget -= cost
+		start--
+	}
+
+	messages := make([]provider.ChatMessage, 0, len(session.Messages)-start+2)
+	if session.SystemPrompt != "" {
+		messages = append(messages, provider.ChatMessage{Role: "system", Content: session.SystemPrompt})
+	}
+	for _, msg := range session.Messages[start:] {
+		messages = append(messages, provider.ChatMessage{Role: msg.Role, Content: msg.Content})
+	}
+	messages = append(messages, provider.ChatMessage{Role: RoleUser, Content: content})
+
+	return messages, start, nil
+}
+
+// lock acquires the append lock for a session and returns its release function
+func (m *Manager) lock(id string) func() {
+	m.mu.Lock()
+	l, ok := m.locks[id]
+	if !ok {
+		l = &sessionLock{}
+		m.locks[id] = l
+	}
+	l.refs++
+	m.mu.Unlock()
+
+	l.Lock()
+	return func() {
+		l.Unlock()
+		m.mu.Lock()
+		l.refs--
+		if l.refs == 0 {
+			delete(m.locks, id)
+		}
+		m.mu.Unlock()
+	}
+}
+
+// sessionIDPrefix starts every session identifier
+const sessionIDPrefix = "sess_"
+
+// newSessionID returns a random session identifier
+func newSessionID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate session id: %v", err))
+	}
+	return sessionIDPrefix + hex.EncodeToString(b)
+}
@@ -0,0 +1,70 @@
+// Package sessions keeps chat conversations on the server so clients send only
+// their newest message; each completion runs with the stored history.
+package sessions
+
+import (
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/provider"
+)
+
+// Module groups the session routes in the registry and the generated spec
+const Module = "sessions"
+
+// Message roles stored in a transcript
+const (
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+)
+
+// Message is one turn of a session transcript
+type Message struct {
+	Role      string    `json:"role"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Session is a stored conversation
+type Session struct {
+	ID           string            `json:"id"`
+	Model        string            `json:"model"`
+	SystemPrompt string            `json:"system_prompt,omitempty"`
+	Metadata     map[string]string `json:"metadata,omitempty"`
+	Messages     []Message         `json:"messages"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+	ExpiresAt    time.Time         `json:"expires_at"`
+}
+
+// clone returns a copy of s that shares no slices or maps with it
+func (s Session) clone() Session {
+	s.Messages = append([]Message(nil), s.Messages...)
+	if s.Metadata != nil {
+		metadata := make(map[string]string, len(s.Metadata))
+		for key, value := range s.Metadata {
+			metadata[key] = value
+		}
+		s.Metadata = metadata
+	}
+	return s
+}
+
+// CreateSessionRequest is the request body for creating a session
+type CreateSessionRequest struct {
+	Model        string            `json:"model" description:"Model every message in the session is completed with"`
+	SystemPrompt string            `json:"system_prompt,omitempty" description:"Instructions sent before the history on every completion"`
+	Metadata     map[string]string `json:"metadata,omitempty" description:"Client-defined labels stored with the session"`
+}
+
+// AppendMessageRequest is the request body for sending a message to a session
+type AppendMessageRequest struct {
+	Content string `json:"content" description:"User message to append and complete"`
+}
+
+// AppendMessageResponse is the assistant's reply to an appended message
+type AppendMessageResponse struct {
+	SessionID       string         `json:"session_id"`
+	Message         Message        `json:"message"`
+	Usage           provider.Usage `json:"usage"`
+	TrimmedMessages int            `json:"trimmed_messages" description:"Oldest history messages left out to fit the model's context window"`
+}
@@ -0,0 +1,357 @@
+package sessions
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/moderation"
+	"github.com/JerkyTreats/llm/internal/provider"
+	"github.com/JerkyTreats/llm/internal/tokenizer"
+)
+
+// recordingCompleter echoes the last message like the mock provider and keeps
+// every request it receives
+type recordingCompleter struct {
+	mu       sync.Mutex
+	requests []provider.ChatRequest
+	err      error
+}
+
+func (c *recordingCompleter) Complete(ctx context.Context, req provider.ChatRequest) (provider.ChatResponse, error) {
+	c.mu.Lock()
+	c.requests = append(c.requests, req)
+	c.mu.Unlock()
+	if c.err != nil {
+		return provider.ChatResponse{}, c.err
+	}
+	last := req.Messages[len(req.Messages)-1].Content
+	return provider.ChatResponse{
+		Model:   req.Model,
+		Choices: []provider.ChatChoice{{Message: provider.ChatMessage{Role: RoleAssistant, Content: "echo: " + last}}},
+	}, nil
+}
+
+func (c *recordingCompleter) last() provider.ChatRequest {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.requests[len(c.requests)-1]
+}
+
+func TestAppend_AccumulatesHistory(t *testing.T) {
+	completer := &recordingCompleter{}
+	manager := NewManager(NewMemoryStore(time.Hour), completer, 0)
+
+	session, err := manager.Create(CreateSessionRequest{Model: "gpt-4o", SystemPrompt: "Be brief."})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	for _, content := range []string{"first", "second"} {
+		if _, err := manager.Append(context.Background(), session.ID, content); err != nil {
+			t.Fatalf("Append(%q) error = %v", content, err)
+		}
+	}
+
+	expected := []provider.ChatMessage{
+		{Role: "system", Content: "Be brief."},
+		{Role: RoleUser, Content: "first"},
+		{Role: RoleAssistant, Content: "echo: first"},
+		{Role: RoleUser, Content: "second"},
+	}
+	if got := completer.last().Messages; fmt.Sprint(got) != fmt.Sprint(expected) {
+		t.Errorf("Prompt = %v, want %v", got, expected)
+	}
+
+	transcript, err := manager.Get(session.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if len(transcript.Messages) != 4 || transcript.Messages[3].Content != "echo: second" {
+		t.Errorf("Unexpected transcript %+v", transcript.Messages)
+	}
+}
+
+func TestAppend_TrimsToContextWindow(t *testing.T) {
+	completer := &recordingCompleter{}
+	system := "Answer in one word."
+	// Room for the system prompt, the new message and two history messages
+	window := tokenizer.CountMessage(system) + 3*tokenizer.CountMessage("msg")
+	manager := NewManager(NewMemoryStore(time.Hour), completer, window)
+
+	session, _ := manager.Create(CreateSessionRequest{Model: "gpt-4o", SystemPrompt: system})
+	var resp AppendMessageResponse
+	for i := 0; i < 3; i++ {
+		var err error
+		resp, err = manager.Append(context.Background(), session.ID, "msg")
+		if err != nil {
+			t.Fatalf("Append() error = %v", err)
+		}
+	}
+
+	// The history has four messages before the third append; the newest user
+	// message does not fit next to its "echo: msg" reply, so only one survives
+	prompt := completer.last().Messages
+	if prompt[0].Role != "system" || prompt[len(prompt)-1].Content != "msg" {
+		t.Errorf("Trimming must keep the system prompt and the new message, got %v", prompt)
+	}
+	if resp.TrimmedMessages != 4-(len(prompt)-2) {
+		t.Errorf("TrimmedMessages = %d for prompt %v", resp.TrimmedMessages, prompt)
+	}
+	if resp.TrimmedMessages == 0 {
+		t.Error("Expected history to be trimmed")
+	}
+
+	total := 0
+	for _, msg := range prompt {
+		total += tokenizer.CountMessage(msg.Content)
+	}
+	if total > window {
+		t.Errorf("Prompt uses %d tokens, window is %d", total, window)
+	}
+
+	transcript, _ := manager.Get(session.ID)
+	if len(transcript.Messages) != 6 {
+		t.Errorf("Trimmed messages must stay in the transcript, got %d messages", len(transcript.Messages))
+	}
+
+	if _, err := manager.Append(context.Background(), session.ID, strings.Repeat("word ", window)); !errors.Is(err, ErrContextExceeded) {
+		t.Errorf("Expected ErrContextExceeded for an oversized message, got %v", err)
+	}
+}
+
+func TestMemoryStore_Expiry(t *testing.T) {
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewMemoryStore(time.Minute)
+	store.now = func() time.Time { return now }
+	manager := NewManager(store, &recordingCompleter{}, 0)
+
+	idle, _ := manager.Create(CreateSessionRequest{Model: "m"})
+	active, _ := manager.Create(CreateSessionRequest{Model: "m"})
+	if !active.ExpiresAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("ExpiresAt = %v, want %v", active.ExpiresAt, now.Add(time.Minute))
+	}
+
+	// Activity extends the expiry
+	now = now.Add(40 * time.Second)
+	if _, err := manager.Append(context.Background(), active.ID, "hi"); err != nil {
+		t.Fatalf("Append() error = %v", err)
+	}
+
+	now = now.Add(40 * time.Second)
+	if _, err := manager.Get(idle.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected the idle session to expire, got %v", err)
+	}
+	if _, err := manager.Get(active.ID); err != nil {
+		t.Errorf("Expected the active session to be kept, got %v", err)
+	}
+
+	now = now.Add(time.Minute)
+	manager.Create(CreateSessionRequest{Model: "m"})
+	if store.Len() != 1 {
+		t.Errorf("Expected expired sessions to be evicted, %d stored", store.Len())
+	}
+}
+
+func TestAppend_ConcurrentAppendsToOneSession(t *testing.T) {
+	manager := NewManager(NewMemoryStore(time.Hour), &recordingCompleter{}, 0)
+	session, _ := manager.Create(CreateSessionRequest{Model: "m"})
+
+	const appends = 20
+	var wg sync.WaitGroup
+	for i := 0; i < appends; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			if _, err := manager.Append(context.Background(), session.ID, fmt.Sprintf("message %d", i)); err != nil {
+				t.Errorf("Append() error = %v", err)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	transcript, _ := manager.Get(session.ID)
+	if len(transcript.Messages) != 2*appends {
+		t.Fatalf("Expected %d messages, got %d", 2*appends, len(transcript.Messages))
+	}
+	// Appends are serialized, so each reply directly follows its own message
+	for i := 0; i < len(transcript.Messages); i += 2 {
+		user, reply := transcript.Messages[i], transcript.Messages[i+1]
+		if user.Role != RoleUser || reply.Role != RoleAssistant || reply.Content != "echo: "+user.Content {
+			t.Errorf("Messages %d and %d are not a user message and its reply: %+v %+v", i, i+1, user, reply)
+		}
+	}
+
+	if len(manager.locks) != 0 {
+		t.Errorf("Expected session locks to be released, %d left", len(manager.locks))
+	}
+}
+
+func TestAppend_FailedCompletionIsNotStored(t *testing.T) {
+	completer := &recordingCompleter{err: provider.ErrProvidersFailed}
+	manager := NewManager(NewMemoryStore(time.Hour), completer, 0)
+	session, _ := manager.Create(CreateSessionRequest{Model: "m"})
+
+	if _, err := manager.Append(context.Background(), session.ID, "hi"); !errors.Is(err, provider.ErrProvidersFailed) {
+		t.Fatalf("Expected ErrProvidersFailed, got %v", err)
+	}
+	transcript, _ := manager.Get(session.ID)
+	if len(transcript.Messages) != 0 {
+		t.Errorf("Failed completions must not be stored, got %+v", transcript.Messages)
+	}
+}
+
+// newTestServer serves the session routes, completing messages with a mock provider
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	router, err := provider.NewRouter(
+		[]provider.Provider{provider.NewMockProvider("mock")},
+		[]provider.Route{{Model: "gpt-*", Providers: []string{"mock"}}},
+	)
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	handler := NewSessionsHandlerWithManager(NewManager(NewMemoryStore(time.Hour), router, 0))
+
+	mux := http.NewServeMux()
+	for _, route := range types.GetRoutesByModule(Module) {
+		mux.HandleFunc(route.Method+" "+route.Path, handler.HandlerFor(route))
+	}
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func do(t *testing.T, method, url, body string) (*http.Response, []byte) {
+	t.Helper()
+	req, err := http.NewRequest(method, url, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("NewRequest() error = %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("%s %s failed: %v", method, url, err)
+	}
+	defer resp.Body.Close()
+	var raw json.RawMessage
+	json.NewDecoder(resp.Body).Decode(&raw)
+	return resp, raw
+}
+
+func TestSessionsHandler_Lifecycle(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, raw := do(t, http.MethodPost, server.URL+"/v1/sessions", `{"model":"gpt-4o","metadata":{"user":"42"}}`)
+	if resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Create status = %d, want 201: %s", resp.StatusCode, raw)
+	}
+	var session Session
+	json.Unmarshal(raw, &session)
+	if resp.Header.Get("Location") != "/v1/sessions/"+session.ID || session.Metadata["user"] != "42" {
+		t.Errorf("Unexpected session %s at %s", raw, resp.Header.Get("Location"))
+	}
+
+	resp, raw = do(t, http.MethodPost, server.URL+"/v1/sessions/"+session.ID+"/messages", `{"content":"hello"}`)
+	var reply AppendMessageResponse
+	json.Unmarshal(raw, &reply)
+	// The mock provider echoes the last message
+	if resp.StatusCode != http.StatusOK || reply.Message.Role != RoleAssistant || reply.Message.Content != "hello" {
+		t.Errorf("Unexpected reply %d %s", resp.StatusCode, raw)
+	}
+
+	resp, raw = do(t, http.MethodGet, server.URL+"/v1/sessions/"+session.ID, "")
+	json.Unmarshal(raw, &session)
+	if resp.StatusCode != http.StatusOK || len(session.Messages) != 2 {
+		t.Errorf("Unexpected transcript %d %s", resp.StatusCode, raw)
+	}
+
+	if resp, _ = do(t, http.MethodDelete, server.URL+"/v1/sessions/"+session.ID, ""); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("Delete status = %d, want 204", resp.StatusCode)
+	}
+	if resp, _ = do(t, http.MethodGet, server.URL+"/v1/sessions/"+session.ID, ""); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("Get after delete = %d, want 404", resp.StatusCode)
+	}
+}
+
+func TestSessionsHandler_Errors(t *testing.T) {
+	server := newTestServer(t)
+
+	_, raw := do(t, http.MethodPost, server.URL+"/v1/sessions", `{"model":"claude-3"}`)
+	var unrouted Session
+	json.Unmarshal(raw, &unrouted)
+	missing := sessionIDPrefix + strings.Repeat("0", 32)
+
+	tests := []struct {
+		method, path, body string
+		expected           int
+	}{
+		{http.MethodPost, "/v1/sessions", `{}`, http.StatusUnprocessableEntity},
+		{http.MethodPost, "/v1/sessions", `{`, http.StatusBadRequest},
+		{http.MethodGet, "/v1/sessions/" + missing, "", http.StatusNotFound},
+		{http.MethodDelete, "/v1/sessions/" + missing, "", http.StatusNotFound},
+		{http.MethodPost, "/v1/sessions/" + missing + "/messages", `{"content":"hi"}`, http.StatusNotFound},
+		{http.MethodGet, "/v1/sessions/missing", "", http.StatusBadRequest},
+		{http.MethodPost, "/v1/sessions/missing/messages", `{"content":"hi"}`, http.StatusBadRequest},
+		{http.MethodPost, "/v1/sessions/" + unrouted.ID + "/messages", `{"content":""}`, http.StatusUnprocessableEntity},
+		{http.MethodPost, "/v1/sessions/" + unrouted.ID + "/messages", `{"content":"hi"}`, http.StatusUnprocessableEntity},
+	}
+
+	for _, tt := range tests {
+		resp, raw := do(t, tt.method, server.URL+tt.path, tt.body)
+		var body types.ErrorResponse
+		json.Unmarshal(raw, &body)
+		if resp.StatusCode != tt.expected || body.Status != tt.expected {
+			t.Errorf("%s %s %s = %d %s, want %d", tt.method, tt.path, tt.body, resp.StatusCode, raw, tt.expected)
+		}
+	}
+}
+
+func TestSessionsHandler_BlockedMessageNamesCategories(t *testing.T) {
+	blocked := &moderation.BlockedError{Categories: []string{"violence"}}
+	manager := NewManager(NewMemoryStore(time.Hour), &recordingCompleter{err: blocked}, 0)
+	session, _ := manager.Create(CreateSessionRequest{Model: "m"})
+	handler := NewSessionsHandlerWithManager(manager)
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/sessions/"+session.ID+"/messages", strings.NewReader(`{"content":"hi"}`))
+	req.SetPathValue("id", session.ID)
+	rec := httptest.NewRecorder()
+	handler.Append(rec, req)
+
+	var body types.ErrorResponse
+	json.Unmarshal(rec.Body.Bytes(), &body)
+	if rec.Code != http.StatusBadRequest || !slices.Equal(body.Categories, blocked.Categories) {
+		t.Errorf("Append() = %d %s, want 400 naming the blocked categories", rec.Code, rec.Body)
+	}
+}
+
+func TestAppendErrorStatus(t *testing.T) {
+	tests := []struct {
+		err      error
+		expected int
+	}{
+		{ErrNotFound, http.StatusNotFound},
+		{ErrContextExceeded, http.StatusUnprocessableEntity},
+		{&moderation.BlockedError{Categories: []string{"violence"}}, http.StatusBadRequest},
+		{fmt.Errorf("%w: gpt-5", provider.ErrUnknownModel), http.StatusUnprocessableEntity},
+		{provider.ErrProvidersFailed, http.StatusBadGateway},
+		{&provider.StatusError{Provider: "p", Status: http.StatusBadRequest}, http.StatusBadGateway},
+		{errors.New("boom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		if got := appendErrorStatus(tt.err); got != tt.expected {
+			t.Errorf("appendErrorStatus(%v) = %d, want %d", tt.err, got, tt.expected)
+		}
+	}
+}
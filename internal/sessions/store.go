@@ -0,0 +1,118 @@
+package sessions
+
+import (
+	"sync"
+	"time"
+)
+
+// Store persists sessions. Implementations expire sessions that have been idle
+// for longer than their TTL and set Session.ExpiresAt accordingly.
+type Store interface {
+	// Create adds a new session and returns it as stored
+	Create(session Session) Session
+	// Get returns the session for id unless it does not exist or has expired
+	Get(id string) (Session, bool)
+	// Update applies fn to the session for id atomically, extends its expiry and
+	// returns the updated session
+	Update(id string, fn func(*Session)) (Session, bool)
+	// Delete removes the session for id and reports whether it existed
+	Delete(id string) bool
+}
+
+// MemoryStore is an in-memory Store with sliding expiry
+type MemoryStore struct {
+	mu       sync.Mutex
+	ttl      time.Duration
+	sessions map[string]*Session
+	now      func() time.Time
+}
+
+// NewMemoryStore creates an in-memory store expiring sessions idle for ttl
+func NewMemoryStore(ttl time.Duration) *MemoryStore {
+	if ttl <= 0 {
+		ttl = defaultTTL
+	}
+	return &MemoryStore{
+		ttl:      ttl,
+		sessions: make(map[string]*Session),
+		now:      time.Now,
+	}
+}
+
+// Create implements Store
+func (s *MemoryStore) Create(session Session) Session {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.evictExpired()
+	session = session.clone()
+	session.ExpiresAt = s.now().Add(s.ttl)
+	s.sessions[session.ID] = &session
+	return session.clone()
+}
+
+// Get implements Store
+func (s *MemoryStore) Get(id string) (Session, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.live(id)
+	if !ok {
+		return Session{}, false
+	}
+	return session.clone(), true
+}
+
+// Update implements Store
+func (s *MemoryStore) Update(id string, fn func(*Session)) (Session, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	session, ok := s.live(id)
+	if !ok {
+		return Session{}, false
+	}
+	fn(session)
+	session.ExpiresAt = s.now().Add(s.ttl)
+	return session.clone(), true
+}
+
+// Delete implements Store
+func (s *MemoryStore) Delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.live(id)
+	delete(s.sessions, id)
+	return ok
+}
+
+// Len returns the number of stored sessions, including expired ones not yet evicted
+func (s *MemoryStore) Len() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.sessions)
+}
+
+// live returns the session for id, dropping it if it has expired; callers must hold s.mu
+func (s *MemoryStore) live(id string) (*Session, bool) {
+	session, exists := s.sessions[id]
+	if !exists {
+		return nil, false
+	}
+	if !s.now().Before(session.ExpiresAt) {
+		delete(s.sessions, id)
+		return nil, false
+	}
+	return session, true
+}
+
+// evictExpired drops every expired session; callers must hold s.mu
+func (s *MemoryStore) evictExpired() {
+	now := s.now()
+	for id, session := range s.sessions {
+		if !now.Before(session.ExpiresAt) {
+			delete(s.sessions, id)
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package templates
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/config"
+	"github.com/JerkyTreats/llm/internal/logging"
+	"github.com/JerkyTreats/llm/internal/provider"
+)
+
+// completionPath is the completion pipeline path rendered prompts are sent to
+const completionPath = "/v1/chat/completions"
+
+// TemplatesHandler serves the template list, fetch and completion routes
+type TemplatesHandler struct {
+	registry  *Registry
+	completer http.Handler
+}
+
+func init() {
+	config.RegisterKey("templates.dir", "")
+}
+
+// NewTemplatesHandler creates a templates handler serving the templates registered
+// in code plus those in the templates.dir directory, completing rendered prompts
+// through the provider router configured by llm.providers and llm.routes
+func NewTemplatesHandler() (*TemplatesHandler, error) {
+	registry := defaultRegistry.clone()
+	if dir := config.GetString("templates.dir"); dir != "" {
+		if err := registry.LoadDir(dir); err != nil {
+			return nil, err
+		}
+	}
+
+	router, err := provider.NewRouterFromConfig()
+	if err != nil {
+		return nil, err
+	}
+	return NewTemplatesHandlerWithCompleter(registry, router), nil
+}
+
+// NewTemplatesHandlerWithCompleter creates a templates handler serving registry and
+// sending rendered prompts to completer as chat completion requests
+func NewTemplatesHandlerWithCompleter(registry *Registry, completer http.Handler) *TemplatesHandler {
+	return &TemplatesHandler{registry: registry, completer: completer}
+}
+
+// HandlerFor returns the handler serving a route of the templates module
+func (h *TemplatesHandler) HandlerFor(route types.RouteInfo) http.HandlerFunc {
+	switch {
+	case route.Method == "GET" && route.Path == "/v1/templates":
+		return h.List
+	case route.Method == "GET" && route.Path == "/v1/templates/{name}":
+		return h.Get
+	case route.Method == "POST" && strings.HasPrefix(route.Path, "/v1/templates/") && strings.HasSuffix(route.Path, "/complete"):
+		// Per-template routes have literal names; the generic route reads {name}
+		return h.Complete
+	default:
+		return nil
+	}
+}
+
+// List returns every template
+func (h *TemplatesHandler) List(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, TemplateList{Templates: h.registry.List()})
+}
+
+// Get returns one template
+func (h *TemplatesHandler) Get(w http.ResponseWriter, r *http.Request) {
+	tmpl, ok := h.registry.Get(r.PathValue("name"))
+	if !ok {
+		types.WriteError(w, http.StatusNotFound, "template not found: "+r.PathValue("name"))
+		return
+	}
+	writeJSON(w, http.StatusOK, tmpl)
+}
+
+// Complete renders a template with the request's variables and sends the prompt
+// to the completion pipeline, relaying its response
+func (h *TemplatesHandler) Complete(w http.ResponseWriter, r *http.Request) {
+	name := templateName(r)
+	if _, ok := h.registry.Get(name); !ok {
+		types.WriteError(w, http.StatusNotFound, "template not found: "+name)
+		return
+	}
+
+	var req CompleteRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		types.WriteError(w, http.StatusBadRequest, "invalid request body: "+err.Error())
+		return
+	}
+	if req.Model == "" {
+		types.WriteError(w, http.StatusUnprocessableEntity, "model is required")
+		return
+	}
+
+	prompt, err := h.registry.Render(name, req.Variables)
+	if err != nil {
+		var verr *ValidationError
+		if errors.As(err, &verr) {
+			types.WriteError(w, http.StatusUnprocessableEntity, verr.Error())
+			return
+		}
+		logging.Error("Failed to render template %s: %v", name, err)
+		types.WriteError(w, http.StatusInternalServerError, "failed to render template")
+		return
+	}
+
+	body, err := json.Marshal(provider.ChatRequest{
+		Model:    req.Model,
+		Messages: []provider.ChatMessage{{Role: "user", Content: prompt}},
+	})
+	if err != nil {
+		types.WriteError(w, http.StatusInternalServerError, "failed to encode completion request")
+		return
+	}
+
+	completion, err := http.NewRequestWithContext(r.Context(), http.MethodPost, completionPath, bytes.NewReader(body))
+	if err != nil {
+		types.WriteError(w, http.StatusInternalServerError, "failed to create completion request")
+		return
+	}
+	completion.Header.Set("Content-Type", "application/json")
+	h.completer.ServeHTTP(w, completion)
+}
+
+// templateName returns the template a completion request targets: the {name}
+// wildcard of the generic route, or the literal segment of a per-template route
+func templateName(r *http.Request) string {
+	if name := r.PathValue("name"); name != "" {
+		return name
+	}
+	return strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/templates/"), "/complete")
+}
+
+// writeJSON encodes body as the JSON response
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logging.Error("Failed to encode templates response: %v", err)
+	}
+}
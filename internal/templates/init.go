@@ -0,0 +1,53 @@
+package templates
+
+import (
+	"net/http"
+	"reflect"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/provider"
+)
+
+func init() {
+	// Register template list endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "GET",
+		Path:            "/v1/templates",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "templates.TemplatesHandler.List",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    reflect.TypeOf(TemplateList{}),
+		Module:          Module,
+		Summary:         "List prompt templates",
+	})
+
+	// Register template fetch endpoint
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "GET",
+		Path:            "/v1/templates/{name}",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "templates.TemplatesHandler.Get",
+		RequestType:     nil, // GET request has no body
+		ResponseType:    reflect.TypeOf(Template{}),
+		Module:          Module,
+		Summary:         "Get a prompt template",
+		ErrorStatuses:   []int{http.StatusNotFound},
+	})
+
+	// Register generic completion endpoint; templates registered in code also get
+	// a route documenting their own variables
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "POST",
+		Path:            "/v1/templates/{name}/complete",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "templates.TemplatesHandler.Complete",
+		RequestType:     reflect.TypeOf(CompleteRequest{}),
+		ResponseType:    reflect.TypeOf(provider.ChatResponse{}),
+		Module:          Module,
+		Summary:         "Render a prompt template and complete it",
+		ErrorStatuses:   []int{http.StatusNotFound, http.StatusBadGateway, http.StatusServiceUnavailable},
+
+		// Completions call the upstream provider, whose concurrency is capped
+		ConcurrencyGroup: types.ConcurrencyGroupCompletions,
+	})
+}
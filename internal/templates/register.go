@@ -0,0 +1,92 @@
+package templates
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/provider"
+)
+
+// CompleteRequest is the request body of the generic template completion route
+type CompleteRequest struct {
+	Model     string                 `json:"model" description:"Model the rendered prompt is sent to"`
+	Variables map[string]interface{} `json:"variables" description:"Values for the template's declared variables"`
+}
+
+// defaultRegistry holds templates registered in code with Register
+var defaultRegistry = NewRegistry()
+
+// Register adds a template defined in code and registers a completion route for
+// it whose request schema documents the template's variables. It should be
+// called from init().
+func Register(tmpl Template) error {
+	if err := defaultRegistry.Add(tmpl); err != nil {
+		return err
+	}
+	// Use the stored copy, whose variable types are defaulted
+	tmpl, _ = defaultRegistry.Get(tmpl.Name)
+
+	summary := tmpl.Description
+	if summary == "" {
+		summary = fmt.Sprintf("Render the %s template and complete it", tmpl.Name)
+	}
+
+	types.RegisterRoute(types.RouteInfo{
+		Method:          "POST",
+		Path:            "/v1/templates/" + tmpl.Name + "/complete",
+		Handler:         nil, // Will be set during handler initialization
+		HandlerFuncName: "templates.TemplatesHandler.Complete",
+		RequestType:     completeRequestType(tmpl.Variables),
+		ResponseType:    reflect.TypeOf(provider.ChatResponse{}),
+		Module:          Module,
+		Summary:         summary,
+		ErrorStatuses:   []int{http.StatusNotFound, http.StatusBadGateway},
+	})
+	return nil
+}
+
+// completeRequestType builds a request body type whose variables object has one
+// field per declared variable, so the spec documents each template's inputs
+func completeRequestType(variables []Variable) reflect.Type {
+	fields := make([]reflect.StructField, 0, len(variables))
+	for i, v := range variables {
+		tag := fmt.Sprintf(`json:%q`, v.Name)
+		if v.Description != "" {
+			tag += fmt.Sprintf(` description:%q`, v.Description)
+		}
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("V%d", i),
+			Type: variableGoType(v.Type),
+			Tag:  reflect.StructTag(tag),
+		})
+	}
+
+	return reflect.StructOf([]reflect.StructField{
+		{
+			Name: "Model",
+			Type: reflect.TypeOf(""),
+			Tag:  `json:"model" description:"Model the rendered prompt is sent to"`,
+		},
+		{
+			Name: "Variables",
+			Type: reflect.StructOf(fields),
+			Tag:  `json:"variables"`,
+		},
+	})
+}
+
+// variableGoType maps a declared variable type to the Go type documenting it
+func variableGoType(varType string) reflect.Type {
+	switch varType {
+	case TypeInteger:
+		return reflect.TypeOf(int64(0))
+	case TypeNumber:
+		return reflect.TypeOf(float64(0))
+	case TypeBoolean:
+		return reflect.TypeOf(false)
+	default:
+		return reflect.TypeOf("")
+	}
+}
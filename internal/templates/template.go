@@ -0,0 +1,301 @@
+// Package templates stores named prompt templates, renders them with validated
+// variables and sends the rendered prompt to the completion pipeline.
+package templates
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Module groups the template routes in the registry and the generated spec
+const Module = "templates"
+
+// Variable types a template can declare
+const (
+	TypeString  = "string"
+	TypeInteger = "integer"
+	TypeNumber  = "number"
+	TypeBoolean = "boolean"
+)
+
+// variableName matches names usable as {{.name}} in a template
+var variableName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ErrNotFound is returned for templates that are not registered
+var ErrNotFound = errors.New("template not found")
+
+// Variable declares a value a template expects when it is rendered
+type Variable struct {
+	Name        string `json:"name" yaml:"name"`
+	Type        string `json:"type" yaml:"type"` // TypeString (default), TypeInteger, TypeNumber or TypeBoolean
+	Description string `json:"description,omitempty" yaml:"description"`
+}
+
+// Template is a named, versioned prompt written in Go text/template syntax, e.g.
+// "Summarize {{.text}} in {{.words}} words". Every declared variable must be
+// supplied when it is rendered, and no others.
+type Template struct {
+	Name        string     `json:"name" yaml:"name"`
+	Version     string     `json:"version" yaml:"version"`
+	Description string     `json:"description,omitempty" yaml:"description"`
+	Text        string     `json:"template" yaml:"template"`
+	Variables   []Variable `json:"variables" yaml:"variables"`
+}
+
+// TemplateList is the response body listing registered templates
+type TemplateList struct {
+	Templates []Template `json:"templates"`
+}
+
+// ValidationError lists the problems with variables supplied to a template
+type ValidationError struct {
+	Missing []string // Declared variables that were not supplied
+	Extra   []string // Supplied variables that are not declared
+	Invalid []string // Supplied variables whose values do not match their declared type
+}
+
+func (e *ValidationError) Error() string {
+	var problems []string
+	if len(e.Missing) > 0 {
+		problems = append(problems, "missing variables: "+strings.Join(e.Missing, ", "))
+	}
+	if len(e.Extra) > 0 {
+		problems = append(problems, "undeclared variables: "+strings.Join(e.Extra, ", "))
+	}
+	if len(e.Invalid) > 0 {
+		problems = append(problems, "invalid variables: "+strings.Join(e.Invalid, ", "))
+	}
+	return strings.Join(problems, "; ")
+}
+
+// compiled is a registered template with its parsed text
+type compiled struct {
+	template Template
+	parsed   *template.Template
+}
+
+// Registry holds templates by name
+type Registry struct {
+	mu        sync.RWMutex
+	templates map[string]compiled
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{templates: make(map[string]compiled)}
+}
+
+// Add validates and parses tmpl and stores it, replacing any template of the same name
+func (r *Registry) Add(tmpl Template) error {
+	c, err := compile(tmpl)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.templates[tmpl.Name] = c
+	return nil
+}
+
+// clone returns a registry holding the same templates
+func (r *Registry) clone() *Registry {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c := NewRegistry()
+	for name, tmpl := range r.templates {
+		c.templates[name] = tmpl
+	}
+	return c
+}
+
+// Get returns the template called name
+func (r *Registry) Get(name string) (Template, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	c, ok := r.templates[name]
+	return c.template, ok
+}
+
+// List returns every template, sorted by name
+func (r *Registry) List() []Template {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	list := make([]Template, 0, len(r.templates))
+	for _, c := range r.templates {
+		list = append(list, c.template)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Name < list[j].Name })
+	return list
+}
+
+// Render validates vars against the template's declarations and renders it.
+// It returns ErrNotFound for unknown templates and a *ValidationError when vars
+// are missing, undeclared or of the wrong type.
+func (r *Registry) Render(name string, vars map[string]interface{}) (string, error) {
+	r.mu.RLock()
+	c, ok := r.templates[name]
+	r.mu.RUnlock()
+	if !ok {
+		return "", fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+
+	if err := validateVariables(c.template.Variables, vars); err != nil {
+		return "", err
+	}
+
+	var out strings.Builder
+	if err := c.parsed.Execute(&out, vars); err != nil {
+		return "", fmt.Errorf("failed to render template %s: %w", name, err)
+	}
+	return out.String(), nil
+}
+
+// LoadDir adds every template defined in the .yaml and .yml files of dir
+func (r *Registry) LoadDir(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return fmt.Errorf("failed to read template directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		ext := strings.ToLower(filepath.Ext(entry.Name()))
+		if entry.IsDir() || (ext != ".yaml" && ext != ".yml") {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read template %s: %w", path, err)
+		}
+
+		var tmpl Template
+		if err := yaml.Unmarshal(content, &tmpl); err != nil {
+			return fmt.Errorf("failed to parse template %s: %w", path, err)
+		}
+		if err := r.Add(tmpl); err != nil {
+			return fmt.Errorf("invalid template %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// compile checks a template's declarations and parses its text. The text is
+// trial-rendered with zero values so references to undeclared variables fail
+// here rather than on a client's request.
+func compile(tmpl Template) (compiled, error) {
+	if tmpl.Name == "" || strings.ContainsAny(tmpl.Name, "/{} ") {
+		return compiled{}, fmt.Errorf("invalid template name %q", tmpl.Name)
+	}
+
+	// Copy the declarations so defaulting types does not modify the caller's slice
+	tmpl.Variables = append([]Variable(nil), tmpl.Variables...)
+
+	zero := make(map[string]interface{}, len(tmpl.Variables))
+	for i, v := range tmpl.Variables {
+		if !variableName.MatchString(v.Name) {
+			return compiled{}, fmt.Errorf("template %s has invalid variable name %q", tmpl.Name, v.Name)
+		}
+		if v.Type == "" {
+			tmpl.Variables[i].Type = TypeString
+		}
+		value, ok := zeroValue(tmpl.Variables[i].Type)
+		if !ok {
+			return compiled{}, fmt.Errorf("template %s variable %s has unknown type %q", tmpl.Name, v.Name, v.Type)
+		}
+		if _, exists := zero[v.Name]; exists {
+			return compiled{}, fmt.Errorf("template %s declares variable %s more than once", tmpl.Name, v.Name)
+		}
+		zero[v.Name] = value
+	}
+
+	parsed, err := template.New(tmpl.Name).Option("missingkey=error").Parse(tmpl.Text)
+	if err != nil {
+		return compiled{}, fmt.Errorf("failed to parse template %s: %w", tmpl.Name, err)
+	}
+	if err := parsed.Execute(io.Discard, zero); err != nil {
+		return compiled{}, fmt.Errorf("template %s does not render with its declared variables: %w", tmpl.Name, err)
+	}
+
+	return compiled{template: tmpl, parsed: parsed}, nil
+}
+
+// zeroValue returns the zero value of a declared variable type
+func zeroValue(varType string) (interface{}, bool) {
+	switch varType {
+	case TypeString:
+		return "", true
+	case TypeInteger:
+		return int64(0), true
+	case TypeNumber:
+		return float64(0), true
+	case TypeBoolean:
+		return false, true
+	default:
+		return nil, false
+	}
+}
+
+// validateVariables checks vars supplies exactly the declared variables with
+// values of the declared types
+func validateVariables(declared []Variable, vars map[string]interface{}) error {
+	verr := &ValidationError{}
+	known := make(map[string]bool, len(declared))
+	for _, v := range declared {
+		known[v.Name] = true
+		value, ok := vars[v.Name]
+		if !ok {
+			verr.Missing = append(verr.Missing, v.Name)
+			continue
+		}
+		if !matchesType(value, v.Type) {
+			verr.Invalid = append(verr.Invalid, fmt.Sprintf("%s (want %s)", v.Name, v.Type))
+		}
+	}
+	for name := range vars {
+		if !known[name] {
+			verr.Extra = append(verr.Extra, name)
+		}
+	}
+	sort.Strings(verr.Extra)
+
+	if len(verr.Missing) > 0 || len(verr.Extra) > 0 || len(verr.Invalid) > 0 {
+		return verr
+	}
+	return nil
+}
+
+// matchesType reports whether a JSON-decoded value has the declared type
+func matchesType(value interface{}, varType string) bool {
+	switch varType {
+	case TypeString:
+		_, ok := value.(string)
+		return ok
+	case TypeInteger:
+		n, ok := value.(float64)
+		return ok && n == math.Trunc(n) && !math.IsInf(n, 0)
+	case TypeNumber:
+		_, ok := value.(float64)
+		return ok
+	case TypeBoolean:
+		_, ok := value.(bool)
+		return ok
+	default:
+		return false
+	}
+}
@@ -0,0 +1,297 @@
+package templates
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"github.com/JerkyTreats/llm/internal/provider"
+)
+
+var summarize = Template{
+	Name:    "summarize",
+	Version: "1.2.0",
+	Text:    "Summarize the following in {{.words}} words{{if .formal}}, formally{{end}}:\n{{.text}}",
+	Variables: []Variable{
+		{Name: "text", Description: "Text to summarize"},
+		{Name: "words", Type: TypeInteger},
+		{Name: "formal", Type: TypeBoolean},
+	},
+}
+
+func newTestRegistry(t *testing.T) *Registry {
+	t.Helper()
+	registry := NewRegistry()
+	if err := registry.Add(summarize); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	return registry
+}
+
+func TestRender(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	prompt, err := registry.Render("summarize", map[string]interface{}{
+		"text":   "Go is a programming language.",
+		"words":  float64(5), // JSON numbers decode as float64
+		"formal": true,
+	})
+	if err != nil {
+		t.Fatalf("Render() error = %v", err)
+	}
+	if expected := "Summarize the following in 5 words, formally:\nGo is a programming language."; prompt != expected {
+		t.Errorf("Render() = %q, want %q", prompt, expected)
+	}
+
+	if _, err := registry.Render("missing", nil); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound for an unknown template, got %v", err)
+	}
+}
+
+func TestRender_ValidatesVariables(t *testing.T) {
+	registry := newTestRegistry(t)
+
+	tests := []struct {
+		name     string
+		vars     map[string]interface{}
+		expected ValidationError
+	}{
+		{
+			"missing",
+			map[string]interface{}{"text": "x", "formal": false},
+			ValidationError{Missing: []string{"words"}},
+		},
+		{
+			"extra",
+			map[string]interface{}{"text": "x", "words": float64(3), "formal": false, "tone": "dry"},
+			ValidationError{Extra: []string{"tone"}},
+		},
+		{
+			"wrong types",
+			map[string]interface{}{"text": float64(1), "words": 2.5, "formal": "yes"},
+			ValidationError{Invalid: []string{"text (want string)", "words (want integer)", "formal (want boolean)"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := registry.Render("summarize", tt.vars)
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Fatalf("Expected a ValidationError, got %v", err)
+			}
+			if !reflect.DeepEqual(*verr, tt.expected) {
+				t.Errorf("ValidationError = %+v, want %+v", *verr, tt.expected)
+			}
+		})
+	}
+}
+
+func TestAdd_RejectsInvalidTemplates(t *testing.T) {
+	tests := []struct {
+		name string
+		tmpl Template
+	}{
+		{"undeclared variable", Template{Name: "t", Text: "Hello {{.name}}"}},
+		{"unknown type", Template{Name: "t", Text: "{{.n}}", Variables: []Variable{{Name: "n", Type: "date"}}}},
+		{"duplicate variable", Template{Name: "t", Text: "{{.n}}", Variables: []Variable{{Name: "n"}, {Name: "n"}}}},
+		{"invalid variable name", Template{Name: "t", Text: "x", Variables: []Variable{{Name: "first-name"}}}},
+		{"parse error", Template{Name: "t", Text: "{{.n"}},
+		{"invalid name", Template{Name: "a/b", Text: "x"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := NewRegistry().Add(tt.tmpl); err == nil {
+				t.Error("Expected Add() to reject the template")
+			}
+		})
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"greet.yaml": "name: greet\nversion: \"1\"\ntemplate: \"Say hello to {{.name}}\"\nvariables:\n  - name: name\n",
+		"notes.txt":  "not a template",
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	registry := NewRegistry()
+	if err := registry.LoadDir(dir); err != nil {
+		t.Fatalf("LoadDir() error = %v", err)
+	}
+
+	list := registry.List()
+	if len(list) != 1 || list[0].Name != "greet" || list[0].Variables[0].Type != TypeString {
+		t.Errorf("Unexpected templates %+v", list)
+	}
+}
+
+// newTestServer serves the template routes, completing prompts with a mock provider
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	router, err := provider.NewRouter(
+		[]provider.Provider{provider.NewMockProvider("mock")},
+		[]provider.Route{{Model: "*", Providers: []string{"mock"}}},
+	)
+	if err != nil {
+		t.Fatalf("NewRouter() error = %v", err)
+	}
+	handler := NewTemplatesHandlerWithCompleter(newTestRegistry(t), router)
+
+	mux := http.NewServeMux()
+	for _, route := range types.GetRoutesByModule(Module) {
+		mux.HandleFunc(route.Method+" "+route.Path, handler.HandlerFor(route))
+	}
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTemplatesHandler_ListAndGet(t *testing.T) {
+	server := newTestServer(t)
+
+	resp, err := http.Get(server.URL + "/v1/templates")
+	if err != nil {
+		t.Fatalf("GET /v1/templates failed: %v", err)
+	}
+	var list TemplateList
+	json.NewDecoder(resp.Body).Decode(&list)
+	resp.Body.Close()
+	if len(list.Templates) != 1 || list.Templates[0].Name != "summarize" {
+		t.Errorf("Unexpected template list %+v", list)
+	}
+
+	resp, err = http.Get(server.URL + "/v1/templates/summarize")
+	if err != nil {
+		t.Fatalf("GET /v1/templates/summarize failed: %v", err)
+	}
+	var tmpl Template
+	json.NewDecoder(resp.Body).Decode(&tmpl)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || tmpl.Version != "1.2.0" || len(tmpl.Variables) != 3 {
+		t.Errorf("Unexpected template %d %+v", resp.StatusCode, tmpl)
+	}
+}
+
+func TestTemplatesHandler_MissingTemplate(t *testing.T) {
+	server := newTestServer(t)
+
+	requests := []*http.Request{
+		httptest.NewRequest(http.MethodGet, server.URL+"/v1/templates/missing", nil),
+		httptest.NewRequest(http.MethodPost, server.URL+"/v1/templates/missing/complete", strings.NewReader(`{"model":"m","variables":{}}`)),
+	}
+	for _, req := range requests {
+		req.RequestURI = ""
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s failed: %v", req.Method, req.URL.Path, err)
+		}
+		var body types.ErrorResponse
+		json.NewDecoder(resp.Body).Decode(&body)
+		resp.Body.Close()
+
+		if resp.StatusCode != http.StatusNotFound || body.Status != http.StatusNotFound {
+			t.Errorf("%s %s = %d %+v, want 404", req.Method, req.URL.Path, resp.StatusCode, body)
+		}
+	}
+}
+
+func TestTemplatesHandler_Complete(t *testing.T) {
+	server := newTestServer(t)
+
+	post := func(body string) (*http.Response, []byte) {
+		resp, err := http.Post(server.URL+"/v1/templates/summarize/complete", "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatalf("POST complete failed: %v", err)
+		}
+		defer resp.Body.Close()
+		var raw json.RawMessage
+		json.NewDecoder(resp.Body).Decode(&raw)
+		return resp, raw
+	}
+
+	resp, raw := post(`{"model":"gpt-4o","variables":{"text":"Go is fast.","words":3,"formal":false}}`)
+	if resp.StatusCode != http.StatusOK {
+		t.Fatalf("Status = %d, want 200: %s", resp.StatusCode, raw)
+	}
+	if got := resp.Header.Get(provider.ProviderHeader); got != "mock" {
+		t.Errorf("%s = %q, want mock", provider.ProviderHeader, got)
+	}
+
+	var completion provider.ChatResponse
+	if err := json.Unmarshal(raw, &completion); err != nil {
+		t.Fatalf("Completion is not a ChatResponse: %v", err)
+	}
+	// The mock provider echoes the rendered prompt
+	if completion.Model != "gpt-4o" || len(completion.Choices) != 1 ||
+		completion.Choices[0].Message.Content != "Summarize the following in 3 words:\nGo is fast." {
+		t.Errorf("Unexpected completion %+v", completion)
+	}
+
+	for _, body := range []string{
+		`{"model":"gpt-4o","variables":{"text":"Go is fast."}}`,
+		`{"model":"gpt-4o","variables":{"text":"x","words":3,"formal":false,"tone":"dry"}}`,
+		`{"variables":{"text":"x","words":3,"formal":false}}`,
+	} {
+		if resp, raw := post(body); resp.StatusCode != http.StatusUnprocessableEntity {
+			t.Errorf("POST %s = %d %s, want 422", body, resp.StatusCode, raw)
+		}
+	}
+}
+
+func TestRegister_DocumentsVariables(t *testing.T) {
+	routes := types.GetRegisteredRoutes()
+	t.Cleanup(func() {
+		types.UpdateRouteRegistry(routes)
+		defaultRegistry.mu.Lock()
+		delete(defaultRegistry.templates, "summarize")
+		defaultRegistry.mu.Unlock()
+	})
+
+	if err := Register(summarize); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	var route *types.RouteInfo
+	for _, r := range types.GetRoutesByModule(Module) {
+		if r.Path == "/v1/templates/summarize/complete" {
+			route = &r
+		}
+	}
+	if route == nil {
+		t.Fatal("Register should add POST /v1/templates/summarize/complete")
+	}
+
+	variables, ok := route.RequestType.FieldByName("Variables")
+	if !ok {
+		t.Fatal("Request type should have a Variables field")
+	}
+	expected := map[string]reflect.Kind{"text": reflect.String, "words": reflect.Int64, "formal": reflect.Bool}
+	for i := 0; i < variables.Type.NumField(); i++ {
+		field := variables.Type.Field(i)
+		name := field.Tag.Get("json")
+		if kind, ok := expected[name]; !ok || field.Type.Kind() != kind {
+			t.Errorf("Unexpected variable field %s %s", name, field.Type)
+		}
+		delete(expected, name)
+	}
+	if len(expected) != 0 {
+		t.Errorf("Variables missing from the request type: %v", expected)
+	}
+}
@@ -0,0 +1,50 @@
+// Package tokenizer estimates how many model tokens text uses, for budgeting
+// prompts against a model's context window without a model-specific vocabulary.
+package tokenizer
+
+import (
+	"unicode"
+	"unicode/utf8"
+)
+
+// charsPerToken is the average word-piece length of common BPE vocabularies
+const charsPerToken = 4
+
+// MessageOverhead is the tokens chat formats add around each message for its role
+// and delimiters
+const MessageOverhead = 4
+
+// Count estimates the tokens in text. Each run of letters and digits costs one
+// token per four characters, rounded up, and every other non-space character
+// costs one token, which slightly overestimates typical BPE counts.
+func Count(text string) int {
+	tokens := 0
+	wordLength := 0
+	flush := func() {
+		tokens += (wordLength + charsPerToken - 1) / charsPerToken
+		wordLength = 0
+	}
+
+	for len(text) > 0 {
+		r, size := utf8.DecodeRuneInString(text)
+		text = text[size:]
+
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			wordLength++
+		case unicode.IsSpace(r):
+			flush()
+		default:
+			flush()
+			tokens++
+		}
+	}
+	flush()
+	return tokens
+}
+
+// CountMessage estimates the tokens a chat message with content uses, including
+// MessageOverhead
+func CountMessage(content string) int {
+	return Count(content) + MessageOverhead
+}
@@ -0,0 +1,29 @@
+package tokenizer
+
+import "testing"
+
+func TestCount(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected int
+	}{
+		{"", 0},
+		{"hello", 2},
+		{"Go is fun", 3},
+		{"Hello, world!", 6},
+		{"  spaced   out  ", 3},
+		{"日本語", 1},
+	}
+
+	for _, tt := range tests {
+		if got := Count(tt.text); got != tt.expected {
+			t.Errorf("Count(%q) = %d, want %d", tt.text, got, tt.expected)
+		}
+	}
+}
+
+func TestCountMessage(t *testing.T) {
+	if got := CountMessage("Go is fun"); got != 3+MessageOverhead {
+		t.Errorf("CountMessage() = %d, want %d", got, 3+MessageOverhead)
+	}
+}
@@ -0,0 +1,99 @@
+// Package tracing provides optional OpenTelemetry tracing for HTTP handlers and
+// outgoing provider calls. When tracing is disabled the wrappers return the
+// handler or transport unchanged, so the request path pays no tracing cost.
+package tracing
+
+import (
+	"context"
+	"sync"
+
+	"github.com/JerkyTreats/llm/internal/config"
+	"github.com/JerkyTreats/llm/internal/logging"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// instrumentationName identifies spans created by this package
+const instrumentationName = "github.com/JerkyTreats/llm/internal/tracing"
+
+// serviceName is reported as the service.name resource attribute
+const serviceName = "llm-api"
+
+var (
+	mu     sync.RWMutex
+	tracer trace.Tracer // nil while tracing is disabled
+
+	// propagator reads and writes W3C traceparent and tracestate headers
+	propagator = propagation.TraceContext{}
+)
+
+func init() {
+	config.RegisterKey("tracing.enabled", false)
+	config.RegisterKey("tracing.endpoint", "")
+	config.RegisterKey("tracing.sample_ratio", 0.0)
+}
+
+// Init configures tracing from the tracing.enabled, tracing.endpoint and
+// tracing.sample_ratio config keys. It must run before handlers and transports
+// are wrapped. The returned function flushes and stops the exporter.
+func Init(ctx context.Context) (func(context.Context) error, error) {
+	if !config.GetBool("tracing.enabled") {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var opts []otlptracehttp.Option
+	if endpoint := config.GetString("tracing.endpoint"); endpoint != "" {
+		opts = append(opts, otlptracehttp.WithEndpointURL(endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sample everything unless a ratio is configured; child spans follow their parent
+	ratio := 1.0
+	if config.HasKey("tracing.sample_ratio") {
+		ratio = config.GetFloat64("tracing.sample_ratio")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(ratio))),
+		sdktrace.WithResource(resource.NewSchemaless(attribute.String("service.name", serviceName))),
+	)
+	SetTracerProvider(provider)
+	logging.Info("Tracing enabled with sample ratio %.2f", ratio)
+
+	return func(ctx context.Context) error {
+		SetTracerProvider(nil)
+		return provider.Shutdown(ctx)
+	}, nil
+}
+
+// SetTracerProvider sets the provider used for new spans; nil disables tracing.
+// Tests use it to install a provider backed by an in-memory exporter.
+func SetTracerProvider(provider trace.TracerProvider) {
+	mu.Lock()
+	defer mu.Unlock()
+	if provider == nil {
+		tracer = nil
+		return
+	}
+	tracer = provider.Tracer(instrumentationName)
+}
+
+// Enabled reports whether tracing is configured
+func Enabled() bool {
+	return currentTracer() != nil
+}
+
+// currentTracer returns the configured tracer, or nil when tracing is disabled
+func currentTracer() trace.Tracer {
+	mu.RLock()
+	defer mu.RUnlock()
+	return tracer
+}
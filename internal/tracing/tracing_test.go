@@ -0,0 +1,159 @@
+package tracing
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/JerkyTreats/llm/internal/api/types"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// useExporter installs a tracer provider that records spans in memory
+func useExporter(t *testing.T) *tracetest.InMemoryExporter {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	SetTracerProvider(provider)
+	t.Cleanup(func() { SetTracerProvider(nil) })
+	return exporter
+}
+
+// spanAttr returns the value of key on span, or an invalid value when missing
+func spanAttr(span tracetest.SpanStub, key string) attribute.Value {
+	for _, kv := range span.Attributes {
+		if string(kv.Key) == key {
+			return kv.Value
+		}
+	}
+	return attribute.Value{}
+}
+
+func TestWithTracing_Disabled(t *testing.T) {
+	SetTracerProvider(nil)
+
+	next := func(w http.ResponseWriter, r *http.Request) {}
+	wrapped := WithTracing(types.RouteInfo{Method: "GET", Path: "/health"}, next)
+	if reflect.ValueOf(wrapped).Pointer() != reflect.ValueOf(next).Pointer() {
+		t.Error("Disabled tracing should return the handler unchanged")
+	}
+
+	if NewTransport(http.DefaultTransport) != http.DefaultTransport {
+		t.Error("Disabled tracing should return the transport unchanged")
+	}
+}
+
+func TestWithTracing_ServerSpan(t *testing.T) {
+	exporter := useExporter(t)
+
+	route := types.RouteInfo{Method: "GET", Path: "/v1/models/{id}"}
+	handler := WithTracing(route, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/models/gpt-4", nil)
+	req.Header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+
+	if span.Name != "GET /v1/models/{id}" {
+		t.Errorf("Span should be named after the path template, got %q", span.Name)
+	}
+	if span.SpanKind != trace.SpanKindServer {
+		t.Errorf("Expected a server span, got %v", span.SpanKind)
+	}
+	if got := span.Parent.TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Span should continue the propagated trace, got trace %s", got)
+	}
+	if got := span.Parent.SpanID().String(); got != "00f067aa0ba902b7" {
+		t.Errorf("Span parent should be the propagated span, got %s", got)
+	}
+	if got := spanAttr(span, "http.request.method").AsString(); got != "GET" {
+		t.Errorf("Expected http.request.method GET, got %q", got)
+	}
+	if got := spanAttr(span, "http.response.status_code").AsInt64(); got != http.StatusNotFound {
+		t.Errorf("Expected http.response.status_code 404, got %d", got)
+	}
+}
+
+func TestNewTransport_ChildSpan(t *testing.T) {
+	exporter := useExporter(t)
+
+	var traceparent string
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+		w.Write([]byte(`{"usage":{}}`))
+	}))
+	defer upstream.Close()
+
+	client := &http.Client{Transport: NewTransport(upstream.Client().Transport)}
+
+	route := types.RouteInfo{Method: "POST", Path: "/v1/chat/completions"}
+	handler := WithTracing(route, func(w http.ResponseWriter, r *http.Request) {
+		req, _ := http.NewRequestWithContext(r.Context(), http.MethodPost, upstream.URL+"/v1/messages", nil)
+		resp, err := client.Do(req)
+		if err != nil {
+			t.Errorf("Provider request failed: %v", err)
+			return
+		}
+		io.Copy(io.Discard, resp.Body)
+		RecordTokenUsage(resp, 12, 34)
+		resp.Body.Close()
+	})
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil))
+
+	spans := exporter.GetSpans()
+	if len(spans) != 2 {
+		t.Fatalf("Expected server and client spans, got %d", len(spans))
+	}
+
+	// The client span ends first, when its body is closed
+	clientSpan, server := spans[0], spans[1]
+	if clientSpan.SpanKind != trace.SpanKindClient || server.SpanKind != trace.SpanKindServer {
+		t.Fatalf("Unexpected span kinds %v and %v", clientSpan.SpanKind, server.SpanKind)
+	}
+	if clientSpan.Parent.SpanID() != server.SpanContext.SpanID() {
+		t.Error("Client span should be a child of the server span")
+	}
+	if clientSpan.SpanContext.TraceID() != server.SpanContext.TraceID() {
+		t.Error("Client and server spans should share a trace")
+	}
+	if traceparent == "" {
+		t.Error("Provider request should carry a traceparent header")
+	}
+	if got := spanAttr(clientSpan, attrInputTokens).AsInt64(); got != 12 {
+		t.Errorf("Expected %s 12, got %d", attrInputTokens, got)
+	}
+	if got := spanAttr(clientSpan, attrOutputTokens).AsInt64(); got != 34 {
+		t.Errorf("Expected %s 34, got %d", attrOutputTokens, got)
+	}
+}
+
+func TestWithTracing_KeepsPush(t *testing.T) {
+	useExporter(t)
+
+	var pushErr error
+	handler := WithTracing(types.RouteInfo{Method: "GET", Path: "/swagger"}, func(w http.ResponseWriter, r *http.Request) {
+		pusher, ok := w.(http.Pusher)
+		if !ok {
+			t.Fatal("Traced writer should implement http.Pusher")
+		}
+		pushErr = pusher.Push("/swagger/swagger-ui.css", nil)
+	})
+
+	// httptest.ResponseRecorder cannot push, so the error passes through
+	handler(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/swagger", nil))
+	if pushErr != http.ErrNotSupported {
+		t.Errorf("Push() error = %v, expected http.ErrNotSupported", pushErr)
+	}
+}
@@ -0,0 +1,100 @@
+package tracing
+
+import (
+	"io"
+	"net/http"
+	"sync"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Token usage attributes recorded on provider client spans
+const (
+	attrInputTokens  = "gen_ai.usage.input_tokens"
+	attrOutputTokens = "gen_ai.usage.output_tokens"
+)
+
+// transport creates a client span for each outgoing request
+type transport struct {
+	base   http.RoundTripper
+	tracer trace.Tracer
+}
+
+// NewTransport wraps base so provider HTTP calls become child client spans of the
+// request's span and carry a traceparent header upstream. The span stays open
+// until the response body is closed so token usage can be recorded after the
+// body is decoded. base is returned unchanged when tracing is disabled.
+func NewTransport(base http.RoundTripper) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	tracer := currentTracer()
+	if tracer == nil {
+		return base
+	}
+	return &transport{base: base, tracer: tracer}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, span := t.tracer.Start(req.Context(), req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.request.method", req.Method),
+			attribute.String("server.address", req.URL.Hostname()),
+			attribute.String("url.full", req.URL.Redacted()),
+		),
+	)
+
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(ctx)
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		span.End()
+		return nil, err
+	}
+
+	span.SetAttributes(attribute.Int("http.response.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+	if resp.Request == nil {
+		resp.Request = req
+	}
+	resp.Body = &spanBody{ReadCloser: resp.Body, span: span}
+	return resp, nil
+}
+
+// spanBody ends the client span when the response body is closed
+type spanBody struct {
+	io.ReadCloser
+	span trace.Span
+	once sync.Once
+}
+
+func (b *spanBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.span.End() })
+	return err
+}
+
+// RecordTokenUsage attaches token counts to the client span of a provider
+// response. Call it before closing the response body; it is a no-op when
+// tracing is disabled.
+func RecordTokenUsage(resp *http.Response, inputTokens, outputTokens int) {
+	if resp == nil || resp.Request == nil {
+		return
+	}
+	span := trace.SpanFromContext(resp.Request.Context())
+	span.SetAttributes(
+		attribute.Int(attrInputTokens, inputTokens),
+		attribute.Int(attrOutputTokens, outputTokens),
+	)
+}
@@ -0,0 +1,63 @@
+// Package version holds the build information stamped into binaries at link time:
+//
+//	go build -ldflags "-X github.com/JerkyTreats/llm/internal/version.Version=1.4.0 \
+//	  -X github.com/JerkyTreats/llm/internal/version.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/JerkyTreats/llm/internal/version.BuildDate=$(date -u +%Y-%m-%dT%H:%M:%SZ)" ./cmd/server
+//
+// Unstamped builds report Version "dev", with the commit and date taken from the
+// VCS information the Go toolchain embeds when available.
+package version
+
+import (
+	"runtime"
+	"runtime/debug"
+)
+
+// DevVersion is the Version of builds not stamped with -ldflags
+const DevVersion = "dev"
+
+// Build information set with -ldflags -X
+var (
+	Version   = DevVersion
+	Commit    = ""
+	BuildDate = ""
+)
+
+// BuildInfo describes the running build
+type BuildInfo struct {
+	Version   string `json:"version" description:"Release version, or dev for unstamped builds" example:"1.4.0"`
+	Commit    string `json:"commit,omitempty" description:"Git commit the binary was built from"`
+	BuildDate string `json:"build_date,omitempty" description:"UTC build time in RFC 3339 format" example:"2025-01-15T10:30:00Z"`
+	GoVersion string `json:"go_version" description:"Go toolchain the binary was built with" example:"go1.24.3"`
+}
+
+// Get returns the build information of the running binary
+func Get() BuildInfo {
+	info := BuildInfo{
+		Version:   Version,
+		Commit:    Commit,
+		BuildDate: BuildDate,
+		GoVersion: runtime.Version(),
+	}
+	if info.Commit != "" && info.BuildDate != "" {
+		return info
+	}
+
+	// Fall back to the VCS stamp go build records for unstamped builds
+	if build, ok := debug.ReadBuildInfo(); ok {
+		for _, setting := range build.Settings {
+			switch {
+			case setting.Key == "vcs.revision" && info.Commit == "":
+				info.Commit = setting.Value
+			case setting.Key == "vcs.time" && info.BuildDate == "":
+				info.BuildDate = setting.Value
+			}
+		}
+	}
+	return info
+}
+
+// Stamped reports whether the binary was built with a release Version
+func Stamped() bool {
+	return Version != "" && Version != DevVersion
+}
@@ -0,0 +1,223 @@
+// Package webhook delivers signed callback notifications to client-supplied URLs.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/config"
+	"github.com/JerkyTreats/llm/internal/logging"
+)
+
+// Headers sent with every webhook delivery
+const (
+	SignatureHeader = "X-Webhook-Signature"
+	TimestampHeader = "X-Webhook-Timestamp"
+	EventHeader     = "X-Webhook-Event"
+)
+
+// Delivery defaults used when the config does not override them
+const (
+	defaultMaxAttempts    = 5
+	defaultBackoff        = time.Second
+	defaultTimeout        = 10 * time.Second
+	defaultMaxDeadLetters = 100
+)
+
+func init() {
+	config.RegisterDefault("webhook.max_attempts", defaultMaxAttempts)
+	config.RegisterDefault("webhook.backoff", defaultBackoff.String())
+	config.RegisterDefault("webhook.max_dead_letters", defaultMaxDeadLetters)
+	config.RegisterKey("webhook.secret", "")
+	config.RegisterKey("webhook.allowed_hosts", []interface{}{})
+}
+
+// DeadLetter records a webhook that could not be delivered
+type DeadLetter struct {
+	URL       string
+	Event     string
+	Payload   []byte
+	Attempts  int
+	LastError string
+	FailedAt  time.Time
+}
+
+// Dispatcher sends JSON payloads to callback URLs, signing each body with HMAC-SHA256
+// and retrying transient failures with exponential backoff
+type Dispatcher struct {
+	Client      *http.Client
+	MaxAttempts int           // Total delivery attempts before giving up
+	Backoff     time.Duration // Delay before the first retry; doubles on each retry
+	// MaxDeadLetters bounds the dead-letter log; the oldest letters are dropped first
+	MaxDeadLetters int
+	// AllowedHosts are callback hosts delivered to even when they resolve to a
+	// loopback or private address, such as a receiver on the same network
+	AllowedHosts []string
+
+	secret []byte
+	now    func() time.Time
+
+	mu          sync.Mutex
+	deadLetters []DeadLetter
+}
+
+// NewDispatcher creates a dispatcher signing payloads with secret. Retry limits are
+// read from webhook.max_attempts and webhook.backoff, the dead-letter log is
+// bounded by webhook.max_dead_letters, and webhook.allowed_hosts lists the hosts
+// exempt from the public address check.
+func NewDispatcher(secret string) *Dispatcher {
+	maxAttempts := config.GetInt("webhook.max_attempts")
+	if maxAttempts <= 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	backoff := defaultBackoff
+	if raw := config.GetString("webhook.backoff"); raw != "" {
+		if parsed, err := time.ParseDuration(raw); err == nil && parsed > 0 {
+			backoff = parsed
+		} else {
+			logging.Warn("Invalid webhook.backoff %q, using %s", raw, defaultBackoff)
+		}
+	}
+
+	maxDeadLetters := config.GetInt("webhook.max_dead_letters")
+	if maxDeadLetters <= 0 {
+		maxDeadLetters = defaultMaxDeadLetters
+	}
+
+	var allowedHosts []string
+	if err := config.UnmarshalKey("webhook.allowed_hosts", &allowedHosts); err != nil {
+		logging.Warn("Invalid webhook.allowed_hosts: %v", err)
+	}
+
+	d := &Dispatcher{
+		MaxAttempts:    maxAttempts,
+		Backoff:        backoff,
+		MaxDeadLetters: maxDeadLetters,
+		AllowedHosts:   allowedHosts,
+		secret:         []byte(secret),
+		now:            time.Now,
+	}
+	d.Client = d.newClient()
+	return d
+}
+
+// Sign returns the signature header value for body sent at timestamp. Receivers
+// verify deliveries by recomputing it over the timestamp header and raw body.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Send delivers payload to url as event. Network errors, 429 and 5xx responses are
+// retried; other failures are permanent. Undeliverable webhooks are recorded in the
+// dead-letter log and returned as an error.
+func (d *Dispatcher) Send(ctx context.Context, url, event string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	maxAttempts := d.MaxAttempts
+	if maxAttempts < 1 {
+		maxAttempts = 1
+	}
+
+	var lastErr error
+	attempts := 0
+	for attempts < maxAttempts {
+		if attempts > 0 {
+			delay := d.Backoff << (attempts - 1)
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return d.deadLetter(url, event, body, attempts, ctx.Err())
+			}
+		}
+		attempts++
+
+		retry, err := d.deliver(ctx, url, event, body)
+		if err == nil {
+			return nil
+		}
+		lastErr = err
+		if !retry {
+			break
+		}
+		logging.Debug("Webhook %s to %s failed (attempt %d/%d): %v", event, url, attempts, maxAttempts, err)
+	}
+
+	return d.deadLetter(url, event, body, attempts, lastErr)
+}
+
+// deliver makes one delivery attempt and reports whether a failure is worth retrying
+func (d *Dispatcher) deliver(ctx context.Context, url, event string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	timestamp := strconv.FormatInt(d.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, event)
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, Sign(d.secret, timestamp, body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil && !errors.Is(err, ErrPrivateDestination), err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+
+	err = fmt.Errorf("callback returned status %d", resp.StatusCode)
+	retry := resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= http.StatusInternalServerError
+	return retry, err
+}
+
+// deadLetter records an undeliverable webhook and returns the delivery error
+func (d *Dispatcher) deadLetter(url, event string, body []byte, attempts int, err error) error {
+	letter := DeadLetter{
+		URL:       url,
+		Event:     event,
+		Payload:   body,
+		Attempts:  attempts,
+		LastError: err.Error(),
+		FailedAt:  d.now(),
+	}
+
+	d.mu.Lock()
+	d.deadLetters = append(d.deadLetters, letter)
+	if limit := max(d.MaxDeadLetters, 1); len(d.deadLetters) > limit {
+		d.deadLetters = slices.Delete(d.deadLetters, 0, len(d.deadLetters)-limit)
+	}
+	d.mu.Unlock()
+
+	logging.Error("Giving up on webhook %s to %s after %d attempts: %v", event, url, attempts, err)
+	return fmt.Errorf("webhook %s to %s failed after %d attempts: %w", event, url, attempts, err)
+}
+
+// DeadLetters returns the most recent webhooks that could not be delivered, oldest first
+func (d *Dispatcher) DeadLetters() []DeadLetter {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]DeadLetter(nil), d.deadLetters...)
+}
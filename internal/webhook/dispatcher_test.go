@@ -0,0 +1,215 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/JerkyTreats/llm/internal/config"
+)
+
+// newTestDispatcher returns a dispatcher with a fixed clock and millisecond backoff
+func newTestDispatcher(t *testing.T) *Dispatcher {
+	t.Helper()
+	config.ResetForTest()
+	t.Cleanup(config.ResetForTest)
+
+	d := NewDispatcher("s3cret")
+	d.AllowedHosts = []string{"127.0.0.1"} // httptest servers listen on loopback
+	d.MaxAttempts = 3
+	d.Backoff = time.Millisecond
+	d.now = func() time.Time { return time.Unix(1700000000, 0) }
+	return d
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"job_id":"j1"}`)
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign([]byte("s3cret"), "1700000000", body); got != want {
+		t.Errorf("Sign() = %s, want %s", got, want)
+	}
+	if Sign([]byte("other"), "1700000000", body) == want {
+		t.Error("Different secrets should produce different signatures")
+	}
+}
+
+func TestSend_SignsPayload(t *testing.T) {
+	d := newTestDispatcher(t)
+
+	var got *http.Request
+	var gotBody []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r
+		gotBody, _ = io.ReadAll(r.Body)
+	}))
+	defer server.Close()
+
+	if err := d.Send(context.Background(), server.URL, "job.completed", map[string]string{"job_id": "j1"}); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	if got.Header.Get(EventHeader) != "job.completed" {
+		t.Errorf("Expected event header job.completed, got %q", got.Header.Get(EventHeader))
+	}
+	if got.Header.Get(TimestampHeader) != "1700000000" {
+		t.Errorf("Expected timestamp header 1700000000, got %q", got.Header.Get(TimestampHeader))
+	}
+	want := Sign([]byte("s3cret"), "1700000000", gotBody)
+	if got.Header.Get(SignatureHeader) != want {
+		t.Errorf("Signature header %q does not match body signature %q", got.Header.Get(SignatureHeader), want)
+	}
+}
+
+func TestSend_RetriesServerErrors(t *testing.T) {
+	d := newTestDispatcher(t)
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	if err := d.Send(context.Background(), server.URL, "job.completed", nil); err != nil {
+		t.Fatalf("Send() should succeed on the third attempt, got %v", err)
+	}
+	if calls != 3 {
+		t.Errorf("Expected 3 attempts, got %d", calls)
+	}
+	if len(d.DeadLetters()) != 0 {
+		t.Error("Delivered webhooks should not be dead-lettered")
+	}
+}
+
+func TestSend_GivesUp(t *testing.T) {
+	d := newTestDispatcher(t)
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	if err := d.Send(context.Background(), server.URL, "job.failed", map[string]string{"job_id": "j2"}); err == nil {
+		t.Fatal("Send() should fail once attempts are exhausted")
+	}
+	if calls != 3 {
+		t.Errorf("Expected MaxAttempts (3) attempts, got %d", calls)
+	}
+
+	letters := d.DeadLetters()
+	if len(letters) != 1 {
+		t.Fatalf("Expected 1 dead letter, got %d", len(letters))
+	}
+	if letters[0].Attempts != 3 || letters[0].Event != "job.failed" || string(letters[0].Payload) != `{"job_id":"j2"}` {
+		t.Errorf("Unexpected dead letter %+v", letters[0])
+	}
+}
+
+func TestSend_ClientErrorIsPermanent(t *testing.T) {
+	d := newTestDispatcher(t)
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusGone)
+	}))
+	defer server.Close()
+
+	if err := d.Send(context.Background(), server.URL, "job.completed", nil); err == nil {
+		t.Fatal("Send() should fail for a 410 response")
+	}
+	if calls != 1 {
+		t.Errorf("Client errors should not be retried, got %d attempts", calls)
+	}
+	if len(d.DeadLetters()) != 1 {
+		t.Error("Permanent failures should be dead-lettered")
+	}
+}
+
+func TestSend_DeadLettersAreCapped(t *testing.T) {
+	d := newTestDispatcher(t)
+	d.MaxAttempts = 1
+	d.MaxDeadLetters = 2
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	for _, id := range []string{"j1", "j2", "j3"} {
+		d.Send(context.Background(), server.URL, "job.finished", map[string]string{"job_id": id})
+	}
+
+	letters := d.DeadLetters()
+	if len(letters) != 2 || string(letters[0].Payload) != `{"job_id":"j2"}` || string(letters[1].Payload) != `{"job_id":"j3"}` {
+		t.Errorf("Expected the two most recent dead letters, got %+v", letters)
+	}
+}
+
+func TestCheckURL(t *testing.T) {
+	d := newTestDispatcher(t)
+	d.AllowedHosts = []string{"hooks.internal"}
+
+	for _, tt := range []struct {
+		url     string
+		private bool
+		valid   bool
+	}{
+		{"https://example.com/hooks", false, true},
+		{"http://93.184.216.34/hooks", false, true},
+		{"http://hooks.internal/jobs", false, true},
+		{"http://127.0.0.1:8080/", true, false},
+		{"http://[::1]/", true, false},
+		{"http://169.254.169.254/latest/meta-data", true, false},
+		{"http://10.0.0.5/", true, false},
+		{"http://192.168.1.1/", true, false},
+		{"http://[::ffff:10.0.0.5]/", true, false},
+		{"ftp://example.com/", false, false},
+		{"/relative", false, false},
+	} {
+		err := d.CheckURL(tt.url)
+		if (err == nil) != tt.valid || errors.Is(err, ErrPrivateDestination) != tt.private {
+			t.Errorf("CheckURL(%q) = %v, want valid %v, private %v", tt.url, err, tt.valid, tt.private)
+		}
+	}
+}
+
+func TestSend_RefusesPrivateDestination(t *testing.T) {
+	d := newTestDispatcher(t)
+	d.AllowedHosts = nil
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	// The loopback address is refused once resolved, before connecting
+	target := strings.Replace(server.URL, "127.0.0.1", "localhost", 1)
+	err := d.Send(context.Background(), target, "job.completed", map[string]string{"job_id": "j1"})
+	if !errors.Is(err, ErrPrivateDestination) {
+		t.Fatalf("Send() error = %v, want ErrPrivateDestination", err)
+	}
+	if calls.Load() != 0 {
+		t.Errorf("Callback server received %d requests, want none", calls.Load())
+	}
+	if letters := d.DeadLetters(); len(letters) != 1 || letters[0].Attempts != 1 {
+		t.Errorf("Refused delivery should be dead-lettered without retries, got %+v", letters)
+	}
+}
@@ -0,0 +1,93 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"slices"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// dialTimeout bounds how long connecting to a callback URL may take
+const dialTimeout = 5 * time.Second
+
+// ErrPrivateDestination is returned for callback URLs that resolve to a loopback,
+// private, link-local or otherwise non-public address. Clients choose callback
+// URLs, so delivering to those addresses would let them reach internal hosts.
+var ErrPrivateDestination = errors.New("callback destination is not a public address")
+
+// CheckURL reports whether raw is a callback URL the dispatcher will deliver to:
+// an absolute http or https URL whose host is in AllowedHosts or is not a
+// non-public IP literal. Host names are checked again once resolved, when dialling.
+func (d *Dispatcher) CheckURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return fmt.Errorf("callback URL must be an absolute http or https URL")
+	}
+	if d.allowedHost(u.Hostname()) {
+		return nil
+	}
+	if addr, err := netip.ParseAddr(u.Hostname()); err == nil && !publicAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrPrivateDestination, u.Hostname())
+	}
+	return nil
+}
+
+// allowedHost reports whether host is exempt from the public address check
+func (d *Dispatcher) allowedHost(host string) bool {
+	return slices.ContainsFunc(d.AllowedHosts, func(allowed string) bool {
+		return strings.EqualFold(allowed, host)
+	})
+}
+
+// newClient returns the delivery client. It dials hosts outside AllowedHosts
+// through a dialer that refuses non-public addresses after resolution, so a host
+// name cannot point a delivery at an internal service. Proxies are not used, since
+// the proxy rather than the destination would be checked.
+func (d *Dispatcher) newClient() *http.Client {
+	dialer := &net.Dialer{Timeout: dialTimeout}
+	guarded := &net.Dialer{Timeout: dialTimeout, Control: refusePrivateAddr}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = func(ctx context.Context, network, address string) (net.Conn, error) {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return nil, err
+		}
+		if d.allowedHost(host) {
+			return dialer.DialContext(ctx, network, address)
+		}
+		return guarded.DialContext(ctx, network, address)
+	}
+	return &http.Client{Transport: transport, Timeout: defaultTimeout}
+}
+
+// refusePrivateAddr is a net.Dialer Control function run with the resolved
+// address before connecting
+func refusePrivateAddr(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil || !publicAddr(addr) {
+		return fmt.Errorf("%w: %s", ErrPrivateDestination, host)
+	}
+	return nil
+}
+
+// publicAddr reports whether addr is a globally routable unicast address
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsGlobalUnicast() && !addr.IsPrivate() && !sharedAddressSpace.Contains(addr)
+}
+
+// sharedAddressSpace is the carrier-grade NAT range, which IsPrivate leaves out
+var sharedAddressSpace = netip.MustParsePrefix("100.64.0.0/10")